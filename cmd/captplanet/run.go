@@ -16,9 +16,11 @@ import (
 	"storj.io/storj/pkg/cfgstruct"
 	"storj.io/storj/pkg/datarepair/checker"
 	"storj.io/storj/pkg/datarepair/repairer"
+	"storj.io/storj/pkg/gracefulexit"
 	"storj.io/storj/pkg/inspector"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/miniogw"
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/piecestore/psserver"
 	"storj.io/storj/pkg/pointerdb"
@@ -35,17 +37,19 @@ const (
 
 // Satellite is for configuring client
 type Satellite struct {
-	Identity    provider.IdentityConfig
-	Kademlia    kademlia.Config
-	PointerDB   pointerdb.Config
-	Overlay     overlay.Config
-	Inspector   inspector.Config
-	Checker     checker.Config
-	Repairer    repairer.Config
-	Audit       audit.Config
-	StatDB      statdb.Config
-	BwAgreement bwagreement.Config
-	Web         satelliteweb.Config
+	Identity     provider.IdentityConfig
+	Kademlia     kademlia.Config
+	Notification notification.Config
+	PointerDB    pointerdb.Config
+	Overlay      overlay.Config
+	Inspector    inspector.Config
+	Checker      checker.Config
+	Repairer     repairer.Config
+	GracefulExit gracefulexit.Config
+	Audit        audit.Config
+	StatDB       statdb.Config
+	BwAgreement  bwagreement.Config
+	Web          satelliteweb.Config
 }
 
 // StorageNode is for configuring storage nodes
@@ -108,9 +112,11 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 			runCfg.Satellite.Audit,
 			runCfg.Satellite.StatDB,
 			runCfg.Satellite.Overlay,
+			runCfg.Satellite.Notification,
 			runCfg.Satellite.PointerDB,
 			runCfg.Satellite.Checker,
 			runCfg.Satellite.Repairer,
+			runCfg.Satellite.GracefulExit,
 			runCfg.Satellite.BwAgreement,
 			runCfg.Satellite.Web,
 
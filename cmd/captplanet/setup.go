@@ -165,6 +165,9 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 
 		// Repairer
 		"piecestore.agreementsender.overlay_addr": overlayAddr,
+
+		// Graceful exit
+		"satellite.graceful-exit.overlay-addr": overlayAddr,
 	}
 
 	for i := 0; i < len(runCfg.StorageNodes); i++ {
@@ -179,6 +182,11 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		overrides[storagenode+"kademlia.bootstrap-addr"] = joinHostPort(
 			setupCfg.ListenHost, startingPort+1)
 		overrides[storagenode+"storage.path"] = filepath.Join(storagenodePath, "data")
+		// the dashboards are only served on loopback addresses
+		overrides[storagenode+"storage.dashboard-addr"] = joinHostPort(
+			"127.0.0.1", startingPort+i*2+4)
+		overrides[storagenode+"storage.trusted-satellites"] =
+			setupCfg.HCIdentity.CertPath + "@" + overlayAddr
 	}
 
 	return process.SaveConfig(runCmd.Flags(),
@@ -23,7 +23,9 @@ import (
 	"storj.io/storj/pkg/datarepair/checker"
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/datarepair/repairer"
+	"storj.io/storj/pkg/gracefulexit"
 	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pointerdb"
@@ -61,13 +63,15 @@ var (
 	}
 
 	runCfg struct {
-		Identity  provider.IdentityConfig
-		Kademlia  kademlia.Config
-		PointerDB pointerdb.Config
-		Overlay   overlay.Config
-		StatDB    statdb.Config
-		Checker   checker.Config
-		Repairer  repairer.Config
+		Identity     provider.IdentityConfig
+		Kademlia     kademlia.Config
+		Notification notification.Config
+		PointerDB    pointerdb.Config
+		Overlay      overlay.Config
+		StatDB       statdb.Config
+		Checker      checker.Config
+		Repairer     repairer.Config
+		GracefulExit gracefulexit.Config
 
 		// Audit audit.Config
 		BwAgreement bwagreement.Config
@@ -105,11 +109,13 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		process.Ctx(cmd),
 		grpcauth.NewAPIKeyInterceptor(),
 		runCfg.Kademlia,
+		runCfg.Notification,
 		runCfg.PointerDB,
 		runCfg.Overlay,
 		runCfg.StatDB,
 		runCfg.Checker,
 		runCfg.Repairer,
+		runCfg.GracefulExit,
 		// runCfg.Audit,
 		runCfg.BwAgreement,
 	)
@@ -6,10 +6,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"text/tabwriter"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/spf13/cobra"
@@ -22,6 +24,8 @@ import (
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
 )
 
 var (
@@ -44,6 +48,12 @@ var (
 		Short: "Diagnostic Tool support",
 		RunE:  cmdDiag,
 	}
+	exitCmd = &cobra.Command{
+		Use:   "exit <satellite-id> <satellite-address>",
+		Short: "Leave a satellite, transferring its pieces to other nodes",
+		Args:  cobra.ExactArgs(2),
+		RunE:  cmdExit,
+	}
 
 	runCfg struct {
 		Identity provider.IdentityConfig
@@ -58,6 +68,9 @@ var (
 	diagCfg struct {
 		BasePath string `default:"$CONFDIR" help:"base path for setup"`
 	}
+	exitCfg struct {
+		Identity provider.IdentityConfig
+	}
 
 	defaultConfDir = "$HOME/.storj/storagenode"
 	defaultDiagDir = "$HOME/.storj/capt/f37/data"
@@ -67,9 +80,11 @@ func init() {
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(diagCmd)
+	rootCmd.AddCommand(exitCmd)
 	cfgstruct.Bind(runCmd.Flags(), &runCfg, cfgstruct.ConfDir(defaultConfDir))
 	cfgstruct.Bind(setupCmd.Flags(), &setupCfg, cfgstruct.ConfDir(defaultConfDir))
 	cfgstruct.Bind(diagCmd.Flags(), &diagCfg, cfgstruct.ConfDir(defaultDiagDir))
+	cfgstruct.Bind(exitCmd.Flags(), &exitCfg, cfgstruct.ConfDir(defaultConfDir))
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
@@ -122,7 +137,7 @@ func cmdDiag(cmd *cobra.Command, args []string) (err error) {
 
 	// open the sql db
 	dbpath := filepath.Join(diagCfg.BasePath, "piecestore.db")
-	db, err := psdb.Open(context.Background(), "", dbpath)
+	db, err := psdb.Open(context.Background(), nil, dbpath)
 	if err != nil {
 		fmt.Println("Storagenode database couldnt open:", dbpath)
 		return err
@@ -195,11 +210,111 @@ func cmdDiag(cmd *cobra.Command, args []string) (err error) {
 
 	// display the data
 	err = w.Flush()
-	return err
+	if err != nil {
+		return err
+	}
+
+	return printSatelliteUsage(db)
+}
+
+// printSatelliteUsage prints the pieces stored for each satellite and the
+// bandwidth each used this month, by action
+func printSatelliteUsage(db *psdb.DB) error {
+	usage, err := db.SpaceUsedBySatellite()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	bandwidth, err := db.GetBandwidthUsedBySatellite(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), now)
+	if err != nil {
+		return err
+	}
+
+	satelliteIDs := storj.NodeIDList{}
+	for satelliteID := range usage {
+		satelliteIDs = append(satelliteIDs, satelliteID)
+	}
+	for satelliteID := range bandwidth {
+		if _, ok := usage[satelliteID]; !ok {
+			satelliteIDs = append(satelliteIDs, satelliteID)
+		}
+	}
+	sort.Sort(satelliteIDs)
+
+	fmt.Println()
+	const padding = 3
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	fmt.Fprintln(w, "SatelliteID\tPieces\tSpace Used\tPUT Bandwidth\tGET Bandwidth\t")
+	for _, satelliteID := range satelliteIDs {
+		u := usage[satelliteID]
+		if u == nil {
+			u = &psdb.SatelliteUsage{}
+		}
+		bw := bandwidth[satelliteID]
+		if bw == nil {
+			bw = &psdb.BandwidthUsage{}
+		}
+		fmt.Fprint(w, satelliteID, "\t", u.Pieces, "\t", u.Size, "\t", bw.Put, "\t", bw.Get, "\t\n")
+	}
+	return w.Flush()
+}
+
+func cmdExit(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	satelliteID, err := storj.NodeIDFromString(args[0])
+	if err != nil {
+		return err
+	}
+
+	identity, err := exitCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+
+	// the running node runs the exit, for callers with its own identity
+	conn, err := transport.NewClient(identity).DialNode(ctx, &pb.Node{
+		Id: identity.ID,
+		Address: &pb.NodeAddress{
+			Transport: pb.NodeTransport_TCP_TLS_GRPC,
+			Address:   exitCfg.Identity.Address,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, conn.Close()) }()
+
+	stream, err := pb.NewNodeExitClient(conn).Exit(ctx, &pb.NodeExitRequest{
+		SatelliteId:      satelliteID,
+		SatelliteAddress: args[1],
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		progress, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d of %d pieces transferred, %d failed\n",
+			progress.GetPiecesTransferred(), progress.GetPiecesTotal(), progress.GetPiecesFailed())
+		if progress.GetFinished() {
+			fmt.Println("Exited satellite", satelliteID)
+		}
+	}
 }
 
 func main() {
 	runCmd.Flags().String("config",
 		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
+	exitCmd.Flags().String("config",
+		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
 	process.Exec(rootCmd)
 }
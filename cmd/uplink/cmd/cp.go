@@ -24,6 +24,7 @@ import (
 
 var (
 	progress *bool
+	resume   *bool
 )
 
 func init() {
@@ -33,6 +34,7 @@ func init() {
 		RunE:  copyMain,
 	}, CLICmd)
 	progress = cpCmd.Flags().Bool("progress", true, "if true, show progress")
+	resume = cpCmd.Flags().Bool("resume", false, "if true, record the progress of the upload and continue an interrupted upload of the same file")
 }
 
 // upload transfers src from local machine to s3 compatible object dst
@@ -76,10 +78,40 @@ func upload(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.FP
 		return convertError(err, dst)
 	}
 
+	// only uploads with --resume keep a journal; the others delete the
+	// uploaded segments when they are interrupted
+	var journal *uploadJournal
+	var offset int64
+	if *resume {
+		if f == os.Stdin {
+			return fmt.Errorf("uploads from standard input cannot be resumed")
+		}
+
+		journal, err = loadUploadJournal(src.Path(), dst.String())
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case journal.Committed == 0:
+			journal.reset(fi)
+		case !journal.matches(fi):
+			fmt.Fprintf(os.Stderr, "Source changed since the interrupted upload, uploading it again: %s\n", src)
+			journal.reset(fi)
+		default:
+			offset = journal.Committed * journal.SegmentSize
+			_, err = f.Seek(offset, io.SeekStart)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	r := io.Reader(f)
 	var bar *progressbar.ProgressBar
 	if showProgress {
 		bar = progressbar.New(int(fi.Size())).SetUnits(progressbar.U_BYTES)
+		bar.Set(int(offset))
 		bar.Start()
 		r = bar.NewProxyReader(r)
 	}
@@ -87,7 +119,18 @@ func upload(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.FP
 	meta := pb.SerializableMeta{}
 	expTime := time.Time{}
 
-	_, err = o.Put(ctx, dst.Path(), r, meta, expTime)
+	if journal != nil {
+		_, err = o.PutResumable(ctx, dst.Path(), r, meta, expTime, journal)
+		if err != nil {
+			if journal.Committed > 0 {
+				fmt.Fprintf(os.Stderr, "Upload interrupted, run the same command to continue it\n")
+			}
+			return err
+		}
+		err = journal.remove()
+	} else {
+		_, err = o.Put(ctx, dst.Path(), r, meta, expTime)
+	}
 	if err != nil {
 		return err
 	}
@@ -165,8 +208,8 @@ func download(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.
 	return nil
 }
 
-// copy copies s3 compatible object src to s3 compatible object dst
-func copy(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.FPath) error {
+// copyObject copies s3 compatible object src to s3 compatible object dst
+func copyObject(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.FPath) error {
 	if src.IsLocal() {
 		return fmt.Errorf("source must be Storj URL: %s", src)
 	}
@@ -268,5 +311,5 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	// if copying from one remote location to another
-	return copy(ctx, bs, src, dst)
+	return copyObject(ctx, bs, src, dst)
 }
@@ -0,0 +1,121 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testobjects"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+// bucketStore serves the objects of every bucket from the same store
+type bucketStore struct {
+	buckets.Store
+	objects objects.Store
+}
+
+func (bs bucketStore) GetObjectStore(ctx context.Context, bucket string) (objects.Store, error) {
+	return bs.objects, nil
+}
+
+// resumableStore records the progress of the resumable uploads in their
+// journals, with segments of testSegmentSize bytes
+type resumableStore struct {
+	*testobjects.Store
+	// fail makes the uploads fail after their first segment
+	fail bool
+	// resumed are the committed segments the uploads started after
+	resumed []int64
+}
+
+const testSegmentSize = 4
+
+func (s *resumableStore) PutResumable(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time, journal streams.Journal) (objects.Meta, error) {
+	_, committed := journal.Resume()
+	s.resumed = append(s.resumed, committed)
+
+	if s.fail {
+		if err := journal.Commit(testSegmentSize, committed+1); err != nil {
+			return objects.Meta{}, err
+		}
+		return objects.Meta{}, errors.New("connection lost")
+	}
+	// only the data after the committed segments is read
+	return s.Put(ctx, path, data, metadata, expiration)
+}
+
+func TestUploadResume(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// the journals are kept in the application directory
+	defer func(dataHome string) { _ = os.Setenv("XDG_DATA_HOME", dataHome) }(os.Getenv("XDG_DATA_HOME"))
+	require.NoError(t, os.Setenv("XDG_DATA_HOME", ctx.Dir("data")))
+	defer func(r bool) { *resume = r }(*resume)
+	*resume = true
+
+	store := &resumableStore{Store: testobjects.NewStore()}
+	bs := bucketStore{objects: store}
+
+	path := ctx.File("file")
+	src, err := fpath.New(path)
+	require.NoError(t, err)
+	dst, err := fpath.New("sj://bucket/file")
+	require.NoError(t, err)
+
+	committed := func() int64 {
+		journal, err := loadUploadJournal(src.Path(), dst.String())
+		require.NoError(t, err)
+		return journal.Committed
+	}
+	stored := func() string {
+		rr, _, err := store.Get(ctx, "file")
+		require.NoError(t, err)
+		r, err := rr.Range(ctx, 0, rr.Size())
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// an interrupted upload continues after its committed segments
+	require.NoError(t, ioutil.WriteFile(path, []byte("0123456789"), 0644))
+	store.fail = true
+	assert.Error(t, upload(ctx, bs, src, dst, false))
+	assert.Equal(t, int64(1), committed())
+
+	store.fail = false
+	require.NoError(t, upload(ctx, bs, src, dst, false))
+	assert.Equal(t, []int64{0, 1}, store.resumed)
+	assert.Equal(t, "456789", stored())
+	assert.Equal(t, int64(0), committed())
+
+	// an interrupted upload starts over when the source changed meanwhile
+	store.fail = true
+	assert.Error(t, upload(ctx, bs, src, dst, false))
+	assert.Equal(t, int64(1), committed())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("abcdefghijklmnop"), 0644))
+	store.fail = false
+	require.NoError(t, upload(ctx, bs, src, dst, false))
+	assert.Equal(t, []int64{0, 1, 0, 0}, store.resumed)
+	assert.Equal(t, "abcdefghijklmnop", stored())
+	assert.Equal(t, int64(0), committed())
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// uploadJournal is a streams.Journal stored as a JSON file on the local
+// machine. It identifies the upload by the source file and the destination.
+type uploadJournal struct {
+	path string // location of the journal file
+
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	Size        int64     `json:"size"`
+	Modified    time.Time `json:"modified"`
+	SegmentSize int64     `json:"segment_size"`
+	Committed   int64     `json:"committed"`
+}
+
+// journalDir returns the directory where the upload journals are kept
+func journalDir() string {
+	return applicationDir("storj", "uplink", "resume")
+}
+
+// loadUploadJournal returns the journal for uploading source to destination.
+// The journal is loaded from the disk if a previous upload left one behind.
+func loadUploadJournal(source, destination string) (*uploadJournal, error) {
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+
+	id := sha256.Sum256([]byte(source + "\x00" + destination))
+	journal := &uploadJournal{
+		path:        filepath.Join(journalDir(), hex.EncodeToString(id[:])+".json"),
+		Source:      source,
+		Destination: destination,
+	}
+
+	data, err := ioutil.ReadFile(journal.path)
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, journal)
+	if err != nil {
+		return nil, err
+	}
+
+	return journal, nil
+}
+
+// matches returns whether the journal was recorded for the same version of
+// the source file
+func (j *uploadJournal) matches(fi os.FileInfo) bool {
+	return j.Size == fi.Size() && j.Modified.Equal(fi.ModTime())
+}
+
+// reset discards the progress of any previous upload and starts a new one
+// for the given version of the source file
+func (j *uploadJournal) reset(fi os.FileInfo) {
+	j.Size = fi.Size()
+	j.Modified = fi.ModTime()
+	j.SegmentSize = 0
+	j.Committed = 0
+}
+
+// Resume implements streams.Journal
+func (j *uploadJournal) Resume() (segmentSize int64, committed int64) {
+	return j.SegmentSize, j.Committed
+}
+
+// Commit implements streams.Journal
+func (j *uploadJournal) Commit(segmentSize int64, committed int64) error {
+	j.SegmentSize = segmentSize
+	j.Committed = committed
+	return j.save()
+}
+
+// save writes the journal to the disk, replacing the previous version
+// atomically
+func (j *uploadJournal) save() error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(j.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	tmp := j.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, j.path)
+}
+
+// remove deletes the journal after the upload has finished
+func (j *uploadJournal) remove() error {
+	err := os.Remove(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -6,14 +6,13 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"os/signal"
 	"strings"
-	"time"
+	"sync"
+	"syscall"
 
 	"github.com/hanwen/go-fuse/fuse"
 	"github.com/hanwen/go-fuse/fuse/nodefs"
@@ -22,7 +21,6 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/storj/internal/fpath"
-	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storage/objects"
@@ -30,12 +28,19 @@ import (
 	"storj.io/storj/pkg/utils"
 )
 
+var (
+	mountCacheDir  *string
+	mountReadAhead *int
+)
+
 func init() {
-	addCmd(&cobra.Command{
+	mountCmd := addCmd(&cobra.Command{
 		Use:   "mount",
 		Short: "Mount a bucket",
 		RunE:  mountBucket,
 	}, CLICmd)
+	mountCacheDir = mountCmd.Flags().String("cache-dir", applicationDir("storj", "uplink", "cache"), "directory for staging written files until they are uploaded")
+	mountReadAhead = mountCmd.Flags().Int("read-ahead", 4, "number of blocks fetched in parallel when reading a file")
 }
 
 func mountBucket(cmd *cobra.Command, args []string) (err error) {
@@ -66,12 +71,15 @@ func mountBucket(cmd *cobra.Command, args []string) (err error) {
 		return convertError(err, src)
 	}
 
-	nfs := pathfs.NewPathNodeFs(newStorjFS(ctx, store), nil)
+	err = os.MkdirAll(*mountCacheDir, 0700)
+	if err != nil {
+		return err
+	}
+
+	nfs := pathfs.NewPathNodeFs(newStorjFS(ctx, store, *mountCacheDir), nil)
 	conn := nodefs.NewFileSystemConnector(nfs.Root(), nil)
 
-	// workaround to avoid async (unordered) reading
-	mountOpts := fuse.MountOptions{MaxBackground: 1}
-	server, err := fuse.NewServer(conn.RawFS(), args[1], &mountOpts)
+	server, err := fuse.NewServer(conn.RawFS(), args[1], &fuse.MountOptions{})
 	if err != nil {
 		return fmt.Errorf("Mount failed: %v", err)
 	}
@@ -92,19 +100,23 @@ func mountBucket(cmd *cobra.Command, args []string) (err error) {
 }
 
 type storjFS struct {
-	ctx          context.Context
-	store        objects.Store
-	createdFiles map[string]*storjFile
-	nodeFS       *pathfs.PathNodeFs
+	ctx      context.Context
+	store    objects.Store
+	cacheDir string
+	nodeFS   *pathfs.PathNodeFs
 	pathfs.FileSystem
+
+	mu          sync.Mutex
+	cachedFiles map[string]*cachedFile // files opened for writing
 }
 
-func newStorjFS(ctx context.Context, store objects.Store) *storjFS {
+func newStorjFS(ctx context.Context, store objects.Store, cacheDir string) *storjFS {
 	return &storjFS{
-		ctx:          ctx,
-		store:        store,
-		createdFiles: make(map[string]*storjFile),
-		FileSystem:   pathfs.NewDefaultFileSystem(),
+		ctx:         ctx,
+		store:       store,
+		cacheDir:    cacheDir,
+		cachedFiles: make(map[string]*cachedFile),
+		FileSystem:  pathfs.NewDefaultFileSystem(),
 	}
 }
 
@@ -119,11 +131,10 @@ func (sf *storjFS) GetAttr(name string, context *fuse.Context) (*fuse.Attr, fuse
 		return &fuse.Attr{Mode: fuse.S_IFDIR | 0755}, fuse.OK
 	}
 
-	// special case for just created files e.g. while coping into directory
-	createdFile, ok := sf.createdFiles[name]
-	if ok {
+	// special case for files being written e.g. while coping into directory
+	if cached := sf.getCachedFile(name); cached != nil {
 		attr := &fuse.Attr{}
-		status := createdFile.GetAttr(attr)
+		status := cached.getAttr(attr)
 		return attr, status
 	}
 
@@ -164,7 +175,7 @@ func (sf *storjFS) OpenDir(name string, context *fuse.Context) (c []fuse.DirEntr
 	zap.S().Debug("OpenDir: ", name)
 
 	var entries []fuse.DirEntry
-	err := sf.listObjects(sf.ctx, name, false, func(items []objects.ListItem) error {
+	err := objects.ListAll(sf.ctx, sf.store, name, false, meta.None, func(items []objects.ListItem) error {
 		for _, item := range items {
 			path := item.Path
 
@@ -188,11 +199,7 @@ func (sf *storjFS) OpenDir(name string, context *fuse.Context) (c []fuse.DirEntr
 func (sf *storjFS) Mkdir(name string, mode uint32, context *fuse.Context) fuse.Status {
 	zap.S().Debug("Mkdir: ", name)
 
-	reader := bytes.NewReader([]byte{})
-	meta := pb.SerializableMeta{ContentType: "application/directory"}
-	expTime := time.Time{}
-
-	_, err := sf.store.Put(sf.ctx, name+"/", reader, meta, expTime)
+	err := objects.PutDirectory(sf.ctx, sf.store, name)
 	if err != nil {
 		return fuse.EIO
 	}
@@ -203,7 +210,7 @@ func (sf *storjFS) Mkdir(name string, mode uint32, context *fuse.Context) fuse.S
 func (sf *storjFS) Rmdir(name string, context *fuse.Context) (code fuse.Status) {
 	zap.S().Debug("Rmdir: ", name)
 
-	err := sf.listObjects(sf.ctx, name, true, func(items []objects.ListItem) error {
+	err := objects.ListAll(sf.ctx, sf.store, name, true, meta.None, func(items []objects.ListItem) error {
 		for _, item := range items {
 			err := sf.store.Delete(sf.ctx, storj.JoinPaths(name, item.Path))
 			if err != nil {
@@ -222,46 +229,104 @@ func (sf *storjFS) Rmdir(name string, context *fuse.Context) (code fuse.Status)
 
 func (sf *storjFS) Open(name string, flags uint32, context *fuse.Context) (file nodefs.File, code fuse.Status) {
 	zap.S().Debug("Open: ", name)
-	return newStorjFile(sf.ctx, name, sf.store, false, sf), fuse.OK
+
+	if flags&fuse.O_ANYWRITE == 0 && sf.getCachedFile(name) == nil {
+		return newStorjFile(sf.ctx, name, sf, nil), fuse.OK
+	}
+
+	cached, err := sf.openCachedFile(name, flags&syscall.O_TRUNC == 0)
+	if err != nil {
+		zap.S().Errorf("error during opening file for writing: %v", err)
+		if storj.ErrObjectNotFound.Has(err) {
+			return nil, fuse.ENOENT
+		}
+		return nil, fuse.EIO
+	}
+
+	if flags&syscall.O_TRUNC != 0 {
+		if status := cached.truncate(0); !status.Ok() {
+			sf.releaseCachedFile(cached)
+			return nil, status
+		}
+	}
+
+	return newStorjFile(sf.ctx, name, sf, cached), fuse.OK
 }
 
 func (sf *storjFS) Create(name string, flags uint32, mode uint32, context *fuse.Context) (file nodefs.File, code fuse.Status) {
 	zap.S().Debug("Create: ", name)
 
-	return sf.addCreatedFile(name, newStorjFile(sf.ctx, name, sf.store, true, sf)), fuse.OK
-}
+	cached, err := sf.openCachedFile(name, false)
+	if err != nil {
+		zap.S().Errorf("error during creating file: %v", err)
+		return nil, fuse.EIO
+	}
 
-func (sf *storjFS) addCreatedFile(name string, file *storjFile) *storjFile {
-	sf.createdFiles[name] = file
-	return file
-}
+	// a new file has to be uploaded even if nothing is written to it
+	cached.markDirty()
 
-func (sf *storjFS) removeCreatedFile(name string) {
-	delete(sf.createdFiles, name)
+	return newStorjFile(sf.ctx, name, sf, cached), fuse.OK
 }
 
-func (sf *storjFS) listObjects(ctx context.Context, name string, recursive bool, handler func([]objects.ListItem) error) (err error) {
-	startAfter := ""
+func (sf *storjFS) Truncate(name string, size uint64, context *fuse.Context) (code fuse.Status) {
+	zap.S().Debug("Truncate: ", name)
 
-	for {
-		items, more, err := sf.store.List(sf.ctx, name, startAfter, "", recursive, 0, meta.None)
-		if err != nil {
-			return err
+	cached, err := sf.openCachedFile(name, size > 0)
+	if err != nil {
+		zap.S().Errorf("error during truncating file: %v", err)
+		if storj.ErrObjectNotFound.Has(err) {
+			return fuse.ENOENT
 		}
+		return fuse.EIO
+	}
+	defer sf.releaseCachedFile(cached)
 
-		err = handler(items)
-		if err != nil {
-			return err
-		}
+	if status := cached.truncate(size); !status.Ok() {
+		return status
+	}
+
+	return cached.flush()
+}
+
+func (sf *storjFS) Rename(oldName string, newName string, context *fuse.Context) (code fuse.Status) {
+	zap.S().Debug("Rename: ", oldName, " -> ", newName)
 
-		if !more {
-			break
+	// upload pending changes, so they are moved along with the file
+	if cached := sf.getCachedFile(oldName); cached != nil {
+		if status := cached.flush(); !status.Ok() {
+			return status
 		}
+	}
 
-		startAfter = items[len(items)-1].Path
+	_, err := sf.store.Meta(sf.ctx, oldName)
+	if err != nil && !storj.ErrObjectNotFound.Has(err) {
+		return fuse.EIO
 	}
 
-	return nil
+	if err == nil {
+		err = objects.Move(sf.ctx, sf.store, oldName, newName)
+	} else {
+		// not an object, so move everything under the prefix
+		err = objects.ListAll(sf.ctx, sf.store, oldName, true, meta.None, func(items []objects.ListItem) error {
+			for _, item := range items {
+				err := objects.Move(sf.ctx, sf.store, storj.JoinPaths(oldName, item.Path), storj.JoinPaths(newName, item.Path))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		zap.S().Errorf("error during renaming: %v", err)
+		if storj.ErrObjectNotFound.Has(err) {
+			return fuse.ENOENT
+		}
+		return fuse.EIO
+	}
+
+	sf.renameCachedFile(oldName, newName)
+	return fuse.OK
 }
 
 func (sf *storjFS) Unlink(name string, context *fuse.Context) (code fuse.Status) {
@@ -278,54 +343,131 @@ func (sf *storjFS) Unlink(name string, context *fuse.Context) (code fuse.Status)
 	return fuse.OK
 }
 
+// getCachedFile returns the local copy of a file opened for writing, or nil
+// if there is none ready
+func (sf *storjFS) getCachedFile(name string) *cachedFile {
+	sf.mu.Lock()
+	cached := sf.cachedFiles[name]
+	sf.mu.Unlock()
+
+	if cached == nil {
+		return nil
+	}
+	select {
+	case <-cached.ready:
+		if cached.err != nil {
+			return nil
+		}
+		return cached
+	default:
+		return nil
+	}
+}
+
+// openCachedFile returns the local copy of a file for writing, creating it
+// if needed. If download is true, a new local copy starts with the content
+// of the stored object. The copy is created outside of the lock, while the
+// other openers of the file wait for it.
+func (sf *storjFS) openCachedFile(name string, download bool) (*cachedFile, error) {
+	sf.mu.Lock()
+	cached, ok := sf.cachedFiles[name]
+	if !ok {
+		cached = newCachedFile(sf.ctx, sf.store, name)
+		sf.cachedFiles[name] = cached
+	}
+	cached.refs++
+	sf.mu.Unlock()
+
+	if !ok {
+		if err := cached.create(sf.cacheDir, download); err != nil {
+			// the copy is not handed to the later openers
+			sf.mu.Lock()
+			if sf.cachedFiles[name] == cached {
+				delete(sf.cachedFiles, name)
+			}
+			sf.mu.Unlock()
+		}
+	}
+
+	<-cached.ready
+	if cached.err != nil {
+		sf.releaseCachedFile(cached)
+		return nil, cached.err
+	}
+	return cached, nil
+}
+
+// releaseCachedFile drops a reference to the local copy of a file and
+// removes the copy after the last one
+func (sf *storjFS) releaseCachedFile(cached *cachedFile) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	cached.refs--
+	if cached.refs > 0 {
+		return
+	}
+
+	if sf.cachedFiles[cached.name] == cached {
+		delete(sf.cachedFiles, cached.name)
+	}
+	cached.remove()
+}
+
+// renameCachedFile updates the name of the local copy of a renamed file
+func (sf *storjFS) renameCachedFile(oldName, newName string) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	cached, ok := sf.cachedFiles[oldName]
+	if !ok {
+		return
+	}
+
+	delete(sf.cachedFiles, oldName)
+	cached.rename(newName)
+	sf.cachedFiles[newName] = cached
+}
+
+// storjFile is a handle of an open file. Files opened for writing are
+// backed by a local copy, read-only files are read with ranged requests.
 type storjFile struct {
-	ctx             context.Context
-	store           objects.Store
-	created         bool
-	name            string
-	size            uint64
-	mtime           uint64
-	reader          io.ReadCloser
-	writer          *io.PipeWriter
-	predictedOffset int64
-	FS              *storjFS
+	ctx    context.Context
+	name   string
+	fs     *storjFS
+	cached *cachedFile // nil if the file is read-only
+
+	mu     sync.Mutex
+	reader *blockReader
 
 	nodefs.File
 }
 
-func newStorjFile(ctx context.Context, name string, store objects.Store, created bool, FS *storjFS) *storjFile {
+func newStorjFile(ctx context.Context, name string, fs *storjFS, cached *cachedFile) *storjFile {
 	return &storjFile{
-		name:    name,
-		ctx:     ctx,
-		store:   store,
-		mtime:   uint64(time.Now().Unix()),
-		created: created,
-		FS:      FS,
-		File:    nodefs.NewDefaultFile(),
+		ctx:    ctx,
+		name:   name,
+		fs:     fs,
+		cached: cached,
+		File:   nodefs.NewDefaultFile(),
 	}
 }
 
 func (f *storjFile) GetAttr(attr *fuse.Attr) fuse.Status {
 	zap.S().Debug("GetAttr file: ", f.name)
 
-	if f.created {
-		attr.Mode = fuse.S_IFREG | 0644
-		if f.size != 0 {
-			attr.Size = f.size
-		}
-		attr.Mtime = f.mtime
-		return fuse.OK
+	if f.cached != nil {
+		return f.cached.getAttr(attr)
 	}
 	return fuse.ENOSYS
 }
 
 func (f *storjFile) Read(buf []byte, off int64) (res fuse.ReadResult, code fuse.Status) {
-	// Detect if offset was moved manually (e.g. stream rev/fwd)
-	if off != f.predictedOffset {
-		f.closeReader()
+	if f.cached != nil {
+		return f.cached.read(buf, off)
 	}
 
-	reader, err := f.getReader(off)
+	reader, err := f.getReader()
 	if err != nil {
 		if storj.ErrObjectNotFound.Has(err) {
 			return nil, fuse.ENOENT
@@ -333,97 +475,75 @@ func (f *storjFile) Read(buf []byte, off int64) (res fuse.ReadResult, code fuse.
 		return nil, fuse.EIO
 	}
 
-	n, err := io.ReadFull(reader, buf)
-	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+	n, err := reader.ReadAt(buf, off)
+	if err != nil {
+		zap.S().Errorf("error during reading: %v", err)
 		return nil, fuse.EIO
 	}
 
-	f.predictedOffset = off + int64(n)
-
 	return fuse.ReadResultData(buf[:n]), fuse.OK
 }
 
 func (f *storjFile) Write(data []byte, off int64) (uint32, fuse.Status) {
-	writer, err := f.getWriter(off)
-	if err != nil {
-		return 0, fuse.EIO
+	if f.cached == nil {
+		return 0, fuse.EBADF
 	}
+	return f.cached.write(data, off)
+}
 
-	written, err := writer.Write(data)
-	if err != nil {
-		return 0, fuse.EIO
-	}
+func (f *storjFile) Truncate(size uint64) fuse.Status {
+	zap.S().Debug("Truncate file: ", f.name)
 
-	f.size += uint64(written)
-	return uint32(written), fuse.OK
+	if f.cached == nil {
+		return fuse.EBADF
+	}
+	return f.cached.truncate(size)
 }
 
-func (f *storjFile) getReader(off int64) (io.ReadCloser, error) {
-	if f.reader == nil {
-		ranger, _, err := f.store.Get(f.ctx, f.name)
-		if err != nil {
-			return nil, err
-		}
+func (f *storjFile) getReader() (*blockReader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-		f.reader, err = ranger.Range(f.ctx, off, ranger.Size()-off)
+	if f.reader == nil {
+		ranger, _, err := f.fs.store.Get(f.ctx, f.name)
 		if err != nil {
 			return nil, err
 		}
+		f.reader = newBlockReader(f.ctx, ranger, mountBlockSize, *mountReadAhead)
 	}
 	return f.reader, nil
 }
 
-func (f *storjFile) getWriter(off int64) (*io.PipeWriter, error) {
-	if off == 0 {
-		f.size = 0
-		f.closeWriter()
-
-		var reader *io.PipeReader
-		reader, f.writer = io.Pipe()
-		go func() {
-			zap.S().Debug("Starts writting: ", f.name)
-
-			defer func() {
-				utils.LogClose(reader)
-				if f.created {
-					f.FS.removeCreatedFile(f.name)
-				}
-			}()
-
-			meta := pb.SerializableMeta{}
-			expTime := time.Time{}
-
-			m, err := f.store.Put(f.ctx, f.name, reader, meta, expTime)
-			if err != nil {
-				zap.S().Errorf("error during writting: %v", err)
-			}
-
-			f.size = uint64(m.Size)
+func (f *storjFile) Flush() fuse.Status {
+	zap.S().Debug("Flush: ", f.name)
 
-			zap.S().Debug("Stops writting: ", f.name)
-		}()
+	if f.cached != nil {
+		return f.cached.flush()
 	}
-	return f.writer, nil
+	return fuse.OK
 }
 
-func (f *storjFile) Flush() fuse.Status {
-	zap.S().Debug("Flush: ", f.name)
+func (f *storjFile) Fsync(flags int) fuse.Status {
+	zap.S().Debug("Fsync: ", f.name)
 
-	f.closeReader()
-	f.closeWriter()
+	if f.cached != nil {
+		return f.cached.flush()
+	}
 	return fuse.OK
 }
 
-func (f *storjFile) closeReader() {
+func (f *storjFile) Release() {
+	zap.S().Debug("Release: ", f.name)
+
+	if f.cached != nil {
+		f.fs.releaseCachedFile(f.cached)
+		f.cached = nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if f.reader != nil {
 		utils.LogClose(f.reader)
 		f.reader = nil
 	}
 }
-
-func (f *storjFile) closeWriter() {
-	if f.writer != nil {
-		utils.LogClose(f.writer)
-		f.writer = nil
-	}
-}
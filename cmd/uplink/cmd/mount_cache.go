@@ -0,0 +1,350 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin netbsd freebsd openbsd
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hanwen/go-fuse/fuse"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+)
+
+// mountBlockSize is the amount of data fetched with a single ranged request
+// when reading a mounted file
+const mountBlockSize = 4 << 20
+
+// cachedFile is the local copy of a mounted file that is open for writing.
+// Changes are written to the local copy and uploaded on flush.
+type cachedFile struct {
+	ctx   context.Context
+	store objects.Store
+
+	// ready is closed when the local copy is created, or failed to be
+	// with err
+	ready chan struct{}
+	err   error
+
+	mu         sync.Mutex
+	name       string
+	file       *os.File
+	meta       pb.SerializableMeta
+	expiration time.Time
+	dirty      bool
+
+	refs int // guarded by storjFS.mu
+}
+
+// newCachedFile returns the local copy of the file name, which is not
+// ready until it is created with create
+func newCachedFile(ctx context.Context, store objects.Store, name string) *cachedFile {
+	return &cachedFile{
+		ctx:   ctx,
+		store: store,
+		ready: make(chan struct{}),
+		name:  name,
+	}
+}
+
+// create creates the local copy in dir and makes it ready. If download is
+// true, the copy starts with the content of the stored object.
+func (c *cachedFile) create(dir string, download bool) error {
+	defer close(c.ready)
+
+	c.mu.Lock()
+	name := c.name
+	c.mu.Unlock()
+
+	file, err := ioutil.TempFile(dir, "mount-")
+	if err != nil {
+		c.err = err
+		return err
+	}
+	c.file = file
+
+	if download {
+		c.err = c.download(name)
+	}
+	return c.err
+}
+
+// download copies the content and metadata of the stored object name to the
+// local copy
+func (c *cachedFile) download(name string) error {
+	rr, m, err := c.store.Get(c.ctx, name)
+	if err != nil {
+		return err
+	}
+
+	r, err := rr.Range(c.ctx, 0, rr.Size())
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	_, err = io.Copy(c.file, r)
+	if err != nil {
+		return err
+	}
+
+	c.meta = m.SerializableMeta
+	c.expiration = m.Expiration
+	return nil
+}
+
+func (c *cachedFile) getAttr(attr *fuse.Attr) fuse.Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fi, err := c.file.Stat()
+	if err != nil {
+		return fuse.EIO
+	}
+
+	attr.Owner = *fuse.CurrentOwner()
+	attr.Mode = fuse.S_IFREG | 0644
+	attr.Size = uint64(fi.Size())
+	attr.Mtime = uint64(fi.ModTime().Unix())
+	return fuse.OK
+}
+
+func (c *cachedFile) read(buf []byte, off int64) (fuse.ReadResult, fuse.Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, err := c.file.ReadAt(buf, off)
+	if err != nil && err != io.EOF {
+		return nil, fuse.EIO
+	}
+
+	return fuse.ReadResultData(buf[:n]), fuse.OK
+}
+
+func (c *cachedFile) write(data []byte, off int64) (uint32, fuse.Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, err := c.file.WriteAt(data, off)
+	if err != nil {
+		return uint32(n), fuse.EIO
+	}
+
+	c.dirty = true
+	return uint32(n), fuse.OK
+}
+
+func (c *cachedFile) truncate(size uint64) fuse.Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.file.Truncate(int64(size))
+	if err != nil {
+		return fuse.EIO
+	}
+
+	c.dirty = true
+	return fuse.OK
+}
+
+// markDirty forces the local copy to be uploaded on the next flush
+func (c *cachedFile) markDirty() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dirty = true
+}
+
+// flush uploads the local copy if it has changed since the last upload
+func (c *cachedFile) flush() fuse.Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return fuse.OK
+	}
+
+	fi, err := c.file.Stat()
+	if err != nil {
+		return fuse.EIO
+	}
+
+	zap.S().Debug("Uploading: ", c.name)
+
+	_, err = c.store.Put(c.ctx, c.name, io.NewSectionReader(c.file, 0, fi.Size()), c.meta, c.expiration)
+	if err != nil {
+		zap.S().Errorf("error during uploading: %v", err)
+		return fuse.EIO
+	}
+
+	c.dirty = false
+	return fuse.OK
+}
+
+func (c *cachedFile) rename(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.name = name
+}
+
+// remove deletes the local copy
+func (c *cachedFile) remove() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return
+	}
+	utils.LogClose(c.file)
+	err := os.Remove(c.file.Name())
+	if err != nil {
+		zap.S().Errorf("error during removing cached file: %v", err)
+	}
+}
+
+// blockReader serves random reads of an object with ranged requests for
+// fixed size blocks. The blocks following the one being read are fetched
+// in parallel ahead of time.
+type blockReader struct {
+	ctx       context.Context
+	cancel    func()
+	rr        ranger.Ranger
+	blockSize int64
+	// readAhead is the number of blocks fetched in parallel, starting
+	// with the one being read
+	readAhead int
+
+	mu     sync.Mutex
+	blocks map[int64]*block
+}
+
+// block is a part of an object being fetched or already fetched
+type block struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func newBlockReader(ctx context.Context, rr ranger.Ranger, blockSize int64, readAhead int) *blockReader {
+	if readAhead < 1 {
+		readAhead = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &blockReader{
+		ctx:       ctx,
+		cancel:    cancel,
+		rr:        rr,
+		blockSize: blockSize,
+		readAhead: readAhead,
+		blocks:    make(map[int64]*block),
+	}
+}
+
+// ReadAt reads len(p) bytes starting at off. It returns fewer bytes only at
+// the end of the object.
+func (r *blockReader) ReadAt(p []byte, off int64) (n int, err error) {
+	size := r.rr.Size()
+	if off >= size {
+		return 0, nil
+	}
+	if int64(len(p)) > size-off {
+		p = p[:size-off]
+	}
+
+	for n < len(p) {
+		pos := off + int64(n)
+		index := pos / r.blockSize
+
+		b := r.fetch(index)
+		select {
+		case <-b.done:
+		case <-r.ctx.Done():
+			return n, r.ctx.Err()
+		}
+
+		if b.err != nil {
+			r.forget(index)
+			return n, b.err
+		}
+
+		k := copy(p[n:], b.data[pos-index*r.blockSize:])
+		if k == 0 {
+			return n, io.ErrUnexpectedEOF
+		}
+		n += k
+	}
+
+	return n, nil
+}
+
+// Close cancels the blocks being fetched
+func (r *blockReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// fetch returns the block with the given index and starts fetching the
+// blocks after it, readAhead blocks in all. Blocks that are not close to
+// index are dropped.
+func (r *blockReader) fetch(index int64) *block {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := index + int64(r.readAhead) - 1
+	for i := index; i <= last && i*r.blockSize < r.rr.Size(); i++ {
+		if _, ok := r.blocks[i]; ok {
+			continue
+		}
+		b := &block{done: make(chan struct{})}
+		r.blocks[i] = b
+		go r.load(i, b)
+	}
+
+	for i := range r.blocks {
+		if i < index-1 || i > last {
+			delete(r.blocks, i)
+		}
+	}
+
+	return r.blocks[index]
+}
+
+// forget drops a block that failed to load, so it is fetched again
+func (r *blockReader) forget(index int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.blocks, index)
+}
+
+// load fetches the content of block b
+func (r *blockReader) load(index int64, b *block) {
+	defer close(b.done)
+
+	offset := index * r.blockSize
+	length := r.blockSize
+	if offset+length > r.rr.Size() {
+		length = r.rr.Size() - offset
+	}
+
+	rc, err := r.rr.Range(r.ctx, offset, length)
+	if err != nil {
+		b.err = err
+		return
+	}
+	defer utils.LogClose(rc)
+
+	b.data, b.err = ioutil.ReadAll(rc)
+}
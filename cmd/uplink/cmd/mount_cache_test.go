@@ -0,0 +1,178 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build linux darwin netbsd freebsd openbsd
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testobjects"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+)
+
+// failingRanger fails the next ranged request for the offsets in fail
+type failingRanger struct {
+	ranger.Ranger
+
+	mu   sync.Mutex
+	fail map[int64]bool
+}
+
+func (r *failingRanger) failNext(offset int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fail[offset] = true
+}
+
+func (r *failingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.fail[offset] {
+		delete(r.fail, offset)
+		return nil, errors.New("range failed")
+	}
+	return r.Ranger.Range(ctx, offset, length)
+}
+
+// fetched returns the sorted indexes of the blocks fetched by r
+func fetched(r *blockReader) []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var indexes []int64
+	for i := range r.blocks {
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, k int) bool { return indexes[i] < indexes[k] })
+	return indexes
+}
+
+func TestBlockReader(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	rr := &failingRanger{Ranger: ranger.ByteRanger(data), fail: map[int64]bool{}}
+	r := newBlockReader(ctx, rr, 4, 3)
+	defer ctx.Check(r.Close)
+
+	read := func(off int64, n int) (string, error) {
+		p := make([]byte, n)
+		k, err := r.ReadAt(p, off)
+		return string(p[:k]), err
+	}
+
+	// reads span blocks, and the next blocks are fetched ahead, readAhead
+	// blocks in all
+	s, err := read(2, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "2345", s)
+	assert.Equal(t, []int64{0, 1, 2, 3}, fetched(r))
+
+	// reads stop at the end of the object
+	s, err = read(34, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "yz", s)
+	s, err = read(36, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "", s)
+
+	// the blocks far from the one being read are dropped, and no blocks
+	// past the end are fetched
+	assert.Equal(t, []int64{8}, fetched(r))
+	s, err = read(20, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "klmn", s)
+	assert.Equal(t, []int64{5, 6, 7}, fetched(r))
+
+	// a block that failed to load is forgotten and fetched again
+	rr.failNext(0)
+	_, err = read(0, 4)
+	assert.Error(t, err)
+	assert.NotContains(t, fetched(r), int64(0))
+	s, err = read(0, 4)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", s)
+}
+
+func TestBlockReaderReadAhead(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// at least the block being read is fetched
+	r := newBlockReader(ctx, ranger.ByteRanger([]byte("0123456789")), 4, 0)
+	defer ctx.Check(r.Close)
+
+	p := make([]byte, 10)
+	n, err := r.ReadAt(p, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(p[:n]))
+	assert.Equal(t, []int64{1, 2}, fetched(r))
+}
+
+func TestCachedFile(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store := testobjects.NewStore()
+	expiration := time.Now().Add(time.Hour)
+	_, err := store.Put(ctx, "file", strings.NewReader("stored"), pb.SerializableMeta{ContentType: "text/plain"}, expiration)
+	require.NoError(t, err)
+
+	stored := func(name string) string {
+		rr, m, err := store.Get(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", m.ContentType)
+		assert.Equal(t, expiration, m.Expiration)
+		r, err := rr.Range(ctx, 0, rr.Size())
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	c := newCachedFile(ctx, store, "file")
+	require.NoError(t, c.create(ctx.Dir("cache"), true))
+	defer c.remove()
+
+	// the local copy starts with the stored object
+	result, status := c.read(make([]byte, 10), 0)
+	require.True(t, status.Ok())
+	data, _ := result.Bytes(nil)
+	assert.Equal(t, "stored", string(data))
+
+	// an unchanged copy is not uploaded
+	require.NoError(t, store.Delete(ctx, "file"))
+	assert.True(t, c.flush().Ok())
+	assert.Empty(t, store.Paths())
+
+	// the changes are uploaded with the metadata of the object
+	_, status = c.write([]byte("changed"), 0)
+	require.True(t, status.Ok())
+	assert.True(t, c.flush().Ok())
+	assert.Equal(t, "changed", stored("file"))
+
+	// a renamed copy is uploaded with its new name
+	c.rename("renamed")
+	require.True(t, c.truncate(3).Ok())
+	assert.True(t, c.flush().Ok())
+	assert.Equal(t, "cha", stored("renamed"))
+	assert.Equal(t, "changed", stored("file"))
+}
@@ -4,20 +4,28 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"storj.io/storj/internal/fpath"
 	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	rmRecursiveFlag *bool
 )
 
 func init() {
-	addCmd(&cobra.Command{
+	rmCmd := addCmd(&cobra.Command{
 		Use:   "rm",
 		Short: "Delete an object",
 		RunE:  deleteObject,
 	}, CLICmd)
+	rmRecursiveFlag = rmCmd.Flags().Bool("recursive", false, "if true, delete all the objects under the path")
 }
 
 func deleteObject(cmd *cobra.Command, args []string) error {
@@ -46,6 +54,10 @@ func deleteObject(cmd *cobra.Command, args []string) error {
 		return convertError(err, dst)
 	}
 
+	if *rmRecursiveFlag {
+		return deletePrefix(ctx, o, dst)
+	}
+
 	err = o.Delete(ctx, dst.Path())
 	if err != nil {
 		return convertError(err, dst)
@@ -55,3 +67,19 @@ func deleteObject(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// deletePrefix deletes all the objects under dst with one request to the
+// satellite
+func deletePrefix(ctx context.Context, o objects.Store, dst fpath.FPath) error {
+	result, err := o.DeleteObjects(ctx, storj.DeleteOptions{Prefixes: []storj.Path{dst.Path()}})
+	if err != nil {
+		return convertError(err, dst)
+	}
+
+	for _, path := range result.Locked {
+		fmt.Printf("Skipped locked %s\n", path)
+	}
+	fmt.Printf("Deleted %d objects under %s\n", result.Deleted, dst)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/sftpgw"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storj"
+)
+
+var (
+	sftpAddress *string
+	sftpHostKey *string
+	sftpUsers   *string
+)
+
+func init() {
+	sftpCmd := addCmd(&cobra.Command{
+		Use:   "sftp",
+		Short: "Run an SFTP server for the configured users",
+		RunE:  sftpMain,
+	}, CLICmd)
+	sftpAddress = sftpCmd.Flags().String("sftp-address", ":2022", "address to serve SFTP on")
+	sftpHostKey = sftpCmd.Flags().String("host-key", filepath.Join(applicationDir("storj", "uplink"), "sftp_host_key"), "path to the SSH host key, generated if missing")
+	sftpUsers = sftpCmd.Flags().String("users", filepath.Join(applicationDir("storj", "uplink"), "sftp_users.json"), "path to the JSON file with the SFTP users")
+}
+
+// sftpMain is the function executed when sftpCmd is called
+func sftpMain(cmd *cobra.Command, args []string) (err error) {
+	for _, arg := range args {
+		return fmt.Errorf("Invalid argument %#v. Try 'uplink sftp'", arg)
+	}
+
+	ctx := process.Ctx(cmd)
+
+	users, err := sftpgw.LoadUsers(*sftpUsers)
+	if err != nil {
+		return err
+	}
+
+	hostKey, err := sftpgw.LoadHostKey(*sftpHostKey)
+	if err != nil {
+		return err
+	}
+
+	identity, err := cfg.Load()
+	if err != nil {
+		return err
+	}
+
+	segments, err := cfg.GetSegmentStore(ctx, identity)
+	if err != nil {
+		return err
+	}
+
+	server := sftpgw.NewServer(hostKey)
+
+	// users with the same encryption key share a bucket store
+	stores := make(map[string]buckets.Store)
+	for _, user := range users {
+		keys, err := user.PublicKeys()
+		if err != nil {
+			return err
+		}
+
+		encKey := user.EncKey
+		if encKey == "" {
+			encKey = cfg.EncKey
+		}
+
+		bs, ok := stores[encKey]
+		if !ok {
+			bs, err = cfg.NewBucketStore(segments, encKey)
+			if err != nil {
+				return err
+			}
+			stores[encKey] = bs
+		}
+
+		store, err := bs.GetObjectStore(ctx, user.Bucket)
+		if storj.ErrBucketNotFound.Has(err) {
+			return fmt.Errorf("Bucket not found for user %s: %s", user.Name, user.Bucket)
+		}
+		if err != nil {
+			return err
+		}
+
+		server.AddUser(user.Name, keys, store, user.Prefix)
+	}
+
+	lis, err := net.Listen("tcp", *sftpAddress)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Serving %d SFTP users on %s\n", len(users), lis.Addr())
+	fmt.Printf("Host key fingerprint: %s\n", ssh.FingerprintSHA256(hostKey.PublicKey()))
+
+	return server.Serve(ctx, lis)
+}
@@ -0,0 +1,488 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+)
+
+const (
+	// syncModifiedKey is the user-defined metadata key for the modification time of the source file
+	syncModifiedKey = "mtime"
+	// syncChecksumKey is the user-defined metadata key for the SHA-256 checksum of the source file
+	syncChecksumKey = "sha256"
+)
+
+var (
+	syncDelete *bool
+	syncDryRun *bool
+)
+
+func init() {
+	syncCmd := addCmd(&cobra.Command{
+		Use:   "sync",
+		Short: "Synchronizes a local directory with a Storj prefix, transferring only changed files",
+		RunE:  syncMain,
+	}, CLICmd)
+	syncDelete = syncCmd.Flags().Bool("delete", false, "if true, delete files in the destination that are not present in the source")
+	syncDryRun = syncCmd.Flags().Bool("dry-run", false, "if true, only print what would be transferred or deleted")
+}
+
+// syncFile describes a file on one side of a sync
+type syncFile struct {
+	size     int64
+	modified time.Time
+	checksum string // hex encoded SHA-256, empty if not known yet
+}
+
+// syncMain is the function executed when syncCmd is called
+func syncMain(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("No source specified for sync")
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("No destination specified")
+	}
+
+	ctx := process.Ctx(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+	dst, err := fpath.New(args[1])
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() == dst.IsLocal() {
+		return fmt.Errorf("Exactly one of the source or the destination must be a Storj URL")
+	}
+
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() {
+		return syncUpload(ctx, bs, src, dst)
+	}
+	return syncDownload(ctx, bs, src, dst)
+}
+
+// syncUpload uploads the files of the local directory src that are missing
+// or changed under the Storj prefix dst
+func syncUpload(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.FPath) error {
+	o, err := bs.GetObjectStore(ctx, dst.Bucket())
+	if err != nil {
+		return convertError(err, dst)
+	}
+
+	local, err := listLocalFiles(src)
+	if err != nil {
+		return err
+	}
+
+	remote, err := listRemoteFiles(ctx, o, dst)
+	if err != nil {
+		return convertError(err, dst)
+	}
+
+	for _, path := range sortedPaths(local) {
+		srcFile := local[path]
+		srcPath := src.Join(filepath.FromSlash(path))
+		dstPath := dst.Join(path)
+
+		changed, err := syncChanged(srcFile, remote[path], srcPath.Path())
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+
+		if *syncDryRun {
+			fmt.Printf("Would upload %s to %s\n", srcPath, dstPath)
+			continue
+		}
+
+		err = syncPut(ctx, o, srcPath, dstPath, srcFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Uploaded %s to %s\n", srcPath, dstPath)
+	}
+
+	if !*syncDelete {
+		return nil
+	}
+
+	for _, path := range sortedPaths(remote) {
+		if _, ok := local[path]; ok {
+			continue
+		}
+
+		dstPath := dst.Join(path)
+		if *syncDryRun {
+			fmt.Printf("Would delete %s\n", dstPath)
+			continue
+		}
+
+		err = o.Delete(ctx, dstPath.Path())
+		if err != nil {
+			return convertError(err, dstPath)
+		}
+
+		fmt.Printf("Deleted %s\n", dstPath)
+	}
+
+	return nil
+}
+
+// syncDownload downloads the objects under the Storj prefix src that are
+// missing or changed in the local directory dst
+func syncDownload(ctx context.Context, bs buckets.Store, src fpath.FPath, dst fpath.FPath) error {
+	o, err := bs.GetObjectStore(ctx, src.Bucket())
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	remote, err := listRemoteFiles(ctx, o, src)
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	local := make(map[string]syncFile)
+	if _, err := os.Stat(dst.Path()); !os.IsNotExist(err) {
+		local, err = listLocalFiles(dst)
+		if err != nil {
+			return err
+		}
+	}
+
+	// object names are chosen by whoever uploaded them, so none may
+	// escape the local directory
+	for path := range remote {
+		if err := checkLocalPath(path); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range sortedPaths(remote) {
+		srcFile := remote[path]
+		srcPath := src.Join(path)
+		dstPath := dst.Join(filepath.FromSlash(path))
+
+		dstFile, exists := local[path]
+		changed, err := syncChanged(srcFile, dstFile, dstPath.Path())
+		if err != nil {
+			return err
+		}
+		if !changed {
+			// same content with a different timestamp, only fix the timestamp
+			if exists && !sameModTime(srcFile.modified, dstFile.modified) && !*syncDryRun {
+				err = os.Chtimes(dstPath.Path(), srcFile.modified, srcFile.modified)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if *syncDryRun {
+			fmt.Printf("Would download %s to %s\n", srcPath, dstPath)
+			continue
+		}
+
+		err = syncGet(ctx, o, srcPath, dstPath, srcFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Downloaded %s to %s\n", srcPath, dstPath)
+	}
+
+	if !*syncDelete {
+		return nil
+	}
+
+	for _, path := range sortedPaths(local) {
+		if _, ok := remote[path]; ok {
+			continue
+		}
+
+		dstPath := dst.Join(filepath.FromSlash(path))
+		if *syncDryRun {
+			fmt.Printf("Would delete %s\n", dstPath)
+			continue
+		}
+
+		err = os.Remove(dstPath.Path())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted %s\n", dstPath)
+	}
+
+	return nil
+}
+
+// checkLocalPath returns an error if the object path relative to the
+// synced prefix is absolute or leads out of the local directory
+func checkLocalPath(path string) error {
+	clean := filepath.Clean(filepath.FromSlash(path))
+	parent := ".." + string(filepath.Separator)
+	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" || strings.HasPrefix(clean, string(filepath.Separator)) ||
+		clean == "." || clean == ".." || strings.HasPrefix(clean, parent) {
+		return fmt.Errorf("refusing to download %q outside of the target directory", path)
+	}
+	return nil
+}
+
+// syncChanged returns whether src has to be transferred over dst. A zero dst
+// means that the destination does not exist. localPath is used to calculate
+// the checksum of the local side.
+func syncChanged(src, dst syncFile, localPath string) (bool, error) {
+	if dst == (syncFile{}) || src.size != dst.size {
+		return true, nil
+	}
+
+	if sameModTime(src.modified, dst.modified) {
+		return false, nil
+	}
+
+	// sizes match but timestamps differ, so compare the content checksums
+	remoteChecksum := src.checksum
+	if remoteChecksum == "" {
+		remoteChecksum = dst.checksum
+	}
+	if remoteChecksum == "" {
+		return true, nil
+	}
+
+	localChecksum, err := fileChecksum(localPath)
+	if err != nil {
+		return false, err
+	}
+
+	return localChecksum != remoteChecksum, nil
+}
+
+// syncPut uploads the local file src to dst, storing its modification time
+// and checksum in the object metadata
+func syncPut(ctx context.Context, o objects.Store, src fpath.FPath, dst fpath.FPath, file syncFile) error {
+	checksum, err := fileChecksum(src.Path())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(src.Path())
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(f)
+
+	metadata := pb.SerializableMeta{
+		UserDefined: map[string]string{
+			syncModifiedKey: file.modified.UTC().Format(time.RFC3339Nano),
+			syncChecksumKey: checksum,
+		},
+	}
+
+	_, err = o.Put(ctx, dst.Path(), f, metadata, time.Time{})
+	return err
+}
+
+// syncGet downloads the object src to the local file dst and sets the
+// file's modification time to the one recorded for the object
+func syncGet(ctx context.Context, o objects.Store, src fpath.FPath, dst fpath.FPath, file syncFile) (err error) {
+	rr, _, err := o.Get(ctx, src.Path())
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	dir := filepath.Dir(dst.Path())
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// download to a temporary file first, so an interrupted download
+	// does not leave a partial file that looks up to date
+	tmp, err := ioutil.TempFile(dir, ".uplink-sync-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			utils.LogClose(tmp)
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chtimes(tmp.Name(), file.modified, file.modified)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst.Path())
+}
+
+// listLocalFiles returns the regular files under the local directory dir
+// keyed by their slash separated path relative to dir
+func listLocalFiles(dir fpath.FPath) (map[string]syncFile, error) {
+	fi, err := os.Stat(dir.Path())
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("source must be a directory: %s", dir)
+	}
+
+	files := make(map[string]syncFile)
+	err = filepath.Walk(dir.Path(), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir.Path(), path)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = syncFile{
+			size:     info.Size(),
+			modified: info.ModTime(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// listRemoteFiles returns the objects under the Storj prefix keyed by their
+// path relative to the prefix
+func listRemoteFiles(ctx context.Context, o objects.Store, prefix fpath.FPath) (map[string]syncFile, error) {
+	files := make(map[string]syncFile)
+	startAfter := ""
+
+	for {
+		items, more, err := o.List(ctx, prefix.Path(), startAfter, "", true, 0, meta.Modified|meta.Size|meta.UserDefined)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			// directory markers have no local file
+			if item.IsPrefix || item.Path == "" || strings.HasSuffix(item.Path, "/") {
+				continue
+			}
+			files[item.Path] = remoteSyncFile(item.Meta)
+		}
+
+		if !more {
+			break
+		}
+
+		startAfter = items[len(items)-1].Path
+	}
+
+	return files, nil
+}
+
+// remoteSyncFile converts object metadata to a syncFile, preferring the
+// modification time of the source file recorded by a previous sync
+func remoteSyncFile(m objects.Meta) syncFile {
+	file := syncFile{
+		size:     m.Size,
+		modified: m.Modified,
+		checksum: m.UserDefined[syncChecksumKey],
+	}
+
+	if modified, ok := m.UserDefined[syncModifiedKey]; ok {
+		t, err := time.Parse(time.RFC3339Nano, modified)
+		if err == nil {
+			file.modified = t
+		}
+	}
+
+	return file
+}
+
+// fileChecksum returns the hex encoded SHA-256 checksum of a local file
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer utils.LogClose(f)
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sameModTime compares modification times with a precision of one second,
+// as not all file systems store sub-second timestamps
+func sameModTime(a, b time.Time) bool {
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+// sortedPaths returns the keys of files in lexical order
+func sortedPaths(files map[string]syncFile) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
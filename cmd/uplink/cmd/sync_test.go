@@ -0,0 +1,229 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testobjects"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+)
+
+// transferStore records the paths of the objects uploaded and downloaded
+type transferStore struct {
+	*testobjects.Store
+	transferred []string
+}
+
+func (s *transferStore) Put(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time) (objects.Meta, error) {
+	s.transferred = append(s.transferred, path)
+	return s.Store.Put(ctx, path, data, metadata, expiration)
+}
+
+func (s *transferStore) Get(ctx context.Context, path storj.Path) (ranger.Ranger, objects.Meta, error) {
+	s.transferred = append(s.transferred, path)
+	return s.Store.Get(ctx, path)
+}
+
+// setSyncFlags sets the flags of the sync command until the returned
+// function restores them
+func setSyncFlags(del, dryRun bool) func() {
+	oldDelete, oldDryRun := *syncDelete, *syncDryRun
+	*syncDelete, *syncDryRun = del, dryRun
+	return func() { *syncDelete, *syncDryRun = oldDelete, oldDryRun }
+}
+
+func writeFile(t *testing.T, path, data string, modified time.Time) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(data), 0644))
+	require.NoError(t, os.Chtimes(path, modified, modified))
+}
+
+func readObject(ctx context.Context, t *testing.T, store objects.Store, path storj.Path) (string, objects.Meta) {
+	rr, m, err := store.Get(ctx, path)
+	require.NoError(t, err)
+	r, err := rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return string(data), m
+}
+
+func TestSyncUpload(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+	defer setSyncFlags(true, false)()
+
+	modified := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	dir := ctx.Dir("local")
+	writeFile(t, filepath.Join(dir, "a"), "aaaa", modified)
+	writeFile(t, filepath.Join(dir, "sub", "b"), "bbbb", modified)
+
+	store := &transferStore{Store: testobjects.NewStore()}
+	_, err := store.Store.Put(ctx, "prefix/stale", strings.NewReader("stale"), pb.SerializableMeta{}, time.Time{})
+	require.NoError(t, err)
+	bs := bucketStore{objects: store}
+
+	src, err := fpath.New(dir)
+	require.NoError(t, err)
+	dst, err := fpath.New("sj://bucket/prefix")
+	require.NoError(t, err)
+
+	// the missing files are uploaded with their modification time and
+	// checksum, and the objects without a local file are deleted
+	require.NoError(t, syncUpload(ctx, bs, src, dst))
+	assert.Equal(t, []string{"prefix/a", "prefix/sub/b"}, store.transferred)
+	assert.Equal(t, []string{"prefix/a", "prefix/sub/b"}, store.Paths())
+
+	data, m := readObject(ctx, t, store.Store, "prefix/a")
+	assert.Equal(t, "aaaa", data)
+	assert.Equal(t, modified.Format(time.RFC3339Nano), m.UserDefined[syncModifiedKey])
+	checksum, err := fileChecksum(filepath.Join(dir, "a"))
+	require.NoError(t, err)
+	assert.Equal(t, checksum, m.UserDefined[syncChecksumKey])
+
+	// unchanged files are skipped, also when only their timestamp changed
+	store.transferred = nil
+	writeFile(t, filepath.Join(dir, "a"), "aaaa", modified.Add(time.Hour))
+	require.NoError(t, syncUpload(ctx, bs, src, dst))
+	assert.Empty(t, store.transferred)
+
+	// files with the same size and other content are uploaded
+	writeFile(t, filepath.Join(dir, "sub", "b"), "BBBB", modified.Add(time.Hour))
+	require.NoError(t, syncUpload(ctx, bs, src, dst))
+	assert.Equal(t, []string{"prefix/sub/b"}, store.transferred)
+	data, _ = readObject(ctx, t, store.Store, "prefix/sub/b")
+	assert.Equal(t, "BBBB", data)
+
+	// a dry run transfers nothing
+	defer setSyncFlags(true, true)()
+	store.transferred = nil
+	writeFile(t, filepath.Join(dir, "c"), "cccc", modified)
+	require.NoError(t, syncUpload(ctx, bs, src, dst))
+	assert.Empty(t, store.transferred)
+	assert.Equal(t, []string{"prefix/a", "prefix/sub/b"}, store.Paths())
+}
+
+func TestSyncDownload(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+	defer setSyncFlags(true, false)()
+
+	modified := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	store := &transferStore{Store: testobjects.NewStore()}
+	for path, data := range map[string]string{"prefix/a": "aaaa", "prefix/sub/b": "bbbb"} {
+		metadata := pb.SerializableMeta{UserDefined: map[string]string{syncModifiedKey: modified.Format(time.RFC3339Nano)}}
+		_, err := store.Store.Put(ctx, path, strings.NewReader(data), metadata, time.Time{})
+		require.NoError(t, err)
+	}
+	// directory markers are not downloaded
+	require.NoError(t, objects.PutDirectory(ctx, store.Store, "prefix/empty"))
+	bs := bucketStore{objects: store}
+
+	dir := ctx.Dir("local")
+	writeFile(t, filepath.Join(dir, "stale"), "stale", modified)
+
+	src, err := fpath.New("sj://bucket/prefix")
+	require.NoError(t, err)
+	dst, err := fpath.New(dir)
+	require.NoError(t, err)
+
+	// the missing objects are downloaded with their recorded modification
+	// time, and the files without an object are deleted
+	require.NoError(t, syncDownload(ctx, bs, src, dst))
+	assert.Equal(t, []string{"prefix/a", "prefix/sub/b"}, store.transferred)
+
+	local, err := listLocalFiles(dst)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "sub/b"}, sortedPaths(local))
+	assert.True(t, sameModTime(modified, local["a"].modified))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "sub", "b"))
+	require.NoError(t, err)
+	assert.Equal(t, "bbbb", string(data))
+
+	// unchanged files are skipped
+	store.transferred = nil
+	require.NoError(t, syncDownload(ctx, bs, src, dst))
+	assert.Empty(t, store.transferred)
+}
+
+func TestSyncDownloadOutside(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+	defer setSyncFlags(false, false)()
+
+	store := &transferStore{Store: testobjects.NewStore()}
+	_, err := store.Store.Put(ctx, "prefix/../escaped", strings.NewReader("data"), pb.SerializableMeta{}, time.Time{})
+	require.NoError(t, err)
+	bs := bucketStore{objects: store}
+
+	src, err := fpath.New("sj://bucket/prefix")
+	require.NoError(t, err)
+	dst, err := fpath.New(ctx.Dir("local", "dir"))
+	require.NoError(t, err)
+
+	// nothing is downloaded if an object would leave the local directory
+	assert.Error(t, syncDownload(ctx, bs, src, dst))
+	assert.Empty(t, store.transferred)
+	_, err = os.Stat(ctx.File("local", "escaped"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCheckLocalPath(t *testing.T) {
+	for _, path := range []string{"a", "sub/b", "sub/../b", "..a", "a.."} {
+		assert.NoError(t, checkLocalPath(path), path)
+	}
+	for _, path := range []string{"", ".", "..", "../a", "sub/../../a", "/a"} {
+		assert.Error(t, checkLocalPath(path), path)
+	}
+}
+
+func TestSyncChanged(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	modified := time.Date(2018, 11, 1, 12, 0, 0, 0, time.UTC)
+	path := ctx.File("file")
+	writeFile(t, path, "data", modified)
+	checksum, err := fileChecksum(path)
+	require.NoError(t, err)
+
+	for i, tt := range []struct {
+		src, dst syncFile
+		changed  bool
+	}{
+		// missing destination
+		{syncFile{size: 4, modified: modified}, syncFile{}, true},
+		// other size
+		{syncFile{size: 4, modified: modified}, syncFile{size: 5, modified: modified}, true},
+		// same size and timestamp, up to a second
+		{syncFile{size: 4, modified: modified}, syncFile{size: 4, modified: modified.Add(time.Millisecond)}, false},
+		// other timestamp without a checksum to compare
+		{syncFile{size: 4, modified: modified}, syncFile{size: 4, modified: modified.Add(time.Hour)}, true},
+		// other timestamp with the checksum of either side
+		{syncFile{size: 4, modified: modified, checksum: checksum}, syncFile{size: 4, modified: modified.Add(time.Hour)}, false},
+		{syncFile{size: 4, modified: modified}, syncFile{size: 4, modified: modified.Add(time.Hour), checksum: checksum}, false},
+		{syncFile{size: 4, modified: modified, checksum: "other"}, syncFile{size: 4, modified: modified.Add(time.Hour)}, true},
+	} {
+		changed, err := syncChanged(tt.src, tt.dst, path)
+		if assert.NoError(t, err, i) {
+			assert.Equal(t, tt.changed, changed, i)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/internal/fpath"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/webdavgw"
+)
+
+var webdavAddress *string
+
+func init() {
+	webdavCmd := addCmd(&cobra.Command{
+		Use:   "webdav",
+		Short: "Serve a bucket over WebDAV",
+		RunE:  webdavMain,
+	}, CLICmd)
+	webdavAddress = webdavCmd.Flags().String("webdav-address", "127.0.0.1:7778", "address to serve WebDAV on")
+}
+
+// webdavMain is the function executed when webdavCmd is called
+func webdavMain(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("No bucket specified for serving")
+	}
+
+	ctx := process.Ctx(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+	if src.IsLocal() {
+		return fmt.Errorf("No bucket specified. Use format sj://bucket/")
+	}
+
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	store, err := bs.GetObjectStore(ctx, src.Bucket())
+	if err != nil {
+		return convertError(err, src)
+	}
+
+	server := &http.Server{
+		Addr:    *webdavAddress,
+		Handler: webdavgw.NewHandler(store, cfg.AccessKey, cfg.SecretKey),
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving %s over WebDAV\n\n", src)
+	fmt.Printf("Endpoint: http://%s/\n", *webdavAddress)
+	fmt.Printf("Username: %s\n", cfg.AccessKey)
+	fmt.Printf("Password: the configured secret key\n")
+
+	err = server.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
@@ -111,7 +111,7 @@ func main() {
 				Data: serializedAllocation,
 			}
 
-			if err := psClient.Put(context.Background(), id, dataSection, ttl, pba, nil); err != nil {
+			if _, err := psClient.Put(context.Background(), id, dataSection, ttl, pba, nil); err != nil {
 				fmt.Printf("Failed to Store data of id: %s\n", id)
 				return err
 			}
@@ -238,6 +238,9 @@ func main() {
 			}
 
 			log.Printf("Space Used: %v, Space Available: %v\nBandwidth Available: %v, Bandwidth Used: %v\n", summary.GetUsedSpace(), summary.GetAvailableSpace(), summary.GetAvailableBandwidth(), summary.GetUsedBandwidth())
+			for _, satellite := range summary.GetSatellites() {
+				log.Printf("Satellite %s: Pieces: %v, Space Used: %v, PUT Bandwidth: %v, GET Bandwidth: %v\n", satellite.SatelliteId, satellite.GetPieces(), satellite.GetUsedSpace(), satellite.GetPutBandwidth(), satellite.GetGetBandwidth())
+			}
 			return nil
 		},
 	})
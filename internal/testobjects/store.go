@@ -0,0 +1,192 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package testobjects implements an in-memory objects.Store for tests.
+package testobjects
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+)
+
+// Store is an in-memory objects.Store for tests
+type Store struct {
+	mu      sync.Mutex
+	objects map[storj.Path]object
+}
+
+type object struct {
+	data []byte
+	meta objects.Meta
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{objects: make(map[storj.Path]object)}
+}
+
+func (s *Store) Meta(ctx context.Context, path storj.Path) (objects.Meta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return objects.Meta{}, storj.ErrObjectNotFound.New("%s", path)
+	}
+	return obj.meta, nil
+}
+
+func (s *Store) Get(ctx context.Context, path storj.Path) (ranger.Ranger, objects.Meta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return nil, objects.Meta{}, storj.ErrObjectNotFound.New("%s", path)
+	}
+	return ranger.ByteRanger(obj.data), obj.meta, nil
+}
+
+func (s *Store) Put(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time) (objects.Meta, error) {
+	b, err := ioutil.ReadAll(data)
+	if err != nil {
+		return objects.Meta{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := objects.Meta{
+		SerializableMeta: metadata,
+		Modified:         time.Now(),
+		Expiration:       expiration,
+		Size:             int64(len(b)),
+	}
+	s.objects[path] = object{data: b, meta: m}
+	return m, nil
+}
+
+func (s *Store) PutResumable(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time, journal streams.Journal) (objects.Meta, error) {
+	return s.Put(ctx, path, data, metadata, expiration)
+}
+
+func (s *Store) UpdateMeta(ctx context.Context, path storj.Path, metadata pb.SerializableMeta) (objects.Meta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return objects.Meta{}, storj.ErrObjectNotFound.New("%s", path)
+	}
+	obj.meta.SerializableMeta = metadata
+	s.objects[path] = obj
+	return obj.meta, nil
+}
+
+func (s *Store) SetLock(ctx context.Context, path storj.Path, lock storj.ObjectLock) (objects.Meta, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return objects.Meta{}, storj.ErrObjectNotFound.New("%s", path)
+	}
+	obj.meta.Lock = lock
+	s.objects[path] = obj
+	return obj.meta, nil
+}
+
+func (s *Store) Delete(ctx context.Context, path storj.Path) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[path]; !ok {
+		return storj.ErrObjectNotFound.New("%s", path)
+	}
+	delete(s.objects, path)
+	return nil
+}
+
+func (s *Store) DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	selected := func(path storj.Path) bool {
+		for _, selected := range options.Paths {
+			if path == selected {
+				return true
+			}
+		}
+		for _, prefix := range options.Prefixes {
+			if prefix == "" || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	for path := range s.objects {
+		if selected(path) {
+			delete(s.objects, path)
+			result.Deleted++
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (items []objects.ListItem, more bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	seen := make(map[storj.Path]bool)
+	for path, obj := range s.objects {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		rel := strings.TrimPrefix(path, prefix)
+
+		item := objects.ListItem{Path: rel, Meta: obj.meta}
+		if i := strings.Index(rel, "/"); !recursive && i >= 0 {
+			item = objects.ListItem{Path: rel[:i+1], IsPrefix: true}
+		}
+		if seen[item.Path] || item.Path <= startAfter && startAfter != "" {
+			continue
+		}
+		seen[item.Path] = true
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, k int) bool { return items[i].Path < items[k].Path })
+	if limit > 0 && len(items) > limit {
+		return items[:limit], true, nil
+	}
+	return items, false, nil
+}
+
+// Paths returns the sorted paths of all stored objects
+func (s *Store) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var paths []string
+	for path := range s.objects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
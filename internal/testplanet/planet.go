@@ -27,6 +27,8 @@ import (
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
 	"storj.io/storj/storage/teststore"
 )
 
@@ -108,9 +110,15 @@ func New(t zaptest.TestingT, satelliteCount, storageNodeCount, uplinkCount int)
 				MinRemoteSegmentSize: 1240,
 				MaxInlineSegmentSize: 8000,
 				Overlay:              true,
+				DeletionMaxAttempts:  10,
 			},
 			node.Identity)
+		pointerServer.EnableDeletion(teststore.New())
 		pb.RegisterPointerDBServer(node.Provider.GRPC(), pointerServer)
+		go func() {
+			// TODO: stop on shutdown
+			_ = pointerServer.Run(context.Background())
+		}()
 		// bootstrap satellite kademlia node
 		go func(n *Node) {
 			if err := n.Kademlia.Bootstrap(context.Background()); err != nil {
@@ -143,12 +151,19 @@ func New(t zaptest.TestingT, satelliteCount, storageNodeCount, uplinkCount int)
 	for _, node := range planet.StorageNodes {
 		storageDir := filepath.Join(planet.directory, node.ID().String())
 
-		serverdb, err := psdb.OpenInMemory(context.Background(), storageDir)
+		blobs, err := filestore.NewAt(storageDir)
 		if err != nil {
 			return nil, utils.CombineErrors(err, planet.Shutdown())
 		}
 
-		server := pieceserver.New(storageDir, serverdb, pieceserver.Config{
+		dirs := []*pieceserver.DataDir{{Path: storageDir, Allocated: memory.GB.Int64(), Blobs: blobs}}
+
+		serverdb, err := psdb.OpenInMemory(context.Background(), map[string]storage.Blobs{storageDir: blobs})
+		if err != nil {
+			return nil, utils.CombineErrors(err, planet.Shutdown())
+		}
+
+		server := pieceserver.New(dirs, serverdb, pieceserver.Config{
 			Path:               storageDir,
 			AllocatedDiskSpace: memory.GB.Int64(),
 			AllocatedBandwidth: 100 * memory.GB.Int64(),
@@ -59,6 +59,14 @@ func (pbd *pointerDBWrapper) Delete(ctx context.Context, in *pb.DeleteRequest, o
 	return pbd.s.Delete(ctx, in)
 }
 
+func (pbd *pointerDBWrapper) UpdateMetadata(ctx context.Context, in *pb.UpdateMetadataRequest, opts ...grpc.CallOption) (*pb.UpdateMetadataResponse, error) {
+	return pbd.s.UpdateMetadata(ctx, in)
+}
+
+func (pbd *pointerDBWrapper) DeleteObjects(ctx context.Context, in *pb.DeleteObjectsRequest, opts ...grpc.CallOption) (*pb.DeleteObjectsResponse, error) {
+	return pbd.s.DeleteObjects(ctx, in)
+}
+
 func (pbd *pointerDBWrapper) PayerBandwidthAllocation(ctx context.Context, in *pb.PayerBandwidthAllocationRequest, opts ...grpc.CallOption) (*pb.PayerBandwidthAllocationResponse, error) {
 	return pbd.s.PayerBandwidthAllocation(ctx, in)
 }
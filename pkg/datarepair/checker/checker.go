@@ -12,8 +12,10 @@ import (
 
 	"storj.io/storj/pkg/datarepair/queue"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 )
 
@@ -27,6 +29,7 @@ type checker struct {
 	pointerdb   *pointerdb.Server
 	repairQueue *queue.Queue
 	overlay     pb.OverlayServer
+	reports     *corruptionReports
 	limit       int
 	logger      *zap.Logger
 	ticker      *time.Ticker
@@ -38,6 +41,7 @@ func newChecker(pointerdb *pointerdb.Server, repairQueue *queue.Queue, overlay p
 		pointerdb:   pointerdb,
 		repairQueue: repairQueue,
 		overlay:     overlay,
+		reports:     newCorruptionReports(),
 		limit:       limit,
 		logger:      logger,
 		ticker:      time.NewTicker(interval),
@@ -66,6 +70,18 @@ func (c *checker) Run(ctx context.Context) (err error) {
 func (c *checker) identifyInjuredSegments(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	// the reports that do not match any segment are of deleted segments
+	passStart := c.reports.now()
+	defer c.reports.expire(passStart)
+
+	// the reported pieces are marked in the pointers after iterating them
+	reported := make(map[string]*reportedPieces)
+	defer func() {
+		for path, pieces := range reported {
+			err = utils.CombineErrors(err, c.markCorrupted(path, pieces))
+		}
+	}()
+
 	err = c.pointerdb.Iterate(ctx, &pb.IterateRequest{Recurse: true},
 		func(it storage.Iterator) error {
 			var item storage.ListItem
@@ -97,6 +113,13 @@ func (c *checker) identifyInjuredSegments(ctx context.Context) (err error) {
 				if err != nil {
 					return Error.New("error getting offline nodes %s", err)
 				}
+				missingPieces, reportedNodes, err := c.addCorruptedPieces(remote, missingPieces)
+				if err != nil {
+					return Error.New("error deriving piece ids %s", err)
+				}
+				if len(reportedNodes) > 0 {
+					reported[string(item.Key)] = &reportedPieces{pieceID: remote.GetPieceId(), nodeIDs: reportedNodes}
+				}
 				numHealthy := len(nodeIDs) - len(missingPieces)
 				if int32(numHealthy) < pointer.Remote.Redundancy.RepairThreshold {
 					err = c.repairQueue.Enqueue(&pb.InjuredSegment{
@@ -114,6 +137,106 @@ func (c *checker) identifyInjuredSegments(ctx context.Context) (err error) {
 	return err
 }
 
+// addCorruptedPieces adds the indices of the pieces of remote that are
+// marked corrupted, or that their nodes reported corrupted since, to
+// missing. It returns the nodes of the pieces reported since, whose marks
+// are still to be kept in the pointer.
+func (c *checker) addCorruptedPieces(remote *pb.RemoteSegment, missing []int32) (_ []int32, reported []storj.NodeID, err error) {
+	pending := c.reports.pending()
+
+	isMissing := make(map[int32]bool, len(missing))
+	for _, i := range missing {
+		isMissing[i] = true
+	}
+
+	for i, piece := range remote.GetRemotePieces() {
+		if isMissing[int32(i)] {
+			continue
+		}
+		if piece.GetCorrupted() {
+			missing = append(missing, int32(i))
+			continue
+		}
+		if !pending {
+			continue
+		}
+		pieceID, err := psclient.PieceID(remote.GetPieceId()).Derive(piece.NodeId.Bytes())
+		if err != nil {
+			return nil, nil, err
+		}
+		if c.reports.take(piece.NodeId, pieceID.String()) {
+			missing = append(missing, int32(i))
+			reported = append(reported, piece.NodeId)
+		}
+	}
+	return missing, reported, nil
+}
+
+// reportedPieces are the pieces of a segment that their nodes reported
+// corrupted
+type reportedPieces struct {
+	pieceID string
+	nodeIDs []storj.NodeID
+}
+
+// markCorrupted marks the reported pieces of the segment at path corrupted
+// in its pointer, so that they are counted missing until the segment is
+// repaired. The pointer is swapped only if it was not changed meanwhile,
+// and the reports are kept if it cannot be.
+func (c *checker) markCorrupted(path string, reported *reportedPieces) (err error) {
+	defer func() {
+		if err != nil {
+			for _, nodeID := range reported.nodeIDs {
+				pieceID, deriveErr := psclient.PieceID(reported.pieceID).Derive(nodeID.Bytes())
+				if deriveErr == nil {
+					c.reports.restore(nodeID, pieceID.String())
+				}
+			}
+		}
+	}()
+
+	key := storage.Key(path)
+	for {
+		value, err := c.pointerdb.DB.Get(key)
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		pointer := &pb.Pointer{}
+		if err = proto.Unmarshal(value, pointer); err != nil {
+			return Error.Wrap(err)
+		}
+		// the segment may have been replaced meanwhile
+		if pointer.GetRemote().GetPieceId() != reported.pieceID {
+			return nil
+		}
+
+		changed := false
+		for _, piece := range pointer.GetRemote().GetRemotePieces() {
+			for _, nodeID := range reported.nodeIDs {
+				if piece.NodeId == nodeID && !piece.Corrupted {
+					piece.Corrupted, changed = true, true
+				}
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		marked, err := proto.Marshal(pointer)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		err = c.pointerdb.DB.CompareAndSwap(key, value, marked)
+		if !storage.ErrValueChanged.Has(err) {
+			return Error.Wrap(err)
+		}
+	}
+}
+
 // returns the indices of offline nodes
 func (c *checker) offlineNodes(ctx context.Context, nodeIDs storj.NodeIDList) (offline []int32, err error) {
 	responses, err := c.overlay.BulkLookup(ctx, pb.NodeIDsToLookupRequests(nodeIDs))
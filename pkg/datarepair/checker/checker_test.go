@@ -21,8 +21,10 @@ import (
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/overlay/mocks"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
 	"storj.io/storj/storage/redis"
 	"storj.io/storj/storage/redis/redisserver"
 	"storj.io/storj/storage/testqueue"
@@ -136,6 +138,96 @@ func TestOfflineNodes(t *testing.T) {
 	assert.Equal(t, expectedOffline, offline)
 }
 
+func TestCorruptedPieces(t *testing.T) {
+	logger := zap.NewNop()
+	pointerdb := pointerdb.NewServer(teststore.New(), &overlay.Cache{}, logger, pointerdb.Config{}, nil)
+
+	repairQueue := queue.NewQueue(testqueue.New())
+
+	ids := teststorj.NodeIDsFromStrings("a", "b", "c", "d")
+	var nodes []*pb.Node
+	for _, id := range ids {
+		nodes = append(nodes, &pb.Node{Id: id, Type: pb.NodeType_STORAGE, Address: &pb.NodeAddress{Address: ""}})
+	}
+
+	for _, path := range []string{"healthy", "injured"} {
+		p := &pb.Pointer{
+			Remote: &pb.RemoteSegment{
+				Redundancy: &pb.RedundancyScheme{
+					RepairThreshold: int32(3),
+				},
+				PieceId: path,
+				RemotePieces: []*pb.RemotePiece{
+					{PieceNum: 0, NodeId: ids[0]},
+					{PieceNum: 1, NodeId: ids[1]},
+					{PieceNum: 2, NodeId: ids[2]},
+					{PieceNum: 3, NodeId: ids[3]},
+				},
+			},
+		}
+		ctx = auth.WithAPIKey(ctx, nil)
+		_, err := pointerdb.Put(ctx, &pb.PutRequest{Path: path, Pointer: p})
+		assert.NoError(t, err)
+	}
+
+	checker := newChecker(pointerdb, repairQueue, mocks.NewOverlay(nodes), 0, logger, time.Second)
+
+	report := func(path string, id storj.NodeID) {
+		pieceID, err := psclient.PieceID(path).Derive(id.Bytes())
+		assert.NoError(t, err)
+		checker.reports.pieces[corruptedPiece{nodeID: id, pieceID: pieceID.String()}] = time.Now()
+	}
+
+	// all nodes are online, but two of them lost pieces of the segment, and
+	// one lost a piece of a segment that needs no repair yet
+	report("injured", ids[1])
+	report("injured", ids[2])
+	report("healthy", ids[0])
+	checker.reports.pieces[corruptedPiece{nodeID: ids[0], pieceID: "unknown"}] = time.Now().Add(-time.Second)
+
+	err := checker.identifyInjuredSegments(ctx)
+	assert.NoError(t, err)
+
+	injured, err := repairQueue.Dequeue()
+	assert.NoError(t, err)
+	assert.Equal(t, "injured", injured.Path)
+	assert.Equal(t, []int32{1, 2}, injured.LostPieces)
+
+	_, err = repairQueue.Dequeue()
+	assert.Error(t, err)
+
+	assert.False(t, checker.reports.pending())
+
+	// the reported pieces stay marked in the pointers until they are
+	// repaired
+	for path, corrupted := range map[string][]bool{
+		"healthy": {true, false, false, false},
+		"injured": {false, true, true, false},
+	} {
+		value, err := pointerdb.DB.Get(storage.Key(path))
+		assert.NoError(t, err)
+		pointer := &pb.Pointer{}
+		assert.NoError(t, proto.Unmarshal(value, pointer))
+		for i, piece := range pointer.GetRemote().GetRemotePieces() {
+			assert.Equal(t, corrupted[i], piece.GetCorrupted(), path)
+		}
+	}
+
+	report("healthy", ids[3])
+	err = checker.identifyInjuredSegments(ctx)
+	assert.NoError(t, err)
+
+	for _, expected := range []*pb.InjuredSegment{
+		{Path: "healthy", LostPieces: []int32{0, 3}},
+		{Path: "injured", LostPieces: []int32{1, 2}},
+	} {
+		injured, err := repairQueue.Dequeue()
+		assert.NoError(t, err)
+		assert.Equal(t, expected.Path, injured.Path)
+		assert.Equal(t, expected.LostPieces, injured.LostPieces)
+	}
+}
+
 func BenchmarkIdentifyInjuredSegments(b *testing.B) {
 	logger := zap.NewNop()
 	pointerdb := pointerdb.NewServer(teststore.New(), &overlay.Cache{}, logger, pointerdb.Config{}, nil)
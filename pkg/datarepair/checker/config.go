@@ -25,7 +25,7 @@ type Config struct {
 }
 
 // Initialize a Checker struct
-func (c Config) initialize(ctx context.Context) (Checker, error) {
+func (c Config) initialize(ctx context.Context) (*checker, error) {
 	pdb := pointerdb.LoadFromContext(ctx)
 	var o pb.OverlayServer
 	x := overlay.LoadServerFromContext(ctx)
@@ -50,6 +50,8 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 	}
 	ctx, cancel := context.WithCancel(ctx)
 
+	pb.RegisterCorruptionServer(server.GRPC(), check.reports)
+
 	go func() {
 		if err := check.Run(ctx); err != nil {
 			defer cancel()
@@ -0,0 +1,106 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storj"
+)
+
+// maxCorruptedPieces is how many pieces one report can contain
+const maxCorruptedPieces = 1000
+
+// corruptionReports keeps the pieces that storage nodes reported corrupted
+// until the checker finds their segments and marks them in the pointers.
+// They are kept in memory, so reports lost in a restart before are left for
+// the audits to find.
+type corruptionReports struct {
+	mu     sync.Mutex
+	pieces map[corruptedPiece]time.Time
+	now    func() time.Time
+}
+
+// corruptedPiece is a piece as derived for the node storing it
+type corruptedPiece struct {
+	nodeID  storj.NodeID
+	pieceID string
+}
+
+func newCorruptionReports() *corruptionReports {
+	return &corruptionReports{
+		pieces: make(map[corruptedPiece]time.Time),
+		now:    time.Now,
+	}
+}
+
+// ReportCorrupted records the pieces that the calling storage node found
+// corrupted
+func (r *corruptionReports) ReportCorrupted(ctx context.Context, req *pb.CorruptedPieces) (_ *pb.CorruptedPiecesResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if len(req.GetPieceIds()) > maxCorruptedPieces {
+		return nil, status.Errorf(codes.InvalidArgument, "too many pieces: %d > %d", len(req.GetPieceIds()), maxCorruptedPieces)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, pieceID := range req.GetPieceIds() {
+		r.pieces[corruptedPiece{nodeID: pi.ID, pieceID: pieceID}] = now
+	}
+	return &pb.CorruptedPiecesResponse{}, nil
+}
+
+// pending returns whether there are reported pieces
+func (r *corruptionReports) pending() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pieces) > 0
+}
+
+// take returns whether the piece with pieceID on the node was reported
+// corrupted, and forgets it
+func (r *corruptionReports) take(nodeID storj.NodeID, pieceID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	piece := corruptedPiece{nodeID: nodeID, pieceID: pieceID}
+	_, ok := r.pieces[piece]
+	delete(r.pieces, piece)
+	return ok
+}
+
+// restore records again the piece with pieceID on the node, which was
+// taken but could not be marked corrupted
+func (r *corruptionReports) restore(nodeID storj.NodeID, pieceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pieces[corruptedPiece{nodeID: nodeID, pieceID: pieceID}] = r.now()
+}
+
+// expire forgets the pieces reported before, whose segments were not found
+func (r *corruptionReports) expire(before time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for piece, reported := range r.pieces {
+		if reported.Before(before) {
+			delete(r.pieces, piece)
+		}
+	}
+}
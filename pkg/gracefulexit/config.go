@@ -0,0 +1,48 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+)
+
+// Config contains configurable values for graceful exits
+type Config struct {
+	OverlayAddr string `help:"Address to contact overlay server through"`
+	BatchSize   int    `help:"how many pieces an exiting node is given to transfer at a time" default:"100"`
+}
+
+// Run registers the graceful exit endpoint. It assumes PointerDB and
+// Overlay responsibilities have been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pdb := pointerdb.LoadFromContext(ctx)
+	if pdb == nil {
+		return Error.New("programmer error: pointerdb responsibility unstarted")
+	}
+
+	cache := overlay.LoadFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+
+	oc, err := overlay.NewOverlayClient(server.Identity(), c.OverlayAddr)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	endpoint := NewEndpoint(pdb, cache, oc, transport.NewClient(server.Identity()), zap.L(), c.BatchSize)
+	pb.RegisterGracefulExitServer(server.GRPC(), endpoint)
+
+	return server.Run(ctx)
+}
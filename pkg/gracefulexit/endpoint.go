@@ -0,0 +1,483 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/storage"
+)
+
+// Endpoint hands the pieces of exiting storage nodes over to replacement
+// nodes and moves them in the pointers once they are transferred
+type Endpoint struct {
+	pointerdb *pointerdb.Server
+	cache     *overlay.Cache
+	overlay   overlay.Client
+	logger    *zap.Logger
+	batchSize int
+	// pieceInfo returns the size of the piece with id stored on node, and
+	// the key node signs its receipts with
+	pieceInfo func(ctx context.Context, node *pb.Node, id psclient.PieceID, authorization *pb.SignedMessage) (int64, crypto.PublicKey, error)
+
+	mu    sync.Mutex
+	exits map[storj.NodeID]*exit
+}
+
+// exit is the progress of the exit of a node. It is kept in memory until
+// the exit is finished, so after a restart the pieces left on the node are
+// found again in the pointers and only the counts start over. That the node
+// is exiting is kept in the overlay.
+type exit struct {
+	mu       sync.Mutex
+	progress pb.ExitProgress
+	// after is the path of the last segment searched for pieces
+	after    string
+	searched bool
+	pending  map[pieceKey]*transfer
+}
+
+// pieceKey identifies a piece of a segment
+type pieceKey struct {
+	path string
+	num  int32
+}
+
+// transfer is a piece handed to the exiting node for transfer
+type transfer struct {
+	*pb.PieceTransfer
+	pieceSize int64
+	// hash is the hash of the piece in the receipt of the exiting node
+	hash []byte
+}
+
+// NewEndpoint creates a graceful exit endpoint that finds the pieces of the
+// exiting nodes in pdb and their replacements with oc. The exiting nodes
+// are marked in cache, so that they are not chosen for new pieces.
+func NewEndpoint(pdb *pointerdb.Server, cache *overlay.Cache, oc overlay.Client, tc transport.Client, logger *zap.Logger, batchSize int) *Endpoint {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Endpoint{
+		pointerdb: pdb,
+		cache:     cache,
+		overlay:   oc,
+		logger:    logger,
+		batchSize: batchSize,
+		pieceInfo: func(ctx context.Context, node *pb.Node, id psclient.PieceID, authorization *pb.SignedMessage) (int64, crypto.PublicKey, error) {
+			conn, err := tc.DialNode(ctx, node)
+			if err != nil {
+				return 0, nil, err
+			}
+			defer func() { _ = conn.Close() }()
+
+			var p peer.Peer
+			summary, err := pb.NewPieceStoreRoutesClient(conn).Piece(ctx, &pb.PieceId{Id: id.String(), Authorization: authorization}, grpc.Peer(&p))
+			if err != nil {
+				return 0, nil, err
+			}
+			// the node was dialed by its ID, so it is the owner of its leaf key
+			pi, err := provider.PeerIdentityFromPeer(&p)
+			if err != nil {
+				return 0, nil, err
+			}
+			return summary.GetPieceSize(), pi.Leaf.PublicKey, nil
+		},
+		exits: make(map[storj.NodeID]*exit),
+	}
+}
+
+// Exit starts or continues the exit of the calling node and returns the
+// next pieces it should transfer. The pieces whose transfers were not
+// confirmed are handed out again.
+func (e *Endpoint) Exit(ctx context.Context, req *pb.ExitRequest) (_ *pb.ExitResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ex, err := e.start(ctx, pi.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// finished exits are forgotten, after ex is unlocked as start locks
+	// them while holding e.mu
+	var finished bool
+	defer func() {
+		if finished {
+			e.finish(pi.ID, ex)
+		}
+	}()
+
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	if len(ex.pending) < e.batchSize && !ex.searched {
+		err = e.search(ctx, pi.ID, ex)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	resp := &pb.ExitResponse{}
+	for _, t := range ex.pending {
+		resp.Transfers = append(resp.Transfers, t.PieceTransfer)
+	}
+	ex.progress.Finished = ex.searched && len(ex.pending) == 0
+	resp.Progress = copyProgress(&ex.progress)
+	if ex.progress.Finished {
+		finished = true
+		return resp, nil
+	}
+
+	pba, err := e.pointerdb.PayerBandwidthAllocation(ctx, &pb.PayerBandwidthAllocationRequest{Action: pb.PayerBandwidthAllocation_PUT})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp.PayerAllocation = pba.GetPba()
+
+	resp.Authorization, err = e.pointerdb.SignedMessage()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
+
+// ConfirmTransfer moves a piece of the calling node to its replacement
+// node in the pointer, once the replacement node is found storing it
+func (e *Endpoint) ConfirmTransfer(ctx context.Context, req *pb.TransferConfirmation) (_ *pb.TransferConfirmationResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	e.mu.Lock()
+	ex := e.exits[pi.ID]
+	e.mu.Unlock()
+	if ex == nil {
+		return nil, status.Error(codes.FailedPrecondition, "node is not exiting")
+	}
+
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	key := pieceKey{path: req.GetPath(), num: req.GetPieceNum()}
+	t, ok := ex.pending[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no transfer of piece %d of %s", key.num, key.path)
+	}
+	delete(ex.pending, key)
+
+	if req.GetError() == "" {
+		err = e.verify(ctx, t, req.GetReceipt())
+		if err == nil {
+			err = e.movePiece(t, pi.ID, req.GetReceipt())
+		}
+	} else {
+		err = Error.New("%s", req.GetError())
+	}
+
+	if err != nil {
+		// the piece is left to repair once the node is gone
+		e.logger.Info("transfer failed", zap.Stringer("node", pi.ID), zap.String("path", key.path),
+			zap.Int32("piece", key.num), zap.Error(err))
+		ex.progress.PiecesFailed++
+	} else {
+		ex.progress.PiecesTransferred++
+	}
+	return &pb.TransferConfirmationResponse{Progress: copyProgress(&ex.progress), Transferred: err == nil}, nil
+}
+
+// start returns the exit of the node with nodeID, counting the pieces it
+// holds when it is new
+func (e *Endpoint) start(ctx context.Context, nodeID storj.NodeID) (*exit, error) {
+	e.mu.Lock()
+	ex, ok := e.exits[nodeID]
+	if !ok {
+		ex = &exit{pending: make(map[pieceKey]*transfer)}
+		e.exits[nodeID] = ex
+	}
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	e.mu.Unlock()
+
+	if ok {
+		return ex, nil
+	}
+
+	// no new pieces are stored on the node, and it is not chosen to
+	// replace the other exiting nodes
+	err := e.cache.MarkExiting(ctx, nodeID)
+	if err == nil {
+		err = e.iterate(ctx, "", func(path string, pointer *pb.Pointer) (bool, error) {
+			if pieceOn(pointer, nodeID) != nil {
+				ex.progress.PiecesTotal++
+			}
+			return true, nil
+		})
+	}
+	if err != nil {
+		e.mu.Lock()
+		delete(e.exits, nodeID)
+		e.mu.Unlock()
+		return nil, err
+	}
+
+	e.logger.Info("node started exiting", zap.Stringer("node", nodeID), zap.Int64("pieces", ex.progress.PiecesTotal))
+	return ex, nil
+}
+
+// finish forgets the finished exit ex of the node with nodeID. The node
+// stays marked as exiting in the overlay.
+func (e *Endpoint) finish(nodeID storj.NodeID, ex *exit) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.exits[nodeID] == ex {
+		delete(e.exits, nodeID)
+		e.logger.Info("node finished exiting", zap.Stringer("node", nodeID),
+			zap.Int64("transferred", ex.progress.PiecesTransferred), zap.Int64("failed", ex.progress.PiecesFailed))
+	}
+}
+
+// search adds the next pieces of the node with nodeID to the pending
+// transfers of ex, until there is a batch of them
+func (e *Endpoint) search(ctx context.Context, nodeID storj.NodeID, ex *exit) error {
+	searched := true
+	err := e.iterate(ctx, ex.after, func(path string, pointer *pb.Pointer) (bool, error) {
+		if path == ex.after {
+			return true, nil
+		}
+		if len(ex.pending) >= e.batchSize {
+			searched = false
+			return false, nil
+		}
+		ex.after = path
+
+		piece := pieceOn(pointer, nodeID)
+		if piece == nil {
+			return true, nil
+		}
+
+		// without the hash of the piece the data stored by the replacement
+		// node cannot be checked
+		hash := receiptHash(pointer, piece)
+		if hash == nil {
+			e.logger.Info("no receipt for piece, left to repair", zap.String("path", path), zap.Int32("piece", piece.GetPieceNum()))
+			ex.progress.PiecesFailed++
+			return true, nil
+		}
+
+		remote := pointer.GetRemote()
+		var excluded storj.NodeIDList
+		for _, p := range remote.GetRemotePieces() {
+			excluded = append(excluded, p.NodeId)
+		}
+		nodes, err := e.overlay.Choose(ctx, overlay.Options{Amount: 1, Excluded: excluded})
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			e.logger.Info("no replacement for piece", zap.String("path", path), zap.Int32("piece", piece.GetPieceNum()))
+			ex.progress.PiecesFailed++
+			return true, nil
+		}
+
+		ex.pending[pieceKey{path: path, num: piece.GetPieceNum()}] = &transfer{
+			PieceTransfer: &pb.PieceTransfer{
+				Path:              path,
+				PieceNum:          piece.GetPieceNum(),
+				PieceId:           remote.GetPieceId(),
+				Replacement:       nodes[0],
+				ExpirationUnixSec: pointer.GetExpirationDate().GetSeconds(),
+			},
+			pieceSize: pieceSize(pointer),
+			hash:      hash,
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	ex.searched = searched
+	return nil
+}
+
+// iterate calls f with the pointers from the path first on, until f
+// returns false
+func (e *Endpoint) iterate(ctx context.Context, first string, f func(path string, pointer *pb.Pointer) (bool, error)) error {
+	return e.pointerdb.Iterate(ctx, &pb.IterateRequest{First: first, Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				pointer := &pb.Pointer{}
+				if err := proto.Unmarshal(item.Value, pointer); err != nil {
+					return Error.Wrap(err)
+				}
+				more, err := f(string(item.Key), pointer)
+				if err != nil || !more {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// verify returns an error if the replacement node of t did not sign
+// receipt for the piece, or does not store it
+func (e *Endpoint) verify(ctx context.Context, t *transfer, receipt *pb.PieceStoreReceipt) error {
+	data, err := psclient.ReceiptData(receipt)
+	if err != nil {
+		return err
+	}
+
+	id, err := psclient.PieceID(t.GetPieceId()).Derive(t.GetReplacement().Id.Bytes())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	switch {
+	case data.GetId() != id.String():
+		return Error.New("receipt for piece %s instead of %s", data.GetId(), id)
+	case data.GetPieceSize() != t.pieceSize:
+		return Error.New("receipt for %d bytes instead of %d", data.GetPieceSize(), t.pieceSize)
+	case data.GetExpirationUnixSec() != t.GetExpirationUnixSec():
+		return Error.New("receipt expiring at %d instead of %d", data.GetExpirationUnixSec(), t.GetExpirationUnixSec())
+	case t.hash == nil || !bytes.Equal(data.GetHash(), t.hash):
+		return Error.New("receipt for different data")
+	}
+
+	authorization, err := e.pointerdb.SignedMessage()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	size, key, err := e.pieceInfo(ctx, t.GetReplacement(), id, authorization)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err = psclient.CheckReceiptKey(receipt, key); err != nil {
+		return Error.Wrap(err)
+	}
+	if size != data.GetPieceSize() {
+		return Error.New("replacement node stores %d bytes instead of %d", size, data.GetPieceSize())
+	}
+	return nil
+}
+
+// movePiece moves the piece of t from the node with nodeID to the
+// replacement node in its pointer. The pointer is swapped only if it was
+// not changed meanwhile, as by repairs and deletes.
+func (e *Endpoint) movePiece(t *transfer, nodeID storj.NodeID, receipt *pb.PieceStoreReceipt) error {
+	compact, err := psclient.CompactReceipt(receipt)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	key := storage.Key(t.GetPath())
+	for {
+		value, err := e.pointerdb.DB.Get(key)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		pointer := &pb.Pointer{}
+		if err = proto.Unmarshal(value, pointer); err != nil {
+			return Error.Wrap(err)
+		}
+
+		// the segment may have been replaced or repaired meanwhile
+		piece := pieceOn(pointer, nodeID)
+		if piece == nil || piece.GetPieceNum() != t.GetPieceNum() || pointer.GetRemote().GetPieceId() != t.GetPieceId() {
+			return Error.New("piece %d of %s moved", t.GetPieceNum(), t.GetPath())
+		}
+		piece.NodeId = t.GetReplacement().Id
+		piece.Receipt = compact
+
+		moved, err := proto.Marshal(pointer)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		err = e.pointerdb.DB.CompareAndSwap(key, value, moved)
+		if !storage.ErrValueChanged.Has(err) {
+			return Error.Wrap(err)
+		}
+	}
+}
+
+// pieceOn returns the piece of the remote segment of pointer stored on the
+// node with nodeID, or nil
+func pieceOn(pointer *pb.Pointer, nodeID storj.NodeID) *pb.RemotePiece {
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		if piece.NodeId == nodeID {
+			return piece
+		}
+	}
+	return nil
+}
+
+// receiptHash returns the hash of piece in the receipt kept in pointer, or
+// nil if it has no valid one
+func receiptHash(pointer *pb.Pointer, piece *pb.RemotePiece) []byte {
+	size := pieceSize(pointer)
+	if piece.GetReceipt() == nil || size == 0 {
+		return nil
+	}
+	id, err := psclient.PieceID(pointer.GetRemote().GetPieceId()).Derive(piece.NodeId.Bytes())
+	if err != nil {
+		return nil
+	}
+	receipt, err := psclient.ExpandReceipt(piece.GetReceipt(), id, size, pointer.GetExpirationDate().GetSeconds())
+	if err != nil {
+		return nil
+	}
+	data, err := psclient.ReceiptData(receipt)
+	if err != nil {
+		return nil
+	}
+	return data.GetHash()
+}
+
+// pieceSize returns the size of the pieces of the remote segment of
+// pointer, or 0 if it is not known. The segment is padded to a multiple of
+// its stripe size, with the padding ending in its length, as
+// eestream.PadReader does.
+func pieceSize(pointer *pb.Pointer) int64 {
+	redundancy := pointer.GetRemote().GetRedundancy()
+	stripeSize := int64(redundancy.GetErasureShareSize()) * int64(redundancy.GetMinReq())
+	if stripeSize <= 0 {
+		return 0
+	}
+
+	padded := pointer.GetSegmentSize() + 4
+	if r := padded % stripeSize; r > 0 {
+		padded += stripeSize - r
+	}
+	return padded / int64(redundancy.GetMinReq())
+}
+
+func copyProgress(progress *pb.ExitProgress) *pb.ExitProgress {
+	c := *progress
+	return &c
+}
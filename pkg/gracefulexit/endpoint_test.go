@@ -0,0 +1,225 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/mock/gomock"
+	"github.com/gtank/cryptopasta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"storj.io/storj/internal/identity"
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/overlay/mocks"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func newTestIdentity(t *testing.T) *provider.FullIdentity {
+	ca, err := testidentity.NewTestCA(context.Background())
+	require.NoError(t, err)
+	identity, err := ca.NewIdentity()
+	require.NoError(t, err)
+	return identity
+}
+
+func peerContext(identity *provider.FullIdentity) context.Context {
+	info := credentials.TLSInfo{State: tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{identity.Leaf, identity.CA},
+	}}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+}
+
+func TestExit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	satellite := newTestIdentity(t)
+	exiting := newTestIdentity(t)
+	ctx := peerContext(exiting)
+
+	other := teststorj.NodeIDFromString("other")
+	replacementIdentity := newTestIdentity(t)
+	replacement := &pb.Node{Id: replacementIdentity.ID}
+
+	// the pointers keep the compact receipts of the pieces on the exiting node
+	exitingReceipt := func(path string) *pb.PieceReceipt {
+		id, err := psclient.PieceID("piece-" + path).Derive(exiting.ID.Bytes())
+		require.NoError(t, err)
+		return compactReceipt(t, signReceipt(t, exiting, id, 2048, "hash-"+path))
+	}
+
+	pdb := pointerdb.NewServer(teststore.New(), nil, zap.NewNop(), pointerdb.Config{}, satellite)
+	for path, nodes := range map[string][]*pb.RemotePiece{
+		"a": {{PieceNum: 0, NodeId: other}, {PieceNum: 1, NodeId: exiting.ID, Receipt: exitingReceipt("a")}},
+		"b": {{PieceNum: 0, NodeId: other}},
+		"c": {{PieceNum: 0, NodeId: exiting.ID}, {PieceNum: 1, NodeId: other}},
+		"d": {{PieceNum: 0, NodeId: exiting.ID, Receipt: exitingReceipt("d")}},
+		"e": {{PieceNum: 0, NodeId: exiting.ID, Receipt: exitingReceipt("e")}},
+		"f": {{PieceNum: 0, NodeId: exiting.ID, Receipt: exitingReceipt("f")}},
+	} {
+		value, err := proto.Marshal(&pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Remote: &pb.RemoteSegment{
+				Redundancy:   &pb.RedundancyScheme{MinReq: 2, Total: 2, ErasureShareSize: 1024},
+				PieceId:      "piece-" + path,
+				RemotePieces: nodes,
+			},
+			SegmentSize: 4000,
+		})
+		require.NoError(t, err)
+		require.NoError(t, pdb.DB.Put(storage.Key(path), value))
+	}
+
+	overlayDB := teststore.New()
+	cache := overlay.NewOverlayCache(overlayDB, nil, nil)
+	oc := mocks.NewMockClient(ctrl)
+	oc.EXPECT().Choose(gomock.Any(), gomock.Any()).Return([]*pb.Node{replacement}, nil).Times(4)
+
+	endpoint := NewEndpoint(pdb, cache, oc, nil, zap.NewNop(), 1)
+	endpoint.pieceInfo = func(ctx context.Context, node *pb.Node, id psclient.PieceID, authorization *pb.SignedMessage) (int64, crypto.PublicKey, error) {
+		return 2048, replacementIdentity.Leaf.PublicKey, nil
+	}
+
+	replacementID := func(path string) psclient.PieceID {
+		id, err := psclient.PieceID("piece-" + path).Derive(replacement.Id.Bytes())
+		require.NoError(t, err)
+		return id
+	}
+
+	next := func(path string) {
+		resp, err := endpoint.Exit(ctx, &pb.ExitRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Transfers, 1)
+		assert.Equal(t, path, resp.Transfers[0].Path)
+	}
+
+	confirm := func(path string, num int32, receipt *pb.PieceStoreReceipt, progress *pb.ExitProgress) {
+		confirmed, err := endpoint.ConfirmTransfer(ctx, &pb.TransferConfirmation{Path: path, PieceNum: num, Receipt: receipt})
+		require.NoError(t, err)
+		assert.Equal(t, progress, confirmed.Progress)
+
+		value, err := pdb.DB.Get(storage.Key(path))
+		require.NoError(t, err)
+		pointer := &pb.Pointer{}
+		require.NoError(t, proto.Unmarshal(value, pointer))
+		for _, piece := range pointer.Remote.RemotePieces {
+			if piece.PieceNum != num {
+				continue
+			}
+			if confirmed.Transferred {
+				assert.Equal(t, replacement.Id, piece.NodeId)
+				assert.True(t, proto.Equal(compactReceipt(t, receipt), piece.Receipt))
+			} else {
+				assert.Equal(t, exiting.ID, piece.NodeId)
+			}
+		}
+	}
+
+	// the first piece is handed out until its transfer is confirmed
+	for i := 0; i < 2; i++ {
+		resp, err := endpoint.Exit(ctx, &pb.ExitRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Transfers, 1)
+		assert.Equal(t, "a", resp.Transfers[0].Path)
+		assert.Equal(t, int32(1), resp.Transfers[0].PieceNum)
+		assert.Equal(t, "piece-a", resp.Transfers[0].PieceId)
+		assert.Equal(t, replacement, resp.Transfers[0].Replacement)
+		assert.NotNil(t, resp.PayerAllocation)
+		assert.NotNil(t, resp.Authorization)
+		assert.Equal(t, &pb.ExitProgress{PiecesTotal: 5}, resp.Progress)
+	}
+
+	// the exiting node is not chosen for new pieces, also after a restart
+	isExiting, err := overlay.NewOverlayCache(overlayDB, nil, nil).IsExiting(context.Background(), exiting.ID)
+	require.NoError(t, err)
+	assert.True(t, isExiting)
+	isExiting, err = cache.IsExiting(context.Background(), replacement.Id)
+	require.NoError(t, err)
+	assert.False(t, isExiting)
+
+	_, err = endpoint.ConfirmTransfer(ctx, &pb.TransferConfirmation{Path: "b", Receipt: signReceipt(t, replacementIdentity, replacementID("b"), 2048, "")})
+	assert.Error(t, err)
+
+	// a receipt signed by the exiting node itself fails the transfer
+	confirm("a", 1, signReceipt(t, exiting, replacementID("a"), 2048, "hash-a"),
+		&pb.ExitProgress{PiecesTotal: 5, PiecesFailed: 1})
+
+	// the piece without a receipt is left to repair, and a receipt for
+	// another size fails the transfer
+	next("d")
+	confirm("d", 0, signReceipt(t, replacementIdentity, replacementID("d"), 100, "hash-d"),
+		&pb.ExitProgress{PiecesTotal: 5, PiecesFailed: 3})
+
+	// a receipt for other data than the exiting node stored fails the transfer
+	next("e")
+	confirm("e", 0, signReceipt(t, replacementIdentity, replacementID("e"), 2048, "garbage"),
+		&pb.ExitProgress{PiecesTotal: 5, PiecesFailed: 4})
+
+	next("f")
+	confirm("f", 0, signReceipt(t, replacementIdentity, replacementID("f"), 2048, "hash-f"),
+		&pb.ExitProgress{PiecesTotal: 5, PiecesTransferred: 1, PiecesFailed: 4})
+
+	resp, err := endpoint.Exit(ctx, &pb.ExitRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Transfers)
+	assert.True(t, resp.Progress.Finished)
+
+	// the finished exit is forgotten
+	assert.Empty(t, endpoint.exits)
+	_, err = endpoint.ConfirmTransfer(ctx, &pb.TransferConfirmation{Path: "f"})
+	assert.Error(t, err)
+}
+
+func TestPieceSize(t *testing.T) {
+	for _, tt := range []struct {
+		segmentSize, pieceSize int64
+	}{
+		{0, 1024},
+		{2044, 1024},
+		{2045, 2048},
+		{4000, 2048},
+	} {
+		pointer := &pb.Pointer{
+			Remote: &pb.RemoteSegment{
+				Redundancy: &pb.RedundancyScheme{MinReq: 2, ErasureShareSize: 1024},
+			},
+			SegmentSize: tt.segmentSize,
+		}
+		assert.Equal(t, tt.pieceSize, pieceSize(pointer), "segment size %d", tt.segmentSize)
+	}
+	assert.Equal(t, int64(0), pieceSize(&pb.Pointer{}))
+}
+
+func signReceipt(t *testing.T, identity *provider.FullIdentity, id psclient.PieceID, size int64, hash string) *pb.PieceStoreReceipt {
+	pubKey, err := x509.MarshalPKIXPublicKey(identity.Leaf.PublicKey)
+	require.NoError(t, err)
+	data, err := proto.Marshal(&pb.PieceStoreReceipt_Data{Id: id.String(), PieceSize: size, Hash: []byte(hash), PubKey: pubKey})
+	require.NoError(t, err)
+	signature, err := cryptopasta.Sign(data, identity.Key.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	return &pb.PieceStoreReceipt{Signature: signature, Data: data}
+}
+
+func compactReceipt(t *testing.T, receipt *pb.PieceStoreReceipt) *pb.PieceReceipt {
+	compact, err := psclient.CompactReceipt(receipt)
+	require.NoError(t, err)
+	return compact
+}
@@ -138,6 +138,19 @@ func (db *DB) DeleteObject(ctx context.Context, bucket string, path storj.Path)
 	return store.Delete(ctx, path)
 }
 
+// DeleteObjects deletes the objects selected by options with one request
+// to pointerdb
+func (db *DB) DeleteObjects(ctx context.Context, bucket string, options storj.DeleteOptions) (result storj.DeleteResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	store, err := db.buckets.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return result, err
+	}
+
+	return store.DeleteObjects(ctx, options)
+}
+
 // ModifyPendingObject creates an interface for updating a partially uploaded object
 func (db *DB) ModifyPendingObject(ctx context.Context, bucket string, path storj.Path) (object storj.MutableObject, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -280,6 +280,41 @@ func TestDeleteObject(t *testing.T) {
 	})
 }
 
+func TestDeleteObjects(t *testing.T) {
+	runTest(t, func(ctx context.Context, db *DB) {
+		bucket, err := db.CreateBucket(ctx, TestBucket, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		for _, path := range []string{"a", "dir/b", "dir/sub/c", "dirty", "other/d"} {
+			upload(ctx, t, db, bucket, path, []byte(path))
+		}
+
+		result, err := db.DeleteObjects(ctx, bucket.Name, storj.DeleteOptions{
+			Paths:    []storj.Path{"a", "non-existing-file"},
+			Prefixes: []storj.Path{"dir/"},
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, storj.DeleteResult{Deleted: 3}, result)
+
+		list, err := db.ListObjects(ctx, bucket.Name, optionsRecursive("", "", storj.After, 0))
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"dirty", "other/d"}, getObjectPaths(list))
+		}
+
+		result, err = db.DeleteObjects(ctx, bucket.Name, storj.DeleteOptions{Prefixes: []storj.Path{""}})
+		if assert.NoError(t, err) {
+			assert.Equal(t, storj.DeleteResult{Deleted: 2}, result)
+		}
+
+		_, err = db.GetBucket(ctx, bucket.Name)
+		assert.NoError(t, err)
+	})
+}
+
 func TestListObjectsEmpty(t *testing.T) {
 	runTest(t, func(ctx context.Context, db *DB) {
 		bucket, err := db.CreateBucket(ctx, TestBucket, nil)
@@ -5,14 +5,21 @@ package miniogw
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 
 	"github.com/minio/cli"
 	minio "github.com/minio/minio/cmd"
 	"github.com/vivint/infectious"
+	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/miniogw/logging"
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
 	"storj.io/storj/pkg/provider"
@@ -21,6 +28,7 @@ import (
 	segment "storj.io/storj/pkg/storage/segments"
 	"storj.io/storj/pkg/storage/streams"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
 )
 
 // RSConfig is a configuration struct that keeps details about default
@@ -63,6 +71,13 @@ type ClientConfig struct {
 	SegmentSize   int64  `help:"the size of a segment in bytes" default:"64000000"`
 }
 
+// TenantConfig is a configuration struct for serving several tenants, each
+// with its own credentials, API key and encryption key, from one gateway
+type TenantConfig struct {
+	CredentialsPath string `help:"path to a JSON file with the credentials of the tenants. If set, the tenants are served instead of the Minio access key" default:""`
+	TenantCacheSize int    `help:"maximum number of tenants to keep bucket stores open for" default:"100"`
+}
+
 // Config is a general miniogw configuration struct. This should be everything
 // one needs to start a minio gateway.
 type Config struct {
@@ -71,6 +86,11 @@ type Config struct {
 	ClientConfig
 	RSConfig
 	EncryptionConfig
+	TenantConfig
+
+	// Notification emits bucket events from the gateway, for satellites
+	// that do not emit them
+	Notification notification.Config
 }
 
 // Run starts a Minio Gateway given proper config
@@ -82,11 +102,133 @@ func (c Config) Run(ctx context.Context) (err error) {
 		return err
 	}
 
+	if c.CredentialsPath != "" {
+		return c.runTenants(ctx, identity)
+	}
+
+	gw, err := c.NewGateway(ctx, identity)
+	if err != nil {
+		return err
+	}
+	err = c.startNotifier(ctx, gw)
+	if err != nil {
+		return err
+	}
+
+	// minio verifies the signed requests, the proxy forwards the anonymous
+	// reads of public buckets
+	minioAddr, err := freeLocalAddress()
+	if err != nil {
+		return err
+	}
+
+	proxy := NewSingleTenantProxy(&url.URL{Scheme: "http", Host: minioAddr}, c.AccessKey, c.SecretKey)
+	proxy.Tagging = gw.ObjectTagging()
+	proxy.Locking = gw.ObjectLocking()
+	proxy.Deleting = gw.ObjectDeleting()
+	return c.serve(ctx, proxy, minioAddr, c.AccessKey, c.SecretKey, gw)
+}
+
+// runTenants starts a Minio Gateway for the tenants in the credentials file
+// behind a TenantProxy, which authenticates the tenants
+func (c Config) runTenants(ctx context.Context, identity *provider.FullIdentity) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	creds, err := LoadCredentials(c.CredentialsPath)
+	if err != nil {
+		return err
+	}
+
+	// minio is only reachable through the proxy with random credentials
+	accessKey, err := randomKey()
+	if err != nil {
+		return err
+	}
+	secretKey, err := randomKey()
+	if err != nil {
+		return err
+	}
+
+	minioAddr, err := freeLocalAddress()
+	if err != nil {
+		return err
+	}
+
+	tenants, err := c.NewTenantStores(ctx, identity, creds)
+	if err != nil {
+		return err
+	}
+	go func() {
+		err := tenants.LoadPublicBuckets(ctx, creds.AccessKeys())
+		if err != nil {
+			zap.S().Errorf("failed to load the public buckets: %v", err)
+		}
+	}()
+
+	gw := NewMultiTenantGateway(tenants, storj.Cipher(c.PathEncType))
+	err = c.startNotifier(ctx, gw)
+	if err != nil {
+		return err
+	}
+
+	proxy := NewTenantProxy(creds, tenants.Public(), &url.URL{Scheme: "http", Host: minioAddr}, accessKey, secretKey)
+	proxy.Tagging = gw.ObjectTagging()
+	proxy.Locking = gw.ObjectLocking()
+	proxy.Deleting = gw.ObjectDeleting()
+	return c.serve(ctx, proxy, minioAddr, accessKey, secretKey, gw)
+}
+
+// startNotifier makes gw emit the events of the configured buckets. The
+// outbox stays open for as long as the gateway runs.
+func (c Config) startNotifier(ctx context.Context, gw *Storj) error {
+	notifier, _, err := c.Notification.NewNotifier()
+	if err != nil || notifier == nil {
+		return err
+	}
+	gw.notifier = notifier
+
+	go func() {
+		err := notifier.Run(ctx)
+		zap.S().Errorf("event notifications stopped: %v", err)
+	}()
+	return nil
+}
+
+// serve runs minio with gw behind proxy, which serves the address of the
+// gateway. It returns when either of them stops.
+func (c Config) serve(ctx context.Context, proxy *TenantProxy, minioAddr, accessKey, secretKey string, gw minio.Gateway) error {
+	lis, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lis.Close() }()
+
+	errch := make(chan error, 2)
+	go func() {
+		errch <- Error.New("gateway proxy stopped: %v", http.Serve(lis, proxy))
+	}()
+	go func() {
+		errch <- c.runMinio(ctx, minioAddr, accessKey, secretKey, func(ctx context.Context) (minio.Gateway, error) {
+			return gw, nil
+		})
+	}()
+
+	select {
+	case err = <-errch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runMinio runs minio on address with the gateway created by newGateway
+func (c Config) runMinio(ctx context.Context, address, accessKey, secretKey string,
+	newGateway func(context.Context) (minio.Gateway, error)) (err error) {
 	err = minio.RegisterGatewayCommand(cli.Command{
 		Name:  "storj",
 		Usage: "Storj",
 		Action: func(cliCtx *cli.Context) error {
-			return c.action(ctx, cliCtx, identity)
+			return c.action(ctx, cliCtx, newGateway)
 		},
 		HideHelpCommand: true,
 	})
@@ -95,24 +237,24 @@ func (c Config) Run(ctx context.Context) (err error) {
 	}
 
 	// TODO(jt): Surely there is a better way. This is so upsetting
-	err = os.Setenv("MINIO_ACCESS_KEY", c.AccessKey)
+	err = os.Setenv("MINIO_ACCESS_KEY", accessKey)
 	if err != nil {
 		return err
 	}
-	err = os.Setenv("MINIO_SECRET_KEY", c.SecretKey)
+	err = os.Setenv("MINIO_SECRET_KEY", secretKey)
 	if err != nil {
 		return err
 	}
 
 	minio.Main([]string{"storj", "gateway", "storj",
-		"--address", c.Address, "--config-dir", c.MinioDir, "--quiet"})
+		"--address", address, "--config-dir", c.MinioDir, "--quiet"})
 	return Error.New("unexpected minio exit")
 }
 
-func (c Config) action(ctx context.Context, cliCtx *cli.Context, identity *provider.FullIdentity) (err error) {
+func (c Config) action(ctx context.Context, cliCtx *cli.Context, newGateway func(context.Context) (minio.Gateway, error)) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	gw, err := c.NewGateway(ctx, identity)
+	gw, err := newGateway(ctx)
 	if err != nil {
 		return err
 	}
@@ -125,8 +267,20 @@ func (c Config) action(ctx context.Context, cliCtx *cli.Context, identity *provi
 func (c Config) GetBucketStore(ctx context.Context, identity *provider.FullIdentity) (bs buckets.Store, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var oc overlay.Client
-	oc, err = overlay.NewOverlayClient(identity, c.OverlayAddr)
+	segments, err := c.GetSegmentStore(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.NewBucketStore(segments, c.EncKey)
+}
+
+// GetSegmentStore returns the segments.Store that the bucket stores of all
+// encryption keys are built upon
+func (c Config) GetSegmentStore(ctx context.Context, identity *provider.FullIdentity) (ss segment.Store, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	oc, ec, rs, err := c.newStorageClients(identity)
 	if err != nil {
 		return nil, err
 	}
@@ -136,25 +290,39 @@ func (c Config) GetBucketStore(ctx context.Context, identity *provider.FullIdent
 		return nil, err
 	}
 
-	ec := ecclient.NewClient(identity, c.MaxBufferMem)
+	return segment.NewSegmentStore(oc, ec, pdb, rs, c.MaxInlineSize), nil
+}
+
+// newStorageClients creates the clients for storing segments, which do
+// not depend on the API key
+func (c Config) newStorageClients(identity *provider.FullIdentity) (oc overlay.Client, ec ecclient.Client, rs eestream.RedundancyStrategy, err error) {
+	oc, err = overlay.NewOverlayClient(identity, c.OverlayAddr)
+	if err != nil {
+		return nil, nil, rs, err
+	}
+
+	ec = ecclient.NewClient(identity, c.MaxBufferMem)
 	fc, err := infectious.NewFEC(c.MinThreshold, c.MaxThreshold)
 	if err != nil {
-		return nil, err
+		return nil, nil, rs, err
 	}
-	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, c.ErasureShareSize), c.RepairThreshold, c.SuccessThreshold)
+	rs, err = eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, c.ErasureShareSize), c.RepairThreshold, c.SuccessThreshold)
 	if err != nil {
-		return nil, err
+		return nil, nil, rs, err
 	}
 
-	segments := segment.NewSegmentStore(oc, ec, pdb, rs, c.MaxInlineSize)
+	return oc, ec, rs, nil
+}
 
+// NewBucketStore returns a buckets.Store that encrypts the data stored in
+// segments with encKey
+func (c Config) NewBucketStore(segments segment.Store, encKey string) (buckets.Store, error) {
 	if c.ErasureShareSize*c.MinThreshold%c.EncBlockSize != 0 {
-		err = Error.New("EncryptionBlockSize must be a multiple of ErasureShareSize * RS MinThreshold")
-		return nil, err
+		return nil, Error.New("EncryptionBlockSize must be a multiple of ErasureShareSize * RS MinThreshold")
 	}
 
 	key := new(storj.Key)
-	copy(key[:], c.EncKey)
+	copy(key[:], encKey)
 
 	stream, err := streams.NewStreamStore(segments, c.SegmentSize, key, c.EncBlockSize, storj.Cipher(c.EncType))
 	if err != nil {
@@ -165,7 +333,7 @@ func (c Config) GetBucketStore(ctx context.Context, identity *provider.FullIdent
 }
 
 // NewGateway creates a new minio Gateway
-func (c Config) NewGateway(ctx context.Context, identity *provider.FullIdentity) (gw minio.Gateway, err error) {
+func (c Config) NewGateway(ctx context.Context, identity *provider.FullIdentity) (gw *Storj, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	bs, err := c.GetBucketStore(ctx, identity)
@@ -175,3 +343,56 @@ func (c Config) NewGateway(ctx context.Context, identity *provider.FullIdentity)
 
 	return NewStorjGateway(bs, storj.Cipher(c.PathEncType)), nil
 }
+
+// NewTenantStores creates the bucket stores of the tenants in creds, with
+// their API key and encryption key. The gateway serving them gets the
+// requests through a TenantProxy.
+func (c Config) NewTenantStores(ctx context.Context, identity *provider.FullIdentity, creds CredentialStore) (tenants *TenantStores, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the tenants share the connections to the overlay and storage nodes
+	oc, ec, rs, err := c.newStorageClients(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	open := func(ctx context.Context, creds *Credentials) (buckets.Store, func() error, error) {
+		pdb, err := pdbclient.NewClient(identity, c.PointerDBAddr, creds.APIKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encKey := creds.EncKey
+		if encKey == "" {
+			encKey = c.EncKey
+		}
+
+		bs, err := c.NewBucketStore(segment.NewSegmentStore(oc, ec, pdb, rs, c.MaxInlineSize), encKey)
+		if err != nil {
+			return nil, nil, utils.CombineErrors(err, pdb.Disconnect())
+		}
+		return bs, pdb.Disconnect, nil
+	}
+
+	return NewTenantStores(creds, open, c.TenantCacheSize), nil
+}
+
+// randomKey returns a random key to use as minio credentials
+func randomKey() (string, error) {
+	var key [16]byte
+	_, err := rand.Read(key[:])
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key[:]), nil
+}
+
+// freeLocalAddress returns a loopback address with a free port
+func freeLocalAddress() (string, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := lis.Addr().String()
+	return addr, lis.Close()
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrUnknownAccessKey is returned by credential stores for access keys they
+// do not know
+var ErrUnknownAccessKey = errs.Class("unknown access key")
+
+// Credentials are the S3 credentials of a tenant of the gateway together
+// with the keys its data is stored with
+type Credentials struct {
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	// APIKey authenticates the tenant against the satellite
+	APIKey string `json:"api_key"`
+	// EncKey is the root key for encrypting the data of the tenant
+	EncKey string `json:"enc_key"`
+}
+
+// CredentialStore looks up the credentials of tenants
+type CredentialStore interface {
+	// Lookup returns the credentials for accessKey. It returns an
+	// ErrUnknownAccessKey error if there are none.
+	Lookup(ctx context.Context, accessKey string) (*Credentials, error)
+}
+
+// StaticCredentials is a CredentialStore of a fixed set of credentials
+type StaticCredentials map[string]Credentials
+
+// NewStaticCredentials creates a CredentialStore of creds
+func NewStaticCredentials(creds []Credentials) (StaticCredentials, error) {
+	store := make(StaticCredentials, len(creds))
+	for _, c := range creds {
+		if c.AccessKey == "" || c.SecretKey == "" {
+			return nil, Error.New("credentials need an access key and a secret key")
+		}
+		if _, ok := store[c.AccessKey]; ok {
+			return nil, Error.New("duplicate access key %q", c.AccessKey)
+		}
+		store[c.AccessKey] = c
+	}
+	return store, nil
+}
+
+// LoadCredentials reads the credentials of the tenants from a JSON file
+// with a list of Credentials
+func LoadCredentials(path string) (StaticCredentials, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var creds []Credentials
+	err = json.Unmarshal(data, &creds)
+	if err != nil {
+		return nil, Error.New("invalid credentials file %q: %v", path, err)
+	}
+
+	return NewStaticCredentials(creds)
+}
+
+// Lookup implements CredentialStore
+func (store StaticCredentials) Lookup(ctx context.Context, accessKey string) (*Credentials, error) {
+	c, ok := store[accessKey]
+	if !ok {
+		return nil, ErrUnknownAccessKey.New("%s", accessKey)
+	}
+	return &c, nil
+}
+
+// AccessKeys returns the access keys of the tenants in sorted order
+func (store StaticCredentials) AccessKeys() []string {
+	keys := make([]string, 0, len(store))
+	for key := range store {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/notification"
+	"storj.io/storj/pkg/storj"
+)
+
+// maxDeleteObjects is how many objects one request can delete
+const maxDeleteObjects = 1000
+
+// maxDeleteXMLSize is the largest multi-object delete document the proxy
+// reads, which is enough for maxDeleteObjects of the longest keys
+const maxDeleteXMLSize = 2 * 1024 * 1024
+
+// ObjectDeleting is the object layer for deleting several objects of a
+// bucket with one request to the satellite. Minio would delete them one
+// by one, each with its own requests for every segment.
+type ObjectDeleting interface {
+	// DeleteObjects deletes objects from bucket and returns the error for
+	// each of them, which is nil for the deleted ones
+	DeleteObjects(ctx context.Context, bucket string, objects []string) (errs []error, err error)
+}
+
+// ObjectDeleting returns the object layer for deleting several objects
+func (s *Storj) ObjectDeleting() ObjectDeleting {
+	return &storjObjects{storj: s}
+}
+
+func (s *storjObjects) DeleteObjects(ctx context.Context, bucket string, objects []string) (errs []error, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.release()
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return nil, convertBucketNotFoundError(err, bucket)
+	}
+
+	result, err := o.DeleteObjects(ctx, storj.DeleteOptions{Paths: objects})
+	if err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]bool, len(result.Locked))
+	for _, object := range result.Locked {
+		locked[object] = true
+	}
+
+	errs = make([]error, len(objects))
+	for i, object := range objects {
+		if locked[object] {
+			errs[i] = storj.ErrObjectLocked.New("%s", object)
+			continue
+		}
+		// objects that did not exist count as deleted, like in S3
+		s.storj.notify(ctx, notification.ObjectDeleted, bucket, object, 0)
+	}
+	return errs, nil
+}
+
+// deleteRequest is the XML document of a multi-object delete request
+type deleteRequest struct {
+	XMLName xml.Name `xml:"Delete"`
+	Quiet   bool     `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+// deleteResult is the XML document of the response to a multi-object
+// delete request
+type deleteResult struct {
+	XMLName xml.Name        `xml:"DeleteResult"`
+	Xmlns   string          `xml:"xmlns,attr,omitempty"`
+	Deleted []deletedObject `xml:"Deleted"`
+	Errors  []deleteError   `xml:"Error"`
+}
+
+type deletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type deleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// isMultiObjectDelete returns whether r deletes several objects of a
+// bucket
+func isMultiObjectDelete(r *http.Request) bool {
+	bucket, object := splitPath(r.URL.Path)
+	_, ok := r.URL.Query()["delete"]
+	return ok && r.Method == http.MethodPost && bucket != "" && object == ""
+}
+
+// serveDeleting serves a multi-object delete with p.Deleting
+func (p *TenantProxy) serveDeleting(w http.ResponseWriter, r *http.Request) {
+	err := p.deleting(w, r)
+	if err != nil {
+		zap.S().Debugf("multi-object delete %s failed: %v", r.URL.Path, err)
+		writeS3Error(w, r, err)
+	}
+}
+
+func (p *TenantProxy) deleting(w http.ResponseWriter, r *http.Request) error {
+	ctx, out, err := p.objectLayerContext(r)
+	if err != nil {
+		return err
+	}
+
+	data, err := readBody(out, maxDeleteXMLSize)
+	if err != nil {
+		return err
+	}
+	contentMD5 := out.Header.Get("Content-MD5")
+	if contentMD5 == "" {
+		return errMissingContentMD5
+	}
+	sum := md5.Sum(data)
+	if contentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+		return errBadDigest
+	}
+
+	var doc deleteRequest
+	if xml.Unmarshal(data, &doc) != nil {
+		return errMalformedXML
+	}
+	if len(doc.Objects) == 0 || len(doc.Objects) > maxDeleteObjects {
+		return errMalformedXML
+	}
+
+	objects := make([]string, len(doc.Objects))
+	for i, object := range doc.Objects {
+		if object.Key == "" {
+			return errMalformedXML
+		}
+		objects[i] = object.Key
+	}
+
+	bucket, _ := splitPath(out.URL.Path)
+	errs, err := p.Deleting.DeleteObjects(ctx, bucket, objects)
+	if err != nil {
+		return err
+	}
+
+	result := deleteResult{Xmlns: s3Namespace}
+	for i, object := range objects {
+		if errs[i] != nil {
+			_, code, message := s3ErrorCode(errs[i])
+			result.Errors = append(result.Errors, deleteError{Key: object, Code: code, Message: message})
+			continue
+		}
+		if !doc.Quiet {
+			result.Deleted = append(result.Deleted, deletedObject{Key: object})
+		}
+	}
+	return writeXML(w, result)
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/minio/minio-go/pkg/s3signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestProxyDeleting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+
+	// minio does not serve the multi-object deletes
+	target, err := url.Parse("http://127.0.0.1:1")
+	require.NoError(t, err)
+	handler := NewSingleTenantProxy(target, "minio-access", "minio-secret")
+	handler.Deleting = NewStorjGateway(mockBS, storj.Unencrypted).ObjectDeleting()
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	send := func(body string, md5sum bool) (int, string) {
+		req, err := http.NewRequest("POST", proxy.URL+"/bucket?delete", strings.NewReader(body))
+		require.NoError(t, err)
+		if md5sum {
+			sum := md5.Sum([]byte(body))
+			req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		}
+		req.Header.Set("X-Amz-Content-Sha256", sha256Hex([]byte(body)))
+		req = s3signer.SignV4(*req, "minio-access", "minio-secret", "", "us-east-1")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, string(data)
+	}
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil).AnyTimes()
+	mockOS.EXPECT().DeleteObjects(gomock.Any(), storj.DeleteOptions{Paths: []storj.Path{"a", "b/c", "locked"}}).
+		Return(storj.DeleteResult{Deleted: 1, Locked: []storj.Path{"locked"}}, nil)
+	status, body := send(`<Delete><Object><Key>a</Key></Object><Object><Key>b/c</Key></Object>`+
+		`<Object><Key>locked</Key></Object></Delete>`, true)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<DeleteResult xmlns="`+s3Namespace+`">`+
+		`<Deleted><Key>a</Key></Deleted><Deleted><Key>b/c</Key></Deleted>`+
+		`<Error><Key>locked</Key><Code>AccessDenied</Code><Message>Access Denied.</Message></Error></DeleteResult>`)
+
+	mockOS.EXPECT().DeleteObjects(gomock.Any(), storj.DeleteOptions{Paths: []storj.Path{"a"}}).
+		Return(storj.DeleteResult{Deleted: 1}, nil)
+	status, body = send(`<Delete><Quiet>true</Quiet><Object><Key>a</Key></Object></Delete>`, true)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<DeleteResult xmlns="`+s3Namespace+`"></DeleteResult>`)
+
+	status, body = send(`<Delete><Object><Key>a</Key></Object></Delete>`, false)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "InvalidRequest")
+
+	status, body = send(`<Delete></Delete>`, true)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "MalformedXML")
+}
@@ -13,12 +13,15 @@ import (
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/hash"
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/buckets"
 	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/utils"
 )
@@ -31,7 +34,14 @@ var (
 
 // NewStorjGateway creates a *Storj object from an existing ObjectStore
 func NewStorjGateway(bs buckets.Store, pathCipher storj.Cipher) *Storj {
-	return &Storj{bs: bs, pathCipher: pathCipher, multipart: NewMultipartUploads()}
+	return &Storj{bs: bs, pathCipher: pathCipher, multipart: NewMultipartUploads(), usage: new(usageCache)}
+}
+
+// NewMultiTenantGateway creates a *Storj object serving each request from
+// the bucket store of its tenant. The requests must come through a
+// TenantProxy.
+func NewMultiTenantGateway(tenants *TenantStores, pathCipher storj.Cipher) *Storj {
+	return &Storj{pathCipher: pathCipher, tenants: tenants}
 }
 
 //Storj is the implementation of a minio cmd.Gateway
@@ -39,6 +49,57 @@ type Storj struct {
 	bs         buckets.Store
 	pathCipher storj.Cipher
 	multipart  *MultipartUploads
+	usage      *usageCache
+	tenants    *TenantStores
+	notifier   *notification.Notifier
+}
+
+// tenant returns the tenant of the request that ctx belongs to. A gateway
+// without tenants serves all requests from the same store. The tenant must
+// be released when the request is done with it. Anonymous requests are
+// refused.
+func (s *Storj) tenant(ctx context.Context) (*tenant, error) {
+	t, anonymous, err := s.requestTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if anonymous {
+		t.release()
+		return nil, minio.PrefixAccessDenied{}
+	}
+	return t, nil
+}
+
+// readTenant returns the tenant of a request that reads from bucket.
+// Anonymous requests may read from public buckets.
+func (s *Storj) readTenant(ctx context.Context, bucket string) (*tenant, error) {
+	t, anonymous, err := s.requestTenant(ctx)
+	if err != nil || !anonymous {
+		return t, err
+	}
+
+	meta, err := t.store.Get(ctx, bucket)
+	if err == nil && !meta.PublicRead {
+		err = minio.PrefixAccessDenied{Bucket: bucket}
+	}
+	if err != nil {
+		t.release()
+		return nil, convertBucketNotFoundError(err, bucket)
+	}
+	return t, nil
+}
+
+func (s *Storj) requestTenant(ctx context.Context) (t *tenant, anonymous bool, err error) {
+	accessKey, anonymous, ok := tenantFromContext(ctx)
+	if s.tenants == nil {
+		return &tenant{store: s.bs, multipart: s.multipart, usage: s.usage}, anonymous, nil
+	}
+	if !ok {
+		return nil, false, Error.New("request without tenant")
+	}
+
+	t, err = s.tenants.acquire(ctx, accessKey)
+	return t, anonymous, err
 }
 
 // Name implements cmd.Gateway
@@ -52,6 +113,12 @@ func (s *Storj) NewGatewayLayer(creds auth.Credentials) (
 	return &storjObjects{storj: s}, nil
 }
 
+// ObjectTagging returns the object layer for the tagging of objects, which
+// minio does not serve
+func (s *Storj) ObjectTagging() ObjectTagging {
+	return &storjObjects{storj: s}
+}
+
 // Production implements cmd.Gateway
 func (s *Storj) Production() bool {
 	return false
@@ -65,7 +132,13 @@ type storjObjects struct {
 func (s *storjObjects) DeleteBucket(ctx context.Context, bucket string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return convertBucketNotFoundError(err, bucket)
 	}
@@ -79,7 +152,10 @@ func (s *storjObjects) DeleteBucket(ctx context.Context, bucket string) (err err
 		return minio.BucketNotEmpty{Bucket: bucket}
 	}
 
-	err = s.storj.bs.Delete(ctx, bucket)
+	err = t.store.Delete(ctx, bucket)
+	if err == nil && s.storj.tenants != nil {
+		s.storj.tenants.public.unclaim(bucket, t.accessKey)
+	}
 
 	return convertBucketNotFoundError(err, bucket)
 }
@@ -87,12 +163,22 @@ func (s *storjObjects) DeleteBucket(ctx context.Context, bucket string) (err err
 func (s *storjObjects) DeleteObject(ctx context.Context, bucket, object string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return convertBucketNotFoundError(err, bucket)
 	}
 
 	err = o.Delete(ctx, object)
+	if err == nil {
+		s.storj.notify(ctx, notification.ObjectDeleted, bucket, object, 0)
+	}
+	err = convertObjectLockedError(err, bucket, object)
 
 	return convertObjectNotFoundError(err, bucket, object)
 }
@@ -100,7 +186,13 @@ func (s *storjObjects) DeleteObject(ctx context.Context, bucket, object string)
 func (s *storjObjects) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo minio.BucketInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	meta, err := s.storj.bs.Get(ctx, bucket)
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.BucketInfo{}, err
+	}
+	defer t.release()
+
+	meta, err := t.store.Get(ctx, bucket)
 
 	if err != nil {
 		return minio.BucketInfo{}, convertBucketNotFoundError(err, bucket)
@@ -109,23 +201,29 @@ func (s *storjObjects) GetBucketInfo(ctx context.Context, bucket string) (bucket
 	return minio.BucketInfo{Name: bucket, Created: meta.Created}, nil
 }
 
-func (s *storjObjects) getObject(ctx context.Context, bucket, object string) (rr ranger.Ranger, err error) {
+func (s *storjObjects) getObject(ctx context.Context, bs buckets.Store, bucket, object string) (rr ranger.Ranger, meta objects.Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	o, err := bs.GetObjectStore(ctx, bucket)
 	if err != nil {
-		return nil, convertBucketNotFoundError(err, bucket)
+		return nil, objects.Meta{}, convertBucketNotFoundError(err, bucket)
 	}
 
-	rr, _, err = o.Get(ctx, object)
+	rr, meta, err = o.Get(ctx, object)
 
-	return rr, convertObjectNotFoundError(err, bucket, object)
+	return rr, meta, convertObjectNotFoundError(err, bucket, object)
 }
 
 func (s *storjObjects) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	rr, err := s.getObject(ctx, bucket, object)
+	t, err := s.storj.readTenant(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	rr, _, err := s.getObject(ctx, t.store, bucket, object)
 	if err != nil {
 		return err
 	}
@@ -148,7 +246,13 @@ func (s *storjObjects) GetObject(ctx context.Context, bucket, object string, sta
 func (s *storjObjects) GetObjectInfo(ctx context.Context, bucket, object string) (objInfo minio.ObjectInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	t, err := s.storj.readTenant(ctx, bucket)
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+	defer t.release()
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return minio.ObjectInfo{}, convertBucketNotFoundError(err, bucket)
 	}
@@ -158,25 +262,23 @@ func (s *storjObjects) GetObjectInfo(ctx context.Context, bucket, object string)
 		return objInfo, convertObjectNotFoundError(err, bucket, object)
 	}
 
-	return minio.ObjectInfo{
-		Name:        object,
-		Bucket:      bucket,
-		ModTime:     m.Modified,
-		Size:        m.Size,
-		ETag:        m.Checksum,
-		ContentType: m.ContentType,
-		UserDefined: m.UserDefined,
-	}, err
+	return objectInfo(bucket, object, m), err
 }
 
 func (s *storjObjects) ListBuckets(ctx context.Context) (bucketItems []minio.BucketInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.release()
+
 	startAfter := ""
 	var items []buckets.ListItem
 
 	for {
-		moreItems, more, err := s.storj.bs.List(ctx, startAfter, "", 0)
+		moreItems, more, err := t.store.List(ctx, startAfter, "", 0)
 		if err != nil {
 			return nil, err
 		}
@@ -206,9 +308,15 @@ func (s *storjObjects) ListObjects(ctx context.Context, bucket, prefix, marker,
 	startAfter := marker
 	recursive := delimiter == ""
 
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.ListObjectsInfo{}, err
+	}
+	defer t.release()
+
 	var objects []minio.ObjectInfo
 	var prefixes []string
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	o, err := t.store.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return minio.ListObjectsInfo{}, convertBucketNotFoundError(err, bucket)
 	}
@@ -226,16 +334,7 @@ func (s *storjObjects) ListObjects(ctx context.Context, bucket, prefix, marker,
 				prefixes = append(prefixes, path)
 				continue
 			}
-			objects = append(objects, minio.ObjectInfo{
-				Bucket:      bucket,
-				IsDir:       false,
-				Name:        path,
-				ModTime:     item.Meta.Modified,
-				Size:        item.Meta.Size,
-				ContentType: item.Meta.ContentType,
-				UserDefined: item.Meta.UserDefined,
-				ETag:        item.Meta.Checksum,
-			})
+			objects = append(objects, objectInfo(bucket, path, item.Meta))
 		}
 		startAfter = items[len(items)-1].Path
 	}
@@ -271,9 +370,15 @@ func (s *storjObjects) ListObjectsV2(ctx context.Context, bucket, prefix, contin
 		startAfterPath = startAfter
 	}
 
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.ListObjectsV2Info{ContinuationToken: continuationToken}, err
+	}
+	defer t.release()
+
 	var objects []minio.ObjectInfo
 	var prefixes []string
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	o, err := t.store.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return minio.ListObjectsV2Info{ContinuationToken: continuationToken}, convertBucketNotFoundError(err, bucket)
 	}
@@ -292,16 +397,7 @@ func (s *storjObjects) ListObjectsV2(ctx context.Context, bucket, prefix, contin
 				prefixes = append(prefixes, path)
 				continue
 			}
-			objects = append(objects, minio.ObjectInfo{
-				Bucket:      bucket,
-				IsDir:       false,
-				Name:        path,
-				ModTime:     item.Meta.Modified,
-				Size:        item.Meta.Size,
-				ContentType: item.Meta.ContentType,
-				UserDefined: item.Meta.UserDefined,
-				ETag:        item.Meta.Checksum,
-			})
+			objects = append(objects, objectInfo(bucket, path, item.Meta))
 		}
 
 		nextContinuationToken = items[len(items)-1].Path + "\x00"
@@ -329,21 +425,49 @@ func (s *storjObjects) MakeBucketWithLocation(ctx context.Context, bucket string
 	// therefore try to Put a bucket at the same time.
 	// The reason for the Get call to check if the
 	// bucket already exists is to match S3 CLI behavior.
-	_, err = s.storj.bs.Get(ctx, bucket)
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	_, err = t.store.Get(ctx, bucket)
 	if err == nil {
 		return minio.BucketAlreadyExists{Bucket: bucket}
 	}
 	if !storj.ErrBucketNotFound.Has(err) {
 		return err
 	}
-	_, err = s.storj.bs.Put(ctx, bucket, s.storj.pathCipher)
+	_, err = t.store.Put(ctx, bucket, s.storj.pathCipher)
 	return err
 }
 
 func (s *storjObjects) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo minio.ObjectInfo) (objInfo minio.ObjectInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	rr, err := s.getObject(ctx, srcBucket, srcObject)
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return objInfo, err
+	}
+	defer t.release()
+
+	serMetaInfo := serializeMeta(srcInfo.UserDefined)
+	if serMetaInfo.ContentType == "" {
+		serMetaInfo.ContentType = srcInfo.ContentType
+	}
+	if serMetaInfo.ContentEncoding == "" {
+		serMetaInfo.ContentEncoding = srcInfo.ContentEncoding
+	}
+
+	// copying an object onto itself only replaces its metadata
+	if srcBucket == destBucket && srcObject == destObject {
+		return s.updateMeta(ctx, t.store, destBucket, destObject, func(meta *pb.SerializableMeta) {
+			serMetaInfo.Tags = meta.Tags
+			*meta = serMetaInfo
+		})
+	}
+
+	rr, srcMeta, err := s.getObject(ctx, t.store, srcBucket, srcObject)
 	if err != nil {
 		return objInfo, err
 	}
@@ -355,47 +479,39 @@ func (s *storjObjects) CopyObject(ctx context.Context, srcBucket, srcObject, des
 
 	defer utils.LogClose(r)
 
-	serMetaInfo := pb.SerializableMeta{
-		ContentType: srcInfo.ContentType,
-		UserDefined: srcInfo.UserDefined,
-	}
+	// the tags are copied along with the object
+	serMetaInfo.Tags = srcMeta.Tags
 
-	return s.putObject(ctx, destBucket, destObject, r, serMetaInfo)
+	return s.putObject(ctx, t.store, destBucket, destObject, r, serMetaInfo)
 }
 
-func (s *storjObjects) putObject(ctx context.Context, bucket, object string, r io.Reader, meta pb.SerializableMeta) (objInfo minio.ObjectInfo, err error) {
+func (s *storjObjects) putObject(ctx context.Context, bs buckets.Store, bucket, object string, r io.Reader, meta pb.SerializableMeta) (objInfo minio.ObjectInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	// setting zero value means the object never expires
 	expTime := time.Time{}
-	o, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	o, err := bs.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return minio.ObjectInfo{}, convertBucketNotFoundError(err, bucket)
 	}
 	m, err := o.Put(ctx, object, r, meta, expTime)
-	return minio.ObjectInfo{
-		Name:        object,
-		Bucket:      bucket,
-		ModTime:     m.Modified,
-		Size:        m.Size,
-		ETag:        m.Checksum,
-		ContentType: m.ContentType,
-		UserDefined: m.UserDefined,
-	}, err
+	if err == nil {
+		s.storj.notify(ctx, notification.ObjectCreated, bucket, object, m.Size)
+	}
+	return objectInfo(bucket, object, m), convertObjectLockedError(err, bucket, object)
 }
 
 func (s *storjObjects) PutObject(ctx context.Context, bucket, object string, data *hash.Reader, metadata map[string]string) (objInfo minio.ObjectInfo, err error) {
 
 	defer mon.Task()(&ctx)(&err)
-	tempContType := metadata["content-type"]
-	delete(metadata, "content-type")
-	//metadata serialized
-	serMetaInfo := pb.SerializableMeta{
-		ContentType: tempContType,
-		UserDefined: metadata,
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.ObjectInfo{}, err
 	}
+	defer t.release()
 
-	return s.putObject(ctx, bucket, object, data, serMetaInfo)
+	return s.putObject(ctx, t.store, bucket, object, data, serializeMeta(metadata))
 }
 
 func (s *storjObjects) Shutdown(ctx context.Context) (err error) {
@@ -403,8 +519,12 @@ func (s *storjObjects) Shutdown(ctx context.Context) (err error) {
 	return nil
 }
 
-func (s *storjObjects) StorageInfo(context.Context) minio.StorageInfo {
-	return minio.StorageInfo{}
+// notify emits an event for the object if there is a notifier
+func (s *Storj) notify(ctx context.Context, eventType notification.EventType, bucket, object string, size int64) {
+	err := s.notifier.Notify(ctx, eventType, bucket, object, size)
+	if err != nil {
+		zap.S().Errorf("failed to emit %s event for %s/%s: %v", eventType, bucket, object, err)
+	}
 }
 
 func convertBucketNotFoundError(err error, bucket string) error {
@@ -420,3 +540,10 @@ func convertObjectNotFoundError(err error, bucket, object string) error {
 	}
 	return err
 }
+
+func convertObjectLockedError(err error, bucket, object string) error {
+	if storj.ErrObjectLocked.Has(err) {
+		return minio.PrefixAccessDenied{Bucket: bucket, Object: object}
+	}
+	return err
+}
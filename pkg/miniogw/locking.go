@@ -0,0 +1,366 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+)
+
+// The values of the object lock documents of S3
+const (
+	objectLockEnabled = "Enabled"
+	complianceMode    = "COMPLIANCE"
+	legalHoldOn       = "ON"
+	legalHoldOff      = "OFF"
+)
+
+// ErrInvalidRetention is returned for object lock settings the gateway does
+// not allow
+var ErrInvalidRetention = errs.Class("invalid retention")
+
+var (
+	// errNoObjectLock is returned for locking objects in a bucket without
+	// object lock
+	errNoObjectLock = Error.New("object lock is not enabled for the bucket")
+	// errNoLockConfiguration is returned for the lock configuration of a
+	// bucket without object lock
+	errNoLockConfiguration = Error.New("object lock configuration not found")
+	// errNoRetention is returned for the retention of an object without
+	// one
+	errNoRetention = Error.New("object has no retention")
+)
+
+// ObjectLocking is the object layer for the object lock of buckets and
+// objects, which minio does not support. Locked objects cannot be
+// overwritten or deleted. There is only the compliance mode, so no one can
+// shorten the retention of an object.
+type ObjectLocking interface {
+	GetObjectLockEnabled(ctx context.Context, bucket string) (enabled bool, err error)
+	EnableObjectLock(ctx context.Context, bucket string) error
+	GetObjectRetention(ctx context.Context, bucket, object string) (retainUntil time.Time, err error)
+	PutObjectRetention(ctx context.Context, bucket, object string, retainUntil time.Time) error
+	GetObjectLegalHold(ctx context.Context, bucket, object string) (legalHold bool, err error)
+	PutObjectLegalHold(ctx context.Context, bucket, object string, legalHold bool) error
+}
+
+// ObjectLocking returns the object layer for the object lock
+func (s *Storj) ObjectLocking() ObjectLocking {
+	return &storjObjects{storj: s}
+}
+
+func (s *storjObjects) GetObjectLockEnabled(ctx context.Context, bucket string) (enabled bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer t.release()
+
+	meta, err := t.store.Get(ctx, bucket)
+	if err != nil {
+		return false, convertBucketNotFoundError(err, bucket)
+	}
+	return meta.ObjectLock, nil
+}
+
+func (s *storjObjects) EnableObjectLock(ctx context.Context, bucket string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	meta, err := t.store.Get(ctx, bucket)
+	if err != nil {
+		return convertBucketNotFoundError(err, bucket)
+	}
+	if meta.ObjectLock {
+		return nil
+	}
+
+	meta.ObjectLock = true
+	_, err = t.store.Update(ctx, bucket, meta)
+	return convertBucketNotFoundError(err, bucket)
+}
+
+func (s *storjObjects) GetObjectRetention(ctx context.Context, bucket, object string) (retainUntil time.Time, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lock, err := s.getLock(ctx, bucket, object)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if lock.RetainUntil.IsZero() {
+		return time.Time{}, errNoRetention
+	}
+	return lock.RetainUntil, nil
+}
+
+func (s *storjObjects) PutObjectRetention(ctx context.Context, bucket, object string, retainUntil time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !retainUntil.After(time.Now()) {
+		return ErrInvalidRetention.New("the retain until date must be in the future")
+	}
+	return s.setLock(ctx, bucket, object, func(lock *storj.ObjectLock) {
+		lock.RetainUntil = retainUntil
+	})
+}
+
+func (s *storjObjects) GetObjectLegalHold(ctx context.Context, bucket, object string) (legalHold bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lock, err := s.getLock(ctx, bucket, object)
+	if err != nil {
+		return false, err
+	}
+	return lock.LegalHold, nil
+}
+
+func (s *storjObjects) PutObjectLegalHold(ctx context.Context, bucket, object string, legalHold bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return s.setLock(ctx, bucket, object, func(lock *storj.ObjectLock) {
+		lock.LegalHold = legalHold
+	})
+}
+
+// getLock returns the lock of an object
+func (s *storjObjects) getLock(ctx context.Context, bucket, object string) (lock storj.ObjectLock, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return lock, err
+	}
+	defer t.release()
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return lock, convertBucketNotFoundError(err, bucket)
+	}
+
+	m, err := o.Meta(ctx, object)
+	if err != nil {
+		return lock, convertObjectNotFoundError(err, bucket, object)
+	}
+	return m.Lock, nil
+}
+
+// setLock changes the lock of an object in a bucket with object lock with
+// update
+func (s *storjObjects) setLock(ctx context.Context, bucket, object string, update func(lock *storj.ObjectLock)) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	meta, err := t.store.Get(ctx, bucket)
+	if err != nil {
+		return convertBucketNotFoundError(err, bucket)
+	}
+	if !meta.ObjectLock {
+		return errNoObjectLock
+	}
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return convertBucketNotFoundError(err, bucket)
+	}
+
+	var m objects.Meta
+	m, err = o.Meta(ctx, object)
+	if err != nil {
+		return convertObjectNotFoundError(err, bucket, object)
+	}
+
+	update(&m.Lock)
+	_, err = o.SetLock(ctx, object, m.Lock)
+	err = convertObjectLockedError(err, bucket, object)
+	return convertObjectNotFoundError(err, bucket, object)
+}
+
+// objectLockConfiguration is the XML document of the object lock of a
+// bucket
+type objectLockConfiguration struct {
+	XMLName           xml.Name  `xml:"ObjectLockConfiguration"`
+	Xmlns             string    `xml:"xmlns,attr,omitempty"`
+	ObjectLockEnabled string    `xml:"ObjectLockEnabled"`
+	Rule              *struct{} `xml:"Rule,omitempty"`
+}
+
+// retention is the XML document of the retention of an object
+type retention struct {
+	XMLName         xml.Name `xml:"Retention"`
+	Xmlns           string   `xml:"xmlns,attr,omitempty"`
+	Mode            string   `xml:"Mode"`
+	RetainUntilDate string   `xml:"RetainUntilDate"`
+}
+
+// legalHold is the XML document of the legal hold of an object
+type legalHold struct {
+	XMLName xml.Name `xml:"LegalHold"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Status  string   `xml:"Status"`
+}
+
+// isObjectLocking returns whether r is for the object lock of a bucket or
+// the retention or legal hold of an object
+func isObjectLocking(r *http.Request) bool {
+	query := r.URL.Query()
+	bucket, object := splitPath(r.URL.Path)
+	if bucket == "" {
+		return false
+	}
+	if object == "" {
+		_, ok := query["object-lock"]
+		return ok
+	}
+	_, retention := query["retention"]
+	_, legalHold := query["legal-hold"]
+	return retention || legalHold
+}
+
+// serveLocking serves a request for an object lock with p.Locking
+func (p *TenantProxy) serveLocking(w http.ResponseWriter, r *http.Request) {
+	err := p.locking(w, r)
+	if err != nil {
+		zap.S().Debugf("object lock %s %s failed: %v", r.Method, r.URL.Path, err)
+		writeS3Error(w, r, err)
+	}
+}
+
+func (p *TenantProxy) locking(w http.ResponseWriter, r *http.Request) error {
+	ctx, out, err := p.objectLayerContext(r)
+	if err != nil {
+		return err
+	}
+
+	bucket, object := splitPath(r.URL.Path)
+	_, retention := r.URL.Query()["retention"]
+	switch {
+	case object == "":
+		return p.bucketLock(ctx, w, out, bucket)
+	case retention:
+		return p.objectRetention(ctx, w, out, bucket, object)
+	}
+	return p.objectLegalHold(ctx, w, out, bucket, object)
+}
+
+func (p *TenantProxy) bucketLock(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket string) error {
+	switch r.Method {
+	case http.MethodGet:
+		enabled, err := p.Locking.GetObjectLockEnabled(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			return errNoLockConfiguration
+		}
+		return writeXML(w, objectLockConfiguration{Xmlns: s3Namespace, ObjectLockEnabled: objectLockEnabled})
+
+	case http.MethodPut:
+		var doc objectLockConfiguration
+		err := readXML(r, &doc)
+		if err != nil {
+			return err
+		}
+		if doc.ObjectLockEnabled != objectLockEnabled {
+			return errMalformedXML
+		}
+		if doc.Rule != nil {
+			return ErrInvalidRetention.New("default retention is not supported")
+		}
+		err = p.Locking.EnableObjectLock(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	return errMethodNotAllowed
+}
+
+func (p *TenantProxy) objectRetention(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket, object string) error {
+	switch r.Method {
+	case http.MethodGet:
+		retainUntil, err := p.Locking.GetObjectRetention(ctx, bucket, object)
+		if err != nil {
+			return err
+		}
+		return writeXML(w, retention{
+			Xmlns:           s3Namespace,
+			Mode:            complianceMode,
+			RetainUntilDate: retainUntil.UTC().Format(time.RFC3339),
+		})
+
+	case http.MethodPut:
+		var doc retention
+		err := readXML(r, &doc)
+		if err != nil {
+			return err
+		}
+		if doc.Mode != complianceMode {
+			return ErrInvalidRetention.New("only the %s mode is supported", complianceMode)
+		}
+		retainUntil, err := time.Parse(time.RFC3339, doc.RetainUntilDate)
+		if err != nil {
+			return errMalformedXML
+		}
+		err = p.Locking.PutObjectRetention(ctx, bucket, object, retainUntil)
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	return errMethodNotAllowed
+}
+
+func (p *TenantProxy) objectLegalHold(ctx context.Context, w http.ResponseWriter, r *http.Request, bucket, object string) error {
+	switch r.Method {
+	case http.MethodGet:
+		hold, err := p.Locking.GetObjectLegalHold(ctx, bucket, object)
+		if err != nil {
+			return err
+		}
+		status := legalHoldOff
+		if hold {
+			status = legalHoldOn
+		}
+		return writeXML(w, legalHold{Xmlns: s3Namespace, Status: status})
+
+	case http.MethodPut:
+		var doc legalHold
+		err := readXML(r, &doc)
+		if err != nil {
+			return err
+		}
+		if doc.Status != legalHoldOn && doc.Status != legalHoldOff {
+			return errMalformedXML
+		}
+		err = p.Locking.PutObjectLegalHold(ctx, bucket, object, doc.Status == legalHoldOn)
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	return errMethodNotAllowed
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/minio/minio-go/pkg/s3signer"
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestObjectLocking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	// object lock can only be enabled
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{PublicRead: true}, nil)
+	mockBS.EXPECT().Update(gomock.Any(), "bucket", buckets.Meta{PublicRead: true, ObjectLock: true}).Return(buckets.Meta{}, nil)
+	assert.NoError(t, storjObj.EnableObjectLock(ctx, "bucket"))
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{ObjectLock: true}, nil)
+	assert.NoError(t, storjObj.EnableObjectLock(ctx, "bucket"))
+
+	// objects are locked only in buckets with object lock
+	retainUntil := time.Now().Add(time.Hour)
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, nil)
+	assert.Equal(t, errNoObjectLock, storjObj.PutObjectRetention(ctx, "bucket", "object", retainUntil))
+
+	err := storjObj.PutObjectRetention(ctx, "bucket", "object", time.Now().Add(-time.Hour))
+	assert.True(t, ErrInvalidRetention.Has(err))
+
+	// the retention and the legal hold are set separately
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{ObjectLock: true}, nil).Times(2)
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil).Times(2)
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{Lock: storj.ObjectLock{LegalHold: true}}, nil)
+	mockOS.EXPECT().SetLock(gomock.Any(), "object", storj.ObjectLock{RetainUntil: retainUntil, LegalHold: true}).Return(objects.Meta{}, nil)
+	assert.NoError(t, storjObj.PutObjectRetention(ctx, "bucket", "object", retainUntil))
+
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{Lock: storj.ObjectLock{RetainUntil: retainUntil, LegalHold: true}}, nil)
+	mockOS.EXPECT().SetLock(gomock.Any(), "object", storj.ObjectLock{RetainUntil: retainUntil}).
+		Return(objects.Meta{}, storj.ErrObjectLocked.New("retention cannot be shortened"))
+	err = storjObj.PutObjectLegalHold(ctx, "bucket", "object", false)
+	assert.Equal(t, minio.PrefixAccessDenied{Bucket: "bucket", Object: "object"}, err)
+
+	// locked objects cannot be deleted
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil)
+	mockOS.EXPECT().Delete(gomock.Any(), "object").Return(storj.ErrObjectLocked.New("l/bucket/object"))
+	err = storjObj.DeleteObject(ctx, "bucket", "object")
+	assert.Equal(t, minio.PrefixAccessDenied{Bucket: "bucket", Object: "object"}, err)
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil)
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{}, nil)
+	_, err = storjObj.GetObjectRetention(ctx, "bucket", "object")
+	assert.Equal(t, errNoRetention, err)
+}
+
+func TestProxyLocking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+
+	// minio does not serve the object lock requests
+	target, err := url.Parse("http://127.0.0.1:1")
+	require.NoError(t, err)
+	handler := NewSingleTenantProxy(target, "minio-access", "minio-secret")
+	handler.Locking = NewStorjGateway(mockBS, storj.Unencrypted).ObjectLocking()
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	send := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, proxy.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("X-Amz-Content-Sha256", sha256Hex([]byte(body)))
+		req = s3signer.SignV4(*req, "minio-access", "minio-secret", "", "us-east-1")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, string(data)
+	}
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, nil)
+	status, body := send("GET", "/bucket?object-lock", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "ObjectLockConfigurationNotFoundError")
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, nil)
+	mockBS.EXPECT().Update(gomock.Any(), "bucket", buckets.Meta{ObjectLock: true}).Return(buckets.Meta{ObjectLock: true}, nil)
+	status, _ = send("PUT", "/bucket?object-lock", `<ObjectLockConfiguration>`+
+		`<ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>`)
+	assert.Equal(t, http.StatusOK, status)
+
+	retainUntil := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{ObjectLock: true}, nil).AnyTimes()
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil).AnyTimes()
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{}, nil)
+	mockOS.EXPECT().SetLock(gomock.Any(), "object", storj.ObjectLock{RetainUntil: retainUntil}).Return(objects.Meta{}, nil)
+	status, _ = send("PUT", "/bucket/object?retention", `<Retention><Mode>COMPLIANCE</Mode>`+
+		`<RetainUntilDate>2100-01-01T00:00:00Z</RetainUntilDate></Retention>`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body = send("PUT", "/bucket/object?retention", `<Retention><Mode>GOVERNANCE</Mode>`+
+		`<RetainUntilDate>2100-01-01T00:00:00Z</RetainUntilDate></Retention>`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "InvalidRequest")
+
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{Lock: storj.ObjectLock{RetainUntil: retainUntil}}, nil).Times(2)
+	status, body = send("GET", "/bucket/object?retention", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<Retention xmlns="`+s3Namespace+`"><Mode>COMPLIANCE</Mode>`+
+		`<RetainUntilDate>2100-01-01T00:00:00Z</RetainUntilDate></Retention>`)
+
+	mockOS.EXPECT().SetLock(gomock.Any(), "object", storj.ObjectLock{RetainUntil: retainUntil, LegalHold: true}).Return(objects.Meta{}, nil)
+	status, _ = send("PUT", "/bucket/object?legal-hold", `<LegalHold><Status>ON</Status></LegalHold>`)
+	assert.Equal(t, http.StatusOK, status)
+
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{Lock: storj.ObjectLock{LegalHold: true}}, nil)
+	status, body = send("GET", "/bucket/object?legal-hold", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<Status>ON</Status>`)
+
+	// pointerdb refuses to shorten the retention
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(objects.Meta{Lock: storj.ObjectLock{RetainUntil: retainUntil}}, nil)
+	mockOS.EXPECT().SetLock(gomock.Any(), "object", gomock.Any()).Return(objects.Meta{}, storj.ErrObjectLocked.New(""))
+	status, body = send("PUT", "/bucket/object?retention", `<Retention><Mode>COMPLIANCE</Mode>`+
+		`<RetainUntilDate>2099-01-01T00:00:00Z</RetainUntilDate></Retention>`)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, "AccessDenied")
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"strconv"
+	"strings"
+
+	minio "github.com/minio/minio/cmd"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/objects"
+)
+
+// taggingCountHeader is the header with the number of tags of an object
+const taggingCountHeader = "X-Amz-Tagging-Count"
+
+// serializeMeta converts the metadata minio gets from the headers of a
+// request into the metadata stored with an object. The standard headers
+// are stored in their own fields, everything else as user defined.
+func serializeMeta(metadata map[string]string) pb.SerializableMeta {
+	meta := pb.SerializableMeta{UserDefined: make(map[string]string, len(metadata))}
+	for key, value := range metadata {
+		switch strings.ToLower(key) {
+		case "content-type":
+			meta.ContentType = value
+		case "cache-control":
+			meta.CacheControl = value
+		case "content-disposition":
+			meta.ContentDisposition = value
+		case "content-encoding":
+			meta.ContentEncoding = value
+		case strings.ToLower(taggingCountHeader):
+			// the tags are not part of the metadata minio passes
+		default:
+			meta.UserDefined[key] = value
+		}
+	}
+	return meta
+}
+
+// objectInfo returns the minio.ObjectInfo of the object with meta
+func objectInfo(bucket, object string, meta objects.Meta) minio.ObjectInfo {
+	return minio.ObjectInfo{
+		Name:            object,
+		Bucket:          bucket,
+		ModTime:         meta.Modified,
+		Size:            meta.Size,
+		ETag:            meta.Checksum,
+		ContentType:     meta.ContentType,
+		ContentEncoding: meta.ContentEncoding,
+		UserDefined:     userDefined(meta.SerializableMeta),
+	}
+}
+
+// userDefined returns the metadata minio returns as headers of the object
+// with meta
+func userDefined(meta pb.SerializableMeta) map[string]string {
+	if meta.CacheControl == "" && meta.ContentDisposition == "" && len(meta.Tags) == 0 {
+		return meta.UserDefined
+	}
+
+	headers := make(map[string]string, len(meta.UserDefined)+3)
+	for key, value := range meta.UserDefined {
+		headers[key] = value
+	}
+	if meta.CacheControl != "" {
+		headers["Cache-Control"] = meta.CacheControl
+	}
+	if meta.ContentDisposition != "" {
+		headers["Content-Disposition"] = meta.ContentDisposition
+	}
+	if len(meta.Tags) > 0 {
+		headers[taggingCountHeader] = strconv.Itoa(len(meta.Tags))
+	}
+	return headers
+}
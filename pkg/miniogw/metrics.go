@@ -0,0 +1,247 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsPath is where minio serves its Prometheus metrics, which it does
+// not do in gateway mode. The proxy serves the gateway metrics there.
+const metricsPath = "/minio/prometheus/metrics"
+
+var (
+	metrics = prometheus.NewRegistry()
+
+	requests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "storj_gateway_requests_total",
+			Help: "Total number of S3 requests served by the gateway",
+		},
+		[]string{"operation", "code"},
+	)
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "storj_gateway_request_duration_seconds",
+			Help:    "Time taken by the S3 requests served by the gateway",
+			Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 60},
+		},
+		[]string{"operation"},
+	)
+	receivedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "storj_gateway_received_bytes_total",
+			Help: "Total number of bytes in the bodies of S3 requests",
+		},
+		[]string{"operation"},
+	)
+	sentBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "storj_gateway_sent_bytes_total",
+			Help: "Total number of bytes in the bodies of S3 responses",
+		},
+		[]string{"operation"},
+	)
+	usedBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "storj_gateway_used_bytes",
+			Help: "Bytes stored in the project of a tenant, when last computed",
+		},
+		[]string{"tenant"},
+	)
+	storedObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "storj_gateway_objects",
+			Help: "Objects stored in the project of a tenant, when last computed",
+		},
+		[]string{"tenant"},
+	)
+)
+
+func init() {
+	metrics.MustRegister(requests)
+	metrics.MustRegister(requestDuration)
+	metrics.MustRegister(receivedBytes)
+	metrics.MustRegister(sentBytes)
+	metrics.MustRegister(usedBytes)
+	metrics.MustRegister(storedObjects)
+}
+
+// metricsHandler serves the gateway metrics
+var metricsHandler = promhttp.HandlerFor(metrics, promhttp.HandlerOpts{})
+
+// tenantID returns the label of the tenant with accessKey in the metrics.
+// The metrics are served without authentication, so the access key is
+// hashed.
+func tenantID(accessKey string) string {
+	hash := sha256.Sum256([]byte(accessKey))
+	return hex.EncodeToString(hash[:8])
+}
+
+// observe records a request for operation
+func observe(operation string, status int, duration time.Duration, received, sent int64) {
+	requests.WithLabelValues(operation, strconv.Itoa(status)).Inc()
+	requestDuration.WithLabelValues(operation).Observe(duration.Seconds())
+	receivedBytes.WithLabelValues(operation).Add(float64(received))
+	sentBytes.WithLabelValues(operation).Add(float64(sent))
+}
+
+// s3Operation returns the name of the S3 operation r is for
+func s3Operation(r *http.Request) string {
+	query := r.URL.Query()
+	has := func(key string) bool {
+		_, ok := query[key]
+		return ok
+	}
+
+	bucket, object := splitPath(r.URL.Path)
+	switch {
+	case bucket == "":
+		if r.Method == http.MethodGet {
+			return "ListBuckets"
+		}
+	case object == "":
+		switch r.Method {
+		case http.MethodGet:
+			switch {
+			case has("policy"):
+				return "GetBucketPolicy"
+			case has("object-lock"):
+				return "GetObjectLockConfiguration"
+			case has("location"):
+				return "GetBucketLocation"
+			case has("uploads"):
+				return "ListMultipartUploads"
+			case query.Get("list-type") == "2":
+				return "ListObjectsV2"
+			}
+			return "ListObjects"
+		case http.MethodHead:
+			return "HeadBucket"
+		case http.MethodPut:
+			switch {
+			case has("policy"):
+				return "PutBucketPolicy"
+			case has("object-lock"):
+				return "PutObjectLockConfiguration"
+			}
+			return "CreateBucket"
+		case http.MethodDelete:
+			if has("policy") {
+				return "DeleteBucketPolicy"
+			}
+			return "DeleteBucket"
+		case http.MethodPost:
+			if has("delete") {
+				return "DeleteObjects"
+			}
+		}
+	case has("tagging"):
+		switch r.Method {
+		case http.MethodGet:
+			return "GetObjectTagging"
+		case http.MethodPut:
+			return "PutObjectTagging"
+		case http.MethodDelete:
+			return "DeleteObjectTagging"
+		}
+	case has("retention"):
+		switch r.Method {
+		case http.MethodGet:
+			return "GetObjectRetention"
+		case http.MethodPut:
+			return "PutObjectRetention"
+		}
+	case has("legal-hold"):
+		switch r.Method {
+		case http.MethodGet:
+			return "GetObjectLegalHold"
+		case http.MethodPut:
+			return "PutObjectLegalHold"
+		}
+	case has("uploadId"):
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("X-Amz-Copy-Source") != "" {
+				return "UploadPartCopy"
+			}
+			return "UploadPart"
+		case http.MethodGet:
+			return "ListParts"
+		case http.MethodPost:
+			return "CompleteMultipartUpload"
+		case http.MethodDelete:
+			return "AbortMultipartUpload"
+		}
+	default:
+		switch r.Method {
+		case http.MethodGet:
+			return "GetObject"
+		case http.MethodHead:
+			return "HeadObject"
+		case http.MethodPut:
+			if r.Header.Get("X-Amz-Copy-Source") != "" {
+				return "CopyObject"
+			}
+			return "PutObject"
+		case http.MethodPost:
+			if has("uploads") {
+				return "CreateMultipartUpload"
+			}
+		case http.MethodDelete:
+			return "DeleteObject"
+		}
+	}
+	return "Other"
+}
+
+// countingReader counts the bytes read from a request body
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// statusRecorder records the status and the body size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	n      int64
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(p []byte) (n int, err error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err = w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher for the streamed responses of minio
+func (w *statusRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -14,55 +14,46 @@ import (
 
 	minio "github.com/minio/minio/cmd"
 	"github.com/minio/minio/pkg/hash"
-
-	"storj.io/storj/pkg/pb"
 )
 
 func (s *storjObjects) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	uploads := t.multipart
 
 	upload, err := uploads.Create(bucket, object, metadata)
 	if err != nil {
+		t.release()
 		return "", err
 	}
 
-	objectStore, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	objectStore, err := t.store.GetObjectStore(ctx, bucket)
 	if err != nil {
 		uploads.RemoveByID(upload.ID)
 		upload.fail(err)
+		t.release()
 		return "", err
 	}
 
 	go func() {
+		// the tenant with the pending upload is not evicted
+		defer t.release()
+
 		// setting zero value means the object never expires
 		expTime := time.Time{}
 
-		tempContType := metadata["content-type"]
-		delete(metadata, "content-type")
-
-		// metadata serialized
-		serMetaInfo := pb.SerializableMeta{
-			ContentType: tempContType,
-			UserDefined: metadata,
-		}
-
-		result, err := objectStore.Put(ctx, object, upload.Stream, serMetaInfo, expTime)
+		result, err := objectStore.Put(ctx, object, upload.Stream, serializeMeta(metadata), expTime)
 		uploads.RemoveByID(upload.ID)
 
 		if err != nil {
 			upload.fail(err)
 		} else {
-			upload.complete(minio.ObjectInfo{
-				Name:        object,
-				Bucket:      bucket,
-				ModTime:     result.Modified,
-				Size:        result.Size,
-				ETag:        result.Checksum,
-				ContentType: result.ContentType,
-				UserDefined: result.UserDefined,
-			})
+			upload.complete(objectInfo(bucket, object, result))
 		}
 	}()
 
@@ -72,7 +63,13 @@ func (s *storjObjects) NewMultipartUpload(ctx context.Context, bucket, object st
 func (s *storjObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *hash.Reader) (info minio.PartInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.PartInfo{}, err
+	}
+	defer t.release()
+
+	uploads := t.multipart
 
 	upload, err := uploads.Get(bucket, object, uploadID)
 	if err != nil {
@@ -104,7 +101,13 @@ func (s *storjObjects) PutObjectPart(ctx context.Context, bucket, object, upload
 func (s *storjObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	uploads := t.multipart
 
 	upload, err := uploads.Remove(bucket, object, uploadID)
 	if err != nil {
@@ -123,7 +126,13 @@ func (s *storjObjects) AbortMultipartUpload(ctx context.Context, bucket, object,
 func (s *storjObjects) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []minio.CompletePart) (objInfo minio.ObjectInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+	defer t.release()
+
+	uploads := t.multipart
 	upload, err := uploads.Remove(bucket, object, uploadID)
 	if err != nil {
 		return minio.ObjectInfo{}, err
@@ -140,7 +149,13 @@ func (s *storjObjects) CompleteMultipartUpload(ctx context.Context, bucket, obje
 func (s *storjObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (result minio.ListPartsInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return minio.ListPartsInfo{}, err
+	}
+	defer t.release()
+
+	uploads := t.multipart
 	upload, err := uploads.Get(bucket, object, uploadID)
 	if err != nil {
 		return minio.ListPartsInfo{}, err
@@ -18,6 +18,8 @@ import (
 	"storj.io/storj/pkg/pb"
 	ranger "storj.io/storj/pkg/ranger"
 	objects "storj.io/storj/pkg/storage/objects"
+	streams "storj.io/storj/pkg/storage/streams"
+	storj "storj.io/storj/pkg/storj"
 )
 
 // MockStore is a mock of Store interface
@@ -55,6 +57,19 @@ func (mr *MockStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStore)(nil).Delete), arg0, arg1)
 }
 
+// DeleteObjects mocks base method
+func (m *MockStore) DeleteObjects(arg0 context.Context, arg1 storj.DeleteOptions) (storj.DeleteResult, error) {
+	ret := m.ctrl.Call(m, "DeleteObjects", arg0, arg1)
+	ret0, _ := ret[0].(storj.DeleteResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteObjects indicates an expected call of DeleteObjects
+func (mr *MockStoreMockRecorder) DeleteObjects(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjects", reflect.TypeOf((*MockStore)(nil).DeleteObjects), arg0, arg1)
+}
+
 // Get mocks base method
 func (m *MockStore) Get(arg0 context.Context, arg1 string) (ranger.Ranger, objects.Meta, error) {
 	ret := m.ctrl.Call(m, "Get", arg0, arg1)
@@ -108,3 +123,42 @@ func (m *MockStore) Put(arg0 context.Context, arg1 string, arg2 io.Reader, arg3
 func (mr *MockStoreMockRecorder) Put(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStore)(nil).Put), arg0, arg1, arg2, arg3, arg4)
 }
+
+// PutResumable mocks base method
+func (m *MockStore) PutResumable(arg0 context.Context, arg1 string, arg2 io.Reader, arg3 pb.SerializableMeta, arg4 time.Time, arg5 streams.Journal) (objects.Meta, error) {
+	ret := m.ctrl.Call(m, "PutResumable", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(objects.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutResumable indicates an expected call of PutResumable
+func (mr *MockStoreMockRecorder) PutResumable(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutResumable", reflect.TypeOf((*MockStore)(nil).PutResumable), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// UpdateMeta mocks base method
+func (m *MockStore) UpdateMeta(arg0 context.Context, arg1 string, arg2 pb.SerializableMeta) (objects.Meta, error) {
+	ret := m.ctrl.Call(m, "UpdateMeta", arg0, arg1, arg2)
+	ret0, _ := ret[0].(objects.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMeta indicates an expected call of UpdateMeta
+func (mr *MockStoreMockRecorder) UpdateMeta(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMeta", reflect.TypeOf((*MockStore)(nil).UpdateMeta), arg0, arg1, arg2)
+}
+
+// SetLock mocks base method
+func (m *MockStore) SetLock(arg0 context.Context, arg1 string, arg2 storj.ObjectLock) (objects.Meta, error) {
+	ret := m.ctrl.Call(m, "SetLock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(objects.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLock indicates an expected call of SetLock
+func (mr *MockStoreMockRecorder) SetLock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLock", reflect.TypeOf((*MockStore)(nil).SetLock), arg0, arg1, arg2)
+}
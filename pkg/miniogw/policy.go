@@ -0,0 +1,131 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+)
+
+// The only bucket policy the gateway supports is public-read, which allows
+// anyone to get the objects in the bucket. It is stored as the PublicRead
+// flag of the bucket metadata.
+
+// publicReadPolicy returns the public-read policy of bucket
+func publicReadPolicy(bucket string) *policy.Policy {
+	return &policy.Policy{
+		Version: policy.DefaultVersion,
+		Statements: []policy.Statement{
+			policy.NewStatement(
+				policy.Allow,
+				policy.NewPrincipal("*"),
+				policy.NewActionSet(policy.GetObjectAction),
+				policy.NewResourceSet(policy.NewResource(bucket, "*")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+}
+
+// isPublicRead returns whether p allows anonymous reads of the objects in
+// bucket and nothing else
+func isPublicRead(p *policy.Policy, bucket string) bool {
+	if len(p.Statements) == 0 {
+		return false
+	}
+
+	everything := policy.NewResource(bucket, "*")
+	for _, statement := range p.Statements {
+		if statement.Effect != policy.Allow || !statement.Principal.Match("*") || len(statement.Conditions) > 0 {
+			return false
+		}
+		if len(statement.Actions) != 1 || !statement.Actions.Contains(policy.GetObjectAction) {
+			return false
+		}
+		if len(statement.Resources) != 1 {
+			return false
+		}
+		if _, ok := statement.Resources[everything]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *storjObjects) SetBucketPolicy(ctx context.Context, bucket string, p *policy.Policy) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !isPublicRead(p, bucket) {
+		return minio.NotImplemented{}
+	}
+	return s.setPublicRead(ctx, bucket, true)
+}
+
+func (s *storjObjects) GetBucketPolicy(ctx context.Context, bucket string) (p *policy.Policy, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.release()
+
+	meta, err := t.store.Get(ctx, bucket)
+	if err != nil {
+		return nil, convertBucketNotFoundError(err, bucket)
+	}
+	if !meta.PublicRead {
+		return nil, minio.BucketPolicyNotFound{Bucket: bucket}
+	}
+	return publicReadPolicy(bucket), nil
+}
+
+func (s *storjObjects) DeleteBucketPolicy(ctx context.Context, bucket string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.setPublicRead(ctx, bucket, false)
+}
+
+// setPublicRead updates the public-read flag of bucket
+func (s *storjObjects) setPublicRead(ctx context.Context, bucket string, public bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	meta, err := t.store.Get(ctx, bucket)
+	if err != nil {
+		return convertBucketNotFoundError(err, bucket)
+	}
+	if meta.PublicRead == public {
+		return nil
+	}
+
+	// anonymous requests find the tenant of a public bucket by its name
+	if s.storj.tenants != nil && public {
+		err = s.storj.tenants.public.claim(bucket, t.accessKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	meta.PublicRead = public
+	_, err = t.store.Update(ctx, bucket, meta)
+	if err != nil {
+		if s.storj.tenants != nil && public {
+			s.storj.tenants.public.unclaim(bucket, t.accessKey)
+		}
+		return convertBucketNotFoundError(err, bucket)
+	}
+
+	if s.storj.tenants != nil && !public {
+		s.storj.tenants.public.unclaim(bucket, t.accessKey)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/policy/condition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+)
+
+func TestIsPublicRead(t *testing.T) {
+	assert.True(t, isPublicRead(publicReadPolicy("bucket"), "bucket"))
+	assert.False(t, isPublicRead(publicReadPolicy("bucket"), "other"))
+	assert.False(t, isPublicRead(&policy.Policy{Version: policy.DefaultVersion}, "bucket"))
+
+	for _, statement := range []policy.Statement{
+		policy.NewStatement(policy.Deny, policy.NewPrincipal("*"),
+			policy.NewActionSet(policy.GetObjectAction),
+			policy.NewResourceSet(policy.NewResource("bucket", "*")), condition.NewFunctions()),
+		policy.NewStatement(policy.Allow, policy.NewPrincipal("*"),
+			policy.NewActionSet(policy.GetObjectAction, policy.PutObjectAction),
+			policy.NewResourceSet(policy.NewResource("bucket", "*")), condition.NewFunctions()),
+		policy.NewStatement(policy.Allow, policy.NewPrincipal("*"),
+			policy.NewActionSet(policy.GetObjectAction),
+			policy.NewResourceSet(policy.NewResource("bucket", "private/*")), condition.NewFunctions()),
+	} {
+		p := &policy.Policy{Version: policy.DefaultVersion, Statements: []policy.Statement{statement}}
+		assert.False(t, isPublicRead(p, "bucket"))
+	}
+}
+
+func TestBucketPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	anonymous := logger.SetReqInfo(context.Background(), &logger.ReqInfo{UserAgent: anonymousAgent})
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, nil).Times(3)
+	_, err := storjObj.GetBucketPolicy(ctx, "bucket")
+	assert.Equal(t, minio.BucketPolicyNotFound{Bucket: "bucket"}, err)
+	_, err = storjObj.GetObjectInfo(anonymous, "bucket", "object")
+	assert.Equal(t, minio.PrefixAccessDenied{Bucket: "bucket"}, err)
+
+	mockBS.EXPECT().Update(gomock.Any(), "bucket", buckets.Meta{PublicRead: true}).
+		Return(buckets.Meta{PublicRead: true}, nil)
+	require.NoError(t, storjObj.SetBucketPolicy(ctx, "bucket", publicReadPolicy("bucket")))
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{PublicRead: true}, nil)
+	p, err := storjObj.GetBucketPolicy(ctx, "bucket")
+	require.NoError(t, err)
+	assert.True(t, isPublicRead(p, "bucket"))
+	// anonymous requests cannot change anything
+	assert.Equal(t, minio.PrefixAccessDenied{}, storjObj.DeleteBucketPolicy(anonymous, "bucket"))
+	assert.Equal(t, minio.NotImplemented{}, storjObj.SetBucketPolicy(ctx, "bucket", &policy.Policy{}))
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{PublicRead: true}, nil)
+	mockBS.EXPECT().Update(gomock.Any(), "bucket", buckets.Meta{}).Return(buckets.Meta{}, nil)
+	require.NoError(t, storjObj.DeleteBucketPolicy(ctx, "bucket"))
+}
@@ -0,0 +1,411 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	minio "github.com/minio/minio/cmd"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// proxyRegion is the region the proxy signs the forwarded requests for
+const proxyRegion = "us-east-1"
+
+// TenantProxy authenticates the S3 requests of the tenants with their own
+// credentials and forwards them to a minio gateway that only the proxy has
+// the credentials of. The forwarded requests carry the access key of the
+// tenant, so that the gateway can serve them from the tenant's store.
+//
+// Anonymous reads of objects are forwarded as well, for the gateway to
+// allow them on public buckets.
+type TenantProxy struct {
+	// Tagging serves the tagging of objects, which minio does not support
+	Tagging ObjectTagging
+	// Locking serves the object lock of buckets and objects, which minio
+	// does not support either
+	Locking ObjectLocking
+	// Deleting serves the deletes of several objects with one request to
+	// the satellite
+	Deleting ObjectDeleting
+
+	creds     CredentialStore // nil if minio verifies the requests
+	public    *PublicBuckets
+	accessKey string // of the minio gateway
+	secretKey string // of the minio gateway
+
+	proxy       *httputil.ReverseProxy // signs the requests for minio
+	passthrough *httputil.ReverseProxy
+
+	now func() time.Time
+}
+
+// NewTenantProxy creates a TenantProxy for the tenants in creds, forwarding
+// to the minio gateway at target with accessKey and secretKey. Anonymous
+// requests are forwarded for the owners of the buckets in public.
+func NewTenantProxy(creds CredentialStore, public *PublicBuckets, target *url.URL, accessKey, secretKey string) *TenantProxy {
+	p := &TenantProxy{
+		creds:     creds,
+		public:    public,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		now:       time.Now,
+	}
+	p.proxy = &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL.Scheme = target.Scheme
+			r.URL.Host = target.Host
+			r.Host = target.Host
+			signV4(r, p.accessKey, p.secretKey, proxyRegion, p.now())
+		},
+	}
+	p.passthrough = &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			// the host stays the one the client signed
+			r.URL.Scheme = target.Scheme
+			r.URL.Host = target.Host
+		},
+	}
+	return p
+}
+
+// NewSingleTenantProxy creates a TenantProxy for a gateway without tenants.
+// Requests with credentials are forwarded unchanged, for minio to verify
+// them with accessKey and secretKey. The proxy only signs the anonymous
+// reads.
+func NewSingleTenantProxy(target *url.URL, accessKey, secretKey string) *TenantProxy {
+	return NewTenantProxy(nil, nil, target, accessKey, secretKey)
+}
+
+// ServeHTTP implements http.Handler
+func (p *TenantProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == metricsPath {
+		metricsHandler.ServeHTTP(w, r)
+		return
+	}
+
+	operation, start := s3Operation(r), time.Now()
+	body := &countingReader{ReadCloser: r.Body}
+	r.Body = body
+	recorder := &statusRecorder{ResponseWriter: w}
+
+	p.serve(recorder, r)
+
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	observe(operation, recorder.status, time.Since(start), body.n, recorder.n)
+}
+
+func (p *TenantProxy) serve(w http.ResponseWriter, r *http.Request) {
+	if p.Tagging != nil && isObjectTagging(r) {
+		p.serveTagging(w, r)
+		return
+	}
+	if p.Locking != nil && isObjectLocking(r) {
+		p.serveLocking(w, r)
+		return
+	}
+	if p.Deleting != nil && isMultiObjectDelete(r) {
+		p.serveDeleting(w, r)
+		return
+	}
+
+	authenticated := r.Header.Get("Authorization") != "" || isPresigned(r)
+	if p.creds == nil && (authenticated || !isObjectRead(r)) {
+		p.passthrough.ServeHTTP(w, r)
+		return
+	}
+
+	var out *http.Request
+	var err error
+	if authenticated {
+		out, err = p.authenticate(r)
+	} else {
+		out, err = p.anonymous(r)
+	}
+	if err != nil {
+		zap.S().Debugf("rejected %s %s: %v", r.Method, r.URL.Path, err)
+		writeS3Error(w, r, err)
+		return
+	}
+	p.proxy.ServeHTTP(w, out)
+}
+
+var (
+	// errAccessDenied is returned for requests the proxy does not forward
+	errAccessDenied = Error.New("access denied")
+	// errMalformedXML is returned for request bodies that are not valid
+	errMalformedXML = Error.New("malformed XML")
+	// errMethodNotAllowed is returned for methods a resource does not have
+	errMethodNotAllowed = Error.New("method not allowed")
+	// errMissingContentMD5 is returned for requests that must have the MD5
+	// of their body
+	errMissingContentMD5 = Error.New("missing Content-MD5")
+	// errBadDigest is returned for bodies that do not match their MD5
+	errBadDigest = Error.New("bad digest")
+)
+
+// isPresigned returns whether r is a request for a presigned URL of any
+// signature version
+func isPresigned(r *http.Request) bool {
+	query := r.URL.Query()
+	_, v2 := query["Signature"]
+	return v2 || isPresignedV4(r)
+}
+
+// isObjectRead returns whether r gets an object, without any sub-resource
+// like its ACL or tags
+func isObjectRead(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	for key := range r.URL.Query() {
+		// the response headers can be overridden when getting an object
+		if !strings.HasPrefix(key, "response-") {
+			return false
+		}
+	}
+	_, object := splitPath(r.URL.Path)
+	return object != ""
+}
+
+// splitPath splits the path of a request into the bucket and the object
+func splitPath(path string) (bucket, object string) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// authenticate verifies the signature of r and returns the request to
+// forward for the tenant that signed it
+func (p *TenantProxy) authenticate(r *http.Request) (*http.Request, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return p.authenticateHeader(r, header)
+	}
+	if isPresignedV4(r) {
+		return p.authenticatePresigned(r)
+	}
+	return nil, errAccessDenied
+}
+
+func (p *TenantProxy) authenticateHeader(r *http.Request, header string) (*http.Request, error) {
+	sig, err := parseSignatureV4(header)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := p.lookup(r.Context(), sig.accessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := verifySignatureV4(r, sig, creds.SecretKey, p.now())
+	if err != nil {
+		return nil, err
+	}
+
+	out := cloneRequest(r)
+	if payloadHash(r) == streamingPayload {
+		// minio gets the decoded body, the proxy verifies the chunks
+		size, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64)
+		if err != nil {
+			return nil, ErrSignature.New("missing decoded content length")
+		}
+		out.Body = struct {
+			io.Reader
+			io.Closer
+		}{newChunkReader(r.Body, sig, creds.SecretKey, t), r.Body}
+		out.ContentLength = size
+		out.Header.Del("X-Amz-Decoded-Content-Length")
+		out.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+		removeEncoding(out.Header, "aws-chunked")
+	}
+
+	out.Header.Del("Authorization")
+	out.Header.Set("User-Agent", tenantAgent+sig.accessKey)
+	return out, nil
+}
+
+// lookup returns the credentials for accessKey. Without tenants, the only
+// credentials are the ones of minio.
+func (p *TenantProxy) lookup(ctx context.Context, accessKey string) (*Credentials, error) {
+	if p.creds != nil {
+		return p.creds.Lookup(ctx, accessKey)
+	}
+	if accessKey != p.accessKey {
+		return nil, ErrUnknownAccessKey.New("%s", accessKey)
+	}
+	return &Credentials{AccessKey: p.accessKey, SecretKey: p.secretKey}, nil
+}
+
+// authenticatePresigned verifies a presigned URL for getting or putting an
+// object
+func (p *TenantProxy) authenticatePresigned(r *http.Request) (*http.Request, error) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut:
+	default:
+		return nil, errAccessDenied
+	}
+
+	sig, err := parsePresignedV4(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := p.lookup(r.Context(), sig.accessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifyPresignedV4(r, sig, creds.SecretKey, p.now())
+	if err != nil {
+		return nil, err
+	}
+
+	out := cloneRequest(r)
+	query := out.URL.Query()
+	for _, key := range presignedQuery {
+		query.Del(key)
+	}
+	out.URL.RawQuery = query.Encode()
+
+	// the body of presigned uploads is not signed
+	out.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	out.Header.Set("User-Agent", tenantAgent+sig.accessKey)
+	return out, nil
+}
+
+// anonymous returns the request to forward for an anonymous read of an
+// object. The gateway allows it if the bucket is public.
+func (p *TenantProxy) anonymous(r *http.Request) (*http.Request, error) {
+	if !isObjectRead(r) {
+		return nil, errAccessDenied
+	}
+
+	var accessKey string
+	if p.creds != nil {
+		bucket, _ := splitPath(r.URL.Path)
+		owner, ok := p.public.Owner(bucket)
+		if !ok {
+			return nil, errAccessDenied
+		}
+		accessKey = owner
+	}
+
+	out := cloneRequest(r)
+	out.Header.Del("X-Amz-Content-Sha256")
+	out.Header.Set("User-Agent", anonymousAgent+accessKey)
+	return out, nil
+}
+
+// cloneRequest returns a copy of r with its own URL and header
+func cloneRequest(r *http.Request) *http.Request {
+	out := new(http.Request)
+	*out = *r
+
+	u := *r.URL
+	out.URL = &u
+
+	out.Header = make(http.Header, len(r.Header))
+	for key, values := range r.Header {
+		out.Header[key] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// removeEncoding removes encoding from the Content-Encoding header
+func removeEncoding(header http.Header, encoding string) {
+	var encodings []string
+	for _, e := range strings.Split(header.Get("Content-Encoding"), ",") {
+		if e = strings.TrimSpace(e); e != "" && e != encoding {
+			encodings = append(encodings, e)
+		}
+	}
+	if len(encodings) == 0 {
+		header.Del("Content-Encoding")
+		return
+	}
+	header.Set("Content-Encoding", strings.Join(encodings, ","))
+}
+
+// s3Error is the body of the error responses of S3
+type s3Error struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string
+	Message  string
+	Resource string
+}
+
+// writeS3Error responds to r with the S3 error for err
+func writeS3Error(w http.ResponseWriter, r *http.Request, err error) {
+	status, code, message := s3ErrorCode(err)
+	body := s3Error{Code: code, Message: message, Resource: r.URL.Path}
+
+	data, err := xml.Marshal(body)
+	if err != nil {
+		http.Error(w, body.Message, status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+}
+
+// s3ErrorCode returns the HTTP status and the S3 error code and message
+// for err
+func s3ErrorCode(err error) (status int, code, message string) {
+	switch err.(type) {
+	case minio.BucketNotFound:
+		return http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist."
+	case minio.ObjectNotFound:
+		return http.StatusNotFound, "NoSuchKey", "The specified key does not exist."
+	case minio.PrefixAccessDenied:
+		err = errAccessDenied
+	}
+
+	switch {
+	case err == errAccessDenied:
+		return http.StatusForbidden, "AccessDenied", "Access Denied."
+	case err == errMalformedXML:
+		return http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed or did not validate against our published schema."
+	case err == errMethodNotAllowed:
+		return http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource."
+	case err == errMissingContentMD5:
+		return http.StatusBadRequest, "InvalidRequest", "Missing required header for this request: Content-MD5."
+	case err == errBadDigest:
+		return http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received."
+	case ErrUnknownAccessKey.Has(err):
+		return http.StatusForbidden, "InvalidAccessKeyId", "The access key ID you provided does not exist in our records."
+	case ErrSignature.Has(err):
+		return http.StatusForbidden, "SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided."
+	case ErrInvalidTag.Has(err):
+		return http.StatusBadRequest, "InvalidTag", err.Error()
+	case err == errNoObjectLock:
+		return http.StatusBadRequest, "InvalidRequest", "Bucket is missing Object Lock Configuration"
+	case err == errNoLockConfiguration:
+		return http.StatusNotFound, "ObjectLockConfigurationNotFoundError", "Object Lock configuration does not exist for this bucket"
+	case err == errNoRetention:
+		return http.StatusNotFound, "NoSuchObjectLockConfiguration", "The specified object does not have a ObjectLock configuration"
+	case ErrInvalidRetention.Has(err):
+		return http.StatusBadRequest, "InvalidRequest", err.Error()
+	case storj.ErrObjectLocked.Has(err):
+		return http.StatusForbidden, "AccessDenied", "Access Denied."
+	}
+	return http.StatusInternalServerError, "InternalError", "We encountered an internal error, please try again."
+}
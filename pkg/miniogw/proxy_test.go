@@ -0,0 +1,234 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/pkg/s3signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forwarded is a request the proxy forwarded
+type forwarded struct {
+	agent   string
+	body    []byte
+	bodyErr error
+}
+
+// backend is a fake minio that verifies the requests of the proxy
+type backend struct {
+	t         *testing.T
+	forwarded chan forwarded
+}
+
+func (b *backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sig, err := parseSignatureV4(r.Header.Get("Authorization"))
+	require.NoError(b.t, err)
+	assert.Equal(b.t, "minio-access", sig.accessKey)
+
+	_, err = verifySignatureV4(r, sig, "minio-secret", time.Now())
+	assert.NoError(b.t, err)
+
+	f := forwarded{agent: r.Header.Get("User-Agent")}
+	f.body, f.bodyErr = ioutil.ReadAll(r.Body)
+	if f.bodyErr == nil {
+		assert.Equal(b.t, int64(len(f.body)), r.ContentLength)
+	}
+	b.forwarded <- f
+}
+
+func TestTenantProxy(t *testing.T) {
+	creds, err := NewStaticCredentials([]Credentials{
+		{AccessKey: "tenant-a", SecretKey: "secret-a"},
+		{AccessKey: "tenant/b", SecretKey: "secret-b"},
+	})
+	require.NoError(t, err)
+
+	back := &backend{t: t, forwarded: make(chan forwarded, 1)}
+	minio := httptest.NewServer(back)
+	defer minio.Close()
+
+	target, err := url.Parse(minio.URL)
+	require.NoError(t, err)
+	public := NewPublicBuckets()
+	require.NoError(t, public.claim("public", "tenant-a"))
+	handler := NewTenantProxy(creds, public, target, "minio-access", "minio-secret")
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	data := make([]byte, 200000)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	newRequest := func(method, path string) *http.Request {
+		req, err := http.NewRequest(method, proxy.URL+path, bytes.NewReader(data))
+		require.NoError(t, err)
+		return req
+	}
+
+	send := func(req *http.Request) int {
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	{ // signed payload
+		req := newRequest("PUT", "/bucket/some%20object?partNumber=1&uploadId=a+b")
+		sum := sha256Hex(data)
+		req.Header.Set("X-Amz-Content-Sha256", sum)
+		req = s3signer.SignV4(*req, "tenant-a", "secret-a", "", "us-east-1")
+
+		assert.Equal(t, http.StatusOK, send(req))
+		f := <-back.forwarded
+		assert.Equal(t, tenantAgent+"tenant-a", f.agent)
+		assert.NoError(t, f.bodyErr)
+		assert.Equal(t, data, f.body)
+	}
+
+	{ // streaming payload
+		req := newRequest("PUT", "/bucket/object")
+		req = s3signer.StreamingSignV4(req, "tenant/b", "secret-b", "", "us-east-1", int64(len(data)), time.Now().UTC())
+
+		assert.Equal(t, http.StatusOK, send(req))
+		f := <-back.forwarded
+		assert.Equal(t, tenantAgent+"tenant/b", f.agent)
+		assert.NoError(t, f.bodyErr)
+		assert.Equal(t, data, f.body)
+	}
+
+	for _, req := range []*http.Request{
+		newRequest("GET", "/bucket/object"),
+		s3signer.SignV4(*newRequest("GET", "/bucket/object"), "tenant-a", "secret-b", "", "us-east-1"),
+		s3signer.SignV4(*newRequest("GET", "/bucket/object"), "tenant-c", "secret-a", "", "us-east-1"),
+	} {
+		assert.Equal(t, http.StatusForbidden, send(req))
+	}
+
+	{ // modified after signing
+		req := s3signer.SignV4(*newRequest("GET", "/bucket/object"), "tenant-a", "secret-a", "", "us-east-1")
+		req.URL.Path = "/bucket/other"
+		assert.Equal(t, http.StatusForbidden, send(req))
+	}
+	assert.Empty(t, back.forwarded, "rejected requests reached minio")
+
+	{ // tampered chunk
+		req := newRequest("PUT", "/bucket/object")
+		req = s3signer.StreamingSignV4(req, "tenant-a", "secret-a", "", "us-east-1", int64(len(data)), time.Now().UTC())
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		body[len(body)/2] ^= 1
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			assert.NoError(t, resp.Body.Close())
+		}
+		assert.Error(t, (<-back.forwarded).bodyErr, "minio reads the tampered chunk")
+	}
+
+	{ // presigned URL
+		req := s3signer.PreSignV4(*newRequest("GET", "/bucket/object?response-content-type=text%2Fplain"),
+			"tenant/b", "secret-b", "", "us-east-1", 60)
+		assert.Equal(t, http.StatusOK, send(req))
+		f := <-back.forwarded
+		assert.Equal(t, tenantAgent+"tenant/b", f.agent)
+
+		presigned := req.URL.String()
+		req, err = http.NewRequest("DELETE", presigned, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, send(req))
+
+		req, err = http.NewRequest("GET", strings.Replace(presigned, "object", "other", 1), nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, send(req))
+
+		handler.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		req, err = http.NewRequest("GET", presigned, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, send(req), "expired URL")
+		handler.now = time.Now
+	}
+
+	{ // anonymous reads
+		assert.Equal(t, http.StatusOK, send(newRequest("GET", "/public/object")))
+		f := <-back.forwarded
+		assert.Equal(t, anonymousAgent+"tenant-a", f.agent)
+
+		for _, req := range []*http.Request{
+			newRequest("GET", "/bucket/object"),
+			newRequest("PUT", "/public/object"),
+			newRequest("GET", "/public/object?tagging"),
+			newRequest("GET", "/public"),
+		} {
+			assert.Equal(t, http.StatusForbidden, send(req), req.Method+" "+req.URL.String())
+		}
+	}
+	assert.Empty(t, back.forwarded, "rejected requests reached minio")
+}
+
+func TestSingleTenantProxy(t *testing.T) {
+	back := &backend{t: t, forwarded: make(chan forwarded, 1)}
+	minio := httptest.NewServer(back)
+	defer minio.Close()
+
+	target, err := url.Parse(minio.URL)
+	require.NoError(t, err)
+	proxy := httptest.NewServer(NewSingleTenantProxy(target, "minio-access", "minio-secret"))
+	defer proxy.Close()
+
+	send := func(req *http.Request) int {
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	// minio verifies the requests of the clients
+	req, err := http.NewRequest("PUT", proxy.URL+"/bucket/object", strings.NewReader("data"))
+	require.NoError(t, err)
+	req.Header.Set("X-Amz-Content-Sha256", sha256Hex([]byte("data")))
+	req = s3signer.SignV4(*req, "minio-access", "minio-secret", "", "us-east-1")
+	assert.Equal(t, http.StatusOK, send(req))
+	f := <-back.forwarded
+	assert.Equal(t, "data", string(f.body))
+
+	// the proxy signs the anonymous reads
+	req, err = http.NewRequest("GET", proxy.URL+"/bucket/object", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, send(req))
+	f = <-back.forwarded
+	assert.Equal(t, anonymousAgent, f.agent)
+}
+
+func TestParseSignatureV4(t *testing.T) {
+	sig, err := parseSignatureV4("AWS4-HMAC-SHA256 Credential=key/20181019/us-east-1/s3/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=" + hex.EncodeToString([]byte("sig")))
+	require.NoError(t, err)
+	assert.Equal(t, "key", sig.accessKey)
+	assert.Equal(t, "20181019/us-east-1/s3/aws4_request", sig.scope())
+	assert.Equal(t, []string{"host", "x-amz-date"}, sig.signedHeaders)
+
+	for _, header := range []string{
+		"",
+		"AWS key:signature",
+		"AWS4-HMAC-SHA256 Credential=key/20181019/us-east-1/s3/aws4_request",
+		"AWS4-HMAC-SHA256 Credential=key/20181019/us-east-1/sqs/aws4_request, SignedHeaders=host, Signature=00",
+		"AWS4-HMAC-SHA256 Credential=key/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=00",
+	} {
+		_, err := parseSignatureV4(header)
+		assert.Error(t, err, header)
+	}
+}
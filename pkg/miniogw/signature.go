@@ -0,0 +1,433 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/pkg/s3utils"
+	"github.com/zeebo/errs"
+)
+
+// The AWS signature version 4 constants the tenant proxy needs
+const (
+	signV4Algorithm  = "AWS4-HMAC-SHA256"
+	signV4Chunk      = "AWS4-HMAC-SHA256-PAYLOAD"
+	streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	emptySHA256      = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	iso8601Format = "20060102T150405Z"
+	yyyymmdd      = "20060102"
+
+	// maxSkew is how far the time of a signed request may be off
+	maxSkew = 15 * time.Minute
+)
+
+// ErrSignature is returned for requests with a malformed or wrong signature
+var ErrSignature = errs.Class("signature error")
+
+// signatureV4 is a parsed AWS signature version 4 Authorization header
+type signatureV4 struct {
+	accessKey     string
+	date          string // yyyymmdd of the scope
+	region        string
+	service       string
+	signedHeaders []string
+	signature     string
+}
+
+// scope returns the credential scope of the signature
+func (sig *signatureV4) scope() string {
+	return strings.Join([]string{sig.date, sig.region, sig.service, "aws4_request"}, "/")
+}
+
+// parseSignatureV4 parses an Authorization header of the form
+//
+//	AWS4-HMAC-SHA256 Credential=key/date/region/s3/aws4_request,
+//	  SignedHeaders=host;x-amz-date, Signature=hex
+func parseSignatureV4(header string) (*signatureV4, error) {
+	if !strings.HasPrefix(header, signV4Algorithm+" ") {
+		return nil, ErrSignature.New("unsupported authorization algorithm")
+	}
+
+	sig := &signatureV4{}
+	for _, field := range strings.Split(strings.TrimPrefix(header, signV4Algorithm), ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			return nil, ErrSignature.New("malformed authorization field %q", field)
+		}
+		switch kv[0] {
+		case "Credential":
+			if err := sig.parseCredential(kv[1]); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sig.signedHeaders = strings.Split(kv[1], ";")
+		case "Signature":
+			sig.signature = kv[1]
+		}
+	}
+
+	if sig.accessKey == "" || len(sig.signedHeaders) == 0 || sig.signature == "" {
+		return nil, ErrSignature.New("incomplete authorization header")
+	}
+	if sig.service != "s3" {
+		return nil, ErrSignature.New("unsupported service %q", sig.service)
+	}
+	return sig, nil
+}
+
+// parseCredential parses a credential of the form
+// key/date/region/service/aws4_request
+func (sig *signatureV4) parseCredential(credential string) error {
+	// the access key may contain slashes, the scope cannot
+	parts := strings.Split(credential, "/")
+	if len(parts) < 5 || parts[len(parts)-1] != "aws4_request" {
+		return ErrSignature.New("malformed credential %q", credential)
+	}
+	n := len(parts)
+	sig.accessKey = strings.Join(parts[:n-4], "/")
+	sig.date, sig.region, sig.service = parts[n-4], parts[n-3], parts[n-2]
+	return nil
+}
+
+// maxExpires is the longest time a presigned URL can be valid for
+const maxExpires = 7 * 24 * time.Hour
+
+// presignedV4 is the signature of a presigned URL
+type presignedV4 struct {
+	*signatureV4
+	time    time.Time
+	expires time.Duration
+}
+
+// presignedQuery are the query parameters of presigned URLs
+var presignedQuery = []string{
+	"X-Amz-Algorithm", "X-Amz-Credential", "X-Amz-Date", "X-Amz-Expires",
+	"X-Amz-SignedHeaders", "X-Amz-Signature", "X-Amz-Content-Sha256",
+}
+
+// isPresignedV4 returns whether r has a signature version 4 in the query
+func isPresignedV4(r *http.Request) bool {
+	_, ok := r.URL.Query()["X-Amz-Credential"]
+	return ok
+}
+
+// parsePresignedV4 parses the signature in the query of a presigned URL
+func parsePresignedV4(query url.Values) (*presignedV4, error) {
+	if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+		return nil, ErrSignature.New("unsupported presign algorithm")
+	}
+
+	sig := &presignedV4{signatureV4: &signatureV4{}}
+	if err := sig.parseCredential(query.Get("X-Amz-Credential")); err != nil {
+		return nil, err
+	}
+	if sig.service != "s3" {
+		return nil, ErrSignature.New("unsupported service %q", sig.service)
+	}
+
+	var err error
+	sig.time, err = time.Parse(iso8601Format, query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, ErrSignature.New("malformed date")
+	}
+
+	seconds, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	sig.expires = time.Duration(seconds) * time.Second
+	if err != nil || sig.expires <= 0 || sig.expires > maxExpires {
+		return nil, ErrSignature.New("malformed expiry")
+	}
+
+	sig.signedHeaders = strings.Split(query.Get("X-Amz-SignedHeaders"), ";")
+	sig.signature = query.Get("X-Amz-Signature")
+	if sig.signature == "" {
+		return nil, ErrSignature.New("missing signature")
+	}
+	return sig, nil
+}
+
+// verifyPresignedV4 checks that the presigned URL of r is signed by sig with
+// secretKey and has not expired
+func verifyPresignedV4(r *http.Request, sig *presignedV4, secretKey string, now time.Time) error {
+	if now.Before(sig.time.Add(-maxSkew)) {
+		return ErrSignature.New("presigned URL is not valid yet")
+	}
+	if now.After(sig.time.Add(sig.expires)) {
+		return ErrSignature.New("presigned URL expired")
+	}
+	if sig.time.UTC().Format(yyyymmdd) != sig.date {
+		return ErrSignature.New("credential date does not match the request date")
+	}
+	if !signsHost(sig.signedHeaders) {
+		return ErrSignature.New("host header is not signed")
+	}
+
+	hash := r.URL.Query().Get("X-Amz-Content-Sha256")
+	if hash == "" {
+		hash = unsignedPayload
+	}
+
+	canonical := canonicalRequest(r, sig.signedHeaders, hash)
+	expected := signString(secretKey, sig.date, sig.region, stringToSign(sig.time, sig.scope(), canonical))
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return ErrSignature.New("signature does not match")
+	}
+	return nil
+}
+
+// signsHost returns whether the host header is in signedHeaders
+func signsHost(signedHeaders []string) bool {
+	for _, header := range signedHeaders {
+		if header == "host" {
+			return true
+		}
+	}
+	return false
+}
+
+// requestTime returns the time the request was signed at
+func requestTime(r *http.Request) (time.Time, error) {
+	if date := r.Header.Get("X-Amz-Date"); date != "" {
+		return time.Parse(iso8601Format, date)
+	}
+	return http.ParseTime(r.Header.Get("Date"))
+}
+
+// payloadHash returns the hash of the body the request was signed with
+func payloadHash(r *http.Request) string {
+	if hash := r.Header.Get("X-Amz-Content-Sha256"); hash != "" {
+		return hash
+	}
+	return emptySHA256
+}
+
+// verifySignatureV4 checks that r is signed by sig with secretKey. It
+// returns the time the request was signed at.
+func verifySignatureV4(r *http.Request, sig *signatureV4, secretKey string, now time.Time) (time.Time, error) {
+	t, err := requestTime(r)
+	if err != nil {
+		return time.Time{}, ErrSignature.New("missing or malformed date")
+	}
+	if skew := now.Sub(t); skew > maxSkew || skew < -maxSkew {
+		return time.Time{}, ErrSignature.New("request time too skewed")
+	}
+	if t.UTC().Format(yyyymmdd) != sig.date {
+		return time.Time{}, ErrSignature.New("credential date does not match the request date")
+	}
+
+	if !signsHost(sig.signedHeaders) {
+		return time.Time{}, ErrSignature.New("host header is not signed")
+	}
+
+	canonical := canonicalRequest(r, sig.signedHeaders, payloadHash(r))
+	expected := signString(secretKey, sig.date, sig.region, stringToSign(t, sig.scope(), canonical))
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return time.Time{}, ErrSignature.New("signature does not match")
+	}
+	return t, nil
+}
+
+// signV4 signs r with the header based signature version 4, signing only
+// the host, the payload hash and the date
+func signV4(r *http.Request, accessKey, secretKey, region string, t time.Time) {
+	t = t.UTC()
+	r.Header.Set("X-Amz-Date", t.Format(iso8601Format))
+	if r.Header.Get("X-Amz-Content-Sha256") == "" {
+		r.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	}
+
+	sig := &signatureV4{
+		accessKey:     accessKey,
+		date:          t.Format(yyyymmdd),
+		region:        region,
+		service:       "s3",
+		signedHeaders: []string{"host", "x-amz-content-sha256", "x-amz-date"},
+	}
+	canonical := canonicalRequest(r, sig.signedHeaders, payloadHash(r))
+	sig.signature = signString(secretKey, sig.date, region, stringToSign(t, sig.scope(), canonical))
+
+	r.Header.Set("Authorization", signV4Algorithm+
+		" Credential="+accessKey+"/"+sig.scope()+
+		", SignedHeaders="+strings.Join(sig.signedHeaders, ";")+
+		", Signature="+sig.signature)
+}
+
+// canonicalRequest returns the canonical form of r that is signed
+func canonicalRequest(r *http.Request, signedHeaders []string, hash string) string {
+	signedHeaders = append([]string(nil), signedHeaders...)
+	sort.Strings(signedHeaders)
+
+	var headers bytes.Buffer
+	for _, name := range signedHeaders {
+		headers.WriteString(name)
+		headers.WriteByte(':')
+		headers.WriteString(headerValue(r, name))
+		headers.WriteByte('\n')
+	}
+
+	query := r.URL.Query()
+	query.Del("X-Amz-Signature")
+
+	return strings.Join([]string{
+		r.Method,
+		s3utils.EncodePath(r.URL.Path),
+		strings.Replace(query.Encode(), "+", "%20", -1),
+		headers.String(),
+		strings.Join(signedHeaders, ";"),
+		hash,
+	}, "\n")
+}
+
+// headerValue returns the canonical value of the header name of r. Go
+// moves some headers out of r.Header, they are taken from the request.
+func headerValue(r *http.Request, name string) string {
+	values := r.Header[http.CanonicalHeaderKey(name)]
+	if len(values) == 0 {
+		switch name {
+		case "host":
+			values = []string{r.Host}
+		case "content-length":
+			values = []string{strconv.FormatInt(r.ContentLength, 10)}
+		case "transfer-encoding":
+			values = r.TransferEncoding
+		case "expect":
+			values = []string{"100-continue"}
+		}
+	}
+
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		trimmed[i] = strings.Join(strings.Fields(value), " ")
+	}
+	return strings.Join(trimmed, ",")
+}
+
+// stringToSign returns the string signed for a request with canonical form
+// canonical
+func stringToSign(t time.Time, scope, canonical string) string {
+	return signV4Algorithm + "\n" + t.UTC().Format(iso8601Format) + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+}
+
+// signString signs s with the key derived from secretKey for date and
+// region
+func signString(secretKey, date, region, s string) string {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, s))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// maxChunkSize limits the chunks of a streaming upload that are buffered
+// for verification
+const maxChunkSize = 16 << 20
+
+// chunkReader decodes the body of a streaming signature version 4 upload
+// and verifies the signature of every chunk
+type chunkReader struct {
+	r         *bufio.Reader
+	secretKey string
+	sig       *signatureV4
+	time      time.Time
+	previous  string // signature of the previous chunk
+
+	chunk []byte
+	err   error
+}
+
+// newChunkReader reads the chunks of body. The first chunk is signed with
+// the seed signature sig of the request.
+func newChunkReader(body io.Reader, sig *signatureV4, secretKey string, t time.Time) *chunkReader {
+	return &chunkReader{
+		r:         bufio.NewReader(body),
+		secretKey: secretKey,
+		sig:       sig,
+		time:      t,
+		previous:  sig.signature,
+	}
+}
+
+// Read implements io.Reader
+func (cr *chunkReader) Read(p []byte) (n int, err error) {
+	for len(cr.chunk) == 0 {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		cr.err = cr.next()
+	}
+	n = copy(p, cr.chunk)
+	cr.chunk = cr.chunk[n:]
+	return n, nil
+}
+
+// next reads and verifies the next chunk of the form
+//
+//	hex-size;chunk-signature=signature\r\n data \r\n
+func (cr *chunkReader) next() error {
+	header, err := cr.r.ReadString('\n')
+	if err != nil {
+		return ErrSignature.New("malformed chunk header")
+	}
+	header = strings.TrimSuffix(header, "\r\n")
+
+	parts := strings.SplitN(header, ";chunk-signature=", 2)
+	if len(parts) != 2 {
+		return ErrSignature.New("malformed chunk header")
+	}
+	size, err := strconv.ParseInt(parts[0], 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize {
+		return ErrSignature.New("malformed chunk size")
+	}
+
+	data := make([]byte, size+2)
+	_, err = io.ReadFull(cr.r, data)
+	if err != nil || !bytes.HasSuffix(data, []byte("\r\n")) {
+		return ErrSignature.New("malformed chunk")
+	}
+	data = data[:size]
+
+	s := strings.Join([]string{
+		signV4Chunk,
+		cr.time.UTC().Format(iso8601Format),
+		cr.sig.scope(),
+		cr.previous,
+		emptySHA256,
+		sha256Hex(data),
+	}, "\n")
+	expected := signString(cr.secretKey, cr.sig.date, cr.sig.region, s)
+	if !hmac.Equal([]byte(expected), []byte(parts[1])) {
+		return ErrSignature.New("chunk signature does not match")
+	}
+	cr.previous = expected
+
+	if size == 0 {
+		return io.EOF
+	}
+	cr.chunk = data
+	return nil
+}
@@ -0,0 +1,289 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"unicode/utf8"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/buckets"
+)
+
+// The limits of S3 on the tags of an object
+const (
+	maxTags           = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// maxXMLSize is the largest XML document the proxy reads
+const maxXMLSize = 64 * 1024
+
+// s3Namespace is the XML namespace of the S3 API
+const s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+// ErrInvalidTag is returned for tags that S3 does not allow
+var ErrInvalidTag = errs.Class("invalid tag")
+
+// ObjectTagging is the object layer for the tagging of objects, which minio
+// does not support
+type ObjectTagging interface {
+	GetObjectTagging(ctx context.Context, bucket, object string) (tags map[string]string, err error)
+	PutObjectTagging(ctx context.Context, bucket, object string, tags map[string]string) error
+	DeleteObjectTagging(ctx context.Context, bucket, object string) error
+}
+
+// validateTags returns an ErrInvalidTag error if S3 does not allow tags
+func validateTags(tags map[string]string) error {
+	if len(tags) > maxTags {
+		return ErrInvalidTag.New("an object can have at most %d tags", maxTags)
+	}
+	for key, value := range tags {
+		if key == "" || utf8.RuneCountInString(key) > maxTagKeyLength {
+			return ErrInvalidTag.New("the key %q must have 1 to %d characters", key, maxTagKeyLength)
+		}
+		if utf8.RuneCountInString(value) > maxTagValueLength {
+			return ErrInvalidTag.New("the value of %q can have at most %d characters", key, maxTagValueLength)
+		}
+	}
+	return nil
+}
+
+func (s *storjObjects) GetObjectTagging(ctx context.Context, bucket, object string) (tags map[string]string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.release()
+
+	o, err := t.store.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return nil, convertBucketNotFoundError(err, bucket)
+	}
+
+	m, err := o.Meta(ctx, object)
+	if err != nil {
+		return nil, convertObjectNotFoundError(err, bucket, object)
+	}
+	return m.Tags, nil
+}
+
+func (s *storjObjects) PutObjectTagging(ctx context.Context, bucket, object string, tags map[string]string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = validateTags(tags)
+	if err != nil {
+		return err
+	}
+	return s.setTags(ctx, bucket, object, tags)
+}
+
+func (s *storjObjects) DeleteObjectTagging(ctx context.Context, bucket, object string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.setTags(ctx, bucket, object, nil)
+}
+
+// setTags replaces the tags of an object
+func (s *storjObjects) setTags(ctx context.Context, bucket, object string, tags map[string]string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	_, err = s.updateMeta(ctx, t.store, bucket, object, func(meta *pb.SerializableMeta) {
+		meta.Tags = tags
+	})
+	return err
+}
+
+// updateMeta changes the metadata of an object with update, without
+// uploading the object again
+func (s *storjObjects) updateMeta(ctx context.Context, bs buckets.Store, bucket, object string,
+	update func(meta *pb.SerializableMeta)) (objInfo minio.ObjectInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	o, err := bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return objInfo, convertBucketNotFoundError(err, bucket)
+	}
+
+	m, err := o.Meta(ctx, object)
+	if err != nil {
+		return objInfo, convertObjectNotFoundError(err, bucket, object)
+	}
+
+	update(&m.SerializableMeta)
+	m, err = o.UpdateMeta(ctx, object, m.SerializableMeta)
+	if err != nil {
+		return objInfo, convertObjectNotFoundError(err, bucket, object)
+	}
+	return objectInfo(bucket, object, m), nil
+}
+
+// tagging is the XML document of the tags of an object
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	TagSet  []tag    `xml:"TagSet>Tag"`
+}
+
+type tag struct {
+	Key   string
+	Value string
+}
+
+// isObjectTagging returns whether r is for the tags of an object
+func isObjectTagging(r *http.Request) bool {
+	if _, ok := r.URL.Query()["tagging"]; !ok {
+		return false
+	}
+	_, object := splitPath(r.URL.Path)
+	return object != ""
+}
+
+// serveTagging serves a request for the tags of an object with p.Tagging
+func (p *TenantProxy) serveTagging(w http.ResponseWriter, r *http.Request) {
+	err := p.tagging(w, r)
+	if err != nil {
+		zap.S().Debugf("tagging %s %s failed: %v", r.Method, r.URL.Path, err)
+		writeS3Error(w, r, err)
+	}
+}
+
+func (p *TenantProxy) tagging(w http.ResponseWriter, r *http.Request) error {
+	ctx, out, err := p.objectLayerContext(r)
+	if err != nil {
+		return err
+	}
+
+	bucket, object := splitPath(r.URL.Path)
+
+	switch r.Method {
+	case http.MethodGet:
+		tags, err := p.Tagging.GetObjectTagging(ctx, bucket, object)
+		if err != nil {
+			return err
+		}
+
+		doc := tagging{Xmlns: s3Namespace, TagSet: []tag{}}
+		for key, value := range tags {
+			doc.TagSet = append(doc.TagSet, tag{Key: key, Value: value})
+		}
+		sort.Slice(doc.TagSet, func(i, k int) bool { return doc.TagSet[i].Key < doc.TagSet[k].Key })
+		return writeXML(w, doc)
+
+	case http.MethodPut:
+		tags, err := readTagging(out)
+		if err != nil {
+			return err
+		}
+		err = p.Tagging.PutObjectTagging(ctx, bucket, object, tags)
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+
+	case http.MethodDelete:
+		err := p.Tagging.DeleteObjectTagging(ctx, bucket, object)
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return errMethodNotAllowed
+}
+
+// readTagging reads the tags from the body of an authenticated request
+func readTagging(r *http.Request) (map[string]string, error) {
+	var doc tagging
+	err := readXML(r, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(doc.TagSet))
+	for _, t := range doc.TagSet {
+		if _, ok := tags[t.Key]; ok {
+			return nil, ErrInvalidTag.New("duplicate key %q", t.Key)
+		}
+		tags[t.Key] = t.Value
+	}
+	return tags, nil
+}
+
+// objectLayerContext authenticates r and returns the context for calling
+// the object layer with the request that minio would get
+func (p *TenantProxy) objectLayerContext(r *http.Request) (ctx context.Context, out *http.Request, err error) {
+	if r.Header.Get("Authorization") == "" && !isPresigned(r) {
+		return nil, nil, errAccessDenied
+	}
+	out, err = p.authenticate(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the object layer finds the tenant like it does for minio
+	ctx = logger.SetReqInfo(r.Context(), &logger.ReqInfo{UserAgent: out.Header.Get("User-Agent")})
+	return ctx, out, nil
+}
+
+// readXML decodes the XML body of an authenticated request into v
+func readXML(r *http.Request, v interface{}) error {
+	data, err := readBody(r, maxXMLSize)
+	if err != nil {
+		return err
+	}
+	if xml.Unmarshal(data, v) != nil {
+		return errMalformedXML
+	}
+	return nil
+}
+
+// readBody reads the body of an authenticated request of up to limit
+// bytes and verifies its hash
+func readBody(r *http.Request, limit int64) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errMalformedXML
+	}
+
+	// the signature only covers the hash of the body
+	if hash := payloadHash(r); hash != unsignedPayload && hash != sha256Hex(data) {
+		return nil, ErrSignature.New("payload hash mismatch")
+	}
+	return data, nil
+}
+
+// writeXML writes v as the XML body of a response
+func writeXML(w http.ResponseWriter, v interface{}) error {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(data)
+	return nil
+}
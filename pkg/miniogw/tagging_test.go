@@ -0,0 +1,194 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/minio/minio-go/pkg/s3signer"
+	minio "github.com/minio/minio/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/pb"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/objects"
+)
+
+func TestSerializeMeta(t *testing.T) {
+	meta := serializeMeta(map[string]string{
+		"content-type":        "text/plain",
+		"cache-control":       "no-cache",
+		"content-disposition": "attachment",
+		"content-encoding":    "gzip",
+		"X-Amz-Meta-Color":    "blue",
+	})
+	assert.Equal(t, pb.SerializableMeta{
+		ContentType:        "text/plain",
+		CacheControl:       "no-cache",
+		ContentDisposition: "attachment",
+		ContentEncoding:    "gzip",
+		UserDefined:        map[string]string{"X-Amz-Meta-Color": "blue"},
+	}, meta)
+
+	meta.Tags = map[string]string{"a": "1", "b": "2"}
+	info := objectInfo("bucket", "object", objects.Meta{SerializableMeta: meta})
+	assert.Equal(t, "text/plain", info.ContentType)
+	assert.Equal(t, "gzip", info.ContentEncoding)
+	assert.Equal(t, map[string]string{
+		"X-Amz-Meta-Color":    "blue",
+		"Cache-Control":       "no-cache",
+		"Content-Disposition": "attachment",
+		"X-Amz-Tagging-Count": "2",
+	}, info.UserDefined)
+
+	// copying keeps the metadata minio got from objectInfo
+	copied := serializeMeta(info.UserDefined)
+	copied.ContentType, copied.ContentEncoding = info.ContentType, info.ContentEncoding
+	meta.Tags = nil
+	assert.Equal(t, meta, copied)
+}
+
+func TestObjectTagging(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+	storjObj := storjObjects{storj: &Storj{bs: mockBS}}
+
+	meta := objects.Meta{SerializableMeta: pb.SerializableMeta{ContentType: "text/plain"}}
+	tags := map[string]string{"project": "storj"}
+
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil).AnyTimes()
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(meta, nil)
+	mockOS.EXPECT().UpdateMeta(gomock.Any(), "object", pb.SerializableMeta{ContentType: "text/plain", Tags: tags}).
+		Return(meta, nil)
+	require.NoError(t, storjObj.PutObjectTagging(ctx, "bucket", "object", tags))
+
+	meta.Tags = tags
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(meta, nil)
+	got, err := storjObj.GetObjectTagging(ctx, "bucket", "object")
+	require.NoError(t, err)
+	assert.Equal(t, tags, got)
+
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(meta, nil)
+	mockOS.EXPECT().UpdateMeta(gomock.Any(), "object", pb.SerializableMeta{ContentType: "text/plain"}).
+		Return(meta, nil)
+	require.NoError(t, storjObj.DeleteObjectTagging(ctx, "bucket", "object"))
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= maxTags; i++ {
+		tooMany[fmt.Sprint(i)] = ""
+	}
+	for _, invalid := range []map[string]string{
+		tooMany,
+		{"": "empty key"},
+		{strings.Repeat("k", maxTagKeyLength+1): ""},
+		{"key": strings.Repeat("v", maxTagValueLength+1)},
+	} {
+		assert.True(t, ErrInvalidTag.Has(storjObj.PutObjectTagging(ctx, "bucket", "object", invalid)))
+	}
+
+	// copying an object onto itself updates its metadata and keeps its tags
+	mockOS.EXPECT().Meta(gomock.Any(), "object").Return(meta, nil)
+	mockOS.EXPECT().UpdateMeta(gomock.Any(), "object", pb.SerializableMeta{
+		ContentType:  "text/html",
+		CacheControl: "no-cache",
+		UserDefined:  map[string]string{},
+		Tags:         tags,
+	}).Return(meta, nil)
+	_, err = storjObj.CopyObject(ctx, "bucket", "object", "bucket", "object", minio.ObjectInfo{
+		ContentType: "text/html",
+		UserDefined: map[string]string{"cache-control": "no-cache"},
+	})
+	require.NoError(t, err)
+}
+
+// memTagging is an in-memory ObjectTagging
+type memTagging map[string]map[string]string
+
+func (m memTagging) GetObjectTagging(ctx context.Context, bucket, object string) (map[string]string, error) {
+	tags, ok := m[bucket+"/"+object]
+	if !ok {
+		return nil, minio.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return tags, nil
+}
+
+func (m memTagging) PutObjectTagging(ctx context.Context, bucket, object string, tags map[string]string) error {
+	if _, ok := m[bucket+"/"+object]; !ok {
+		return minio.ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	m[bucket+"/"+object] = tags
+	return nil
+}
+
+func (m memTagging) DeleteObjectTagging(ctx context.Context, bucket, object string) error {
+	return m.PutObjectTagging(ctx, bucket, object, map[string]string{})
+}
+
+func TestProxyTagging(t *testing.T) {
+	tagging := memTagging{"bucket/object": {}}
+
+	// minio does not serve the tagging requests
+	target, err := url.Parse("http://127.0.0.1:1")
+	require.NoError(t, err)
+	handler := NewSingleTenantProxy(target, "minio-access", "minio-secret")
+	handler.Tagging = tagging
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	send := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, proxy.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("X-Amz-Content-Sha256", sha256Hex([]byte(body)))
+		req = s3signer.SignV4(*req, "minio-access", "minio-secret", "", "us-east-1")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode, string(data)
+	}
+
+	status, _ := send("PUT", "/bucket/object?tagging", `<Tagging><TagSet>`+
+		`<Tag><Key>b</Key><Value>2</Value></Tag><Tag><Key>a</Key><Value>1</Value></Tag>`+
+		`</TagSet></Tagging>`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, tagging["bucket/object"])
+
+	status, body := send("GET", "/bucket/object?tagging", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `<Tagging xmlns="`+s3Namespace+`"><TagSet>`+
+		`<Tag><Key>a</Key><Value>1</Value></Tag><Tag><Key>b</Key><Value>2</Value></Tag>`+
+		`</TagSet></Tagging>`)
+
+	status, _ = send("DELETE", "/bucket/object?tagging", "")
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Empty(t, tagging["bucket/object"])
+
+	status, body = send("GET", "/bucket/missing?tagging", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "NoSuchKey")
+
+	status, body = send("PUT", "/bucket/object?tagging", "<Tagging>")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "MalformedXML")
+
+	// anonymous requests cannot read the tags
+	resp, err := http.Get(proxy.URL + "/bucket/object?tagging")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
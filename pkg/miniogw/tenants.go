@@ -0,0 +1,325 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/cmd/logger"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/utils"
+)
+
+// tenantAgent and anonymousAgent prefix the user agent of the requests the
+// tenant proxy forwards to minio. minio keeps the user agent in the context
+// of the object layer calls, which is how the gateway learns the tenant and
+// whether the request was authenticated.
+const (
+	tenantAgent    = "storj-tenant/"
+	anonymousAgent = "storj-anonymous/"
+)
+
+// tenantFromContext returns the access key of the tenant of the request
+// that ctx belongs to and whether the request is anonymous
+func tenantFromContext(ctx context.Context) (accessKey string, anonymous bool, ok bool) {
+	info := logger.GetReqInfo(ctx)
+	if info == nil {
+		return "", false, false
+	}
+	switch {
+	case strings.HasPrefix(info.UserAgent, tenantAgent):
+		return strings.TrimPrefix(info.UserAgent, tenantAgent), false, true
+	case strings.HasPrefix(info.UserAgent, anonymousAgent):
+		return strings.TrimPrefix(info.UserAgent, anonymousAgent), true, true
+	}
+	return "", false, false
+}
+
+// StoreOpener creates the bucket store of a tenant. disconnect releases the
+// resources of the store once the tenant is evicted.
+type StoreOpener func(ctx context.Context, creds *Credentials) (store buckets.Store, disconnect func() error, err error)
+
+// tenant is the state the gateway keeps for a tenant
+type tenant struct {
+	accessKey  string
+	store      buckets.Store
+	multipart  *MultipartUploads
+	usage      *usageCache
+	disconnect func() error
+
+	owner   *TenantStores
+	element *list.Element
+	refs    int
+}
+
+// release marks the tenant as no longer used by a request
+func (t *tenant) release() {
+	if t.owner != nil {
+		t.owner.release(t)
+	}
+}
+
+// TenantStores keeps the bucket stores of the recently active tenants. When
+// there are more than its capacity, the least recently used tenants that no
+// request is using are evicted.
+type TenantStores struct {
+	creds    CredentialStore
+	open     StoreOpener
+	capacity int
+
+	public *PublicBuckets
+
+	mu      sync.Mutex
+	lru     *list.List // of *tenant, most recently used in front
+	tenants map[string]*tenant
+	opening map[string]*opening
+}
+
+// NewTenantStores creates TenantStores keeping up to capacity stores opened
+// with open for the tenants in creds
+func NewTenantStores(creds CredentialStore, open StoreOpener, capacity int) *TenantStores {
+	return &TenantStores{
+		creds:    creds,
+		open:     open,
+		capacity: capacity,
+		public:   NewPublicBuckets(),
+		lru:      list.New(),
+		tenants:  make(map[string]*tenant),
+		opening:  make(map[string]*opening),
+	}
+}
+
+// opening is a tenant store that is being opened
+type opening struct {
+	done   chan struct{}
+	tenant *tenant
+	err    error
+}
+
+// acquire returns the tenant with accessKey, opening its store if it is not
+// cached. The tenant is not evicted until it is released.
+func (ts *TenantStores) acquire(ctx context.Context, accessKey string) (t *tenant, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		ts.mu.Lock()
+		if t, ok := ts.tenants[accessKey]; ok {
+			t.refs++
+			ts.lru.MoveToFront(t.element)
+			ts.mu.Unlock()
+			return t, nil
+		}
+
+		// wait for the request that is already opening the store; the
+		// tenant may be evicted again before it is acquired
+		if op, ok := ts.opening[accessKey]; ok {
+			ts.mu.Unlock()
+			select {
+			case <-op.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if op.err != nil {
+				return nil, op.err
+			}
+			continue
+		}
+
+		op := &opening{done: make(chan struct{})}
+		ts.opening[accessKey] = op
+		ts.mu.Unlock()
+
+		// looking up the credentials and dialing may take a while, which
+		// must not block the requests of the other tenants
+		op.tenant, op.err = ts.openTenant(ctx, accessKey)
+
+		ts.mu.Lock()
+		delete(ts.opening, accessKey)
+		var evicted []*tenant
+		if op.err == nil {
+			op.tenant.element = ts.lru.PushFront(op.tenant)
+			ts.tenants[accessKey] = op.tenant
+			evicted = ts.evict()
+		}
+		close(op.done)
+		ts.mu.Unlock()
+
+		disconnectAll(evicted)
+		return op.tenant, op.err
+	}
+}
+
+// openTenant opens the store of the tenant with accessKey
+func (ts *TenantStores) openTenant(ctx context.Context, accessKey string) (*tenant, error) {
+	creds, err := ts.creds.Lookup(ctx, accessKey)
+	if err != nil {
+		return nil, err
+	}
+
+	store, disconnect, err := ts.open(ctx, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tenant{
+		accessKey:  accessKey,
+		store:      store,
+		multipart:  NewMultipartUploads(),
+		usage:      new(usageCache),
+		disconnect: disconnect,
+		owner:      ts,
+		refs:       1,
+	}, nil
+}
+
+func (ts *TenantStores) release(t *tenant) {
+	ts.mu.Lock()
+	t.refs--
+	evicted := ts.evict()
+	ts.mu.Unlock()
+
+	disconnectAll(evicted)
+}
+
+// evict removes unused tenants, starting with the least recently used, until
+// the stores fit into the capacity. The removed tenants are returned to be
+// disconnected without holding the lock.
+func (ts *TenantStores) evict() (evicted []*tenant) {
+	for e := ts.lru.Back(); e != nil && ts.lru.Len() > ts.capacity; {
+		prev := e.Prev()
+		if t := e.Value.(*tenant); t.refs == 0 {
+			ts.lru.Remove(t.element)
+			delete(ts.tenants, t.accessKey)
+			evicted = append(evicted, t)
+		}
+		e = prev
+	}
+	return evicted
+}
+
+// disconnectAll releases the resources of evicted tenants
+func disconnectAll(tenants []*tenant) {
+	for _, t := range tenants {
+		if err := t.disconnect(); err != nil {
+			zap.S().Errorf("failed to disconnect tenant %s: %v", t.accessKey, err)
+		}
+	}
+}
+
+// Public returns the public buckets of the tenants
+func (ts *TenantStores) Public() *PublicBuckets {
+	return ts.public
+}
+
+// LoadPublicBuckets finds the public buckets of the tenants with accessKeys
+func (ts *TenantStores) LoadPublicBuckets(ctx context.Context, accessKeys []string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, accessKey := range accessKeys {
+		err = ts.loadPublicBuckets(ctx, accessKey)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *TenantStores) loadPublicBuckets(ctx context.Context, accessKey string) error {
+	t, err := ts.acquire(ctx, accessKey)
+	if err != nil {
+		return err
+	}
+	defer t.release()
+
+	startAfter := ""
+	for {
+		items, more, err := t.store.List(ctx, startAfter, "", 0)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if !item.Meta.PublicRead {
+				continue
+			}
+			err = ts.public.claim(item.Bucket, accessKey)
+			if err != nil {
+				zap.S().Warnf("bucket %s of tenant %s is not public: %v", item.Bucket, accessKey, err)
+			}
+		}
+		if !more {
+			return nil
+		}
+		startAfter = items[len(items)-1].Bucket
+	}
+}
+
+// Len returns the number of cached tenant stores
+func (ts *TenantStores) Len() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.lru.Len()
+}
+
+// Close disconnects the stores of all cached tenants
+func (ts *TenantStores) Close() error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var errlist []error
+	for e := ts.lru.Front(); e != nil; e = e.Next() {
+		errlist = append(errlist, e.Value.(*tenant).disconnect())
+	}
+	ts.lru.Init()
+	ts.tenants = make(map[string]*tenant)
+	return utils.CombineErrors(errlist...)
+}
+
+// PublicBuckets keeps track of the tenants that own public-read buckets.
+// Anonymous requests do not name a tenant, so the name of a public bucket
+// must be unique among the tenants.
+type PublicBuckets struct {
+	mu     sync.RWMutex
+	owners map[string]string // access key of the owner by bucket
+}
+
+// NewPublicBuckets creates an empty PublicBuckets
+func NewPublicBuckets() *PublicBuckets {
+	return &PublicBuckets{owners: make(map[string]string)}
+}
+
+// Owner returns the access key of the tenant that owns the public bucket
+func (pb *PublicBuckets) Owner(bucket string) (accessKey string, ok bool) {
+	pb.mu.RLock()
+	defer pb.mu.RUnlock()
+	accessKey, ok = pb.owners[bucket]
+	return accessKey, ok
+}
+
+// claim makes bucket of the tenant with accessKey public
+func (pb *PublicBuckets) claim(bucket, accessKey string) error {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if owner, ok := pb.owners[bucket]; ok && owner != accessKey {
+		return minio.BucketAlreadyExists{Bucket: bucket}
+	}
+	pb.owners[bucket] = accessKey
+	return nil
+}
+
+// unclaim makes bucket of the tenant with accessKey private
+func (pb *PublicBuckets) unclaim(bucket, accessKey string) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if pb.owners[bucket] == accessKey {
+		delete(pb.owners, bucket)
+	}
+}
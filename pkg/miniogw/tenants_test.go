@@ -0,0 +1,141 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+)
+
+func tenantContext(accessKey string) context.Context {
+	return logger.SetReqInfo(context.Background(), &logger.ReqInfo{UserAgent: tenantAgent + accessKey})
+}
+
+func TestTenantStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	creds, err := NewStaticCredentials([]Credentials{
+		{AccessKey: "a", SecretKey: "secret", APIKey: "api-a"},
+		{AccessKey: "b", SecretKey: "secret", APIKey: "api-b"},
+		{AccessKey: "c", SecretKey: "secret", APIKey: "api-c"},
+	})
+	require.NoError(t, err)
+
+	opened := make(map[string]int)
+	disconnected := make(map[string]int)
+	open := func(ctx context.Context, creds *Credentials) (buckets.Store, func() error, error) {
+		opened[creds.APIKey]++
+		store := mock_buckets.NewMockStore(ctrl)
+		store.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, nil).AnyTimes()
+		return store, func() error {
+			disconnected[creds.APIKey]++
+			return nil
+		}, nil
+	}
+
+	tenants := NewTenantStores(creds, open, 2)
+	gw := &storjObjects{storj: NewMultiTenantGateway(tenants, 0)}
+
+	getBucket := func(accessKey string) {
+		_, err := gw.GetBucketInfo(tenantContext(accessKey), "bucket")
+		assert.NoError(t, err)
+	}
+
+	getBucket("a")
+	getBucket("b")
+	assert.Equal(t, 2, tenants.Len())
+	assert.Empty(t, disconnected)
+
+	// a is used more recently than b, so c evicts b
+	getBucket("a")
+	getBucket("c")
+	assert.Equal(t, 2, tenants.Len())
+	assert.Equal(t, map[string]int{"api-b": 1}, disconnected)
+
+	getBucket("b")
+	assert.Equal(t, map[string]int{"api-a": 1, "api-b": 1}, disconnected)
+
+	// tenants in use are not evicted
+	a, err := tenants.acquire(context.Background(), "a")
+	require.NoError(t, err)
+	b, err := tenants.acquire(context.Background(), "b")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"api-a": 1, "api-b": 1, "api-c": 1}, disconnected)
+
+	getBucket("c")
+	assert.Equal(t, 2, tenants.Len())
+	assert.Equal(t, map[string]int{"api-a": 2, "api-b": 2, "api-c": 2}, opened)
+	assert.Equal(t, map[string]int{"api-a": 1, "api-b": 1, "api-c": 2}, disconnected)
+
+	a.release()
+	b.release()
+	assert.Equal(t, 2, tenants.Len())
+
+	_, err = tenants.acquire(context.Background(), "unknown")
+	assert.True(t, ErrUnknownAccessKey.Has(err))
+	_, err = gw.GetBucketInfo(context.Background(), "bucket")
+	assert.Error(t, err, "request without tenant")
+
+	require.NoError(t, tenants.Close())
+	assert.Equal(t, 0, tenants.Len())
+}
+
+func TestTenantStoresOpenConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	creds, err := NewStaticCredentials([]Credentials{
+		{AccessKey: "slow", SecretKey: "secret", APIKey: "api-slow"},
+		{AccessKey: "fast", SecretKey: "secret", APIKey: "api-fast"},
+	})
+	require.NoError(t, err)
+
+	dialing := make(chan struct{})
+	dialed := make(chan struct{})
+	var opened int32
+	open := func(ctx context.Context, creds *Credentials) (buckets.Store, func() error, error) {
+		atomic.AddInt32(&opened, 1)
+		if creds.APIKey == "api-slow" {
+			close(dialing)
+			<-dialed
+		}
+		return mock_buckets.NewMockStore(ctrl), func() error { return nil }, nil
+	}
+
+	tenants := NewTenantStores(creds, open, 2)
+
+	results := make(chan *tenant, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			slow, err := tenants.acquire(context.Background(), "slow")
+			assert.NoError(t, err)
+			results <- slow
+		}()
+	}
+	<-dialing
+
+	// the other tenants are not blocked while a store is being opened
+	fast, err := tenants.acquire(context.Background(), "fast")
+	require.NoError(t, err)
+	fast.release()
+
+	close(dialed)
+	first, second := <-results, <-results
+	assert.True(t, first == second, "requests share the opened store")
+	assert.EqualValues(t, 2, atomic.LoadInt32(&opened))
+	first.release()
+	second.release()
+
+	require.NoError(t, tenants.Close())
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	minio "github.com/minio/minio/cmd"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/meta"
+)
+
+// usageInterval is how long the usage of a store is reused before it is
+// computed again, as that lists all of its objects
+const usageInterval = 5 * time.Minute
+
+// Usage is the storage a project uses
+type Usage struct {
+	Buckets int64
+	Objects int64
+	Bytes   int64
+}
+
+// ComputeUsage lists all the objects in bs to sum up their sizes
+func ComputeUsage(ctx context.Context, bs buckets.Store) (usage Usage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	startAfter := ""
+	for {
+		items, more, err := bs.List(ctx, startAfter, "", 0)
+		if err != nil {
+			return Usage{}, err
+		}
+		for _, item := range items {
+			usage.Buckets++
+			err = addBucketUsage(ctx, bs, item.Bucket, &usage)
+			if err != nil {
+				return Usage{}, err
+			}
+		}
+		if !more {
+			return usage, nil
+		}
+		startAfter = items[len(items)-1].Bucket
+	}
+}
+
+func addBucketUsage(ctx context.Context, bs buckets.Store, bucket string, usage *Usage) error {
+	o, err := bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	startAfter := ""
+	for {
+		items, more, err := o.List(ctx, "", startAfter, "", true, 0, meta.Size)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.IsPrefix {
+				continue
+			}
+			usage.Objects++
+			usage.Bytes += item.Meta.Size
+		}
+		if !more {
+			return nil
+		}
+		startAfter = items[len(items)-1].Path
+	}
+}
+
+// usageCache keeps the usage of a store for usageInterval
+type usageCache struct {
+	mu      sync.Mutex
+	usage   Usage
+	updated time.Time
+	// computing is closed once the usage that is being computed is cached
+	computing chan struct{}
+}
+
+// get returns the usage of bs, computing it if the cached one is too old.
+// The store is listed without holding the lock, and concurrent requests
+// wait for the same listing.
+func (cache *usageCache) get(ctx context.Context, bs buckets.Store, now time.Time) (Usage, error) {
+	cache.mu.Lock()
+	for cache.updated.IsZero() || now.Sub(cache.updated) >= usageInterval {
+		if cache.computing == nil {
+			return cache.compute(ctx, bs, now)
+		}
+
+		computing := cache.computing
+		cache.mu.Unlock()
+		select {
+		case <-computing:
+		case <-ctx.Done():
+			return Usage{}, ctx.Err()
+		}
+		cache.mu.Lock()
+	}
+	defer cache.mu.Unlock()
+	return cache.usage, nil
+}
+
+// compute lists bs to cache its usage. It is called with the lock held and
+// returns with it released.
+func (cache *usageCache) compute(ctx context.Context, bs buckets.Store, now time.Time) (Usage, error) {
+	computing := make(chan struct{})
+	cache.computing = computing
+	cache.mu.Unlock()
+
+	usage, err := ComputeUsage(ctx, bs)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err == nil {
+		cache.usage, cache.updated = usage, now
+	}
+	cache.computing = nil
+	close(computing)
+	if err != nil {
+		return Usage{}, err
+	}
+	return usage, nil
+}
+
+// StorageInfo reports the bytes the project of the request uses as the
+// total storage. A gateway has no limit on the space, so nothing is free.
+func (s *storjObjects) StorageInfo(ctx context.Context) (info minio.StorageInfo) {
+	t, err := s.storj.tenant(ctx)
+	if err != nil {
+		// minio asks without a request for its startup message
+		return info
+	}
+	defer t.release()
+
+	usage, err := t.usage.get(ctx, t.store, time.Now())
+	if err != nil {
+		zap.S().Warnf("failed to compute the storage usage: %v", err)
+		return info
+	}
+	usedBytes.WithLabelValues(tenantID(t.accessKey)).Set(float64(usage.Bytes))
+	storedObjects.WithLabelValues(tenantID(t.accessKey)).Set(float64(usage.Objects))
+
+	info.Total = uint64(usage.Bytes)
+	info.Backend.Type = minio.Unknown
+	return info
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/minio/minio-go/pkg/s3signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestComputeUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	first, second := NewMockStore(ctrl), NewMockStore(ctrl)
+
+	gomock.InOrder(
+		mockBS.EXPECT().List(gomock.Any(), "", "", 0).Return([]buckets.ListItem{{Bucket: "first"}}, true, nil),
+		mockBS.EXPECT().GetObjectStore(gomock.Any(), "first").Return(first, nil),
+		first.EXPECT().List(gomock.Any(), "", "", "", true, 0, meta.Size).Return([]objects.ListItem{
+			{Path: "a", Meta: objects.Meta{Size: 10}},
+			{Path: "b/", IsPrefix: true},
+			{Path: "c", Meta: objects.Meta{Size: 20}},
+		}, true, nil),
+		first.EXPECT().List(gomock.Any(), "", "c", "", true, 0, meta.Size).Return([]objects.ListItem{
+			{Path: "d", Meta: objects.Meta{Size: 30}},
+		}, false, nil),
+		mockBS.EXPECT().List(gomock.Any(), "first", "", 0).Return([]buckets.ListItem{{Bucket: "second"}}, false, nil),
+		mockBS.EXPECT().GetObjectStore(gomock.Any(), "second").Return(second, nil),
+		second.EXPECT().List(gomock.Any(), "", "", "", true, 0, meta.Size).Return(nil, false, nil),
+	)
+
+	usage, err := ComputeUsage(ctx, mockBS)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Buckets: 2, Objects: 3, Bytes: 60}, usage)
+
+	mockBS.EXPECT().List(gomock.Any(), "", "", 0).Return(nil, false, errors.New("list failed"))
+	_, err = ComputeUsage(ctx, mockBS)
+	assert.EqualError(t, err, "list failed")
+}
+
+func TestStorageInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+	storjObj := storjObjects{storj: NewStorjGateway(mockBS, storj.Unencrypted)}
+
+	// the usage is computed once per interval
+	mockBS.EXPECT().List(gomock.Any(), "", "", 0).Return([]buckets.ListItem{{Bucket: "bucket"}}, false, nil)
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil)
+	mockOS.EXPECT().List(gomock.Any(), "", "", "", true, 0, meta.Size).Return([]objects.ListItem{
+		{Path: "object", Meta: objects.Meta{Size: 42}},
+	}, false, nil)
+
+	info := storjObj.StorageInfo(ctx)
+	assert.EqualValues(t, 42, info.Total)
+	assert.EqualValues(t, 0, info.Free)
+	info = storjObj.StorageInfo(ctx)
+	assert.EqualValues(t, 42, info.Total)
+
+	cache := storjObj.storj.usage
+	usage, err := cache.get(ctx, mockBS, cache.updated.Add(usageInterval-time.Second))
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, usage.Bytes)
+
+	mockBS.EXPECT().List(gomock.Any(), "", "", 0).Return(nil, false, nil)
+	usage, err = cache.get(context.Background(), mockBS, cache.updated.Add(usageInterval))
+	require.NoError(t, err)
+	assert.Equal(t, Usage{}, usage)
+}
+
+func TestUsageCacheConcurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	started, listing := make(chan struct{}), make(chan struct{})
+	mockBS.EXPECT().List(gomock.Any(), "", "", 0).DoAndReturn(
+		func(ctx context.Context, startAfter, endBefore string, limit int) ([]buckets.ListItem, bool, error) {
+			close(started)
+			<-listing
+			return nil, false, nil
+		})
+
+	// the store is listed once, without blocking the cache
+	cache := new(usageCache)
+	now := time.Now()
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := cache.get(ctx, mockBS, now)
+			results <- err
+		}()
+	}
+
+	<-started
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := cache.get(canceled, mockBS, now)
+	assert.Equal(t, context.Canceled, err)
+
+	close(listing)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, <-results)
+	}
+}
+
+func TestS3Operation(t *testing.T) {
+	for i, tt := range []struct {
+		method, target string
+		copySource     string
+		operation      string
+	}{
+		{"GET", "/", "", "ListBuckets"},
+		{"GET", "/bucket", "", "ListObjects"},
+		{"GET", "/bucket/?list-type=2&prefix=a", "", "ListObjectsV2"},
+		{"GET", "/bucket?uploads", "", "ListMultipartUploads"},
+		{"GET", "/bucket?policy", "", "GetBucketPolicy"},
+		{"PUT", "/bucket", "", "CreateBucket"},
+		{"HEAD", "/bucket", "", "HeadBucket"},
+		{"DELETE", "/bucket", "", "DeleteBucket"},
+		{"POST", "/bucket?delete", "", "DeleteObjects"},
+		{"GET", "/bucket/object", "", "GetObject"},
+		{"HEAD", "/bucket/dir/object", "", "HeadObject"},
+		{"PUT", "/bucket/object", "", "PutObject"},
+		{"PUT", "/bucket/object", "/other/object", "CopyObject"},
+		{"DELETE", "/bucket/object", "", "DeleteObject"},
+		{"GET", "/bucket/object?tagging", "", "GetObjectTagging"},
+		{"PUT", "/bucket?object-lock", "", "PutObjectLockConfiguration"},
+		{"PUT", "/bucket/object?retention", "", "PutObjectRetention"},
+		{"GET", "/bucket/object?legal-hold", "", "GetObjectLegalHold"},
+		{"POST", "/bucket/object?uploads", "", "CreateMultipartUpload"},
+		{"PUT", "/bucket/object?partNumber=1&uploadId=id", "", "UploadPart"},
+		{"POST", "/bucket/object?uploadId=id", "", "CompleteMultipartUpload"},
+		{"PATCH", "/bucket/object", "", "Other"},
+	} {
+		r := httptest.NewRequest(tt.method, tt.target, nil)
+		if tt.copySource != "" {
+			r.Header.Set("X-Amz-Copy-Source", tt.copySource)
+		}
+		assert.Equal(t, tt.operation, s3Operation(r), "Test case #%d", i)
+	}
+}
+
+func TestProxyMetrics(t *testing.T) {
+	back := &backend{t: t, forwarded: make(chan forwarded, 1)}
+	minio := httptest.NewServer(back)
+	defer minio.Close()
+
+	target, err := url.Parse(minio.URL)
+	require.NoError(t, err)
+	proxy := httptest.NewServer(NewSingleTenantProxy(target, "minio-access", "minio-secret"))
+	defer proxy.Close()
+
+	req, err := http.NewRequest("PUT", proxy.URL+"/metrics/object", strings.NewReader("data"))
+	require.NoError(t, err)
+	req.Header.Set("X-Amz-Content-Sha256", sha256Hex([]byte("data")))
+	req = s3signer.SignV4(*req, "minio-access", "minio-secret", "", "us-east-1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	<-back.forwarded
+
+	usedBytes.WithLabelValues(tenantID("metrics-tenant")).Set(42)
+
+	resp, err = http.Get(proxy.URL + metricsPath)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	metrics := string(body)
+	assert.Contains(t, metrics, `storj_gateway_requests_total{code="200",operation="PutObject"}`)
+	assert.Contains(t, metrics, `storj_gateway_request_duration_seconds_count{operation="PutObject"}`)
+	assert.Contains(t, metrics, `storj_gateway_received_bytes_total{operation="PutObject"}`)
+	assert.Contains(t, metrics, `storj_gateway_used_bytes{tenant="`+tenantID("metrics-tenant")+`"} 42`)
+	// the access keys of the tenants are not disclosed
+	assert.NotContains(t, metrics, "metrics-tenant")
+	assert.NotEqual(t, tenantID("metrics-tenant"), tenantID("other-tenant"))
+}
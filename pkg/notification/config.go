@@ -0,0 +1,103 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/boltdb"
+	"storj.io/storj/storage/postgreskv"
+)
+
+// ctxKey is used to find the Notifier in the context
+type ctxKey int
+
+const (
+	// BoltOutboxBucket is the bucket used for the outbox in BoltDB
+	BoltOutboxBucket        = "outbox"
+	notifierKey      ctxKey = iota
+)
+
+// Config is a configuration struct for emitting bucket event notifications
+type Config struct {
+	Webhook     string `help:"URL to post the bucket events to. If empty, no events are emitted" default:""`
+	Secret      string `help:"secret to sign the events with, in the X-Storj-Signature header" default:""`
+	Buckets     string `help:"comma separated buckets to emit events for, each optionally followed by a slash and a path prefix" default:""`
+	OutboxURL   string `help:"the database connection string of the outbox for the events" default:"bolt://$CONFDIR/notifications.db"`
+	MaxAttempts int    `help:"how many times to try delivering an event" default:"20"`
+}
+
+// NewNotifier creates a Notifier with the configured values. It returns
+// nil if no webhook is configured. closeOutbox closes the outbox.
+func (c Config) NewNotifier() (n *Notifier, closeOutbox func() error, err error) {
+	if c.Webhook == "" {
+		return nil, func() error { return nil }, nil
+	}
+
+	rules, err := ParseRules(c.Buckets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := newKeyValueStore(c.OutboxURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n = NewNotifier(rules, NewOutbox(db), c.Webhook, c.Secret, zap.L())
+	if c.MaxAttempts > 0 {
+		n.maxAttempts = c.MaxAttempts
+	}
+	return n, db.Close, nil
+}
+
+func newKeyValueStore(dbURLString string) (db storage.KeyValueStore, err error) {
+	dburl, err := utils.ParseURL(dbURLString)
+	if err != nil {
+		return nil, err
+	}
+	if dburl.Scheme == "bolt" {
+		db, err = boltdb.New(dburl.Path, BoltOutboxBucket)
+	} else if dburl.Scheme == "postgresql" || dburl.Scheme == "postgres" {
+		db, err = postgreskv.New(dbURLString)
+	} else {
+		err = Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+	return db, err
+}
+
+// Run implements the provider.Responsibility interface. It has to run
+// before the responsibilities that emit events.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	n, closeOutbox, err := c.NewNotifier()
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, closeOutbox()) }()
+
+	if n != nil {
+		ctx = context.WithValue(ctx, notifierKey, n)
+		go func() {
+			err := n.Run(ctx)
+			if err != nil && err != context.Canceled {
+				zap.L().Error("event notifications stopped", zap.Error(err))
+			}
+		}()
+	}
+	return server.Run(ctx)
+}
+
+// LoadFromContext gives access to the Notifier from the context, or returns
+// nil
+func LoadFromContext(ctx context.Context) *Notifier {
+	if v, ok := ctx.Value(notifierKey).(*Notifier); ok {
+		return v
+	}
+	return nil
+}
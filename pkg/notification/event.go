@@ -0,0 +1,69 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"strings"
+	"time"
+)
+
+// EventType is the kind of change an event is about
+type EventType string
+
+// The events that are emitted
+const (
+	ObjectCreated EventType = "ObjectCreated"
+	ObjectDeleted EventType = "ObjectDeleted"
+)
+
+// Event is a change to an object in a bucket. At the satellite the path is
+// encrypted and the size is only known for created objects.
+type Event struct {
+	ID     string    `json:"id"`
+	Type   EventType `json:"type"`
+	Bucket string    `json:"bucket"`
+	Path   string    `json:"path"`
+	Size   int64     `json:"size"`
+	Time   time.Time `json:"time"`
+}
+
+// Rule selects the objects in a bucket with a prefix for notifications
+type Rule struct {
+	Bucket string
+	Prefix string
+}
+
+// Rules are the rules for which events are emitted
+type Rules []Rule
+
+// ParseRules parses a comma separated list of buckets, each optionally
+// followed by a slash and a prefix, like "photos,logs/2018/"
+func ParseRules(s string) (rules Rules, err error) {
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		parts := strings.SplitN(field, "/", 2)
+		if parts[0] == "" {
+			return nil, Error.New("rule %q has no bucket", field)
+		}
+		rule := Rule{Bucket: parts[0]}
+		if len(parts) == 2 {
+			rule.Prefix = parts[1]
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Match returns whether there is a rule for the object at path in bucket
+func (rules Rules) Match(bucket, path string) bool {
+	for _, rule := range rules {
+		if rule.Bucket == bucket && strings.HasPrefix(path, rule.Prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+)
+
+// signatureHeader carries the HMAC-SHA256 of the body of a webhook request
+// with the webhook secret
+const signatureHeader = "X-Storj-Signature"
+
+// batchSize is how many events are delivered at a time
+const batchSize = 100
+
+// errRejected is returned for events that the webhook will not accept when
+// they are delivered again
+var errRejected = errs.Class("event rejected")
+
+// Notifier emits the events of the objects that match its rules to a
+// webhook. The events are stored in an outbox first and delivered in the
+// background, retrying with exponential backoff until the webhook accepts
+// them or rejects them with a client error.
+type Notifier struct {
+	rules   Rules
+	outbox  *Outbox
+	webhook string
+	secret  []byte
+	client  *http.Client
+	logger  *zap.Logger
+
+	interval    time.Duration
+	maxBackoff  time.Duration
+	maxAttempts int
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+// NewNotifier creates a Notifier for the events matching rules
+func NewNotifier(rules Rules, outbox *Outbox, webhook, secret string, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		rules:       rules,
+		outbox:      outbox,
+		webhook:     webhook,
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: 30 * time.Second},
+		logger:      logger,
+		interval:    time.Second,
+		maxBackoff:  time.Hour,
+		maxAttempts: 20,
+		wake:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+}
+
+// Notify stores an event for the object at path in bucket if a rule
+// matches it. It is safe to call on a nil Notifier.
+func (n *Notifier) Notify(ctx context.Context, eventType EventType, bucket, path string, size int64) (err error) {
+	if n == nil || !n.rules.Match(bucket, path) {
+		return nil
+	}
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = n.outbox.Add(Event{
+		Type:   eventType,
+		Bucket: bucket,
+		Path:   path,
+		Size:   size,
+		Time:   n.now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers the events in the outbox until ctx is canceled
+func (n *Notifier) Run(ctx context.Context) error {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		err := n.deliverDue(ctx)
+		if err != nil {
+			n.logger.Error("failed to deliver events", zap.Error(err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-n.wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// deliverDue delivers the events whose next attempt is due
+func (n *Notifier) deliverDue(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := n.now()
+	entries, err := n.outbox.List(now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		err = n.deliver(ctx, entry.Event)
+		if err == nil {
+			err = n.outbox.Delete(entry)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		entry.Attempts++
+		if errRejected.Has(err) || entry.Attempts >= n.maxAttempts {
+			n.logger.Error("dropping event",
+				zap.String("id", entry.Event.ID), zap.Int("attempts", entry.Attempts), zap.Error(err))
+			err = n.outbox.Delete(entry)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		n.logger.Debug("failed to deliver event",
+			zap.String("id", entry.Event.ID), zap.Int("attempts", entry.Attempts), zap.Error(err))
+		err = n.outbox.Reschedule(entry, now.Add(n.backoff(entry.Attempts)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backoff returns how long to wait after the failed attempts to deliver an
+// event
+func (n *Notifier) backoff(attempts int) time.Duration {
+	backoff := n.interval
+	for i := 1; i < attempts && backoff < n.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > n.maxBackoff {
+		backoff = n.maxBackoff
+	}
+	return backoff
+}
+
+// deliver posts event to the webhook
+func (n *Notifier) deliver(ctx context.Context, event Event) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.webhook, bytes.NewReader(body))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		_, _ = mac.Write(body)
+		req.Header.Set(signatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests:
+		return errRejected.New("webhook responded with %s", resp.Status)
+	}
+	return Error.New("webhook responded with %s", resp.Status)
+}
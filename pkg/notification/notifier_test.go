@@ -0,0 +1,201 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage/teststore"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(" photos, logs/2018/ ,")
+	require.NoError(t, err)
+	assert.Equal(t, Rules{{Bucket: "photos"}, {Bucket: "logs", Prefix: "2018/"}}, rules)
+
+	for i, tt := range []struct {
+		bucket, path string
+		match        bool
+	}{
+		{"photos", "a.jpg", true},
+		{"photos", "", true},
+		{"logs", "2018/01/01.log", true},
+		{"logs", "2017/01/01.log", false},
+		{"videos", "a.mp4", false},
+	} {
+		assert.Equal(t, tt.match, rules.Match(tt.bucket, tt.path), i)
+	}
+
+	_, err = ParseRules("/prefix")
+	assert.Error(t, err)
+
+	rules, err = ParseRules("")
+	assert.NoError(t, err)
+	assert.False(t, rules.Match("photos", "a.jpg"))
+}
+
+// farFuture lists all the entries of an outbox
+var farFuture = time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOutbox(t *testing.T) {
+	outbox := NewOutbox(teststore.New())
+
+	now := time.Now()
+	var ids []string
+	for i := 0; i < 3; i++ {
+		// the same time must still give increasing IDs
+		id, err := outbox.Add(Event{Type: ObjectCreated, Bucket: "bucket", Time: now})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	entries, err := outbox.List(now, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, ids[0], entries[0].Event.ID)
+	assert.Equal(t, ids[1], entries[1].Event.ID)
+
+	// only the entries that are due are listed, by their next attempt
+	entries[0].Attempts = 3
+	require.NoError(t, outbox.Reschedule(entries[0], now.Add(time.Minute)))
+	require.NoError(t, outbox.Delete(entries[1]))
+
+	entries, err = outbox.List(now, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ids[2], entries[0].Event.ID)
+
+	entries, err = outbox.List(now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, ids[2], entries[0].Event.ID)
+	assert.Equal(t, ids[0], entries[1].Event.ID)
+	assert.Equal(t, 3, entries[1].Attempts)
+}
+
+func TestNotifier(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var received []Event
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		_, _ = mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(signatureHeader))
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		var event Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	rules := Rules{{Bucket: "bucket", Prefix: "dir/"}}
+	outbox := NewOutbox(teststore.New())
+	n := NewNotifier(rules, outbox, server.URL, "secret", zap.NewNop())
+	now := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	n.now = func() time.Time { return now }
+	n.maxAttempts = 3
+
+	require.NoError(t, n.Notify(ctx, ObjectCreated, "bucket", "dir/file", 10))
+	require.NoError(t, n.Notify(ctx, ObjectCreated, "bucket", "other", 10))
+	require.NoError(t, n.Notify(ctx, ObjectDeleted, "other", "dir/file", 0))
+
+	// a nil notifier emits nothing
+	var nilNotifier *Notifier
+	assert.NoError(t, nilNotifier.Notify(ctx, ObjectCreated, "bucket", "dir/file", 10))
+
+	entries, err := outbox.List(farFuture, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// a failed delivery is retried after a backoff
+	require.NoError(t, n.deliverDue(ctx))
+	entries, err = outbox.List(farFuture, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 1, entries[0].Attempts)
+	assert.Equal(t, now.Add(n.interval), entries[0].NextAttempt.UTC())
+
+	// the event is not delivered before its next attempt
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+	require.NoError(t, n.deliverDue(ctx))
+	assert.Empty(t, received)
+
+	now = now.Add(n.interval)
+	require.NoError(t, n.deliverDue(ctx))
+	require.Len(t, received, 1)
+	assert.Equal(t, Event{
+		ID:     entries[0].Event.ID,
+		Type:   ObjectCreated,
+		Bucket: "bucket",
+		Path:   "dir/file",
+		Size:   10,
+		Time:   time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC),
+	}, received[0])
+
+	entries, err = outbox.List(farFuture, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// the event is dropped after too many attempts
+	mu.Lock()
+	status = http.StatusInternalServerError
+	mu.Unlock()
+	require.NoError(t, n.Notify(ctx, ObjectDeleted, "bucket", "dir/file", 0))
+	for i := 0; i < n.maxAttempts; i++ {
+		now = now.Add(n.maxBackoff)
+		require.NoError(t, n.deliverDue(ctx))
+	}
+	entries, err = outbox.List(farFuture, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// the event is dropped when the webhook rejects it
+	mu.Lock()
+	status = http.StatusBadRequest
+	mu.Unlock()
+	require.NoError(t, n.Notify(ctx, ObjectDeleted, "bucket", "dir/file", 0))
+	require.NoError(t, n.deliverDue(ctx))
+	entries, err = outbox.List(farFuture, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestBackoff(t *testing.T) {
+	n := NewNotifier(nil, nil, "", "", zap.NewNop())
+	n.interval = time.Second
+	n.maxBackoff = 10 * time.Second
+
+	for attempts, backoff := range []time.Duration{
+		time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second,
+	} {
+		assert.Equal(t, backoff, n.backoff(attempts), attempts)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"storj.io/storj/storage"
+)
+
+// Entry is an event in the outbox that is not delivered yet
+type Entry struct {
+	Event       Event     `json:"event"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// Outbox keeps the events until they are delivered, so that they survive
+// restarts. The entries are ordered by their next attempt, then by the time
+// their events were added.
+type Outbox struct {
+	db storage.KeyValueStore
+
+	mu   sync.Mutex
+	last int64
+}
+
+// NewOutbox returns an outbox that stores the events in db
+func NewOutbox(db storage.KeyValueStore) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Add stores event with a new ID, which it returns
+func (outbox *Outbox) Add(event Event) (id string, err error) {
+	event.ID = outbox.nextID(event.Time)
+	err = outbox.Put(Entry{Event: event, NextAttempt: event.Time})
+	if err != nil {
+		return "", err
+	}
+	return event.ID, nil
+}
+
+// nextID returns an ID that sorts after all the previous ones
+func (outbox *Outbox) nextID(now time.Time) string {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+
+	id := now.UnixNano()
+	if id <= outbox.last {
+		id = outbox.last + 1
+	}
+	outbox.last = id
+	return fmt.Sprintf("%020d", id)
+}
+
+// entryKey returns the key of entry, which sorts by its next attempt
+func entryKey(entry Entry) storage.Key {
+	return storage.Key(fmt.Sprintf("%020d/%s", entry.NextAttempt.UnixNano(), entry.Event.ID))
+}
+
+// Put stores entry
+func (outbox *Outbox) Put(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(outbox.db.Put(entryKey(entry), data))
+}
+
+// Reschedule stores entry to be attempted again at next. The entry is
+// stored again before it is deleted, so that it is delivered twice rather
+// than lost if the outbox fails in between.
+func (outbox *Outbox) Reschedule(entry Entry, next time.Time) error {
+	rescheduled := entry
+	rescheduled.NextAttempt = next
+	if err := outbox.Put(rescheduled); err != nil {
+		return err
+	}
+	return outbox.Delete(entry)
+}
+
+// Delete removes entry
+func (outbox *Outbox) Delete(entry Entry) error {
+	return Error.Wrap(outbox.db.Delete(entryKey(entry)))
+}
+
+// List returns up to limit of the entries whose next attempt is due at
+// now, ordered by their next attempt
+func (outbox *Outbox) List(now time.Time, limit int) (entries []Entry, err error) {
+	err = outbox.db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for len(entries) < limit && it.Next(&item) {
+			var entry Entry
+			err := json.Unmarshal(item.Value, &entry)
+			if err != nil {
+				return err
+			}
+			if entry.NextAttempt.After(now) {
+				return nil
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, Error.Wrap(err)
+}
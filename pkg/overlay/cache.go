@@ -96,6 +96,14 @@ func (o *Cache) Put(nodeID storj.NodeID, value pb.Node) error {
 		return nil
 	}
 
+	// only the satellite marks the nodes leaving the network, so the flag
+	// is kept from the stored node rather than taken from value
+	exiting, err := o.IsExiting(context.Background(), nodeID)
+	if err != nil {
+		return err
+	}
+	value.Exiting = exiting
+
 	data, err := proto.Marshal(&value)
 	if err != nil {
 		return err
@@ -104,6 +112,38 @@ func (o *Cache) Put(nodeID storj.NodeID, value pb.Node) error {
 	return o.DB.Put(nodeID.Bytes(), data)
 }
 
+// MarkExiting marks the node with nodeID as leaving the network, so that it
+// is no longer selected for new pieces. The node is stored if it is not
+// known yet.
+func (o *Cache) MarkExiting(ctx context.Context, nodeID storj.NodeID) error {
+	node, err := o.Get(ctx, nodeID)
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return OverlayError.Wrap(err)
+	}
+	if node == nil {
+		node = &pb.Node{Id: nodeID}
+	}
+	node.Exiting = true
+
+	data, err := proto.Marshal(node)
+	if err != nil {
+		return OverlayError.Wrap(err)
+	}
+	return OverlayError.Wrap(o.DB.Put(nodeID.Bytes(), data))
+}
+
+// IsExiting returns whether the node with nodeID is leaving the network
+func (o *Cache) IsExiting(ctx context.Context, nodeID storj.NodeID) (bool, error) {
+	node, err := o.Get(ctx, nodeID)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return false, nil
+		}
+		return false, OverlayError.Wrap(err)
+	}
+	return node.GetExiting(), nil
+}
+
 // Bootstrap walks the initialized network and populates the cache
 func (o *Cache) Bootstrap(ctx context.Context) error {
 	// TODO(coyle): make Bootstrap work
@@ -89,6 +89,45 @@ func testCache(ctx context.Context, t *testing.T, store storage.KeyValueStore) {
 			assert.Error(t, err)
 		}
 	}
+
+	{ // MarkExiting
+		exiting, err := cache.IsExiting(ctx, valid1ID)
+		if assert.NoError(t, err) {
+			assert.False(t, exiting)
+		}
+
+		assert.NoError(t, cache.MarkExiting(ctx, valid1ID))
+		exiting, err = cache.IsExiting(ctx, valid1ID)
+		if assert.NoError(t, err) {
+			assert.True(t, exiting)
+		}
+
+		// the flag is kept when the node is refreshed
+		err = cache.Put(valid1ID, pb.Node{Address: &pb.NodeAddress{Transport: pb.NodeTransport_TCP_TLS_GRPC, Address: "127.0.0.1:9003"}})
+		if assert.NoError(t, err) {
+			valid1, err := cache.Get(ctx, valid1ID)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "127.0.0.1:9003", valid1.Address.Address)
+				assert.True(t, valid1.Exiting)
+			}
+		}
+
+		// and is not taken from the nodes put
+		err = cache.Put(valid2ID, pb.Node{Exiting: true})
+		if assert.NoError(t, err) {
+			exiting, err = cache.IsExiting(ctx, valid2ID)
+			if assert.NoError(t, err) {
+				assert.False(t, exiting)
+			}
+		}
+
+		// unknown nodes are stored as exiting
+		assert.NoError(t, cache.MarkExiting(ctx, invalid1ID))
+		exiting, err = cache.IsExiting(ctx, invalid1ID)
+		if assert.NoError(t, err) {
+			assert.True(t, exiting)
+		}
+	}
 }
 
 func TestCache_Redis(t *testing.T) {
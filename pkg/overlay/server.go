@@ -78,19 +78,17 @@ func (o *Server) FindStorageNodes(ctx context.Context, req *pb.FindStorageNodesR
 	restrictedBandwidth := restrictions.GetFreeBandwidth()
 	restrictedSpace := restrictions.GetFreeDisk()
 
-	var startID storj.NodeID
+	startID := req.Start
 	result := []*pb.Node{}
 	for {
 		var nodes []*pb.Node
-		nodes, startID, err = o.populate(ctx, req.Start, maxNodes, restrictedBandwidth, restrictedSpace, excluded)
+		// the nodes of a page may all be excluded or exiting, so the pages
+		// are searched until the last one
+		nodes, startID, err = o.populate(ctx, startID, maxNodes, restrictedBandwidth, restrictedSpace, excluded)
 		if err != nil {
 			return nil, Error.Wrap(err)
 		}
 
-		if len(nodes) <= 0 {
-			break
-		}
-
 		result = append(result, nodes...)
 
 		if len(result) >= int(maxNodes) || startID == (storj.NodeID{}) {
@@ -154,6 +152,10 @@ func (o *Server) populate(ctx context.Context, startID storj.NodeID, maxNodes, r
 	}
 
 	for _, v := range nodes {
+		// the first node of a page is the last one of the previous page
+		if startID != (storj.NodeID{}) && v.Id == startID {
+			continue
+		}
 		if v.Type != pb.NodeType_STORAGE {
 			continue
 		}
@@ -162,7 +164,7 @@ func (o *Server) populate(ctx context.Context, startID storj.NodeID, maxNodes, r
 		if rest.GetFreeBandwidth() < restrictedBandwidth || rest.GetFreeDisk() < restrictedSpace {
 			continue
 		}
-		if contains(excluded, v.Id) {
+		if contains(excluded, v.Id) || v.GetExiting() {
 			continue
 		}
 		result = append(result, v)
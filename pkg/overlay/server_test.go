@@ -65,4 +65,16 @@ func TestServer(t *testing.T) {
 			}
 		}
 	}
+
+	{ // FindStorageNodes skips exiting nodes
+		first, err := server.FindStorageNodes(ctx, &pb.FindStorageNodesRequest{Opts: &pb.OverlayOptions{Amount: 1}})
+		if assert.NoError(t, err) && assert.Len(t, first.Nodes, 1) {
+			assert.NoError(t, satellite.Overlay.MarkExiting(ctx, first.Nodes[0].Id))
+
+			result, err := server.FindStorageNodes(ctx, &pb.FindStorageNodesRequest{Opts: &pb.OverlayOptions{Amount: 1}})
+			if assert.NoError(t, err) && assert.Len(t, result.Nodes, 1) {
+				assert.NotEqual(t, first.Nodes[0].Id, result.Nodes[0].Id)
+			}
+		}
+	}
 }
@@ -7,6 +7,11 @@ import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
 
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
@@ -31,7 +36,7 @@ func (m *InjuredSegment) Reset()         { *m = InjuredSegment{} }
 func (m *InjuredSegment) String() string { return proto.CompactTextString(m) }
 func (*InjuredSegment) ProtoMessage()    {}
 func (*InjuredSegment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_datarepair_13e4beab54f194bd, []int{0}
+	return fileDescriptor_datarepair_ff83bef3ce9c14b1, []int{0}
 }
 func (m *InjuredSegment) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InjuredSegment.Unmarshal(m, b)
@@ -65,20 +70,169 @@ func (m *InjuredSegment) GetLostPieces() []int32 {
 	return nil
 }
 
+// CorruptedPieces are the IDs of the pieces that the reporting storage node
+// can no longer serve
+type CorruptedPieces struct {
+	PieceIds             []string `protobuf:"bytes,1,rep,name=piece_ids,json=pieceIds" json:"piece_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CorruptedPieces) Reset()         { *m = CorruptedPieces{} }
+func (m *CorruptedPieces) String() string { return proto.CompactTextString(m) }
+func (*CorruptedPieces) ProtoMessage()    {}
+func (*CorruptedPieces) Descriptor() ([]byte, []int) {
+	return fileDescriptor_datarepair_ff83bef3ce9c14b1, []int{1}
+}
+func (m *CorruptedPieces) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CorruptedPieces.Unmarshal(m, b)
+}
+func (m *CorruptedPieces) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CorruptedPieces.Marshal(b, m, deterministic)
+}
+func (dst *CorruptedPieces) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CorruptedPieces.Merge(dst, src)
+}
+func (m *CorruptedPieces) XXX_Size() int {
+	return xxx_messageInfo_CorruptedPieces.Size(m)
+}
+func (m *CorruptedPieces) XXX_DiscardUnknown() {
+	xxx_messageInfo_CorruptedPieces.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CorruptedPieces proto.InternalMessageInfo
+
+func (m *CorruptedPieces) GetPieceIds() []string {
+	if m != nil {
+		return m.PieceIds
+	}
+	return nil
+}
+
+type CorruptedPiecesResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CorruptedPiecesResponse) Reset()         { *m = CorruptedPiecesResponse{} }
+func (m *CorruptedPiecesResponse) String() string { return proto.CompactTextString(m) }
+func (*CorruptedPiecesResponse) ProtoMessage()    {}
+func (*CorruptedPiecesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_datarepair_ff83bef3ce9c14b1, []int{2}
+}
+func (m *CorruptedPiecesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CorruptedPiecesResponse.Unmarshal(m, b)
+}
+func (m *CorruptedPiecesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CorruptedPiecesResponse.Marshal(b, m, deterministic)
+}
+func (dst *CorruptedPiecesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CorruptedPiecesResponse.Merge(dst, src)
+}
+func (m *CorruptedPiecesResponse) XXX_Size() int {
+	return xxx_messageInfo_CorruptedPiecesResponse.Size(m)
+}
+func (m *CorruptedPiecesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CorruptedPiecesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CorruptedPiecesResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*InjuredSegment)(nil), "repair.InjuredSegment")
+	proto.RegisterType((*CorruptedPieces)(nil), "repair.CorruptedPieces")
+	proto.RegisterType((*CorruptedPiecesResponse)(nil), "repair.CorruptedPiecesResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CorruptionClient is the client API for Corruption service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CorruptionClient interface {
+	ReportCorrupted(ctx context.Context, in *CorruptedPieces, opts ...grpc.CallOption) (*CorruptedPiecesResponse, error)
+}
+
+type corruptionClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCorruptionClient(cc *grpc.ClientConn) CorruptionClient {
+	return &corruptionClient{cc}
+}
+
+func (c *corruptionClient) ReportCorrupted(ctx context.Context, in *CorruptedPieces, opts ...grpc.CallOption) (*CorruptedPiecesResponse, error) {
+	out := new(CorruptedPiecesResponse)
+	err := c.cc.Invoke(ctx, "/repair.Corruption/ReportCorrupted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CorruptionServer is the server API for Corruption service.
+type CorruptionServer interface {
+	ReportCorrupted(context.Context, *CorruptedPieces) (*CorruptedPiecesResponse, error)
+}
+
+func RegisterCorruptionServer(s *grpc.Server, srv CorruptionServer) {
+	s.RegisterService(&_Corruption_serviceDesc, srv)
+}
+
+func _Corruption_ReportCorrupted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CorruptedPieces)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CorruptionServer).ReportCorrupted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/repair.Corruption/ReportCorrupted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CorruptionServer).ReportCorrupted(ctx, req.(*CorruptedPieces))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Corruption_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "repair.Corruption",
+	HandlerType: (*CorruptionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReportCorrupted",
+			Handler:    _Corruption_ReportCorrupted_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "datarepair.proto",
 }
 
-func init() { proto.RegisterFile("datarepair.proto", fileDescriptor_datarepair_13e4beab54f194bd) }
+func init() { proto.RegisterFile("datarepair.proto", fileDescriptor_datarepair_ff83bef3ce9c14b1) }
 
-var fileDescriptor_datarepair_13e4beab54f194bd = []byte{
-	// 119 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_datarepair_ff83bef3ce9c14b1 = []byte{
+	// 203 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x48, 0x49, 0x2c, 0x49,
 	0x2c, 0x4a, 0x2d, 0x48, 0xcc, 0x2c, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x83, 0xf0,
 	0x94, 0x5c, 0xb9, 0xf8, 0x3c, 0xf3, 0xb2, 0x4a, 0x8b, 0x52, 0x53, 0x82, 0x53, 0xd3, 0x73, 0x53,
 	0xf3, 0x4a, 0x84, 0x84, 0xb8, 0x58, 0x0a, 0x12, 0x4b, 0x32, 0x24, 0x18, 0x15, 0x18, 0x35, 0x38,
 	0x83, 0xc0, 0x6c, 0x21, 0x79, 0x2e, 0xee, 0x9c, 0xfc, 0xe2, 0x92, 0xf8, 0x82, 0xcc, 0xd4, 0xe4,
-	0xd4, 0x62, 0x09, 0x26, 0x05, 0x66, 0x0d, 0xd6, 0x20, 0x2e, 0x90, 0x50, 0x00, 0x58, 0xc4, 0x89,
-	0x25, 0x8a, 0xa9, 0x20, 0x29, 0x89, 0x0d, 0x6c, 0xb6, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0x13,
-	0xff, 0xff, 0x1e, 0x6f, 0x00, 0x00, 0x00,
+	0xd4, 0x62, 0x09, 0x26, 0x05, 0x66, 0x0d, 0xd6, 0x20, 0x2e, 0x90, 0x50, 0x00, 0x58, 0x44, 0x49,
+	0x8f, 0x8b, 0xdf, 0x39, 0xbf, 0xa8, 0xa8, 0xb4, 0xa0, 0x24, 0x35, 0x05, 0x22, 0x24, 0x24, 0xcd,
+	0xc5, 0x09, 0x56, 0x1e, 0x9f, 0x99, 0x52, 0x2c, 0xc1, 0xa8, 0xc0, 0xac, 0xc1, 0x19, 0xc4, 0x01,
+	0x16, 0xf0, 0x4c, 0x29, 0x56, 0x92, 0xe4, 0x12, 0x47, 0x53, 0x1f, 0x94, 0x5a, 0x5c, 0x90, 0x9f,
+	0x57, 0x9c, 0x6a, 0x14, 0xc9, 0xc5, 0x05, 0x95, 0xca, 0xcc, 0xcf, 0x13, 0xf2, 0xe6, 0xe2, 0x0f,
+	0x4a, 0x2d, 0xc8, 0x2f, 0x2a, 0x81, 0x2b, 0x17, 0x12, 0xd7, 0x83, 0xfa, 0x04, 0xcd, 0x04, 0x29,
+	0x79, 0x1c, 0x12, 0x30, 0xa3, 0x9d, 0x58, 0xa2, 0x98, 0x0a, 0x92, 0x92, 0xd8, 0xc0, 0x21, 0x60,
+	0x0c, 0x18, 0x00, 0x5d, 0x6a, 0xe6, 0x31, 0x15, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,661 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: gracefulexit.proto
+
+package pb
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import _ "github.com/gogo/protobuf/gogoproto"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type ExitRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExitRequest) Reset()         { *m = ExitRequest{} }
+func (m *ExitRequest) String() string { return proto.CompactTextString(m) }
+func (*ExitRequest) ProtoMessage()    {}
+func (*ExitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{0}
+}
+func (m *ExitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExitRequest.Unmarshal(m, b)
+}
+func (m *ExitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExitRequest.Marshal(b, m, deterministic)
+}
+func (dst *ExitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExitRequest.Merge(dst, src)
+}
+func (m *ExitRequest) XXX_Size() int {
+	return xxx_messageInfo_ExitRequest.Size(m)
+}
+func (m *ExitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExitRequest proto.InternalMessageInfo
+
+type ExitResponse struct {
+	Transfers []*PieceTransfer `protobuf:"bytes,1,rep,name=transfers" json:"transfers,omitempty"`
+	// allocation and authorization for uploading the pieces
+	PayerAllocation      *PayerBandwidthAllocation `protobuf:"bytes,2,opt,name=payer_allocation,json=payerAllocation" json:"payer_allocation,omitempty"`
+	Authorization        *SignedMessage            `protobuf:"bytes,3,opt,name=authorization" json:"authorization,omitempty"`
+	Progress             *ExitProgress             `protobuf:"bytes,4,opt,name=progress" json:"progress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ExitResponse) Reset()         { *m = ExitResponse{} }
+func (m *ExitResponse) String() string { return proto.CompactTextString(m) }
+func (*ExitResponse) ProtoMessage()    {}
+func (*ExitResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{1}
+}
+func (m *ExitResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExitResponse.Unmarshal(m, b)
+}
+func (m *ExitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExitResponse.Marshal(b, m, deterministic)
+}
+func (dst *ExitResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExitResponse.Merge(dst, src)
+}
+func (m *ExitResponse) XXX_Size() int {
+	return xxx_messageInfo_ExitResponse.Size(m)
+}
+func (m *ExitResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExitResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExitResponse proto.InternalMessageInfo
+
+func (m *ExitResponse) GetTransfers() []*PieceTransfer {
+	if m != nil {
+		return m.Transfers
+	}
+	return nil
+}
+
+func (m *ExitResponse) GetPayerAllocation() *PayerBandwidthAllocation {
+	if m != nil {
+		return m.PayerAllocation
+	}
+	return nil
+}
+
+func (m *ExitResponse) GetAuthorization() *SignedMessage {
+	if m != nil {
+		return m.Authorization
+	}
+	return nil
+}
+
+func (m *ExitResponse) GetProgress() *ExitProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+// PieceTransfer is a piece to upload to a replacement node
+type PieceTransfer struct {
+	Path     string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	PieceNum int32  `protobuf:"varint,2,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
+	// the piece ID of the segment, which the node IDs are derived from
+	PieceId              string   `protobuf:"bytes,3,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
+	Replacement          *Node    `protobuf:"bytes,4,opt,name=replacement" json:"replacement,omitempty"`
+	ExpirationUnixSec    int64    `protobuf:"varint,5,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceTransfer) Reset()         { *m = PieceTransfer{} }
+func (m *PieceTransfer) String() string { return proto.CompactTextString(m) }
+func (*PieceTransfer) ProtoMessage()    {}
+func (*PieceTransfer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{2}
+}
+func (m *PieceTransfer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceTransfer.Unmarshal(m, b)
+}
+func (m *PieceTransfer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceTransfer.Marshal(b, m, deterministic)
+}
+func (dst *PieceTransfer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceTransfer.Merge(dst, src)
+}
+func (m *PieceTransfer) XXX_Size() int {
+	return xxx_messageInfo_PieceTransfer.Size(m)
+}
+func (m *PieceTransfer) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceTransfer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceTransfer proto.InternalMessageInfo
+
+func (m *PieceTransfer) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *PieceTransfer) GetPieceNum() int32 {
+	if m != nil {
+		return m.PieceNum
+	}
+	return 0
+}
+
+func (m *PieceTransfer) GetPieceId() string {
+	if m != nil {
+		return m.PieceId
+	}
+	return ""
+}
+
+func (m *PieceTransfer) GetReplacement() *Node {
+	if m != nil {
+		return m.Replacement
+	}
+	return nil
+}
+
+func (m *PieceTransfer) GetExpirationUnixSec() int64 {
+	if m != nil {
+		return m.ExpirationUnixSec
+	}
+	return 0
+}
+
+type TransferConfirmation struct {
+	Path     string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	PieceNum int32  `protobuf:"varint,2,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
+	// the receipt of the replacement node, or the reason of the failure
+	Receipt              *PieceStoreReceipt `protobuf:"bytes,3,opt,name=receipt" json:"receipt,omitempty"`
+	Error                string             `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *TransferConfirmation) Reset()         { *m = TransferConfirmation{} }
+func (m *TransferConfirmation) String() string { return proto.CompactTextString(m) }
+func (*TransferConfirmation) ProtoMessage()    {}
+func (*TransferConfirmation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{3}
+}
+func (m *TransferConfirmation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferConfirmation.Unmarshal(m, b)
+}
+func (m *TransferConfirmation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferConfirmation.Marshal(b, m, deterministic)
+}
+func (dst *TransferConfirmation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferConfirmation.Merge(dst, src)
+}
+func (m *TransferConfirmation) XXX_Size() int {
+	return xxx_messageInfo_TransferConfirmation.Size(m)
+}
+func (m *TransferConfirmation) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferConfirmation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferConfirmation proto.InternalMessageInfo
+
+func (m *TransferConfirmation) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TransferConfirmation) GetPieceNum() int32 {
+	if m != nil {
+		return m.PieceNum
+	}
+	return 0
+}
+
+func (m *TransferConfirmation) GetReceipt() *PieceStoreReceipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+func (m *TransferConfirmation) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type TransferConfirmationResponse struct {
+	Progress *ExitProgress `protobuf:"bytes,1,opt,name=progress" json:"progress,omitempty"`
+	// whether the piece was moved to the replacement node, so that the
+	// exiting node can delete it
+	Transferred          bool     `protobuf:"varint,2,opt,name=transferred,proto3" json:"transferred,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferConfirmationResponse) Reset()         { *m = TransferConfirmationResponse{} }
+func (m *TransferConfirmationResponse) String() string { return proto.CompactTextString(m) }
+func (*TransferConfirmationResponse) ProtoMessage()    {}
+func (*TransferConfirmationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{4}
+}
+func (m *TransferConfirmationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferConfirmationResponse.Unmarshal(m, b)
+}
+func (m *TransferConfirmationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferConfirmationResponse.Marshal(b, m, deterministic)
+}
+func (dst *TransferConfirmationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferConfirmationResponse.Merge(dst, src)
+}
+func (m *TransferConfirmationResponse) XXX_Size() int {
+	return xxx_messageInfo_TransferConfirmationResponse.Size(m)
+}
+func (m *TransferConfirmationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferConfirmationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferConfirmationResponse proto.InternalMessageInfo
+
+func (m *TransferConfirmationResponse) GetProgress() *ExitProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+func (m *TransferConfirmationResponse) GetTransferred() bool {
+	if m != nil {
+		return m.Transferred
+	}
+	return false
+}
+
+type ExitProgress struct {
+	PiecesTotal          int64    `protobuf:"varint,1,opt,name=pieces_total,json=piecesTotal,proto3" json:"pieces_total,omitempty"`
+	PiecesTransferred    int64    `protobuf:"varint,2,opt,name=pieces_transferred,json=piecesTransferred,proto3" json:"pieces_transferred,omitempty"`
+	PiecesFailed         int64    `protobuf:"varint,3,opt,name=pieces_failed,json=piecesFailed,proto3" json:"pieces_failed,omitempty"`
+	Finished             bool     `protobuf:"varint,4,opt,name=finished,proto3" json:"finished,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExitProgress) Reset()         { *m = ExitProgress{} }
+func (m *ExitProgress) String() string { return proto.CompactTextString(m) }
+func (*ExitProgress) ProtoMessage()    {}
+func (*ExitProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{5}
+}
+func (m *ExitProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExitProgress.Unmarshal(m, b)
+}
+func (m *ExitProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExitProgress.Marshal(b, m, deterministic)
+}
+func (dst *ExitProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExitProgress.Merge(dst, src)
+}
+func (m *ExitProgress) XXX_Size() int {
+	return xxx_messageInfo_ExitProgress.Size(m)
+}
+func (m *ExitProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExitProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExitProgress proto.InternalMessageInfo
+
+func (m *ExitProgress) GetPiecesTotal() int64 {
+	if m != nil {
+		return m.PiecesTotal
+	}
+	return 0
+}
+
+func (m *ExitProgress) GetPiecesTransferred() int64 {
+	if m != nil {
+		return m.PiecesTransferred
+	}
+	return 0
+}
+
+func (m *ExitProgress) GetPiecesFailed() int64 {
+	if m != nil {
+		return m.PiecesFailed
+	}
+	return 0
+}
+
+func (m *ExitProgress) GetFinished() bool {
+	if m != nil {
+		return m.Finished
+	}
+	return false
+}
+
+type NodeExitRequest struct {
+	SatelliteId          NodeID   `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	SatelliteAddress     string   `protobuf:"bytes,2,opt,name=satellite_address,json=satelliteAddress,proto3" json:"satellite_address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeExitRequest) Reset()         { *m = NodeExitRequest{} }
+func (m *NodeExitRequest) String() string { return proto.CompactTextString(m) }
+func (*NodeExitRequest) ProtoMessage()    {}
+func (*NodeExitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_gracefulexit_f451021617107ca8, []int{6}
+}
+func (m *NodeExitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeExitRequest.Unmarshal(m, b)
+}
+func (m *NodeExitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeExitRequest.Marshal(b, m, deterministic)
+}
+func (dst *NodeExitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeExitRequest.Merge(dst, src)
+}
+func (m *NodeExitRequest) XXX_Size() int {
+	return xxx_messageInfo_NodeExitRequest.Size(m)
+}
+func (m *NodeExitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeExitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeExitRequest proto.InternalMessageInfo
+
+func (m *NodeExitRequest) GetSatelliteAddress() string {
+	if m != nil {
+		return m.SatelliteAddress
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ExitRequest)(nil), "gracefulexit.ExitRequest")
+	proto.RegisterType((*ExitResponse)(nil), "gracefulexit.ExitResponse")
+	proto.RegisterType((*PieceTransfer)(nil), "gracefulexit.PieceTransfer")
+	proto.RegisterType((*TransferConfirmation)(nil), "gracefulexit.TransferConfirmation")
+	proto.RegisterType((*TransferConfirmationResponse)(nil), "gracefulexit.TransferConfirmationResponse")
+	proto.RegisterType((*ExitProgress)(nil), "gracefulexit.ExitProgress")
+	proto.RegisterType((*NodeExitRequest)(nil), "gracefulexit.NodeExitRequest")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GracefulExitClient is the client API for GracefulExit service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GracefulExitClient interface {
+	// Exit starts or continues the exit of the calling node and returns the
+	// next pieces to transfer
+	Exit(ctx context.Context, in *ExitRequest, opts ...grpc.CallOption) (*ExitResponse, error)
+	// ConfirmTransfer reports the transfer of a piece to its replacement node
+	ConfirmTransfer(ctx context.Context, in *TransferConfirmation, opts ...grpc.CallOption) (*TransferConfirmationResponse, error)
+}
+
+type gracefulExitClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGracefulExitClient(cc *grpc.ClientConn) GracefulExitClient {
+	return &gracefulExitClient{cc}
+}
+
+func (c *gracefulExitClient) Exit(ctx context.Context, in *ExitRequest, opts ...grpc.CallOption) (*ExitResponse, error) {
+	out := new(ExitResponse)
+	err := c.cc.Invoke(ctx, "/gracefulexit.GracefulExit/Exit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gracefulExitClient) ConfirmTransfer(ctx context.Context, in *TransferConfirmation, opts ...grpc.CallOption) (*TransferConfirmationResponse, error) {
+	out := new(TransferConfirmationResponse)
+	err := c.cc.Invoke(ctx, "/gracefulexit.GracefulExit/ConfirmTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GracefulExitServer is the server API for GracefulExit service.
+type GracefulExitServer interface {
+	// Exit starts or continues the exit of the calling node and returns the
+	// next pieces to transfer
+	Exit(context.Context, *ExitRequest) (*ExitResponse, error)
+	// ConfirmTransfer reports the transfer of a piece to its replacement node
+	ConfirmTransfer(context.Context, *TransferConfirmation) (*TransferConfirmationResponse, error)
+}
+
+func RegisterGracefulExitServer(s *grpc.Server, srv GracefulExitServer) {
+	s.RegisterService(&_GracefulExit_serviceDesc, srv)
+}
+
+func _GracefulExit_Exit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GracefulExitServer).Exit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gracefulexit.GracefulExit/Exit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GracefulExitServer).Exit(ctx, req.(*ExitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GracefulExit_ConfirmTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferConfirmation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GracefulExitServer).ConfirmTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gracefulexit.GracefulExit/ConfirmTransfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GracefulExitServer).ConfirmTransfer(ctx, req.(*TransferConfirmation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GracefulExit_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gracefulexit.GracefulExit",
+	HandlerType: (*GracefulExitServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Exit",
+			Handler:    _GracefulExit_Exit_Handler,
+		},
+		{
+			MethodName: "ConfirmTransfer",
+			Handler:    _GracefulExit_ConfirmTransfer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gracefulexit.proto",
+}
+
+// NodeExitClient is the client API for NodeExit service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NodeExitClient interface {
+	Exit(ctx context.Context, in *NodeExitRequest, opts ...grpc.CallOption) (NodeExit_ExitClient, error)
+}
+
+type nodeExitClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNodeExitClient(cc *grpc.ClientConn) NodeExitClient {
+	return &nodeExitClient{cc}
+}
+
+func (c *nodeExitClient) Exit(ctx context.Context, in *NodeExitRequest, opts ...grpc.CallOption) (NodeExit_ExitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NodeExit_serviceDesc.Streams[0], "/gracefulexit.NodeExit/Exit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &nodeExitExitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NodeExit_ExitClient interface {
+	Recv() (*ExitProgress, error)
+	grpc.ClientStream
+}
+
+type nodeExitExitClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeExitExitClient) Recv() (*ExitProgress, error) {
+	m := new(ExitProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NodeExitServer is the server API for NodeExit service.
+type NodeExitServer interface {
+	Exit(*NodeExitRequest, NodeExit_ExitServer) error
+}
+
+func RegisterNodeExitServer(s *grpc.Server, srv NodeExitServer) {
+	s.RegisterService(&_NodeExit_serviceDesc, srv)
+}
+
+func _NodeExit_Exit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeExitRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeExitServer).Exit(m, &nodeExitExitServer{stream})
+}
+
+type NodeExit_ExitServer interface {
+	Send(*ExitProgress) error
+	grpc.ServerStream
+}
+
+type nodeExitExitServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeExitExitServer) Send(m *ExitProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _NodeExit_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gracefulexit.NodeExit",
+	HandlerType: (*NodeExitServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Exit",
+			Handler:       _NodeExit_Exit_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "gracefulexit.proto",
+}
+
+func init() { proto.RegisterFile("gracefulexit.proto", fileDescriptor_gracefulexit_f451021617107ca8) }
+
+var fileDescriptor_gracefulexit_f451021617107ca8 = []byte{
+	// 650 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x4e, 0xdb, 0x4a,
+	0x14, 0xbe, 0x4e, 0x02, 0x24, 0xc7, 0xce, 0x05, 0xe6, 0xb2, 0x08, 0xe6, 0x5e, 0x91, 0x6b, 0x36,
+	0x11, 0x6d, 0xa3, 0x36, 0x95, 0x2a, 0x75, 0xc1, 0x02, 0x28, 0xad, 0x58, 0x94, 0xa2, 0x01, 0x36,
+	0xdd, 0x44, 0x83, 0x7d, 0x92, 0x8c, 0xe4, 0x78, 0xcc, 0xcc, 0x58, 0x4d, 0xfb, 0x2c, 0x95, 0xaa,
+	0xbe, 0x41, 0xb7, 0x7d, 0x83, 0x3e, 0x43, 0x17, 0x3c, 0x4b, 0xe5, 0xf1, 0xc4, 0x49, 0x20, 0xea,
+	0xcf, 0x2a, 0x33, 0xdf, 0xf9, 0xce, 0x99, 0x73, 0xbe, 0xef, 0x38, 0x40, 0x86, 0x92, 0x85, 0x38,
+	0xc8, 0x62, 0x9c, 0x70, 0xdd, 0x4d, 0xa5, 0xd0, 0x82, 0x78, 0xf3, 0x98, 0x0f, 0x43, 0x31, 0x14,
+	0x45, 0xc4, 0x87, 0x44, 0x44, 0x68, 0xcf, 0x1b, 0x29, 0xc7, 0x10, 0x95, 0x16, 0xd2, 0x22, 0x41,
+	0x13, 0xdc, 0x93, 0x09, 0xd7, 0x14, 0x6f, 0x32, 0x54, 0x3a, 0xf8, 0x54, 0x01, 0xaf, 0xb8, 0xab,
+	0x54, 0x24, 0x0a, 0xc9, 0x73, 0x68, 0x68, 0xc9, 0x12, 0x35, 0x40, 0xa9, 0x5a, 0x4e, 0xbb, 0xda,
+	0x71, 0x7b, 0x3b, 0xdd, 0x85, 0xf7, 0xcf, 0xf3, 0x92, 0x97, 0x96, 0x43, 0x67, 0x6c, 0x72, 0x05,
+	0x1b, 0x29, 0x7b, 0x8f, 0xb2, 0xcf, 0xe2, 0x58, 0x84, 0x4c, 0x73, 0x91, 0xb4, 0x2a, 0x6d, 0xa7,
+	0xe3, 0xf6, 0xf6, 0xbb, 0xb3, 0x3e, 0xa4, 0xc8, 0x34, 0xaa, 0xee, 0x79, 0xce, 0x3c, 0x62, 0x49,
+	0xf4, 0x8e, 0x47, 0x7a, 0x74, 0x58, 0x66, 0xd0, 0x75, 0x53, 0x63, 0x06, 0x90, 0x13, 0x68, 0xb2,
+	0x4c, 0x8f, 0x84, 0xe4, 0x1f, 0x8a, 0x9a, 0x55, 0x53, 0x73, 0xf7, 0x7e, 0xcd, 0x0b, 0x3e, 0x4c,
+	0x30, 0x7a, 0x8d, 0x4a, 0xb1, 0x21, 0xd2, 0xc5, 0x2c, 0xf2, 0x0c, 0xea, 0xa9, 0x14, 0x43, 0x89,
+	0x4a, 0xb5, 0x6a, 0xa6, 0x82, 0xbf, 0x38, 0x57, 0x2e, 0xc3, 0xb9, 0x65, 0xd0, 0x92, 0x1b, 0x7c,
+	0x75, 0xa0, 0xb9, 0x30, 0x32, 0x21, 0x50, 0x4b, 0x99, 0x1e, 0xb5, 0x9c, 0xb6, 0xd3, 0x69, 0x50,
+	0x73, 0x26, 0x3b, 0xd0, 0x30, 0xed, 0xf4, 0x93, 0x6c, 0x6c, 0x86, 0x5e, 0xa1, 0x75, 0x03, 0x9c,
+	0x65, 0x63, 0xb2, 0x0d, 0xc5, 0xb9, 0xcf, 0x23, 0xd3, 0x7c, 0x83, 0xae, 0x99, 0xfb, 0x69, 0x44,
+	0x1e, 0x82, 0x2b, 0x31, 0x8d, 0x59, 0x88, 0x63, 0x4c, 0xb4, 0x6d, 0x0c, 0xba, 0xc6, 0xc2, 0x33,
+	0x11, 0x21, 0x9d, 0x0f, 0x93, 0x2e, 0xfc, 0x83, 0x93, 0x94, 0x4b, 0x33, 0x51, 0x3f, 0x4b, 0xf8,
+	0xa4, 0xaf, 0x30, 0x6c, 0xad, 0xb4, 0x9d, 0x4e, 0x95, 0x6e, 0xce, 0x42, 0x57, 0x09, 0x9f, 0x5c,
+	0x60, 0x18, 0x7c, 0x74, 0x60, 0x6b, 0xda, 0xf6, 0xb1, 0x48, 0x06, 0x5c, 0x8e, 0x0b, 0x31, 0xfe,
+	0x78, 0x84, 0x03, 0x58, 0x93, 0x18, 0x22, 0x4f, 0xb5, 0x95, 0x7f, 0x6f, 0x89, 0xa5, 0x39, 0x70,
+	0x91, 0x03, 0xb4, 0xa0, 0xd2, 0x69, 0x0e, 0xd9, 0x82, 0x15, 0x94, 0x52, 0x48, 0x33, 0x60, 0x83,
+	0x16, 0x97, 0x60, 0x02, 0xff, 0x2e, 0xeb, 0xae, 0xdc, 0xc5, 0x79, 0xcb, 0x9c, 0xdf, 0xb7, 0x8c,
+	0xb4, 0xc1, 0x9d, 0x6e, 0xa5, 0xc4, 0xc8, 0xcc, 0x52, 0xa7, 0xf3, 0x50, 0xf0, 0xd9, 0x01, 0x6f,
+	0x3e, 0x99, 0xfc, 0x0f, 0x5e, 0x31, 0x4f, 0x5f, 0x0b, 0xcd, 0x62, 0xf3, 0x5c, 0x95, 0xba, 0x05,
+	0x76, 0x99, 0x43, 0xe4, 0x11, 0x90, 0x29, 0xe5, 0x4e, 0xf1, 0x2a, 0xdd, 0xb4, 0xc4, 0x59, 0x80,
+	0xec, 0x41, 0xd3, 0xd2, 0x07, 0x8c, 0xc7, 0x58, 0x38, 0x5f, 0xa5, 0xf6, 0x99, 0x97, 0x06, 0x23,
+	0x3e, 0xd4, 0x07, 0x3c, 0xe1, 0x6a, 0x84, 0x91, 0x91, 0xa6, 0x4e, 0xcb, 0x7b, 0x70, 0x03, 0xeb,
+	0xf9, 0x06, 0xcc, 0x7d, 0xad, 0xe4, 0x09, 0x78, 0x8a, 0x69, 0x8c, 0x63, 0xae, 0xcd, 0x32, 0xe5,
+	0x5d, 0x7a, 0x47, 0x7f, 0x7f, 0xbb, 0xdd, 0xfd, 0xeb, 0xfb, 0xed, 0xee, 0x6a, 0x4e, 0x3f, 0x7d,
+	0x41, 0xdd, 0x92, 0x73, 0x1a, 0x91, 0x07, 0xb0, 0x39, 0x4b, 0x61, 0x51, 0x64, 0xc4, 0xac, 0x18,
+	0x17, 0x36, 0xca, 0xc0, 0x61, 0x81, 0xf7, 0xbe, 0x38, 0xe0, 0xbd, 0xb2, 0x02, 0xe7, 0xef, 0x92,
+	0x03, 0xa8, 0x99, 0xdf, 0xed, 0xfb, 0xba, 0xdb, 0x9e, 0x7c, 0x7f, 0x59, 0xc8, 0x1a, 0xc8, 0x60,
+	0xdd, 0x1a, 0x5b, 0x7e, 0x3c, 0xc1, 0x22, 0x7d, 0x99, 0xff, 0xfe, 0xfe, 0xaf, 0x39, 0xd3, 0x27,
+	0x7a, 0x6f, 0xa0, 0x3e, 0x55, 0x89, 0x1c, 0xdb, 0x6e, 0xff, 0x5b, 0xcc, 0xbf, 0xa3, 0xa2, 0xff,
+	0x93, 0x25, 0x7a, 0xec, 0x1c, 0xd5, 0xde, 0x56, 0xd2, 0xeb, 0xeb, 0x55, 0xf3, 0x6f, 0xf9, 0xf4,
+	0xc7, 0x00, 0x2f, 0xd7, 0xa2, 0x60, 0x7b, 0x05, 0x00, 0x00,
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pb
+
+import (
+	"github.com/golang/protobuf/ptypes"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// ObjectLockFromStorj converts lock to the lock stored in the metadata of a
+// stream. The zero lock is stored as nil.
+func ObjectLockFromStorj(lock storj.ObjectLock) (*ObjectLock, error) {
+	if lock == (storj.ObjectLock{}) {
+		return nil, nil
+	}
+
+	msg := &ObjectLock{LegalHold: lock.LegalHold}
+	if !lock.RetainUntil.IsZero() {
+		retainUntil, err := ptypes.TimestampProto(lock.RetainUntil)
+		if err != nil {
+			return nil, err
+		}
+		msg.RetainUntil = retainUntil
+	}
+	return msg, nil
+}
+
+// ObjectLockToStorj converts the lock stored in the metadata of a stream
+func ObjectLockToStorj(msg *ObjectLock) (lock storj.ObjectLock, err error) {
+	if msg == nil {
+		return lock, nil
+	}
+
+	lock.LegalHold = msg.LegalHold
+	if msg.RetainUntil != nil {
+		lock.RetainUntil, err = ptypes.Timestamp(msg.RetainUntil)
+	}
+	return lock, err
+}
@@ -22,6 +22,10 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 type SerializableMeta struct {
 	ContentType          string            `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
 	UserDefined          map[string]string `protobuf:"bytes,2,rep,name=user_defined,json=userDefined" json:"user_defined,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CacheControl         string            `protobuf:"bytes,3,opt,name=cache_control,json=cacheControl,proto3" json:"cache_control,omitempty"`
+	ContentDisposition   string            `protobuf:"bytes,4,opt,name=content_disposition,json=contentDisposition,proto3" json:"content_disposition,omitempty"`
+	ContentEncoding      string            `protobuf:"bytes,5,opt,name=content_encoding,json=contentEncoding,proto3" json:"content_encoding,omitempty"`
+	Tags                 map[string]string `protobuf:"bytes,6,rep,name=tags" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -31,7 +35,7 @@ func (m *SerializableMeta) Reset()         { *m = SerializableMeta{} }
 func (m *SerializableMeta) String() string { return proto.CompactTextString(m) }
 func (*SerializableMeta) ProtoMessage()    {}
 func (*SerializableMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_12afffbb4ed1a0bb, []int{0}
+	return fileDescriptor_meta_5f6642d53cd3fa55, []int{0}
 }
 func (m *SerializableMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SerializableMeta.Unmarshal(m, b)
@@ -65,25 +69,60 @@ func (m *SerializableMeta) GetUserDefined() map[string]string {
 	return nil
 }
 
+func (m *SerializableMeta) GetCacheControl() string {
+	if m != nil {
+		return m.CacheControl
+	}
+	return ""
+}
+
+func (m *SerializableMeta) GetContentDisposition() string {
+	if m != nil {
+		return m.ContentDisposition
+	}
+	return ""
+}
+
+func (m *SerializableMeta) GetContentEncoding() string {
+	if m != nil {
+		return m.ContentEncoding
+	}
+	return ""
+}
+
+func (m *SerializableMeta) GetTags() map[string]string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
 func init() {
-	proto.RegisterType((*SerializableMeta)(nil), "pb.SerializableMeta")
-	proto.RegisterMapType((map[string]string)(nil), "pb.SerializableMeta.UserDefinedEntry")
+	proto.RegisterType((*SerializableMeta)(nil), "objects.SerializableMeta")
+	proto.RegisterMapType((map[string]string)(nil), "objects.SerializableMeta.TagsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "objects.SerializableMeta.UserDefinedEntry")
 }
 
-func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_12afffbb4ed1a0bb) }
-
-var fileDescriptor_meta_12afffbb4ed1a0bb = []byte{
-	// 191 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xca, 0x4d, 0x2d, 0x49,
-	0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xcf, 0x4f, 0xca, 0x4a, 0x4d, 0x2e, 0x29, 0x56,
-	0x3a, 0xc2, 0xc8, 0x25, 0x10, 0x9c, 0x5a, 0x94, 0x99, 0x98, 0x93, 0x59, 0x95, 0x98, 0x94, 0x93,
-	0xea, 0x9b, 0x5a, 0x92, 0x28, 0xa4, 0xc8, 0xc5, 0x93, 0x9c, 0x9f, 0x57, 0x92, 0x9a, 0x57, 0x12,
-	0x5f, 0x52, 0x59, 0x90, 0x2a, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x19, 0xc4, 0x0d, 0x15, 0x0b, 0xa9,
-	0x2c, 0x48, 0x15, 0xf2, 0xe5, 0xe2, 0x29, 0x2d, 0x4e, 0x2d, 0x8a, 0x4f, 0x49, 0x4d, 0xcb, 0xcc,
-	0x4b, 0x4d, 0x91, 0x60, 0x52, 0x60, 0xd6, 0xe0, 0x36, 0xd2, 0xd2, 0x83, 0x9a, 0xab, 0x87, 0x6e,
-	0xa6, 0x5e, 0x68, 0x71, 0x6a, 0x91, 0x0b, 0x44, 0xb1, 0x6b, 0x5e, 0x49, 0x51, 0x65, 0x10, 0x77,
-	0x29, 0x42, 0x44, 0xca, 0x8e, 0x4b, 0x00, 0x5d, 0x81, 0x90, 0x00, 0x17, 0x73, 0x76, 0x6a, 0x25,
-	0xd4, 0x72, 0x10, 0x53, 0x48, 0x84, 0x8b, 0xb5, 0x2c, 0x31, 0xa7, 0x34, 0x55, 0x82, 0x09, 0x2c,
-	0x06, 0xe1, 0x58, 0x31, 0x59, 0x30, 0x3a, 0xb1, 0x44, 0x31, 0x15, 0x24, 0x25, 0xb1, 0x81, 0x3d,
-	0x67, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0x4f, 0x25, 0xc0, 0xd0, 0xea, 0x00, 0x00, 0x00,
+func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_5f6642d53cd3fa55) }
+
+var fileDescriptor_meta_5f6642d53cd3fa55 = []byte{
+	// 287 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0x31, 0x4f, 0xc3, 0x30,
+	0x10, 0x85, 0xd5, 0x24, 0x2d, 0xea, 0x25, 0x88, 0xc8, 0x30, 0x58, 0x9d, 0x0a, 0x5d, 0x0a, 0x43,
+	0x90, 0x60, 0x28, 0x62, 0x60, 0x80, 0x76, 0xec, 0x52, 0xca, 0xc2, 0x12, 0x39, 0xc9, 0x11, 0x0c,
+	0xc1, 0x8e, 0x6c, 0x07, 0x29, 0xfc, 0x31, 0xfe, 0x1e, 0xaa, 0xe3, 0x52, 0xa9, 0x12, 0x03, 0x5b,
+	0xf2, 0xdd, 0x7b, 0xef, 0xfc, 0x6c, 0x80, 0x0f, 0x34, 0x2c, 0xa9, 0x95, 0x34, 0x92, 0x1c, 0xc8,
+	0xec, 0x0d, 0x73, 0xa3, 0xcf, 0xbe, 0x7d, 0x88, 0x1f, 0x51, 0x71, 0x56, 0xf1, 0x2f, 0x96, 0x55,
+	0xb8, 0x44, 0xc3, 0xc8, 0x29, 0x44, 0xb9, 0x14, 0x06, 0x85, 0x49, 0x4d, 0x5b, 0x23, 0xed, 0x8d,
+	0x7b, 0xd3, 0xe1, 0x2a, 0x74, 0x6c, 0xdd, 0xd6, 0x48, 0x96, 0x10, 0x35, 0x1a, 0x55, 0x5a, 0xe0,
+	0x0b, 0x17, 0x58, 0x50, 0x6f, 0xec, 0x4f, 0xc3, 0xab, 0x8b, 0xc4, 0xe5, 0x26, 0xfb, 0x99, 0xc9,
+	0x93, 0x46, 0x35, 0xef, 0xc4, 0x0b, 0x61, 0x54, 0xbb, 0x0a, 0x9b, 0x1d, 0x21, 0x13, 0x38, 0xcc,
+	0x59, 0xfe, 0x8a, 0xe9, 0x66, 0x87, 0x92, 0x15, 0xf5, 0xed, 0xca, 0xc8, 0xc2, 0x87, 0x8e, 0x91,
+	0x4b, 0x38, 0xde, 0x1e, 0xab, 0xe0, 0xba, 0x96, 0x9a, 0x1b, 0x2e, 0x05, 0x0d, 0xac, 0x94, 0xb8,
+	0xd1, 0x7c, 0x37, 0x21, 0xe7, 0x10, 0x6f, 0x0d, 0x28, 0x72, 0x59, 0x70, 0x51, 0xd2, 0xbe, 0x55,
+	0x1f, 0x39, 0xbe, 0x70, 0x98, 0xcc, 0x20, 0x30, 0xac, 0xd4, 0x74, 0x60, 0x7b, 0x4c, 0xfe, 0xee,
+	0xb1, 0x66, 0xa5, 0xee, 0x0a, 0x58, 0xc3, 0xe8, 0x0e, 0xe2, 0xfd, 0x6a, 0x24, 0x06, 0xff, 0x1d,
+	0x5b, 0x77, 0x6d, 0x9b, 0x4f, 0x72, 0x02, 0xfd, 0x4f, 0x56, 0x35, 0x48, 0x3d, 0xcb, 0xba, 0x9f,
+	0x5b, 0xef, 0xa6, 0x37, 0x9a, 0xc1, 0xf0, 0x37, 0xf2, 0x3f, 0xc6, 0xfb, 0xe0, 0xd9, 0xab, 0xb3,
+	0x6c, 0x60, 0xdf, 0xf3, 0xfa, 0x67, 0x00, 0x25, 0x17, 0xfb, 0x05, 0xdd, 0x01, 0x00, 0x00,
 }
@@ -40,7 +40,7 @@ func (x NodeType) String() string {
 	return proto.EnumName(NodeType_name, int32(x))
 }
 func (NodeType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{0}
+	return fileDescriptor_node_05a5c625b8051e32, []int{0}
 }
 
 // NodeTransport is an enum of possible transports for the overlay network
@@ -61,10 +61,10 @@ func (x NodeTransport) String() string {
 	return proto.EnumName(NodeTransport_name, int32(x))
 }
 func (NodeTransport) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{1}
+	return fileDescriptor_node_05a5c625b8051e32, []int{1}
 }
 
-//  NodeRestrictions contains all relevant data about a nodes ability to store data
+// NodeRestrictions contains all relevant data about a nodes ability to store data
 type NodeRestrictions struct {
 	FreeBandwidth        int64    `protobuf:"varint,1,opt,name=free_bandwidth,json=freeBandwidth,proto3" json:"free_bandwidth,omitempty"`
 	FreeDisk             int64    `protobuf:"varint,2,opt,name=free_disk,json=freeDisk,proto3" json:"free_disk,omitempty"`
@@ -77,7 +77,7 @@ func (m *NodeRestrictions) Reset()         { *m = NodeRestrictions{} }
 func (m *NodeRestrictions) String() string { return proto.CompactTextString(m) }
 func (*NodeRestrictions) ProtoMessage()    {}
 func (*NodeRestrictions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{0}
+	return fileDescriptor_node_05a5c625b8051e32, []int{0}
 }
 func (m *NodeRestrictions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeRestrictions.Unmarshal(m, b)
@@ -114,27 +114,29 @@ func (m *NodeRestrictions) GetFreeDisk() int64 {
 // Node represents a node in the overlay network
 // Node is info for a updating a single storagenode, used in the Update rpc calls
 type Node struct {
-	Id                   NodeID            `protobuf:"bytes,1,opt,name=id,proto3,customtype=NodeID" json:"id"`
-	Address              *NodeAddress      `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
-	Type                 NodeType          `protobuf:"varint,3,opt,name=type,proto3,enum=node.NodeType" json:"type,omitempty"`
-	Restrictions         *NodeRestrictions `protobuf:"bytes,4,opt,name=restrictions" json:"restrictions,omitempty"`
-	Metadata             *NodeMetadata     `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
-	LatencyList          []int64           `protobuf:"varint,6,rep,packed,name=latency_list,json=latencyList" json:"latency_list,omitempty"`
-	AuditSuccess         bool              `protobuf:"varint,7,opt,name=audit_success,json=auditSuccess,proto3" json:"audit_success,omitempty"`
-	IsUp                 bool              `protobuf:"varint,8,opt,name=is_up,json=isUp,proto3" json:"is_up,omitempty"`
-	UpdateLatency        bool              `protobuf:"varint,9,opt,name=update_latency,json=updateLatency,proto3" json:"update_latency,omitempty"`
-	UpdateAuditSuccess   bool              `protobuf:"varint,10,opt,name=update_audit_success,json=updateAuditSuccess,proto3" json:"update_audit_success,omitempty"`
-	UpdateUptime         bool              `protobuf:"varint,11,opt,name=update_uptime,json=updateUptime,proto3" json:"update_uptime,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Id                 NodeID            `protobuf:"bytes,1,opt,name=id,proto3,customtype=NodeID" json:"id"`
+	Address            *NodeAddress      `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Type               NodeType          `protobuf:"varint,3,opt,name=type,proto3,enum=node.NodeType" json:"type,omitempty"`
+	Restrictions       *NodeRestrictions `protobuf:"bytes,4,opt,name=restrictions" json:"restrictions,omitempty"`
+	Metadata           *NodeMetadata     `protobuf:"bytes,5,opt,name=metadata" json:"metadata,omitempty"`
+	LatencyList        []int64           `protobuf:"varint,6,rep,packed,name=latency_list,json=latencyList" json:"latency_list,omitempty"`
+	AuditSuccess       bool              `protobuf:"varint,7,opt,name=audit_success,json=auditSuccess,proto3" json:"audit_success,omitempty"`
+	IsUp               bool              `protobuf:"varint,8,opt,name=is_up,json=isUp,proto3" json:"is_up,omitempty"`
+	UpdateLatency      bool              `protobuf:"varint,9,opt,name=update_latency,json=updateLatency,proto3" json:"update_latency,omitempty"`
+	UpdateAuditSuccess bool              `protobuf:"varint,10,opt,name=update_audit_success,json=updateAuditSuccess,proto3" json:"update_audit_success,omitempty"`
+	UpdateUptime       bool              `protobuf:"varint,11,opt,name=update_uptime,json=updateUptime,proto3" json:"update_uptime,omitempty"`
+	// exiting is set by the satellite for the nodes leaving the network
+	Exiting              bool     `protobuf:"varint,12,opt,name=exiting,proto3" json:"exiting,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{1}
+	return fileDescriptor_node_05a5c625b8051e32, []int{1}
 }
 func (m *Node) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Node.Unmarshal(m, b)
@@ -224,6 +226,13 @@ func (m *Node) GetUpdateUptime() bool {
 	return false
 }
 
+func (m *Node) GetExiting() bool {
+	if m != nil {
+		return m.Exiting
+	}
+	return false
+}
+
 // NodeAddress contains the information needed to communicate with a node on the network
 type NodeAddress struct {
 	Transport            NodeTransport `protobuf:"varint,1,opt,name=transport,proto3,enum=node.NodeTransport" json:"transport,omitempty"`
@@ -237,7 +246,7 @@ func (m *NodeAddress) Reset()         { *m = NodeAddress{} }
 func (m *NodeAddress) String() string { return proto.CompactTextString(m) }
 func (*NodeAddress) ProtoMessage()    {}
 func (*NodeAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{2}
+	return fileDescriptor_node_05a5c625b8051e32, []int{2}
 }
 func (m *NodeAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeAddress.Unmarshal(m, b)
@@ -290,7 +299,7 @@ func (m *NodeStats) Reset()         { *m = NodeStats{} }
 func (m *NodeStats) String() string { return proto.CompactTextString(m) }
 func (*NodeStats) ProtoMessage()    {}
 func (*NodeStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{3}
+	return fileDescriptor_node_05a5c625b8051e32, []int{3}
 }
 func (m *NodeStats) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeStats.Unmarshal(m, b)
@@ -374,7 +383,7 @@ func (m *NodeRep) Reset()         { *m = NodeRep{} }
 func (m *NodeRep) String() string { return proto.CompactTextString(m) }
 func (*NodeRep) ProtoMessage()    {}
 func (*NodeRep) Descriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{4}
+	return fileDescriptor_node_05a5c625b8051e32, []int{4}
 }
 func (m *NodeRep) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeRep.Unmarshal(m, b)
@@ -427,7 +436,7 @@ func (m *NodeMetadata) Reset()         { *m = NodeMetadata{} }
 func (m *NodeMetadata) String() string { return proto.CompactTextString(m) }
 func (*NodeMetadata) ProtoMessage()    {}
 func (*NodeMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_node_a85419faa51872ac, []int{5}
+	return fileDescriptor_node_05a5c625b8051e32, []int{5}
 }
 func (m *NodeMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeMetadata.Unmarshal(m, b)
@@ -472,49 +481,50 @@ func init() {
 	proto.RegisterEnum("node.NodeTransport", NodeTransport_name, NodeTransport_value)
 }
 
-func init() { proto.RegisterFile("node.proto", fileDescriptor_node_a85419faa51872ac) }
-
-var fileDescriptor_node_a85419faa51872ac = []byte{
-	// 652 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x94, 0x4f, 0x4f, 0xdb, 0x4c,
-	0x10, 0xc6, 0x89, 0xed, 0xfc, 0xf1, 0x38, 0x09, 0x61, 0x40, 0xc8, 0x7a, 0x5f, 0xb5, 0x04, 0xa3,
-	0xb6, 0x11, 0x95, 0x22, 0x4a, 0x4f, 0x54, 0xbd, 0x04, 0xa8, 0x10, 0x12, 0x50, 0xb4, 0x09, 0x3d,
-	0x70, 0xb1, 0x4c, 0xbc, 0xa5, 0x2b, 0x12, 0xdb, 0xb2, 0x37, 0x42, 0x91, 0xfa, 0xd9, 0x7a, 0xee,
-	0xa1, 0x9f, 0xa0, 0x07, 0x3e, 0x4b, 0xb5, 0xb3, 0x36, 0xb1, 0x55, 0xf5, 0x96, 0x3c, 0xcf, 0xcf,
-	0xcf, 0xec, 0xce, 0x8c, 0x0d, 0x10, 0xc5, 0x21, 0x1f, 0x26, 0x69, 0x2c, 0x63, 0xb4, 0xd4, 0xef,
-	0xff, 0xe0, 0x3e, 0xbe, 0x8f, 0xb5, 0xe2, 0x7d, 0x81, 0xde, 0x55, 0x1c, 0x72, 0xc6, 0x33, 0x99,
-	0x8a, 0xa9, 0x14, 0x71, 0x94, 0xe1, 0x2b, 0xe8, 0x7e, 0x4d, 0x39, 0xf7, 0xef, 0x82, 0x28, 0x7c,
-	0x14, 0xa1, 0xfc, 0xe6, 0xd6, 0xfa, 0xb5, 0x81, 0xc9, 0x3a, 0x4a, 0x3d, 0x2e, 0x44, 0xfc, 0x1f,
-	0x6c, 0xc2, 0x42, 0x91, 0x3d, 0xb8, 0x06, 0x11, 0x2d, 0x25, 0x9c, 0x8a, 0xec, 0xc1, 0xfb, 0x61,
-	0x82, 0xa5, 0x82, 0xf1, 0x25, 0x18, 0x22, 0xa4, 0x80, 0xf6, 0x71, 0xf7, 0xe7, 0xd3, 0xce, 0xda,
-	0xef, 0xa7, 0x9d, 0x86, 0x72, 0xce, 0x4f, 0x99, 0x21, 0x42, 0x7c, 0x0b, 0xcd, 0x20, 0x0c, 0x53,
-	0x9e, 0x65, 0x94, 0xe1, 0x1c, 0x6e, 0x0c, 0xe9, 0xc0, 0x0a, 0x19, 0x69, 0x83, 0x15, 0x04, 0x7a,
-	0x60, 0xc9, 0x65, 0xc2, 0x5d, 0xb3, 0x5f, 0x1b, 0x74, 0x0f, 0xbb, 0x2b, 0x72, 0xb2, 0x4c, 0x38,
-	0x23, 0x0f, 0x3f, 0x40, 0x3b, 0x2d, 0xdd, 0xc6, 0xb5, 0x28, 0x75, 0x7b, 0xc5, 0x96, 0xef, 0xca,
-	0x2a, 0x2c, 0x0e, 0xa1, 0x35, 0xe7, 0x32, 0x08, 0x03, 0x19, 0xb8, 0x75, 0x7a, 0x0e, 0x57, 0xcf,
-	0x5d, 0xe6, 0x0e, 0x7b, 0x66, 0x70, 0x17, 0xda, 0xb3, 0x40, 0xf2, 0x68, 0xba, 0xf4, 0x67, 0x22,
-	0x93, 0x6e, 0xa3, 0x6f, 0x0e, 0x4c, 0xe6, 0xe4, 0xda, 0x85, 0xc8, 0x24, 0xee, 0x41, 0x27, 0x58,
-	0x84, 0x42, 0xfa, 0xd9, 0x62, 0x3a, 0x55, 0xb7, 0x6c, 0xf6, 0x6b, 0x83, 0x16, 0x6b, 0x93, 0x38,
-	0xd6, 0x1a, 0x6e, 0x42, 0x5d, 0x64, 0xfe, 0x22, 0x71, 0x5b, 0x64, 0x5a, 0x22, 0xbb, 0x49, 0xd4,
-	0x18, 0x16, 0x49, 0x18, 0x48, 0xee, 0xe7, 0x79, 0xae, 0x4d, 0x6e, 0x47, 0xab, 0x17, 0x5a, 0xc4,
-	0x03, 0xd8, 0xca, 0xb1, 0x6a, 0x1d, 0x20, 0x18, 0xb5, 0x37, 0x2a, 0x57, 0xdb, 0x83, 0x3c, 0xc2,
-	0x5f, 0x24, 0x52, 0xcc, 0xb9, 0xeb, 0xe8, 0x23, 0x69, 0xf1, 0x86, 0x34, 0xef, 0x16, 0x9c, 0xd2,
-	0x08, 0xf0, 0x1d, 0xd8, 0x32, 0x0d, 0xa2, 0x2c, 0x89, 0x53, 0x49, 0xd3, 0xec, 0x1e, 0x6e, 0x96,
-	0xda, 0x5f, 0x58, 0x6c, 0x45, 0xa1, 0x5b, 0x9d, 0xac, 0xfd, 0x3c, 0x46, 0xef, 0x97, 0x01, 0xb6,
-	0x7a, 0x6c, 0x2c, 0x03, 0x99, 0xe1, 0x1b, 0x68, 0xaa, 0x20, 0xff, 0x9f, 0x6b, 0xd2, 0x50, 0xf6,
-	0x79, 0x88, 0x2f, 0x00, 0x8a, 0x6e, 0x1f, 0x1d, 0xe4, 0x1b, 0x67, 0xe7, 0xca, 0xd1, 0x01, 0x0e,
-	0x61, 0xb3, 0xd2, 0x01, 0x3f, 0x0d, 0xa4, 0x88, 0x69, 0x57, 0x6a, 0x6c, 0xa3, 0xdc, 0x6f, 0xa6,
-	0x0c, 0x35, 0x3c, 0x7d, 0xff, 0x1c, 0xb4, 0x08, 0x74, 0xb4, 0xa6, 0x91, 0x1d, 0x70, 0x74, 0xe4,
-	0x34, 0x5e, 0x44, 0x92, 0x56, 0xc2, 0x64, 0x40, 0xd2, 0x89, 0x52, 0xfe, 0xae, 0xa9, 0xc1, 0x06,
-	0x81, 0x95, 0x9a, 0x9a, 0x5f, 0xd5, 0xd4, 0x60, 0x93, 0xc0, 0xbc, 0xa6, 0x46, 0x68, 0x9e, 0x84,
-	0x54, 0x33, 0x5b, 0x84, 0xa2, 0xf6, 0xca, 0xa1, 0xde, 0x77, 0x68, 0xea, 0xbd, 0x4e, 0x54, 0x8b,
-	0xe6, 0x22, 0x2a, 0xe6, 0xaa, 0xda, 0x69, 0x30, 0x7b, 0x2e, 0x22, 0x3d, 0x54, 0xdc, 0x87, 0x0d,
-	0x65, 0x57, 0x17, 0xc5, 0x20, 0x6a, 0x7d, 0x2e, 0xa2, 0xca, 0x96, 0xbc, 0x86, 0xf5, 0x15, 0xab,
-	0x8f, 0x60, 0xea, 0xcf, 0x40, 0x41, 0xea, 0xea, 0x1f, 0xa1, 0x5d, 0x7e, 0x3b, 0x70, 0x0b, 0xea,
-	0x7c, 0x1e, 0x88, 0x19, 0x55, 0xb7, 0x99, 0xfe, 0x83, 0xdb, 0xd0, 0x78, 0x0c, 0x66, 0x33, 0x2e,
-	0xf3, 0x5d, 0xc8, 0xff, 0xed, 0x7b, 0xd0, 0x2a, 0xde, 0x5f, 0xb4, 0xa1, 0x3e, 0x3a, 0xbd, 0x3c,
-	0xbf, 0xea, 0xad, 0xa1, 0x03, 0xcd, 0xf1, 0xe4, 0x33, 0x1b, 0x9d, 0x7d, 0xea, 0xd5, 0xf6, 0x77,
-	0xa1, 0x53, 0x59, 0x32, 0xec, 0x41, 0x7b, 0x72, 0x72, 0xed, 0x4f, 0x2e, 0xc6, 0xfe, 0x19, 0xbb,
-	0x3e, 0xe9, 0xad, 0x1d, 0x5b, 0xb7, 0x46, 0x72, 0x77, 0xd7, 0xa0, 0x6f, 0xda, 0xfb, 0x3f, 0x01,
-	0x00, 0x00, 0xff, 0xff, 0xf3, 0xcb, 0x6b, 0x95, 0xf3, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("node.proto", fileDescriptor_node_05a5c625b8051e32) }
+
+var fileDescriptor_node_05a5c625b8051e32 = []byte{
+	// 659 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x94, 0x41, 0x4f, 0xdb, 0x30,
+	0x14, 0xc7, 0x69, 0x92, 0x36, 0xcd, 0x4b, 0x5a, 0x8a, 0x41, 0x28, 0xda, 0xb4, 0x51, 0x82, 0xb6,
+	0x55, 0x4c, 0xaa, 0x18, 0x3b, 0x31, 0xed, 0x52, 0x60, 0x42, 0x48, 0xc0, 0x90, 0x5b, 0x76, 0xe0,
+	0x12, 0x85, 0xc6, 0x63, 0x16, 0x6d, 0x12, 0xc5, 0xae, 0x18, 0xd2, 0x3e, 0xe0, 0x0e, 0xfb, 0x00,
+	0xd3, 0x0e, 0x7c, 0x96, 0xc9, 0xcf, 0x09, 0x4d, 0x34, 0xed, 0x56, 0xff, 0xff, 0x3f, 0xbf, 0x67,
+	0xfb, 0xff, 0x1a, 0x80, 0x24, 0x8d, 0xd9, 0x30, 0xcb, 0x53, 0x99, 0x12, 0x4b, 0xfd, 0x7e, 0x06,
+	0xb7, 0xe9, 0x6d, 0xaa, 0x95, 0xe0, 0x0b, 0xf4, 0x2e, 0xd2, 0x98, 0x51, 0x26, 0x64, 0xce, 0xa7,
+	0x92, 0xa7, 0x89, 0x20, 0xaf, 0xa0, 0xfb, 0x35, 0x67, 0x2c, 0xbc, 0x89, 0x92, 0xf8, 0x9e, 0xc7,
+	0xf2, 0x9b, 0xdf, 0xe8, 0x37, 0x06, 0x26, 0xed, 0x28, 0xf5, 0xb0, 0x14, 0xc9, 0x73, 0x70, 0x10,
+	0x8b, 0xb9, 0xb8, 0xf3, 0x0d, 0x24, 0xda, 0x4a, 0x38, 0xe6, 0xe2, 0x2e, 0xf8, 0x6d, 0x82, 0xa5,
+	0x0a, 0x93, 0x97, 0x60, 0xf0, 0x18, 0x0b, 0x78, 0x87, 0xdd, 0x9f, 0x8f, 0x5b, 0x2b, 0x7f, 0x1e,
+	0xb7, 0x5a, 0xca, 0x39, 0x3d, 0xa6, 0x06, 0x8f, 0xc9, 0x5b, 0xb0, 0xa3, 0x38, 0xce, 0x99, 0x10,
+	0x58, 0xc3, 0xdd, 0x5f, 0x1b, 0xe2, 0x81, 0x15, 0x32, 0xd2, 0x06, 0x2d, 0x09, 0x12, 0x80, 0x25,
+	0x1f, 0x32, 0xe6, 0x9b, 0xfd, 0xc6, 0xa0, 0xbb, 0xdf, 0x5d, 0x92, 0x93, 0x87, 0x8c, 0x51, 0xf4,
+	0xc8, 0x07, 0xf0, 0xf2, 0xca, 0x6d, 0x7c, 0x0b, 0xab, 0x6e, 0x2e, 0xd9, 0xea, 0x5d, 0x69, 0x8d,
+	0x25, 0x43, 0x68, 0xcf, 0x99, 0x8c, 0xe2, 0x48, 0x46, 0x7e, 0x13, 0xf7, 0x91, 0xe5, 0xbe, 0xf3,
+	0xc2, 0xa1, 0x4f, 0x0c, 0xd9, 0x06, 0x6f, 0x16, 0x49, 0x96, 0x4c, 0x1f, 0xc2, 0x19, 0x17, 0xd2,
+	0x6f, 0xf5, 0xcd, 0x81, 0x49, 0xdd, 0x42, 0x3b, 0xe3, 0x42, 0x92, 0x1d, 0xe8, 0x44, 0x8b, 0x98,
+	0xcb, 0x50, 0x2c, 0xa6, 0x53, 0x75, 0x4b, 0xbb, 0xdf, 0x18, 0xb4, 0xa9, 0x87, 0xe2, 0x58, 0x6b,
+	0x64, 0x1d, 0x9a, 0x5c, 0x84, 0x8b, 0xcc, 0x6f, 0xa3, 0x69, 0x71, 0x71, 0x95, 0xa9, 0x18, 0x16,
+	0x59, 0x1c, 0x49, 0x16, 0x16, 0xf5, 0x7c, 0x07, 0xdd, 0x8e, 0x56, 0xcf, 0xb4, 0x48, 0xf6, 0x60,
+	0xa3, 0xc0, 0xea, 0x7d, 0x00, 0x61, 0xa2, 0xbd, 0x51, 0xb5, 0xdb, 0x0e, 0x14, 0x25, 0xc2, 0x45,
+	0x26, 0xf9, 0x9c, 0xf9, 0xae, 0x3e, 0x92, 0x16, 0xaf, 0x50, 0x23, 0x3e, 0xd8, 0xec, 0x3b, 0x97,
+	0x3c, 0xb9, 0xf5, 0x3d, 0xb4, 0xcb, 0x65, 0x70, 0x0d, 0x6e, 0x25, 0x1c, 0xf2, 0x0e, 0x1c, 0x99,
+	0x47, 0x89, 0xc8, 0xd2, 0x5c, 0x62, 0xce, 0xdd, 0xfd, 0xf5, 0x4a, 0x30, 0xa5, 0x45, 0x97, 0x94,
+	0xaa, 0x5d, 0xcd, 0xdc, 0x79, 0x0a, 0x38, 0xf8, 0x65, 0x80, 0xa3, 0xb6, 0x8d, 0x65, 0x24, 0x05,
+	0x79, 0x03, 0xb6, 0x2a, 0x14, 0xfe, 0x77, 0x80, 0x5a, 0xca, 0x3e, 0x8d, 0xc9, 0x0b, 0x80, 0x32,
+	0x87, 0x83, 0xbd, 0x62, 0x16, 0x9d, 0x42, 0x39, 0xd8, 0x23, 0x43, 0x58, 0xaf, 0xbd, 0x4d, 0x98,
+	0x47, 0x92, 0xa7, 0x38, 0x45, 0x0d, 0xba, 0x56, 0x4d, 0x82, 0x2a, 0x43, 0xc5, 0xaa, 0x5f, 0xa6,
+	0x00, 0x2d, 0x04, 0x5d, 0xad, 0x69, 0x64, 0x0b, 0x5c, 0x5d, 0x72, 0x9a, 0x2e, 0x12, 0x89, 0xc3,
+	0x62, 0x52, 0x40, 0xe9, 0x48, 0x29, 0xff, 0xf6, 0xd4, 0x60, 0x0b, 0xc1, 0x5a, 0x4f, 0xcd, 0x2f,
+	0x7b, 0x6a, 0xd0, 0x46, 0xb0, 0xe8, 0xa9, 0x11, 0x4c, 0x1a, 0x91, 0x7a, 0xcd, 0x36, 0xa2, 0x44,
+	0x7b, 0xd5, 0xa2, 0xc1, 0x0f, 0xb0, 0xf5, 0xc4, 0x67, 0xea, 0x89, 0xe6, 0x3c, 0x29, 0x13, 0x57,
+	0xcf, 0x69, 0x50, 0x67, 0xce, 0x93, 0x22, 0xee, 0x5d, 0x58, 0x53, 0x76, 0x7d, 0x84, 0x0c, 0xa4,
+	0x56, 0xe7, 0x3c, 0xa9, 0xcd, 0xcf, 0x6b, 0x58, 0x5d, 0xb2, 0xfa, 0x08, 0xa6, 0xfe, 0x40, 0x94,
+	0xa4, 0xee, 0xfe, 0x11, 0xbc, 0xea, 0xff, 0x86, 0x6c, 0x40, 0x93, 0xcd, 0x23, 0x3e, 0xc3, 0xee,
+	0x0e, 0xd5, 0x0b, 0xb2, 0x09, 0xad, 0xfb, 0x68, 0x36, 0x63, 0xb2, 0x98, 0x85, 0x62, 0xb5, 0x1b,
+	0x40, 0xbb, 0xfc, 0x67, 0x13, 0x07, 0x9a, 0xa3, 0xe3, 0xf3, 0xd3, 0x8b, 0xde, 0x0a, 0x71, 0xc1,
+	0x1e, 0x4f, 0x3e, 0xd3, 0xd1, 0xc9, 0xa7, 0x5e, 0x63, 0x77, 0x1b, 0x3a, 0xb5, 0x21, 0x23, 0x3d,
+	0xf0, 0x26, 0x47, 0x97, 0xe1, 0xe4, 0x6c, 0x1c, 0x9e, 0xd0, 0xcb, 0xa3, 0xde, 0xca, 0xa1, 0x75,
+	0x6d, 0x64, 0x37, 0x37, 0x2d, 0xfc, 0xda, 0xbd, 0xff, 0x3b, 0x00, 0xf0, 0x94, 0x30, 0x7a, 0x0d,
+	0x05, 0x00, 0x00,
 }
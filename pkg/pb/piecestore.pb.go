@@ -44,7 +44,7 @@ func (x PayerBandwidthAllocation_Action) String() string {
 	return proto.EnumName(PayerBandwidthAllocation_Action_name, int32(x))
 }
 func (PayerBandwidthAllocation_Action) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{0, 0}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{0, 0}
 }
 
 type PayerBandwidthAllocation struct {
@@ -59,7 +59,7 @@ func (m *PayerBandwidthAllocation) Reset()         { *m = PayerBandwidthAllocati
 func (m *PayerBandwidthAllocation) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocation) ProtoMessage()    {}
 func (*PayerBandwidthAllocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{0}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{0}
 }
 func (m *PayerBandwidthAllocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocation.Unmarshal(m, b)
@@ -110,7 +110,7 @@ func (m *PayerBandwidthAllocation_Data) Reset()         { *m = PayerBandwidthAll
 func (m *PayerBandwidthAllocation_Data) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocation_Data) ProtoMessage()    {}
 func (*PayerBandwidthAllocation_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{0, 0}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{0, 0}
 }
 func (m *PayerBandwidthAllocation_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocation_Data.Unmarshal(m, b)
@@ -177,7 +177,7 @@ func (m *RenterBandwidthAllocation) Reset()         { *m = RenterBandwidthAlloca
 func (m *RenterBandwidthAllocation) String() string { return proto.CompactTextString(m) }
 func (*RenterBandwidthAllocation) ProtoMessage()    {}
 func (*RenterBandwidthAllocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{1}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{1}
 }
 func (m *RenterBandwidthAllocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenterBandwidthAllocation.Unmarshal(m, b)
@@ -225,7 +225,7 @@ func (m *RenterBandwidthAllocation_Data) Reset()         { *m = RenterBandwidthA
 func (m *RenterBandwidthAllocation_Data) String() string { return proto.CompactTextString(m) }
 func (*RenterBandwidthAllocation_Data) ProtoMessage()    {}
 func (*RenterBandwidthAllocation_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{1, 0}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{1, 0}
 }
 func (m *RenterBandwidthAllocation_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenterBandwidthAllocation_Data.Unmarshal(m, b)
@@ -279,7 +279,7 @@ func (m *PieceStore) Reset()         { *m = PieceStore{} }
 func (m *PieceStore) String() string { return proto.CompactTextString(m) }
 func (*PieceStore) ProtoMessage()    {}
 func (*PieceStore) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{2}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{2}
 }
 func (m *PieceStore) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStore.Unmarshal(m, b)
@@ -334,7 +334,7 @@ func (m *PieceStore_PieceData) Reset()         { *m = PieceStore_PieceData{} }
 func (m *PieceStore_PieceData) String() string { return proto.CompactTextString(m) }
 func (*PieceStore_PieceData) ProtoMessage()    {}
 func (*PieceStore_PieceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{2, 0}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{2, 0}
 }
 func (m *PieceStore_PieceData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStore_PieceData.Unmarshal(m, b)
@@ -388,7 +388,7 @@ func (m *PieceId) Reset()         { *m = PieceId{} }
 func (m *PieceId) String() string { return proto.CompactTextString(m) }
 func (*PieceId) ProtoMessage()    {}
 func (*PieceId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{3}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{3}
 }
 func (m *PieceId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceId.Unmarshal(m, b)
@@ -435,7 +435,7 @@ func (m *PieceSummary) Reset()         { *m = PieceSummary{} }
 func (m *PieceSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceSummary) ProtoMessage()    {}
 func (*PieceSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{4}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{4}
 }
 func (m *PieceSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceSummary.Unmarshal(m, b)
@@ -489,7 +489,7 @@ func (m *PieceRetrieval) Reset()         { *m = PieceRetrieval{} }
 func (m *PieceRetrieval) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrieval) ProtoMessage()    {}
 func (*PieceRetrieval) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{5}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{5}
 }
 func (m *PieceRetrieval) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrieval.Unmarshal(m, b)
@@ -544,7 +544,7 @@ func (m *PieceRetrieval_PieceData) Reset()         { *m = PieceRetrieval_PieceDa
 func (m *PieceRetrieval_PieceData) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrieval_PieceData) ProtoMessage()    {}
 func (*PieceRetrieval_PieceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{5, 0}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{5, 0}
 }
 func (m *PieceRetrieval_PieceData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrieval_PieceData.Unmarshal(m, b)
@@ -597,7 +597,7 @@ func (m *PieceRetrievalStream) Reset()         { *m = PieceRetrievalStream{} }
 func (m *PieceRetrievalStream) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrievalStream) ProtoMessage()    {}
 func (*PieceRetrievalStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{6}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{6}
 }
 func (m *PieceRetrievalStream) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrievalStream.Unmarshal(m, b)
@@ -644,7 +644,7 @@ func (m *PieceDelete) Reset()         { *m = PieceDelete{} }
 func (m *PieceDelete) String() string { return proto.CompactTextString(m) }
 func (*PieceDelete) ProtoMessage()    {}
 func (*PieceDelete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{7}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{7}
 }
 func (m *PieceDelete) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceDelete.Unmarshal(m, b)
@@ -678,6 +678,52 @@ func (m *PieceDelete) GetAuthorization() *SignedMessage {
 	return nil
 }
 
+type PieceDeleteMany struct {
+	Ids                  []string       `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+	Authorization        *SignedMessage `protobuf:"bytes,2,opt,name=authorization" json:"authorization,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PieceDeleteMany) Reset()         { *m = PieceDeleteMany{} }
+func (m *PieceDeleteMany) String() string { return proto.CompactTextString(m) }
+func (*PieceDeleteMany) ProtoMessage()    {}
+func (*PieceDeleteMany) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{8}
+}
+func (m *PieceDeleteMany) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceDeleteMany.Unmarshal(m, b)
+}
+func (m *PieceDeleteMany) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceDeleteMany.Marshal(b, m, deterministic)
+}
+func (dst *PieceDeleteMany) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceDeleteMany.Merge(dst, src)
+}
+func (m *PieceDeleteMany) XXX_Size() int {
+	return xxx_messageInfo_PieceDeleteMany.Size(m)
+}
+func (m *PieceDeleteMany) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceDeleteMany.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceDeleteMany proto.InternalMessageInfo
+
+func (m *PieceDeleteMany) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *PieceDeleteMany) GetAuthorization() *SignedMessage {
+	if m != nil {
+		return m.Authorization
+	}
+	return nil
+}
+
 type PieceDeleteSummary struct {
 	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -689,7 +735,7 @@ func (m *PieceDeleteSummary) Reset()         { *m = PieceDeleteSummary{} }
 func (m *PieceDeleteSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceDeleteSummary) ProtoMessage()    {}
 func (*PieceDeleteSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{8}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{9}
 }
 func (m *PieceDeleteSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceDeleteSummary.Unmarshal(m, b)
@@ -717,18 +763,19 @@ func (m *PieceDeleteSummary) GetMessage() string {
 }
 
 type PieceStoreSummary struct {
-	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	TotalReceived        int64    `protobuf:"varint,2,opt,name=total_received,json=totalReceived,proto3" json:"total_received,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Message              string             `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	TotalReceived        int64              `protobuf:"varint,2,opt,name=total_received,json=totalReceived,proto3" json:"total_received,omitempty"`
+	Receipt              *PieceStoreReceipt `protobuf:"bytes,3,opt,name=receipt" json:"receipt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *PieceStoreSummary) Reset()         { *m = PieceStoreSummary{} }
 func (m *PieceStoreSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceStoreSummary) ProtoMessage()    {}
 func (*PieceStoreSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{9}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{10}
 }
 func (m *PieceStoreSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStoreSummary.Unmarshal(m, b)
@@ -762,6 +809,129 @@ func (m *PieceStoreSummary) GetTotalReceived() int64 {
 	return 0
 }
 
+func (m *PieceStoreSummary) GetReceipt() *PieceStoreReceipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+type PieceStoreReceipt struct {
+	Signature            []byte   `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceStoreReceipt) Reset()         { *m = PieceStoreReceipt{} }
+func (m *PieceStoreReceipt) String() string { return proto.CompactTextString(m) }
+func (*PieceStoreReceipt) ProtoMessage()    {}
+func (*PieceStoreReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{11}
+}
+func (m *PieceStoreReceipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceStoreReceipt.Unmarshal(m, b)
+}
+func (m *PieceStoreReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceStoreReceipt.Marshal(b, m, deterministic)
+}
+func (dst *PieceStoreReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceStoreReceipt.Merge(dst, src)
+}
+func (m *PieceStoreReceipt) XXX_Size() int {
+	return xxx_messageInfo_PieceStoreReceipt.Size(m)
+}
+func (m *PieceStoreReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceStoreReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceStoreReceipt proto.InternalMessageInfo
+
+func (m *PieceStoreReceipt) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *PieceStoreReceipt) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type PieceStoreReceipt_Data struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PieceSize            int64    `protobuf:"varint,2,opt,name=piece_size,json=pieceSize,proto3" json:"piece_size,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	ExpirationUnixSec    int64    `protobuf:"varint,4,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
+	PubKey               []byte   `protobuf:"bytes,5,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceStoreReceipt_Data) Reset()         { *m = PieceStoreReceipt_Data{} }
+func (m *PieceStoreReceipt_Data) String() string { return proto.CompactTextString(m) }
+func (*PieceStoreReceipt_Data) ProtoMessage()    {}
+func (*PieceStoreReceipt_Data) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{11, 0}
+}
+func (m *PieceStoreReceipt_Data) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceStoreReceipt_Data.Unmarshal(m, b)
+}
+func (m *PieceStoreReceipt_Data) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceStoreReceipt_Data.Marshal(b, m, deterministic)
+}
+func (dst *PieceStoreReceipt_Data) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceStoreReceipt_Data.Merge(dst, src)
+}
+func (m *PieceStoreReceipt_Data) XXX_Size() int {
+	return xxx_messageInfo_PieceStoreReceipt_Data.Size(m)
+}
+func (m *PieceStoreReceipt_Data) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceStoreReceipt_Data.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceStoreReceipt_Data proto.InternalMessageInfo
+
+func (m *PieceStoreReceipt_Data) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PieceStoreReceipt_Data) GetPieceSize() int64 {
+	if m != nil {
+		return m.PieceSize
+	}
+	return 0
+}
+
+func (m *PieceStoreReceipt_Data) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *PieceStoreReceipt_Data) GetExpirationUnixSec() int64 {
+	if m != nil {
+		return m.ExpirationUnixSec
+	}
+	return 0
+}
+
+func (m *PieceStoreReceipt_Data) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
 type StatsReq struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -772,7 +942,7 @@ func (m *StatsReq) Reset()         { *m = StatsReq{} }
 func (m *StatsReq) String() string { return proto.CompactTextString(m) }
 func (*StatsReq) ProtoMessage()    {}
 func (*StatsReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{10}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{12}
 }
 func (m *StatsReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsReq.Unmarshal(m, b)
@@ -793,20 +963,21 @@ func (m *StatsReq) XXX_DiscardUnknown() {
 var xxx_messageInfo_StatsReq proto.InternalMessageInfo
 
 type StatSummary struct {
-	UsedSpace            int64    `protobuf:"varint,1,opt,name=used_space,json=usedSpace,proto3" json:"used_space,omitempty"`
-	AvailableSpace       int64    `protobuf:"varint,2,opt,name=available_space,json=availableSpace,proto3" json:"available_space,omitempty"`
-	UsedBandwidth        int64    `protobuf:"varint,3,opt,name=used_bandwidth,json=usedBandwidth,proto3" json:"used_bandwidth,omitempty"`
-	AvailableBandwidth   int64    `protobuf:"varint,4,opt,name=available_bandwidth,json=availableBandwidth,proto3" json:"available_bandwidth,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	UsedSpace            int64             `protobuf:"varint,1,opt,name=used_space,json=usedSpace,proto3" json:"used_space,omitempty"`
+	AvailableSpace       int64             `protobuf:"varint,2,opt,name=available_space,json=availableSpace,proto3" json:"available_space,omitempty"`
+	UsedBandwidth        int64             `protobuf:"varint,3,opt,name=used_bandwidth,json=usedBandwidth,proto3" json:"used_bandwidth,omitempty"`
+	AvailableBandwidth   int64             `protobuf:"varint,4,opt,name=available_bandwidth,json=availableBandwidth,proto3" json:"available_bandwidth,omitempty"`
+	Satellites           []*SatelliteStats `protobuf:"bytes,5,rep,name=satellites" json:"satellites,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *StatSummary) Reset()         { *m = StatSummary{} }
 func (m *StatSummary) String() string { return proto.CompactTextString(m) }
 func (*StatSummary) ProtoMessage()    {}
 func (*StatSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{11}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{13}
 }
 func (m *StatSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummary.Unmarshal(m, b)
@@ -854,6 +1025,78 @@ func (m *StatSummary) GetAvailableBandwidth() int64 {
 	return 0
 }
 
+func (m *StatSummary) GetSatellites() []*SatelliteStats {
+	if m != nil {
+		return m.Satellites
+	}
+	return nil
+}
+
+// SatelliteStats is the usage of the node by a satellite, with the
+// bandwidth used this month
+type SatelliteStats struct {
+	SatelliteId          NodeID   `protobuf:"bytes,1,opt,name=satellite_id,json=satelliteId,proto3,customtype=NodeID" json:"satellite_id"`
+	Pieces               int64    `protobuf:"varint,2,opt,name=pieces,proto3" json:"pieces,omitempty"`
+	UsedSpace            int64    `protobuf:"varint,3,opt,name=used_space,json=usedSpace,proto3" json:"used_space,omitempty"`
+	PutBandwidth         int64    `protobuf:"varint,4,opt,name=put_bandwidth,json=putBandwidth,proto3" json:"put_bandwidth,omitempty"`
+	GetBandwidth         int64    `protobuf:"varint,5,opt,name=get_bandwidth,json=getBandwidth,proto3" json:"get_bandwidth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SatelliteStats) Reset()         { *m = SatelliteStats{} }
+func (m *SatelliteStats) String() string { return proto.CompactTextString(m) }
+func (*SatelliteStats) ProtoMessage()    {}
+func (*SatelliteStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{14}
+}
+func (m *SatelliteStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SatelliteStats.Unmarshal(m, b)
+}
+func (m *SatelliteStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SatelliteStats.Marshal(b, m, deterministic)
+}
+func (dst *SatelliteStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SatelliteStats.Merge(dst, src)
+}
+func (m *SatelliteStats) XXX_Size() int {
+	return xxx_messageInfo_SatelliteStats.Size(m)
+}
+func (m *SatelliteStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_SatelliteStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SatelliteStats proto.InternalMessageInfo
+
+func (m *SatelliteStats) GetPieces() int64 {
+	if m != nil {
+		return m.Pieces
+	}
+	return 0
+}
+
+func (m *SatelliteStats) GetUsedSpace() int64 {
+	if m != nil {
+		return m.UsedSpace
+	}
+	return 0
+}
+
+func (m *SatelliteStats) GetPutBandwidth() int64 {
+	if m != nil {
+		return m.PutBandwidth
+	}
+	return 0
+}
+
+func (m *SatelliteStats) GetGetBandwidth() int64 {
+	if m != nil {
+		return m.GetBandwidth
+	}
+	return 0
+}
+
 type SignedMessage struct {
 	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
@@ -867,7 +1110,7 @@ func (m *SignedMessage) Reset()         { *m = SignedMessage{} }
 func (m *SignedMessage) String() string { return proto.CompactTextString(m) }
 func (*SignedMessage) ProtoMessage()    {}
 func (*SignedMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_f6b209c85f045534, []int{12}
+	return fileDescriptor_piecestore_34b76124d4633eca, []int{15}
 }
 func (m *SignedMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedMessage.Unmarshal(m, b)
@@ -921,10 +1164,14 @@ func init() {
 	proto.RegisterType((*PieceRetrieval_PieceData)(nil), "piecestoreroutes.PieceRetrieval.PieceData")
 	proto.RegisterType((*PieceRetrievalStream)(nil), "piecestoreroutes.PieceRetrievalStream")
 	proto.RegisterType((*PieceDelete)(nil), "piecestoreroutes.PieceDelete")
+	proto.RegisterType((*PieceDeleteMany)(nil), "piecestoreroutes.PieceDeleteMany")
 	proto.RegisterType((*PieceDeleteSummary)(nil), "piecestoreroutes.PieceDeleteSummary")
 	proto.RegisterType((*PieceStoreSummary)(nil), "piecestoreroutes.PieceStoreSummary")
+	proto.RegisterType((*PieceStoreReceipt)(nil), "piecestoreroutes.PieceStoreReceipt")
+	proto.RegisterType((*PieceStoreReceipt_Data)(nil), "piecestoreroutes.PieceStoreReceipt.Data")
 	proto.RegisterType((*StatsReq)(nil), "piecestoreroutes.StatsReq")
 	proto.RegisterType((*StatSummary)(nil), "piecestoreroutes.StatSummary")
+	proto.RegisterType((*SatelliteStats)(nil), "piecestoreroutes.SatelliteStats")
 	proto.RegisterType((*SignedMessage)(nil), "piecestoreroutes.SignedMessage")
 	proto.RegisterEnum("piecestoreroutes.PayerBandwidthAllocation_Action", PayerBandwidthAllocation_Action_name, PayerBandwidthAllocation_Action_value)
 }
@@ -945,6 +1192,7 @@ type PieceStoreRoutesClient interface {
 	Retrieve(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_RetrieveClient, error)
 	Store(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_StoreClient, error)
 	Delete(ctx context.Context, in *PieceDelete, opts ...grpc.CallOption) (*PieceDeleteSummary, error)
+	DeleteMany(ctx context.Context, in *PieceDeleteMany, opts ...grpc.CallOption) (*PieceDeleteSummary, error)
 	Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatSummary, error)
 }
 
@@ -1039,6 +1287,15 @@ func (c *pieceStoreRoutesClient) Delete(ctx context.Context, in *PieceDelete, op
 	return out, nil
 }
 
+func (c *pieceStoreRoutesClient) DeleteMany(ctx context.Context, in *PieceDeleteMany, opts ...grpc.CallOption) (*PieceDeleteSummary, error) {
+	out := new(PieceDeleteSummary)
+	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/DeleteMany", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *pieceStoreRoutesClient) Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatSummary, error) {
 	out := new(StatSummary)
 	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/Stats", in, out, opts...)
@@ -1054,6 +1311,7 @@ type PieceStoreRoutesServer interface {
 	Retrieve(PieceStoreRoutes_RetrieveServer) error
 	Store(PieceStoreRoutes_StoreServer) error
 	Delete(context.Context, *PieceDelete) (*PieceDeleteSummary, error)
+	DeleteMany(context.Context, *PieceDeleteMany) (*PieceDeleteSummary, error)
 	Stats(context.Context, *StatsReq) (*StatSummary, error)
 }
 
@@ -1149,6 +1407,24 @@ func _PieceStoreRoutes_Delete_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PieceStoreRoutes_DeleteMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PieceDeleteMany)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PieceStoreRoutesServer).DeleteMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestoreroutes.PieceStoreRoutes/DeleteMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PieceStoreRoutesServer).DeleteMany(ctx, req.(*PieceDeleteMany))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PieceStoreRoutes_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StatsReq)
 	if err := dec(in); err != nil {
@@ -1179,6 +1455,10 @@ var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _PieceStoreRoutes_Delete_Handler,
 		},
+		{
+			MethodName: "DeleteMany",
+			Handler:    _PieceStoreRoutes_DeleteMany_Handler,
+		},
 		{
 			MethodName: "Stats",
 			Handler:    _PieceStoreRoutes_Stats_Handler,
@@ -1200,68 +1480,77 @@ var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 	Metadata: "piecestore.proto",
 }
 
-func init() { proto.RegisterFile("piecestore.proto", fileDescriptor_piecestore_f6b209c85f045534) }
-
-var fileDescriptor_piecestore_f6b209c85f045534 = []byte{
-	// 946 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xcf, 0x6e, 0xdb, 0xc6,
-	0x13, 0x36, 0x29, 0x5b, 0xb2, 0x46, 0x7f, 0xac, 0xac, 0x8d, 0xdf, 0x4f, 0x26, 0xe2, 0x5a, 0x60,
-	0x9a, 0x54, 0x48, 0x00, 0xb5, 0x71, 0x81, 0xde, 0x63, 0xd8, 0x28, 0x84, 0xa0, 0x8e, 0xb1, 0xb2,
-	0x2f, 0x39, 0x94, 0x59, 0x92, 0x13, 0x65, 0x11, 0x8a, 0x64, 0xc9, 0xa5, 0x2b, 0xf9, 0x39, 0x7a,
-	0xeb, 0x33, 0xf4, 0xda, 0x27, 0xe8, 0xa1, 0x40, 0xef, 0x3d, 0xf4, 0x10, 0xa0, 0x6f, 0x52, 0x70,
-	0x97, 0x22, 0x2d, 0x4b, 0x94, 0x8a, 0xa0, 0xb9, 0x71, 0x67, 0x66, 0xbf, 0x99, 0xf9, 0xf6, 0x9b,
-	0x5d, 0x42, 0x27, 0xe4, 0xe8, 0x60, 0x2c, 0x82, 0x08, 0x07, 0x61, 0x14, 0x88, 0x80, 0xdc, 0xb1,
-	0x44, 0x41, 0x22, 0x30, 0x36, 0x60, 0x1c, 0x8c, 0x03, 0xe5, 0x35, 0x7f, 0xad, 0x40, 0xf7, 0x92,
-	0xcd, 0x30, 0x3a, 0x65, 0xbe, 0xfb, 0x23, 0x77, 0xc5, 0xbb, 0x17, 0x9e, 0x17, 0x38, 0x4c, 0xf0,
-	0xc0, 0x27, 0x0f, 0xa1, 0x1e, 0xf3, 0xb1, 0xcf, 0x44, 0x12, 0x61, 0x57, 0xeb, 0x69, 0xfd, 0x26,
-	0x2d, 0x0c, 0x84, 0xc0, 0xb6, 0xcb, 0x04, 0xeb, 0xea, 0xd2, 0x21, 0xbf, 0x8d, 0x3f, 0x74, 0xd8,
-	0x3e, 0x63, 0x82, 0x91, 0xe7, 0xd0, 0x8c, 0x99, 0x40, 0xcf, 0xe3, 0x02, 0x2d, 0xee, 0xaa, 0xdd,
-	0xa7, 0xed, 0xdf, 0x3f, 0x1c, 0x6f, 0xfd, 0xf5, 0xe1, 0xb8, 0x7a, 0x11, 0xb8, 0x38, 0x3c, 0xa3,
-	0x8d, 0x3c, 0x66, 0xe8, 0x92, 0x67, 0x50, 0x4f, 0x42, 0x8f, 0xfb, 0xef, 0xd3, 0x78, 0x7d, 0x65,
-	0xfc, 0xae, 0x0a, 0x18, 0xba, 0xe4, 0x10, 0x76, 0x27, 0x6c, 0x6a, 0xc5, 0xfc, 0x16, 0xbb, 0x95,
-	0x9e, 0xd6, 0xaf, 0xd0, 0xda, 0x84, 0x4d, 0x47, 0xfc, 0x16, 0xc9, 0x00, 0xf6, 0x71, 0x1a, 0xf2,
-	0x48, 0xf6, 0x60, 0x25, 0x3e, 0x9f, 0x5a, 0x31, 0x3a, 0xdd, 0x6d, 0x19, 0xf5, 0xa0, 0x70, 0x5d,
-	0xfb, 0x7c, 0x3a, 0x42, 0x87, 0x3c, 0x82, 0x56, 0x8c, 0x11, 0x67, 0x9e, 0xe5, 0x27, 0x13, 0x1b,
-	0xa3, 0xee, 0x4e, 0x4f, 0xeb, 0xd7, 0x69, 0x53, 0x19, 0x2f, 0xa4, 0x8d, 0x0c, 0xa1, 0xca, 0x9c,
-	0x74, 0x57, 0xb7, 0xda, 0xd3, 0xfa, 0xed, 0x93, 0xe7, 0x83, 0xfb, 0xb4, 0x0e, 0xca, 0x68, 0x1c,
-	0xbc, 0x90, 0x1b, 0x69, 0x06, 0x40, 0xfa, 0xd0, 0x71, 0x22, 0x64, 0x02, 0xdd, 0xa2, 0xb8, 0x9a,
-	0x2c, 0xae, 0x9d, 0xd9, 0xb3, 0xca, 0x4c, 0x03, 0xaa, 0x6a, 0x2f, 0xa9, 0x41, 0xe5, 0xf2, 0xfa,
-	0xaa, 0xb3, 0x95, 0x7e, 0x7c, 0x7b, 0x7e, 0xd5, 0xd1, 0xcc, 0x9f, 0x74, 0x38, 0xa4, 0xe8, 0x8b,
-	0xff, 0xea, 0xe4, 0x7e, 0xd3, 0xb2, 0x93, 0xbb, 0x86, 0x4e, 0x98, 0x76, 0x62, 0xb1, 0x1c, 0x4e,
-	0x22, 0x34, 0x4e, 0x9e, 0xfe, 0xfb, 0x9e, 0xe9, 0x9e, 0xc4, 0xb8, 0x53, 0xd1, 0x01, 0xec, 0x88,
-	0x40, 0x30, 0x4f, 0x26, 0xad, 0x50, 0xb5, 0x20, 0xdf, 0xc0, 0x5e, 0x0a, 0xc7, 0xc6, 0x68, 0xf9,
-	0x81, 0x2b, 0x95, 0x52, 0x59, 0x79, 0xf2, 0xad, 0x2c, 0x4c, 0x2e, 0x5d, 0xf2, 0x7f, 0xa8, 0x85,
-	0x89, 0x6d, 0xbd, 0xc7, 0x99, 0x3c, 0xd7, 0x26, 0xad, 0x86, 0x89, 0xfd, 0x12, 0x67, 0xe6, 0xdf,
-	0x3a, 0xc0, 0x65, 0x5a, 0xe5, 0x28, 0xad, 0x92, 0x7c, 0x0f, 0x07, 0xf6, 0xbc, 0xba, 0xe5, 0x86,
-	0x9e, 0x2d, 0x37, 0x54, 0x4a, 0x29, 0xdd, 0xb7, 0x57, 0xf0, 0x7c, 0x0e, 0x20, 0x21, 0xac, 0x9c,
-	0xcf, 0xc6, 0xc9, 0x93, 0x15, 0x34, 0xe5, 0x15, 0xa9, 0xcf, 0x94, 0x68, 0x5a, 0x0f, 0xe7, 0x9f,
-	0xe4, 0x1c, 0x5a, 0x2c, 0x11, 0xef, 0x82, 0x88, 0xdf, 0xaa, 0xfa, 0x2a, 0x12, 0xe9, 0x78, 0x19,
-	0x69, 0xc4, 0xc7, 0x3e, 0xba, 0xdf, 0x61, 0x1c, 0xb3, 0x31, 0xd2, 0xc5, 0x5d, 0x06, 0x42, 0x3d,
-	0x87, 0x27, 0x6d, 0xd0, 0xb3, 0xb9, 0xab, 0x53, 0x9d, 0xbb, 0x65, 0x63, 0xa1, 0x97, 0x8d, 0x45,
-	0x17, 0x6a, 0x4e, 0xe0, 0x0b, 0xf4, 0x85, 0x3a, 0x12, 0x3a, 0x5f, 0x9a, 0x6f, 0xa0, 0x26, 0xd3,
-	0x0c, 0xdd, 0xa5, 0x24, 0x4b, 0x8d, 0xe8, 0x1f, 0xd3, 0x88, 0x39, 0x81, 0xa6, 0xa2, 0x2c, 0x99,
-	0x4c, 0x58, 0x34, 0x5b, 0x4a, 0x73, 0x34, 0xa7, 0x5d, 0xce, 0xbf, 0x6a, 0x41, 0xd1, 0xb9, 0xee,
-	0x06, 0xa8, 0x94, 0xb4, 0x6a, 0xfe, 0xa9, 0x43, 0x5b, 0xe6, 0xa3, 0x28, 0x22, 0x8e, 0x37, 0xcc,
-	0xfb, 0xe4, 0xc2, 0x19, 0xae, 0x10, 0xce, 0xd3, 0x12, 0xe1, 0xe4, 0x55, 0x7d, 0x52, 0xf1, 0xd0,
-	0x75, 0xe2, 0xd9, 0x40, 0xf8, 0xff, 0xa0, 0x1a, 0xbc, 0x7d, 0x1b, 0xa3, 0xc8, 0x38, 0xce, 0x56,
-	0xe6, 0x2b, 0x38, 0x58, 0xec, 0x60, 0x24, 0x22, 0x64, 0x93, 0x7b, 0x70, 0xda, 0x7d, 0xb8, 0x3b,
-	0xd2, 0xd3, 0x17, 0xa5, 0xe7, 0x42, 0x43, 0x15, 0x89, 0x1e, 0x0a, 0xdc, 0x2c, 0xbf, 0x8f, 0xa2,
-	0xc2, 0x1c, 0x00, 0xb9, 0x93, 0x65, 0x2e, 0xc2, 0x2e, 0xd4, 0x26, 0x2a, 0x3e, 0xcb, 0x38, 0x5f,
-	0x9a, 0x57, 0xf0, 0xa0, 0x98, 0xf0, 0x8d, 0xe1, 0xe4, 0x31, 0xb4, 0xe5, 0xed, 0x67, 0x45, 0xe8,
-	0x20, 0xbf, 0x41, 0x37, 0x23, 0xb4, 0x25, 0xad, 0x34, 0x33, 0x9a, 0x00, 0xbb, 0x23, 0xc1, 0x44,
-	0x4c, 0xf1, 0x07, 0xf3, 0x17, 0x0d, 0x1a, 0xe9, 0x62, 0x0e, 0x7e, 0x04, 0x90, 0xc4, 0xe8, 0x5a,
-	0x71, 0xc8, 0x9c, 0x9c, 0xc0, 0xd4, 0x32, 0x4a, 0x0d, 0xe4, 0x0b, 0xd8, 0x63, 0x37, 0x8c, 0x7b,
-	0xcc, 0xf6, 0x30, 0x8b, 0x51, 0x29, 0xda, 0xb9, 0x59, 0x05, 0x3e, 0x86, 0xb6, 0xc4, 0xc9, 0x25,
-	0x9a, 0x1d, 0x60, 0x2b, 0xb5, 0xe6, 0x62, 0x26, 0x5f, 0xc2, 0x7e, 0x81, 0x57, 0xc4, 0xaa, 0x27,
-	0x95, 0xe4, 0xae, 0x7c, 0x83, 0xf9, 0x06, 0x5a, 0x0b, 0x0c, 0xe7, 0x4f, 0x8e, 0x56, 0x3c, 0x39,
-	0x8b, 0x8f, 0x94, 0x7e, 0xff, 0x91, 0x4a, 0x35, 0x92, 0xd8, 0x1e, 0x77, 0xe4, 0x2d, 0xaf, 0xae,
-	0xa0, 0xba, 0xb2, 0xbc, 0xc4, 0xd9, 0xc9, 0xcf, 0x15, 0xe8, 0x14, 0xa4, 0x53, 0x79, 0xaa, 0xe4,
-	0x0c, 0x76, 0xa4, 0x8d, 0x1c, 0x96, 0x8c, 0xd2, 0xd0, 0x35, 0x3e, 0x2b, 0xbb, 0x9e, 0x15, 0xb5,
-	0xe6, 0x16, 0x79, 0x0d, 0xbb, 0x99, 0x60, 0x91, 0xf4, 0x36, 0xcd, 0xa4, 0xf1, 0x64, 0x53, 0x84,
-	0xd2, 0xbc, 0xb9, 0xd5, 0xd7, 0xbe, 0xd2, 0xc8, 0x05, 0xec, 0xa8, 0x97, 0xe9, 0xe1, 0xba, 0x57,
-	0xc2, 0x78, 0xb4, 0xce, 0x9b, 0x57, 0xda, 0xd7, 0xc8, 0x2b, 0xa8, 0x66, 0xb3, 0x70, 0x54, 0xb2,
-	0x45, 0xb9, 0x8d, 0xcf, 0xd7, 0xba, 0x8b, 0xe6, 0xcf, 0xd2, 0x02, 0x99, 0x88, 0x89, 0xb1, 0x62,
-	0x68, 0x32, 0x39, 0x1a, 0x47, 0xab, 0x7d, 0x39, 0xca, 0xe9, 0xf6, 0x6b, 0x3d, 0xb4, 0xed, 0xaa,
-	0xfc, 0xc7, 0xfc, 0xfa, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x64, 0xd1, 0x91, 0x47, 0x95, 0x0a,
-	0x00, 0x00,
+func init() { proto.RegisterFile("piecestore.proto", fileDescriptor_piecestore_34b76124d4633eca) }
+
+var fileDescriptor_piecestore_34b76124d4633eca = []byte{
+	// 1099 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x36, 0x49, 0x4b, 0xb2, 0x46, 0x3f, 0x56, 0xd6, 0x46, 0x2a, 0x0b, 0x71, 0xad, 0xd2, 0x4d,
+	0x2a, 0x24, 0x80, 0xda, 0xb8, 0x40, 0x6f, 0x05, 0x1a, 0xc3, 0x46, 0x21, 0x04, 0x71, 0x0c, 0x2a,
+	0x46, 0x81, 0x1c, 0xca, 0xac, 0xc4, 0x89, 0xbc, 0x0d, 0x45, 0xb2, 0xe4, 0xd2, 0xb5, 0xfc, 0x18,
+	0x6d, 0x9f, 0xa5, 0x4f, 0x90, 0x43, 0x81, 0xde, 0x7b, 0xc8, 0x21, 0x40, 0x9f, 0xa0, 0x97, 0x3e,
+	0x40, 0xc1, 0xdd, 0x15, 0xa9, 0x7f, 0x27, 0x46, 0x72, 0xdb, 0x9d, 0xf9, 0x66, 0x76, 0x66, 0xf6,
+	0x9b, 0x59, 0x12, 0x6a, 0x01, 0xc3, 0x3e, 0x46, 0xdc, 0x0f, 0xb1, 0x1d, 0x84, 0x3e, 0xf7, 0xc9,
+	0x84, 0x24, 0xf4, 0x63, 0x8e, 0x51, 0x03, 0x06, 0xfe, 0xc0, 0x97, 0x5a, 0xf3, 0x0f, 0x03, 0xea,
+	0xa7, 0x74, 0x84, 0xe1, 0x21, 0xf5, 0x9c, 0x5f, 0x98, 0xc3, 0xcf, 0x1f, 0xb9, 0xae, 0xdf, 0xa7,
+	0x9c, 0xf9, 0x1e, 0xb9, 0x03, 0xc5, 0x88, 0x0d, 0x3c, 0xca, 0xe3, 0x10, 0xeb, 0x5a, 0x53, 0x6b,
+	0x95, 0xad, 0x4c, 0x40, 0x08, 0xac, 0x3b, 0x94, 0xd3, 0xba, 0x2e, 0x14, 0x62, 0xdd, 0xf8, 0x4b,
+	0x87, 0xf5, 0x23, 0xca, 0x29, 0x79, 0x08, 0xe5, 0x88, 0x72, 0x74, 0x5d, 0xc6, 0xd1, 0x66, 0x8e,
+	0xb4, 0x3e, 0xac, 0xfe, 0xf9, 0x76, 0x6f, 0xed, 0xcd, 0xdb, 0xbd, 0xfc, 0x89, 0xef, 0x60, 0xe7,
+	0xc8, 0x2a, 0xa5, 0x98, 0x8e, 0x43, 0x1e, 0x40, 0x31, 0x0e, 0x5c, 0xe6, 0xbd, 0x4a, 0xf0, 0xfa,
+	0x42, 0xfc, 0x86, 0x04, 0x74, 0x1c, 0xb2, 0x03, 0x1b, 0x43, 0x7a, 0x69, 0x47, 0xec, 0x0a, 0xeb,
+	0x46, 0x53, 0x6b, 0x19, 0x56, 0x61, 0x48, 0x2f, 0xbb, 0xec, 0x0a, 0x49, 0x1b, 0xb6, 0xf0, 0x32,
+	0x60, 0xa1, 0xc8, 0xc1, 0x8e, 0x3d, 0x76, 0x69, 0x47, 0xd8, 0xaf, 0xaf, 0x0b, 0xd4, 0xad, 0x4c,
+	0x75, 0xe6, 0xb1, 0xcb, 0x2e, 0xf6, 0xc9, 0x3e, 0x54, 0x22, 0x0c, 0x19, 0x75, 0x6d, 0x2f, 0x1e,
+	0xf6, 0x30, 0xac, 0xe7, 0x9a, 0x5a, 0xab, 0x68, 0x95, 0xa5, 0xf0, 0x44, 0xc8, 0x48, 0x07, 0xf2,
+	0xb4, 0x9f, 0x58, 0xd5, 0xf3, 0x4d, 0xad, 0x55, 0x3d, 0x78, 0xd8, 0x9e, 0x2d, 0x6b, 0x7b, 0x59,
+	0x19, 0xdb, 0x8f, 0x84, 0xa1, 0xa5, 0x1c, 0x90, 0x16, 0xd4, 0xfa, 0x21, 0x52, 0x8e, 0x4e, 0x16,
+	0x5c, 0x41, 0x04, 0x57, 0x55, 0x72, 0x15, 0x99, 0xd9, 0x80, 0xbc, 0xb4, 0x25, 0x05, 0x30, 0x4e,
+	0xcf, 0x9e, 0xd5, 0xd6, 0x92, 0xc5, 0xf7, 0xc7, 0xcf, 0x6a, 0x9a, 0xf9, 0xbb, 0x0e, 0x3b, 0x16,
+	0x7a, 0xfc, 0x43, 0xdd, 0xdc, 0x6b, 0x4d, 0xdd, 0xdc, 0x19, 0xd4, 0x82, 0x24, 0x13, 0x9b, 0xa6,
+	0xee, 0x84, 0x87, 0xd2, 0xc1, 0xfd, 0x77, 0xcf, 0xd9, 0xda, 0x14, 0x3e, 0x26, 0x22, 0xda, 0x86,
+	0x1c, 0xf7, 0x39, 0x75, 0xc5, 0xa1, 0x86, 0x25, 0x37, 0xe4, 0x1b, 0xd8, 0x4c, 0xdc, 0xd1, 0x01,
+	0xda, 0x9e, 0xef, 0x08, 0xa6, 0x18, 0x0b, 0x6f, 0xbe, 0xa2, 0x60, 0x62, 0xeb, 0x90, 0x4f, 0xa0,
+	0x10, 0xc4, 0x3d, 0xfb, 0x15, 0x8e, 0xc4, 0xbd, 0x96, 0xad, 0x7c, 0x10, 0xf7, 0x1e, 0xe3, 0xc8,
+	0xfc, 0x47, 0x07, 0x38, 0x4d, 0xa2, 0xec, 0x26, 0x51, 0x92, 0x1f, 0x61, 0xbb, 0x37, 0x8e, 0x6e,
+	0x3e, 0xa1, 0x07, 0xf3, 0x09, 0x2d, 0x2d, 0xa9, 0xb5, 0xd5, 0x9b, 0x17, 0x92, 0x63, 0x00, 0xe1,
+	0xc2, 0x4e, 0xeb, 0x59, 0x3a, 0xb8, 0xb7, 0xa0, 0x4c, 0x69, 0x44, 0x72, 0x99, 0x14, 0xda, 0x2a,
+	0x06, 0xe3, 0x25, 0x39, 0x86, 0x0a, 0x8d, 0xf9, 0xb9, 0x1f, 0xb2, 0x2b, 0x19, 0x9f, 0x21, 0x3c,
+	0xed, 0xcd, 0x7b, 0xea, 0xb2, 0x81, 0x87, 0xce, 0x13, 0x8c, 0x22, 0x3a, 0x40, 0x6b, 0xda, 0xaa,
+	0x81, 0x50, 0x4c, 0xdd, 0x93, 0x2a, 0xe8, 0xaa, 0xef, 0x8a, 0x96, 0xce, 0x9c, 0x65, 0x6d, 0xa1,
+	0x2f, 0x6b, 0x8b, 0x3a, 0x14, 0xfa, 0xbe, 0xc7, 0xd1, 0xe3, 0xf2, 0x4a, 0xac, 0xf1, 0xd6, 0x7c,
+	0x01, 0x05, 0x71, 0x4c, 0xc7, 0x99, 0x3b, 0x64, 0x2e, 0x11, 0xfd, 0x26, 0x89, 0x98, 0x43, 0x28,
+	0xcb, 0x92, 0xc5, 0xc3, 0x21, 0x0d, 0x47, 0x73, 0xc7, 0xec, 0x8e, 0xcb, 0x2e, 0xfa, 0x5f, 0xa6,
+	0x20, 0xcb, 0xb9, 0x6a, 0x02, 0x18, 0x4b, 0x52, 0x35, 0xff, 0xd6, 0xa1, 0x2a, 0xce, 0xb3, 0x90,
+	0x87, 0x0c, 0x2f, 0xa8, 0xfb, 0xd1, 0x89, 0xd3, 0x59, 0x40, 0x9c, 0xfb, 0x4b, 0x88, 0x93, 0x46,
+	0xf5, 0x51, 0xc9, 0x63, 0xad, 0x22, 0xcf, 0x35, 0x05, 0xbf, 0x0d, 0x79, 0xff, 0xe5, 0xcb, 0x08,
+	0xb9, 0xaa, 0xb1, 0xda, 0x99, 0x4f, 0x61, 0x7b, 0x3a, 0x83, 0x2e, 0x0f, 0x91, 0x0e, 0x67, 0xdc,
+	0x69, 0xb3, 0xee, 0x26, 0xa8, 0xa7, 0x4f, 0x53, 0xcf, 0x81, 0x92, 0x0c, 0x12, 0x5d, 0xe4, 0x78,
+	0x3d, 0xfd, 0x6e, 0x54, 0x0a, 0xf3, 0x27, 0xd8, 0x9c, 0x38, 0xe5, 0x09, 0xf5, 0x46, 0xa4, 0x06,
+	0x06, 0x73, 0xa2, 0xba, 0xd6, 0x34, 0x5a, 0x45, 0x2b, 0x59, 0x7e, 0x28, 0xaa, 0xb7, 0x81, 0x4c,
+	0x9c, 0x35, 0x26, 0x7c, 0x1d, 0x0a, 0x43, 0x89, 0x57, 0xd9, 0x8d, 0xb7, 0xe6, 0x6f, 0x1a, 0xdc,
+	0xca, 0xc6, 0xc9, 0xb5, 0x78, 0x72, 0x17, 0xaa, 0x62, 0xd4, 0xda, 0x21, 0xf6, 0x91, 0x5d, 0xa0,
+	0xa3, 0x6e, 0xaf, 0x22, 0xa4, 0x96, 0x12, 0x92, 0x6f, 0xa1, 0x20, 0x00, 0x01, 0x57, 0x35, 0xdb,
+	0x5f, 0x35, 0xc5, 0x2c, 0x09, 0xb5, 0xc6, 0x36, 0xe6, 0x9b, 0xa9, 0xa8, 0x94, 0xfa, 0x06, 0xaf,
+	0xd0, 0xaf, 0xe3, 0x57, 0xe8, 0x3d, 0x09, 0x48, 0x60, 0xfd, 0x9c, 0x46, 0xe7, 0x6a, 0x52, 0x89,
+	0xf5, 0x7b, 0x7f, 0x07, 0x4c, 0xbc, 0x29, 0xb9, 0xa9, 0x37, 0x05, 0x60, 0xa3, 0xcb, 0x29, 0x8f,
+	0x2c, 0xfc, 0xd9, 0xfc, 0x57, 0x83, 0x52, 0xb2, 0x19, 0x17, 0x7e, 0x17, 0x20, 0x8e, 0xd0, 0xb1,
+	0xa3, 0x80, 0xf6, 0x53, 0x26, 0x27, 0x92, 0x6e, 0x22, 0x20, 0x5f, 0xc0, 0x26, 0xbd, 0xa0, 0xcc,
+	0xa5, 0x3d, 0x17, 0x15, 0x46, 0xc6, 0x5e, 0x4d, 0xc5, 0x12, 0x78, 0x17, 0xaa, 0xc2, 0x4f, 0x3a,
+	0x2b, 0x54, 0x27, 0x55, 0x12, 0x69, 0x3a, 0x55, 0xc8, 0x97, 0xb0, 0x95, 0xf9, 0xcb, 0xb0, 0x32,
+	0x27, 0x92, 0xaa, 0x32, 0x83, 0xef, 0x00, 0xd2, 0x6f, 0xac, 0xa8, 0x9e, 0x6b, 0x1a, 0xad, 0xd2,
+	0x41, 0x73, 0x01, 0x45, 0xc7, 0x18, 0x99, 0xe8, 0x84, 0x8d, 0xf9, 0x5a, 0x83, 0xea, 0xb4, 0xfa,
+	0x26, 0x1f, 0x77, 0xb7, 0x21, 0x2f, 0x0f, 0x55, 0xf9, 0xab, 0xdd, 0x4c, 0xfd, 0x8c, 0xd9, 0xfa,
+	0xed, 0x43, 0x25, 0x88, 0xf9, 0x5c, 0xa6, 0xe5, 0x20, 0xe6, 0x59, 0x8e, 0xfb, 0x50, 0x19, 0xe0,
+	0x24, 0x28, 0x27, 0x41, 0x03, 0xcc, 0x40, 0xe6, 0x0b, 0xa8, 0x4c, 0xf5, 0x61, 0x4a, 0x3f, 0x2d,
+	0xa3, 0xdf, 0x34, 0x61, 0xf5, 0x59, 0xc2, 0x26, 0x1c, 0x8c, 0x7b, 0x2e, 0xeb, 0x0b, 0x8e, 0x48,
+	0xaa, 0x15, 0xa5, 0xe4, 0x31, 0x8e, 0x0e, 0xfe, 0x33, 0xa0, 0x36, 0xd1, 0x03, 0xa2, 0xb0, 0xe4,
+	0x08, 0x72, 0x42, 0x46, 0x76, 0x96, 0xf4, 0x53, 0xc7, 0x69, 0x7c, 0xba, 0xac, 0xd5, 0x24, 0xc7,
+	0xcc, 0x35, 0xf2, 0x1c, 0x36, 0xd4, 0x08, 0x45, 0xd2, 0xbc, 0xee, 0x95, 0x68, 0xdc, 0xbb, 0x0e,
+	0x21, 0xa7, 0xb0, 0xb9, 0xd6, 0xd2, 0xbe, 0xd2, 0xc8, 0x09, 0xe4, 0xe4, 0xb7, 0xd2, 0x9d, 0x55,
+	0x1d, 0xdf, 0x58, 0x39, 0x0f, 0xd2, 0x48, 0x5b, 0x1a, 0x79, 0x0a, 0x79, 0x35, 0x9d, 0x77, 0x97,
+	0x98, 0x48, 0x75, 0xe3, 0xf3, 0x95, 0xea, 0x2c, 0xf9, 0x1f, 0x00, 0x26, 0x06, 0xf1, 0x67, 0x2b,
+	0xad, 0x12, 0xc8, 0x3b, 0x3b, 0x3e, 0x82, 0x9c, 0xe4, 0x73, 0x63, 0x41, 0x43, 0xa8, 0x86, 0x6f,
+	0xec, 0x2e, 0xd6, 0xa5, 0x5e, 0x0e, 0xd7, 0x9f, 0xeb, 0x41, 0xaf, 0x97, 0x17, 0xbf, 0x53, 0x5f,
+	0xff, 0x3f, 0x00, 0x76, 0x8a, 0x14, 0x81, 0x80, 0x0d, 0x00, 0x00,
 }
@@ -57,6 +57,24 @@ func (mr *MockPieceStoreRoutesClientMockRecorder) Delete(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Delete), varargs...)
 }
 
+// DeleteMany mocks base method
+func (m *MockPieceStoreRoutesClient) DeleteMany(arg0 context.Context, arg1 *PieceDeleteMany, arg2 ...grpc.CallOption) (*PieceDeleteSummary, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteMany", varargs...)
+	ret0, _ := ret[0].(*PieceDeleteSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMany indicates an expected call of DeleteMany
+func (mr *MockPieceStoreRoutesClientMockRecorder) DeleteMany(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMany", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).DeleteMany), varargs...)
+}
+
 // Piece mocks base method
 func (m *MockPieceStoreRoutesClient) Piece(arg0 context.Context, arg1 *PieceId, arg2 ...grpc.CallOption) (*PieceSummary, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -42,7 +42,7 @@ func (x RedundancyScheme_SchemeType) String() string {
 	return proto.EnumName(RedundancyScheme_SchemeType_name, int32(x))
 }
 func (RedundancyScheme_SchemeType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{0, 0}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{0, 0}
 }
 
 type Pointer_DataType int32
@@ -65,7 +65,7 @@ func (x Pointer_DataType) String() string {
 	return proto.EnumName(Pointer_DataType_name, int32(x))
 }
 func (Pointer_DataType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{3, 0}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{4, 0}
 }
 
 type RedundancyScheme struct {
@@ -85,7 +85,7 @@ func (m *RedundancyScheme) Reset()         { *m = RedundancyScheme{} }
 func (m *RedundancyScheme) String() string { return proto.CompactTextString(m) }
 func (*RedundancyScheme) ProtoMessage()    {}
 func (*RedundancyScheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{0}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{0}
 }
 func (m *RedundancyScheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RedundancyScheme.Unmarshal(m, b)
@@ -148,18 +148,20 @@ func (m *RedundancyScheme) GetErasureShareSize() int32 {
 }
 
 type RemotePiece struct {
-	PieceNum             int32    `protobuf:"varint,1,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
-	NodeId               NodeID   `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PieceNum             int32         `protobuf:"varint,1,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
+	NodeId               NodeID        `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3,customtype=NodeID" json:"node_id"`
+	Receipt              *PieceReceipt `protobuf:"bytes,3,opt,name=receipt" json:"receipt,omitempty"`
+	Corrupted            bool          `protobuf:"varint,4,opt,name=corrupted,proto3" json:"corrupted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *RemotePiece) Reset()         { *m = RemotePiece{} }
 func (m *RemotePiece) String() string { return proto.CompactTextString(m) }
 func (*RemotePiece) ProtoMessage()    {}
 func (*RemotePiece) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{1}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{1}
 }
 func (m *RemotePiece) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemotePiece.Unmarshal(m, b)
@@ -186,6 +188,76 @@ func (m *RemotePiece) GetPieceNum() int32 {
 	return 0
 }
 
+func (m *RemotePiece) GetReceipt() *PieceReceipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+func (m *RemotePiece) GetCorrupted() bool {
+	if m != nil {
+		return m.Corrupted
+	}
+	return false
+}
+
+// PieceReceipt keeps the parts of the receipt a storage node signed for a
+// piece which cannot be derived from the pointer of its segment
+type PieceReceipt struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	PubKey               []byte   `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceReceipt) Reset()         { *m = PieceReceipt{} }
+func (m *PieceReceipt) String() string { return proto.CompactTextString(m) }
+func (*PieceReceipt) ProtoMessage()    {}
+func (*PieceReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{2}
+}
+func (m *PieceReceipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceReceipt.Unmarshal(m, b)
+}
+func (m *PieceReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceReceipt.Marshal(b, m, deterministic)
+}
+func (dst *PieceReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceReceipt.Merge(dst, src)
+}
+func (m *PieceReceipt) XXX_Size() int {
+	return xxx_messageInfo_PieceReceipt.Size(m)
+}
+func (m *PieceReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceReceipt proto.InternalMessageInfo
+
+func (m *PieceReceipt) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *PieceReceipt) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *PieceReceipt) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type RemoteSegment struct {
 	Redundancy *RedundancyScheme `protobuf:"bytes,1,opt,name=redundancy" json:"redundancy,omitempty"`
 	// TODO: may want to use customtype and fixed-length byte slice
@@ -201,7 +273,7 @@ func (m *RemoteSegment) Reset()         { *m = RemoteSegment{} }
 func (m *RemoteSegment) String() string { return proto.CompactTextString(m) }
 func (*RemoteSegment) ProtoMessage()    {}
 func (*RemoteSegment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{2}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{3}
 }
 func (m *RemoteSegment) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoteSegment.Unmarshal(m, b)
@@ -267,7 +339,7 @@ func (m *Pointer) Reset()         { *m = Pointer{} }
 func (m *Pointer) String() string { return proto.CompactTextString(m) }
 func (*Pointer) ProtoMessage()    {}
 func (*Pointer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{3}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{4}
 }
 func (m *Pointer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pointer.Unmarshal(m, b)
@@ -349,7 +421,7 @@ func (m *PutRequest) Reset()         { *m = PutRequest{} }
 func (m *PutRequest) String() string { return proto.CompactTextString(m) }
 func (*PutRequest) ProtoMessage()    {}
 func (*PutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{4}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{5}
 }
 func (m *PutRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutRequest.Unmarshal(m, b)
@@ -395,7 +467,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{5}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{6}
 }
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
@@ -439,7 +511,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{6}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{7}
 }
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
@@ -512,7 +584,7 @@ func (m *PutResponse) Reset()         { *m = PutResponse{} }
 func (m *PutResponse) String() string { return proto.CompactTextString(m) }
 func (*PutResponse) ProtoMessage()    {}
 func (*PutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{7}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{8}
 }
 func (m *PutResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutResponse.Unmarshal(m, b)
@@ -547,7 +619,7 @@ func (m *GetResponse) Reset()         { *m = GetResponse{} }
 func (m *GetResponse) String() string { return proto.CompactTextString(m) }
 func (*GetResponse) ProtoMessage()    {}
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{8}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{9}
 }
 func (m *GetResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
@@ -608,7 +680,7 @@ func (m *ListResponse) Reset()         { *m = ListResponse{} }
 func (m *ListResponse) String() string { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()    {}
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{9}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{10}
 }
 func (m *ListResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
@@ -655,7 +727,7 @@ func (m *ListResponse_Item) Reset()         { *m = ListResponse_Item{} }
 func (m *ListResponse_Item) String() string { return proto.CompactTextString(m) }
 func (*ListResponse_Item) ProtoMessage()    {}
 func (*ListResponse_Item) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{9, 0}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{10, 0}
 }
 func (m *ListResponse_Item) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListResponse_Item.Unmarshal(m, b)
@@ -707,7 +779,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{10}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{11}
 }
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
@@ -745,7 +817,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{11}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{12}
 }
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
@@ -765,6 +837,187 @@ func (m *DeleteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
 
+// UpdateMetadataRequest is a request message for the UpdateMetadata rpc call
+type UpdateMetadataRequest struct {
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateMetadataRequest) Reset()         { *m = UpdateMetadataRequest{} }
+func (m *UpdateMetadataRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateMetadataRequest) ProtoMessage()    {}
+func (*UpdateMetadataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{13}
+}
+func (m *UpdateMetadataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateMetadataRequest.Unmarshal(m, b)
+}
+func (m *UpdateMetadataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateMetadataRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateMetadataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateMetadataRequest.Merge(dst, src)
+}
+func (m *UpdateMetadataRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateMetadataRequest.Size(m)
+}
+func (m *UpdateMetadataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateMetadataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateMetadataRequest proto.InternalMessageInfo
+
+func (m *UpdateMetadataRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *UpdateMetadataRequest) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// UpdateMetadataResponse is a response message for the UpdateMetadata rpc call
+type UpdateMetadataResponse struct {
+	Pointer              *Pointer `protobuf:"bytes,1,opt,name=pointer" json:"pointer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateMetadataResponse) Reset()         { *m = UpdateMetadataResponse{} }
+func (m *UpdateMetadataResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateMetadataResponse) ProtoMessage()    {}
+func (*UpdateMetadataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{14}
+}
+func (m *UpdateMetadataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateMetadataResponse.Unmarshal(m, b)
+}
+func (m *UpdateMetadataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateMetadataResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateMetadataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateMetadataResponse.Merge(dst, src)
+}
+func (m *UpdateMetadataResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateMetadataResponse.Size(m)
+}
+func (m *UpdateMetadataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateMetadataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateMetadataResponse proto.InternalMessageInfo
+
+func (m *UpdateMetadataResponse) GetPointer() *Pointer {
+	if m != nil {
+		return m.Pointer
+	}
+	return nil
+}
+
+// DeleteObjectsRequest is a request message for the DeleteObjects rpc call.
+// The paths start with the bucket and have no segment prefix.
+type DeleteObjectsRequest struct {
+	Paths                []string `protobuf:"bytes,1,rep,name=paths" json:"paths,omitempty"`
+	Prefixes             []string `protobuf:"bytes,2,rep,name=prefixes" json:"prefixes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteObjectsRequest) Reset()         { *m = DeleteObjectsRequest{} }
+func (m *DeleteObjectsRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteObjectsRequest) ProtoMessage()    {}
+func (*DeleteObjectsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{15}
+}
+func (m *DeleteObjectsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteObjectsRequest.Unmarshal(m, b)
+}
+func (m *DeleteObjectsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteObjectsRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteObjectsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteObjectsRequest.Merge(dst, src)
+}
+func (m *DeleteObjectsRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteObjectsRequest.Size(m)
+}
+func (m *DeleteObjectsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteObjectsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteObjectsRequest proto.InternalMessageInfo
+
+func (m *DeleteObjectsRequest) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+func (m *DeleteObjectsRequest) GetPrefixes() []string {
+	if m != nil {
+		return m.Prefixes
+	}
+	return nil
+}
+
+// DeleteObjectsResponse is a response message for the DeleteObjects rpc call
+type DeleteObjectsResponse struct {
+	Deleted              int64    `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Locked               []string `protobuf:"bytes,2,rep,name=locked" json:"locked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteObjectsResponse) Reset()         { *m = DeleteObjectsResponse{} }
+func (m *DeleteObjectsResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteObjectsResponse) ProtoMessage()    {}
+func (*DeleteObjectsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{16}
+}
+func (m *DeleteObjectsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteObjectsResponse.Unmarshal(m, b)
+}
+func (m *DeleteObjectsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteObjectsResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeleteObjectsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteObjectsResponse.Merge(dst, src)
+}
+func (m *DeleteObjectsResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteObjectsResponse.Size(m)
+}
+func (m *DeleteObjectsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteObjectsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteObjectsResponse proto.InternalMessageInfo
+
+func (m *DeleteObjectsResponse) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+func (m *DeleteObjectsResponse) GetLocked() []string {
+	if m != nil {
+		return m.Locked
+	}
+	return nil
+}
+
 // IterateRequest is a request message for the Iterate rpc call
 type IterateRequest struct {
 	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
@@ -780,7 +1033,7 @@ func (m *IterateRequest) Reset()         { *m = IterateRequest{} }
 func (m *IterateRequest) String() string { return proto.CompactTextString(m) }
 func (*IterateRequest) ProtoMessage()    {}
 func (*IterateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{12}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{17}
 }
 func (m *IterateRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IterateRequest.Unmarshal(m, b)
@@ -839,7 +1092,7 @@ func (m *PayerBandwidthAllocationRequest) Reset()         { *m = PayerBandwidthA
 func (m *PayerBandwidthAllocationRequest) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocationRequest) ProtoMessage()    {}
 func (*PayerBandwidthAllocationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{13}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{18}
 }
 func (m *PayerBandwidthAllocationRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocationRequest.Unmarshal(m, b)
@@ -877,7 +1130,7 @@ func (m *PayerBandwidthAllocationResponse) Reset()         { *m = PayerBandwidth
 func (m *PayerBandwidthAllocationResponse) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocationResponse) ProtoMessage()    {}
 func (*PayerBandwidthAllocationResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_b7facd921d32685d, []int{14}
+	return fileDescriptor_pointerdb_15cb03ec69bbe4cd, []int{19}
 }
 func (m *PayerBandwidthAllocationResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocationResponse.Unmarshal(m, b)
@@ -907,6 +1160,7 @@ func (m *PayerBandwidthAllocationResponse) GetPba() *PayerBandwidthAllocation {
 func init() {
 	proto.RegisterType((*RedundancyScheme)(nil), "pointerdb.RedundancyScheme")
 	proto.RegisterType((*RemotePiece)(nil), "pointerdb.RemotePiece")
+	proto.RegisterType((*PieceReceipt)(nil), "pointerdb.PieceReceipt")
 	proto.RegisterType((*RemoteSegment)(nil), "pointerdb.RemoteSegment")
 	proto.RegisterType((*Pointer)(nil), "pointerdb.Pointer")
 	proto.RegisterType((*PutRequest)(nil), "pointerdb.PutRequest")
@@ -918,6 +1172,10 @@ func init() {
 	proto.RegisterType((*ListResponse_Item)(nil), "pointerdb.ListResponse.Item")
 	proto.RegisterType((*DeleteRequest)(nil), "pointerdb.DeleteRequest")
 	proto.RegisterType((*DeleteResponse)(nil), "pointerdb.DeleteResponse")
+	proto.RegisterType((*UpdateMetadataRequest)(nil), "pointerdb.UpdateMetadataRequest")
+	proto.RegisterType((*UpdateMetadataResponse)(nil), "pointerdb.UpdateMetadataResponse")
+	proto.RegisterType((*DeleteObjectsRequest)(nil), "pointerdb.DeleteObjectsRequest")
+	proto.RegisterType((*DeleteObjectsResponse)(nil), "pointerdb.DeleteObjectsResponse")
 	proto.RegisterType((*IterateRequest)(nil), "pointerdb.IterateRequest")
 	proto.RegisterType((*PayerBandwidthAllocationRequest)(nil), "pointerdb.PayerBandwidthAllocationRequest")
 	proto.RegisterType((*PayerBandwidthAllocationResponse)(nil), "pointerdb.PayerBandwidthAllocationResponse")
@@ -945,6 +1203,10 @@ type PointerDBClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	// Delete formats and hands off a file path to delete from boltdb
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// UpdateMetadata replaces the metadata of a pointer, leaving its segment unchanged
+	UpdateMetadata(ctx context.Context, in *UpdateMetadataRequest, opts ...grpc.CallOption) (*UpdateMetadataResponse, error)
+	// DeleteObjects deletes the streams of objects and of everything under prefixes
+	DeleteObjects(ctx context.Context, in *DeleteObjectsRequest, opts ...grpc.CallOption) (*DeleteObjectsResponse, error)
 	// PayerBandwidthAllocation returns signed payer bandwidth allocation struct
 	PayerBandwidthAllocation(ctx context.Context, in *PayerBandwidthAllocationRequest, opts ...grpc.CallOption) (*PayerBandwidthAllocationResponse, error)
 }
@@ -993,6 +1255,24 @@ func (c *pointerDBClient) Delete(ctx context.Context, in *DeleteRequest, opts ..
 	return out, nil
 }
 
+func (c *pointerDBClient) UpdateMetadata(ctx context.Context, in *UpdateMetadataRequest, opts ...grpc.CallOption) (*UpdateMetadataResponse, error) {
+	out := new(UpdateMetadataResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/UpdateMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) DeleteObjects(ctx context.Context, in *DeleteObjectsRequest, opts ...grpc.CallOption) (*DeleteObjectsResponse, error) {
+	out := new(DeleteObjectsResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/DeleteObjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *pointerDBClient) PayerBandwidthAllocation(ctx context.Context, in *PayerBandwidthAllocationRequest, opts ...grpc.CallOption) (*PayerBandwidthAllocationResponse, error) {
 	out := new(PayerBandwidthAllocationResponse)
 	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/PayerBandwidthAllocation", in, out, opts...)
@@ -1012,6 +1292,10 @@ type PointerDBServer interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	// Delete formats and hands off a file path to delete from boltdb
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// UpdateMetadata replaces the metadata of a pointer, leaving its segment unchanged
+	UpdateMetadata(context.Context, *UpdateMetadataRequest) (*UpdateMetadataResponse, error)
+	// DeleteObjects deletes the streams of objects and of everything under prefixes
+	DeleteObjects(context.Context, *DeleteObjectsRequest) (*DeleteObjectsResponse, error)
 	// PayerBandwidthAllocation returns signed payer bandwidth allocation struct
 	PayerBandwidthAllocation(context.Context, *PayerBandwidthAllocationRequest) (*PayerBandwidthAllocationResponse, error)
 }
@@ -1092,6 +1376,42 @@ func _PointerDB_Delete_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PointerDB_UpdateMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).UpdateMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/UpdateMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).UpdateMetadata(ctx, req.(*UpdateMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_DeleteObjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteObjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).DeleteObjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/DeleteObjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).DeleteObjects(ctx, req.(*DeleteObjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PointerDB_PayerBandwidthAllocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PayerBandwidthAllocationRequest)
 	if err := dec(in); err != nil {
@@ -1130,6 +1450,14 @@ var _PointerDB_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _PointerDB_Delete_Handler,
 		},
+		{
+			MethodName: "UpdateMetadata",
+			Handler:    _PointerDB_UpdateMetadata_Handler,
+		},
+		{
+			MethodName: "DeleteObjects",
+			Handler:    _PointerDB_DeleteObjects_Handler,
+		},
 		{
 			MethodName: "PayerBandwidthAllocation",
 			Handler:    _PointerDB_PayerBandwidthAllocation_Handler,
@@ -1139,77 +1467,89 @@ var _PointerDB_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pointerdb.proto",
 }
 
-func init() { proto.RegisterFile("pointerdb.proto", fileDescriptor_pointerdb_b7facd921d32685d) }
-
-var fileDescriptor_pointerdb_b7facd921d32685d = []byte{
-	// 1092 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x5d, 0x6f, 0x1b, 0x45,
-	0x17, 0xae, 0xbf, 0xe3, 0xb3, 0x76, 0xea, 0x77, 0xd4, 0x37, 0xdd, 0xba, 0x45, 0x09, 0x8b, 0x80,
-	0xd2, 0x56, 0x5b, 0x30, 0x95, 0x90, 0x28, 0x08, 0x35, 0x24, 0x54, 0x96, 0xda, 0x10, 0x8d, 0x73,
-	0xc5, 0xcd, 0x32, 0xf1, 0x1e, 0xdb, 0x23, 0xbc, 0x1f, 0x9d, 0x99, 0x2d, 0x4d, 0xff, 0x09, 0xff,
-	0x84, 0x1b, 0x2e, 0x91, 0xf8, 0x0d, 0x5c, 0xf4, 0x02, 0xf1, 0x33, 0xb8, 0x40, 0xf3, 0xb1, 0xf6,
-	0xba, 0x21, 0x69, 0x05, 0x37, 0xc9, 0x9e, 0x73, 0x9e, 0x73, 0xe6, 0x9c, 0xf3, 0x3c, 0x33, 0x86,
-	0xab, 0x79, 0xc6, 0x53, 0x85, 0x22, 0x3e, 0x0d, 0x73, 0x91, 0xa9, 0x8c, 0x74, 0x57, 0x8e, 0xe1,
-	0xee, 0x3c, 0xcb, 0xe6, 0x4b, 0xbc, 0x6f, 0x02, 0xa7, 0xc5, 0xec, 0xbe, 0xe2, 0x09, 0x4a, 0xc5,
-	0x92, 0xdc, 0x62, 0x87, 0x30, 0xcf, 0xe6, 0x59, 0xf9, 0x9d, 0x66, 0x31, 0xba, 0xef, 0x41, 0xce,
-	0x71, 0x8a, 0x52, 0x65, 0xc2, 0x79, 0x82, 0x9f, 0xea, 0x30, 0xa0, 0x18, 0x17, 0x69, 0xcc, 0xd2,
-	0xe9, 0xd9, 0x64, 0xba, 0xc0, 0x04, 0xc9, 0xe7, 0xd0, 0x54, 0x67, 0x39, 0xfa, 0xb5, 0xbd, 0xda,
-	0xed, 0xed, 0xd1, 0x07, 0xe1, 0xba, 0x95, 0xd7, 0xa1, 0xa1, 0xfd, 0x77, 0x72, 0x96, 0x23, 0x35,
-	0x39, 0xe4, 0x3a, 0x74, 0x12, 0x9e, 0x46, 0x02, 0x9f, 0xf9, 0xf5, 0xbd, 0xda, 0xed, 0x16, 0x6d,
-	0x27, 0x3c, 0xa5, 0xf8, 0x8c, 0x5c, 0x83, 0x96, 0xca, 0x14, 0x5b, 0xfa, 0x0d, 0xe3, 0xb6, 0x06,
-	0xf9, 0x08, 0x06, 0x02, 0x73, 0xc6, 0x45, 0xa4, 0x16, 0x02, 0xe5, 0x22, 0x5b, 0xc6, 0x7e, 0xd3,
-	0x00, 0xae, 0x5a, 0xff, 0x49, 0xe9, 0x26, 0x77, 0xe1, 0x7f, 0xb2, 0x98, 0x4e, 0x51, 0xca, 0x0a,
-	0xb6, 0x65, 0xb0, 0x03, 0x17, 0x58, 0x83, 0xef, 0x01, 0x41, 0xc1, 0x64, 0x21, 0x30, 0x92, 0x0b,
-	0xa6, 0xff, 0xf2, 0x97, 0xe8, 0xb7, 0x2d, 0xda, 0x45, 0x26, 0x3a, 0x30, 0xe1, 0x2f, 0x31, 0xb8,
-	0x06, 0xb0, 0x1e, 0x84, 0xb4, 0xa1, 0x4e, 0x27, 0x83, 0x2b, 0xc1, 0x04, 0x3c, 0x8a, 0x49, 0xa6,
-	0xf0, 0x58, 0x6f, 0x8d, 0xdc, 0x84, 0xae, 0x59, 0x5f, 0x94, 0x16, 0x89, 0x59, 0x4d, 0x8b, 0x6e,
-	0x19, 0xc7, 0x51, 0x91, 0x90, 0x0f, 0xa1, 0xa3, 0xf7, 0x1c, 0xf1, 0xd8, 0x8c, 0xdd, 0xdb, 0xdf,
-	0xfe, 0xed, 0xd5, 0xee, 0x95, 0xdf, 0x5f, 0xed, 0xb6, 0x8f, 0xb2, 0x18, 0xc7, 0x07, 0xb4, 0xad,
-	0xc3, 0xe3, 0x38, 0xf8, 0xb5, 0x06, 0x7d, 0x5b, 0x75, 0x82, 0xf3, 0x04, 0x53, 0x45, 0x1e, 0x02,
-	0x88, 0xd5, 0x5a, 0x4d, 0x61, 0x6f, 0x74, 0xf3, 0x92, 0x9d, 0xd3, 0x0a, 0x9c, 0xdc, 0x00, 0xdb,
-	0x43, 0x79, 0x70, 0x97, 0x76, 0x8c, 0x3d, 0x8e, 0xc9, 0x43, 0xe8, 0x0b, 0x73, 0x50, 0x64, 0x59,
-	0xf7, 0x1b, 0x7b, 0x8d, 0xdb, 0xde, 0x68, 0x67, 0xa3, 0xf4, 0x6a, 0x3c, 0xda, 0x13, 0x6b, 0x43,
-	0x92, 0x5d, 0xf0, 0x12, 0x14, 0x3f, 0x2c, 0x31, 0x12, 0x59, 0xa6, 0x0c, 0x25, 0x3d, 0x0a, 0xd6,
-	0x45, 0xb3, 0x4c, 0x05, 0x7f, 0xd5, 0xa1, 0x73, 0x6c, 0x0b, 0x91, 0xfb, 0x1b, 0x7a, 0xa9, 0xf6,
-	0xee, 0x10, 0xe1, 0x01, 0x53, 0xac, 0x22, 0x92, 0xf7, 0x61, 0x9b, 0xa7, 0x4b, 0x9e, 0x62, 0x24,
-	0xed, 0x12, 0x8c, 0x28, 0x7a, 0xb4, 0x6f, 0xbd, 0xe5, 0x66, 0x3e, 0x86, 0xb6, 0x6d, 0xca, 0x9c,
-	0xef, 0x8d, 0xfc, 0x73, 0xad, 0x3b, 0x24, 0x75, 0x38, 0xf2, 0x2e, 0xf4, 0x5c, 0x45, 0x4b, 0xb8,
-	0x96, 0x47, 0x83, 0x7a, 0xce, 0xa7, 0xb9, 0x26, 0x5f, 0x41, 0x7f, 0x2a, 0x90, 0x29, 0x9e, 0xa5,
-	0x51, 0xcc, 0x94, 0x15, 0x85, 0x37, 0x1a, 0x86, 0xf6, 0x52, 0x85, 0xe5, 0xa5, 0x0a, 0x4f, 0xca,
-	0x4b, 0x45, 0x7b, 0x65, 0xc2, 0x01, 0x53, 0x48, 0xbe, 0x86, 0xab, 0xf8, 0x22, 0xe7, 0xa2, 0x52,
-	0xa2, 0xf3, 0xc6, 0x12, 0xdb, 0xeb, 0x14, 0x53, 0x64, 0x08, 0x5b, 0x09, 0x2a, 0x16, 0x33, 0xc5,
-	0xfc, 0x2d, 0x33, 0xfb, 0xca, 0x0e, 0x02, 0xd8, 0x2a, 0xf7, 0x45, 0x00, 0xda, 0xe3, 0xa3, 0x27,
-	0xe3, 0xa3, 0xc3, 0xc1, 0x15, 0xfd, 0x4d, 0x0f, 0x9f, 0x7e, 0x7b, 0x72, 0x38, 0xa8, 0x05, 0x47,
-	0x00, 0xc7, 0x85, 0xa2, 0xf8, 0xac, 0x40, 0xa9, 0x08, 0x81, 0x66, 0xce, 0xd4, 0xc2, 0x10, 0xd0,
-	0xa5, 0xe6, 0x9b, 0xdc, 0x83, 0x8e, 0xdb, 0x96, 0x11, 0x86, 0x37, 0x22, 0xe7, 0x79, 0xa1, 0x25,
-	0x24, 0xd8, 0x03, 0x78, 0x8c, 0x97, 0xd5, 0x0b, 0x7e, 0xae, 0x81, 0xf7, 0x84, 0xcb, 0x15, 0x66,
-	0x07, 0xda, 0xb9, 0xc0, 0x19, 0x7f, 0xe1, 0x50, 0xce, 0xd2, 0xca, 0x91, 0x8a, 0x09, 0x15, 0xb1,
-	0x59, 0x79, 0x76, 0x97, 0x82, 0x71, 0x3d, 0xd2, 0x1e, 0xf2, 0x0e, 0x00, 0xa6, 0x71, 0x74, 0x8a,
-	0xb3, 0x4c, 0xa0, 0x21, 0xbe, 0x4b, 0xbb, 0x98, 0xc6, 0xfb, 0xc6, 0x41, 0x6e, 0x41, 0x57, 0xe0,
-	0xb4, 0x10, 0x92, 0x3f, 0xb7, 0xbc, 0x6f, 0xd1, 0xb5, 0x43, 0xbf, 0x22, 0x4b, 0x9e, 0x70, 0xe5,
-	0x2e, 0xbe, 0x35, 0x74, 0x49, 0xbd, 0xbd, 0x68, 0xb6, 0x64, 0x73, 0x69, 0x08, 0xed, 0xd0, 0xae,
-	0xf6, 0x7c, 0xa3, 0x1d, 0x41, 0x1f, 0x3c, 0xb3, 0x2c, 0x99, 0x67, 0xa9, 0xc4, 0xe0, 0x8f, 0x1a,
-	0x78, 0x66, 0x58, 0x6b, 0x57, 0x37, 0x55, 0x7b, 0xe3, 0xa6, 0xc8, 0x1e, 0xb4, 0xf4, 0x55, 0x96,
-	0x7e, 0xdd, 0x5c, 0x27, 0x08, 0xcd, 0xfb, 0xaa, 0x6f, 0x39, 0xb5, 0x01, 0xf2, 0x05, 0x34, 0xf2,
-	0x53, 0x66, 0x26, 0xf3, 0x46, 0x77, 0xc2, 0xf5, 0x9b, 0x2b, 0xb2, 0x42, 0xa1, 0x0c, 0x8f, 0xd9,
-	0x19, 0x8a, 0x7d, 0x96, 0xc6, 0x3f, 0xf2, 0x58, 0x2d, 0x1e, 0x2d, 0x97, 0xd9, 0xd4, 0x08, 0x83,
-	0xea, 0x34, 0x72, 0x08, 0x7d, 0x56, 0xa8, 0x45, 0x26, 0xf8, 0x4b, 0xe3, 0x75, 0xda, 0xdf, 0x3d,
-	0x5f, 0x67, 0xc2, 0xe7, 0x29, 0xc6, 0x4f, 0x51, 0x4a, 0x36, 0x47, 0xba, 0x99, 0x15, 0xfc, 0x52,
-	0x83, 0x9e, 0xa5, 0xcb, 0x4d, 0x39, 0x82, 0x16, 0x57, 0x98, 0x48, 0xbf, 0x66, 0xfa, 0xbe, 0x55,
-	0x99, 0xb1, 0x8a, 0x0b, 0xc7, 0x0a, 0x13, 0x6a, 0xa1, 0x5a, 0x07, 0x89, 0x26, 0xa9, 0x6e, 0x68,
-	0x30, 0xdf, 0x43, 0x84, 0xa6, 0x86, 0xfc, 0x77, 0xcd, 0xe9, 0x07, 0x95, 0xcb, 0xc8, 0x89, 0xa8,
-	0x61, 0x8e, 0xd8, 0xe2, 0xf2, 0xd8, 0xd8, 0xc1, 0x7b, 0xd0, 0x3f, 0xc0, 0x25, 0x2a, 0xbc, 0x4c,
-	0x93, 0x03, 0xd8, 0x2e, 0x41, 0x8e, 0x5b, 0x01, 0xdb, 0x63, 0x85, 0x82, 0xad, 0xf3, 0x2e, 0xd2,
-	0xe9, 0x35, 0x68, 0xcd, 0xb8, 0x90, 0xca, 0x29, 0xd4, 0x1a, 0xc4, 0x87, 0x8e, 0x15, 0x1b, 0xba,
-	0x8e, 0x4a, 0xd3, 0x46, 0x9e, 0xa3, 0x8e, 0x34, 0xcb, 0x88, 0x31, 0x83, 0x25, 0xec, 0x5e, 0x48,
-	0xa9, 0x6b, 0x62, 0x0c, 0x6d, 0x36, 0x35, 0x6c, 0xda, 0x37, 0xf2, 0x93, 0xb7, 0x57, 0x45, 0xf8,
-	0xc8, 0x24, 0x52, 0x57, 0x20, 0xf8, 0x1e, 0xf6, 0x2e, 0x3e, 0xcd, 0x71, 0xed, 0x14, 0x58, 0xfb,
-	0x57, 0x0a, 0x1c, 0xfd, 0x59, 0x87, 0xae, 0x23, 0xeb, 0x60, 0x9f, 0x3c, 0x80, 0xc6, 0x71, 0xa1,
-	0xc8, 0xff, 0xab, 0x4c, 0xae, 0x5e, 0x9e, 0xe1, 0xce, 0xeb, 0x6e, 0xd7, 0xc1, 0x03, 0x68, 0x3c,
-	0xc6, 0xcd, 0xac, 0xf5, 0xfb, 0xb2, 0x91, 0x55, 0xbd, 0x89, 0x9f, 0x41, 0x53, 0x6b, 0x91, 0xec,
-	0x9c, 0x13, 0xa7, 0xcd, 0xbb, 0x7e, 0x81, 0x68, 0xc9, 0x97, 0xd0, 0xb6, 0x42, 0x20, 0xd5, 0xdf,
-	0x88, 0x0d, 0x01, 0x0d, 0x6f, 0xfc, 0x43, 0xc4, 0xa5, 0x4b, 0xf0, 0x2f, 0x5a, 0x09, 0xb9, 0x53,
-	0x9d, 0xf0, 0x72, 0x9a, 0x87, 0x77, 0xdf, 0x0a, 0x6b, 0x0f, 0xdd, 0x6f, 0x7e, 0x57, 0xcf, 0x4f,
-	0x4f, 0xdb, 0xe6, 0xc7, 0xe2, 0xd3, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xfa, 0x56, 0x48, 0xf9,
-	0xf0, 0x09, 0x00, 0x00,
+func init() { proto.RegisterFile("pointerdb.proto", fileDescriptor_pointerdb_15cb03ec69bbe4cd) }
+
+var fileDescriptor_pointerdb_15cb03ec69bbe4cd = []byte{
+	// 1288 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x4d, 0x73, 0x13, 0x47,
+	0x13, 0x46, 0xdf, 0x56, 0x4b, 0x32, 0x7a, 0xa7, 0x8c, 0x11, 0x82, 0xb7, 0x24, 0xf6, 0xad, 0x37,
+	0x21, 0x40, 0x89, 0xa0, 0x50, 0x95, 0xaa, 0x90, 0x54, 0x0a, 0xc7, 0xc6, 0x51, 0x05, 0x8c, 0x6b,
+	0x6c, 0x0e, 0xc9, 0x65, 0x33, 0xd2, 0xb6, 0xa5, 0x09, 0xda, 0x0f, 0x66, 0x66, 0x09, 0xe6, 0x4f,
+	0xe4, 0x9c, 0x7b, 0x7e, 0x44, 0x2e, 0x39, 0xa6, 0x2a, 0xbf, 0x21, 0x07, 0x0e, 0xf9, 0x1d, 0x39,
+	0xa4, 0xe6, 0x63, 0xa5, 0x95, 0x8d, 0x0d, 0x45, 0x2e, 0xf6, 0x74, 0xf7, 0x33, 0x3d, 0xbd, 0xdd,
+	0xcf, 0xd3, 0x82, 0x8b, 0x49, 0xcc, 0x23, 0x85, 0x22, 0x18, 0x0f, 0x12, 0x11, 0xab, 0x98, 0xd4,
+	0x17, 0x8e, 0x6e, 0x6f, 0x1a, 0xc7, 0xd3, 0x39, 0xde, 0x31, 0x81, 0x71, 0x7a, 0x74, 0x47, 0xf1,
+	0x10, 0xa5, 0x62, 0x61, 0x62, 0xb1, 0x5d, 0x98, 0xc6, 0xd3, 0x38, 0x3b, 0x47, 0x71, 0x80, 0xee,
+	0xdc, 0x4e, 0x38, 0x4e, 0x50, 0xaa, 0x58, 0x38, 0x8f, 0xf7, 0x73, 0x11, 0xda, 0x14, 0x83, 0x34,
+	0x0a, 0x58, 0x34, 0x39, 0x3e, 0x98, 0xcc, 0x30, 0x44, 0xf2, 0x19, 0x94, 0xd5, 0x71, 0x82, 0x9d,
+	0x42, 0xbf, 0x70, 0x63, 0x7d, 0xf8, 0xc1, 0x60, 0x59, 0xca, 0x49, 0xe8, 0xc0, 0xfe, 0x3b, 0x3c,
+	0x4e, 0x90, 0x9a, 0x3b, 0xe4, 0x32, 0xd4, 0x42, 0x1e, 0xf9, 0x02, 0x9f, 0x77, 0x8a, 0xfd, 0xc2,
+	0x8d, 0x0a, 0xad, 0x86, 0x3c, 0xa2, 0xf8, 0x9c, 0x6c, 0x40, 0x45, 0xc5, 0x8a, 0xcd, 0x3b, 0x25,
+	0xe3, 0xb6, 0x06, 0xf9, 0x08, 0xda, 0x02, 0x13, 0xc6, 0x85, 0xaf, 0x66, 0x02, 0xe5, 0x2c, 0x9e,
+	0x07, 0x9d, 0xb2, 0x01, 0x5c, 0xb4, 0xfe, 0xc3, 0xcc, 0x4d, 0x6e, 0xc1, 0x7f, 0x64, 0x3a, 0x99,
+	0xa0, 0x94, 0x39, 0x6c, 0xc5, 0x60, 0xdb, 0x2e, 0xb0, 0x04, 0xdf, 0x06, 0x82, 0x82, 0xc9, 0x54,
+	0xa0, 0x2f, 0x67, 0x4c, 0xff, 0xe5, 0xaf, 0xb0, 0x53, 0xb5, 0x68, 0x17, 0x39, 0xd0, 0x81, 0x03,
+	0xfe, 0x0a, 0xbd, 0x0d, 0x80, 0xe5, 0x87, 0x90, 0x2a, 0x14, 0xe9, 0x41, 0xfb, 0x82, 0xf7, 0x4b,
+	0x01, 0x1a, 0x14, 0xc3, 0x58, 0xe1, 0xbe, 0x6e, 0x1b, 0xb9, 0x0a, 0x75, 0xd3, 0x3f, 0x3f, 0x4a,
+	0x43, 0xd3, 0x9b, 0x0a, 0x5d, 0x33, 0x8e, 0xbd, 0x34, 0x24, 0x1f, 0x42, 0x4d, 0x37, 0xda, 0xe7,
+	0x81, 0xf9, 0xee, 0xe6, 0xd6, 0xfa, 0x1f, 0xaf, 0x7b, 0x17, 0xfe, 0x7c, 0xdd, 0xab, 0xee, 0xc5,
+	0x01, 0x8e, 0xb6, 0x69, 0x55, 0x87, 0x47, 0x01, 0xb9, 0x0b, 0x35, 0x81, 0x13, 0xe4, 0x89, 0x32,
+	0x9d, 0x68, 0x0c, 0x2f, 0xe7, 0xfa, 0x6b, 0x1e, 0xa2, 0x36, 0x4c, 0x33, 0x1c, 0xb9, 0x06, 0xf5,
+	0x49, 0x2c, 0x44, 0x9a, 0x28, 0xb4, 0xdd, 0x59, 0xa3, 0x4b, 0x87, 0xf7, 0x2d, 0x34, 0xf3, 0xd7,
+	0x08, 0x81, 0xf2, 0x8c, 0xc9, 0x99, 0xa9, 0xb0, 0x49, 0xcd, 0x59, 0x4f, 0x25, 0x49, 0xc7, 0xfe,
+	0x33, 0x3c, 0xb6, 0xd5, 0xd1, 0x6a, 0x92, 0x8e, 0xbf, 0xc1, 0x63, 0x9d, 0x5a, 0xf2, 0x69, 0xc4,
+	0x54, 0x2a, 0xd0, 0xd4, 0xd3, 0xa4, 0x4b, 0x87, 0xf7, 0x7b, 0x01, 0x5a, 0xb6, 0x03, 0x07, 0x38,
+	0x0d, 0x31, 0x52, 0xe4, 0x3e, 0x80, 0x58, 0x70, 0xc0, 0x3c, 0xd1, 0x18, 0x5e, 0x3d, 0x87, 0x20,
+	0x34, 0x07, 0x27, 0x57, 0xc0, 0xf6, 0x2b, 0x6b, 0x52, 0x9d, 0xd6, 0x8c, 0x3d, 0x0a, 0xc8, 0x7d,
+	0x68, 0x09, 0xf3, 0x90, 0x6f, 0x3c, 0xb2, 0x53, 0xea, 0x97, 0x6e, 0x34, 0x86, 0x9b, 0x2b, 0xa9,
+	0x17, 0xa3, 0xa0, 0x4d, 0xb1, 0x34, 0x24, 0xe9, 0x41, 0x23, 0x44, 0xf1, 0x6c, 0x8e, 0xbe, 0x88,
+	0x63, 0x65, 0x3a, 0xd4, 0xa4, 0x60, 0x5d, 0x34, 0x8e, 0x95, 0xf7, 0x77, 0x11, 0x6a, 0xfb, 0x36,
+	0x11, 0xb9, 0xb3, 0x42, 0xee, 0x7c, 0xed, 0x0e, 0x31, 0xd8, 0x66, 0x8a, 0xe5, 0x18, 0xfd, 0x7f,
+	0x58, 0xe7, 0xd1, 0x9c, 0x47, 0xe8, 0x4b, 0xdb, 0x04, 0xd7, 0xa7, 0x96, 0xf5, 0x66, 0x9d, 0xf9,
+	0x18, 0xaa, 0xb6, 0x28, 0xf3, 0x7e, 0x63, 0xd8, 0x39, 0x55, 0xba, 0x43, 0x52, 0x87, 0x23, 0xd7,
+	0xa1, 0xe9, 0x32, 0x5a, 0x76, 0x6a, 0x2e, 0x97, 0x68, 0xc3, 0xf9, 0x34, 0x31, 0xc9, 0x97, 0xd0,
+	0x9a, 0x08, 0x64, 0x8a, 0xc7, 0x91, 0x1f, 0x30, 0x65, 0x19, 0xdc, 0x18, 0x76, 0x07, 0x76, 0x03,
+	0x0c, 0xb2, 0x0d, 0x30, 0x38, 0xcc, 0x36, 0x00, 0x6d, 0x66, 0x17, 0xb6, 0x99, 0x42, 0xf2, 0x15,
+	0x5c, 0xc4, 0x97, 0x09, 0x17, 0xb9, 0x14, 0xb5, 0xb7, 0xa6, 0x58, 0x5f, 0x5e, 0x31, 0x49, 0xba,
+	0xb0, 0x16, 0xa2, 0x62, 0x01, 0x53, 0xac, 0xb3, 0x66, 0xbe, 0x7d, 0x61, 0x7b, 0x1e, 0xac, 0x65,
+	0xfd, 0x22, 0x00, 0xd5, 0xd1, 0xde, 0xa3, 0xd1, 0xde, 0x4e, 0xfb, 0x82, 0x3e, 0xd3, 0x9d, 0xc7,
+	0x4f, 0x0e, 0x77, 0xda, 0x05, 0x6f, 0x0f, 0x60, 0x3f, 0x55, 0x14, 0x9f, 0xa7, 0x28, 0x0d, 0x3f,
+	0x13, 0xa6, 0x2c, 0x3f, 0xeb, 0xd4, 0x9c, 0xc9, 0x6d, 0xa8, 0xb9, 0x6e, 0x19, 0x62, 0x34, 0x86,
+	0xe4, 0xf4, 0x5c, 0x68, 0x06, 0xf1, 0xfa, 0x00, 0xbb, 0x78, 0x5e, 0x3e, 0xef, 0xd7, 0x02, 0x34,
+	0x1e, 0x71, 0xb9, 0xc0, 0x6c, 0x42, 0x35, 0x11, 0x78, 0xc4, 0x5f, 0x3a, 0x94, 0xb3, 0x34, 0x73,
+	0xa4, 0x62, 0x42, 0xf9, 0xec, 0x28, 0x7b, 0xbb, 0x4e, 0xc1, 0xb8, 0x1e, 0x68, 0x0f, 0xf9, 0x2f,
+	0x00, 0x46, 0x81, 0x3f, 0xc6, 0xa3, 0xd8, 0x09, 0xa4, 0x4e, 0xeb, 0x18, 0x05, 0x5b, 0xc6, 0xa1,
+	0xe5, 0x23, 0x70, 0x92, 0x0a, 0xc9, 0x5f, 0x60, 0xa6, 0xcc, 0x85, 0x43, 0xaf, 0xbc, 0x39, 0x0f,
+	0xb9, 0x72, 0x5b, 0xca, 0x1a, 0x3a, 0xa5, 0xee, 0x9e, 0x7f, 0x34, 0x67, 0x53, 0x69, 0x06, 0x5a,
+	0xa3, 0x75, 0xed, 0x79, 0xa8, 0x1d, 0x5e, 0x0b, 0x1a, 0xa6, 0x59, 0x32, 0x89, 0x23, 0x89, 0xde,
+	0x5f, 0x05, 0x68, 0xec, 0xe2, 0xc2, 0xce, 0x77, 0xaa, 0xf0, 0xd6, 0x4e, 0x91, 0x3e, 0x54, 0xf4,
+	0xda, 0x91, 0x9d, 0xa2, 0x91, 0x13, 0x0c, 0xb4, 0x35, 0xd0, 0x1b, 0x89, 0xda, 0x00, 0xf9, 0x1c,
+	0x4a, 0xc9, 0x98, 0xb9, 0x55, 0x74, 0x73, 0xb0, 0xfc, 0x81, 0x10, 0x71, 0xaa, 0x50, 0x0e, 0xf6,
+	0xd9, 0x31, 0x8a, 0x2d, 0x16, 0x05, 0x3f, 0xf2, 0x40, 0xcd, 0x1e, 0xcc, 0xe7, 0xf1, 0xc4, 0x10,
+	0x83, 0xea, 0x6b, 0x64, 0x07, 0x5a, 0x2c, 0x55, 0xb3, 0x58, 0xf0, 0x57, 0xc6, 0xeb, 0xb8, 0xdf,
+	0x3b, 0x9d, 0xe7, 0x80, 0x4f, 0x23, 0x0c, 0x1e, 0xa3, 0x94, 0x6c, 0x8a, 0x74, 0xf5, 0x96, 0xf7,
+	0x5b, 0x01, 0x9a, 0x76, 0x5c, 0xee, 0x2b, 0x87, 0x50, 0xe1, 0x0a, 0x43, 0xd9, 0x29, 0x98, 0xba,
+	0xaf, 0xe5, 0xbe, 0x31, 0x8f, 0x1b, 0x8c, 0x14, 0x86, 0xd4, 0x42, 0x35, 0x0f, 0x42, 0x3d, 0xa4,
+	0xa2, 0x19, 0x83, 0x39, 0x77, 0x11, 0xca, 0x1a, 0xf2, 0xef, 0x39, 0xa7, 0x97, 0x3f, 0x97, 0xbe,
+	0x23, 0x51, 0xc9, 0x3c, 0xb1, 0xc6, 0xe5, 0xbe, 0xb1, 0xbd, 0xff, 0x41, 0x6b, 0x1b, 0xe7, 0xa8,
+	0xf0, 0x3c, 0x4e, 0xb6, 0x61, 0x3d, 0x03, 0xb9, 0xd9, 0xee, 0xc2, 0xa5, 0xa7, 0x89, 0xd6, 0xe4,
+	0x63, 0xa7, 0xa6, 0xf3, 0x24, 0x92, 0x17, 0x61, 0xf1, 0x84, 0x08, 0x1f, 0xc2, 0xe6, 0xc9, 0x44,
+	0xef, 0x43, 0x17, 0xef, 0x6b, 0xd8, 0xb0, 0x25, 0x3e, 0x19, 0xff, 0x80, 0x13, 0x25, 0xb3, 0x7a,
+	0x36, 0xa0, 0xa2, 0x6b, 0xb0, 0xe3, 0xa8, 0x53, 0x6b, 0xe8, 0x8a, 0x6c, 0x3f, 0x1c, 0xbf, 0xea,
+	0x74, 0x61, 0x7b, 0x23, 0xb8, 0x74, 0x22, 0x93, 0x2b, 0xa8, 0x03, 0xb5, 0xc0, 0x04, 0x02, 0x53,
+	0x50, 0x89, 0x66, 0xa6, 0xd6, 0xe8, 0x3c, 0x9e, 0x3c, 0xc3, 0xc0, 0x25, 0x73, 0x96, 0x27, 0x60,
+	0x7d, 0xa4, 0x50, 0x30, 0x85, 0x6f, 0x53, 0xf3, 0x06, 0x54, 0x8e, 0xb8, 0x90, 0xca, 0xe9, 0xd8,
+	0x1a, 0xfa, 0x45, 0x2b, 0x49, 0x74, 0x73, 0xcb, 0x4c, 0x1b, 0x79, 0x81, 0x3a, 0x52, 0xce, 0x22,
+	0xc6, 0xf4, 0xe6, 0xd0, 0x3b, 0x93, 0xf8, 0xae, 0x88, 0x11, 0x54, 0xd9, 0xc4, 0x70, 0xde, 0xfe,
+	0x92, 0xdc, 0x7d, 0x77, 0xed, 0x0c, 0x1e, 0x98, 0x8b, 0xd4, 0x25, 0xf0, 0xbe, 0x87, 0xfe, 0xd9,
+	0xaf, 0xb9, 0xbe, 0x39, 0x9d, 0x16, 0xde, 0x4b, 0xa7, 0xc3, 0x9f, 0xca, 0x50, 0x77, 0xd3, 0xde,
+	0xde, 0x22, 0xf7, 0xa0, 0xb4, 0x9f, 0x2a, 0x72, 0x29, 0x4f, 0x85, 0xc5, 0x7e, 0xee, 0x6e, 0x9e,
+	0x74, 0xbb, 0x0a, 0xee, 0x41, 0x69, 0x17, 0x57, 0x6f, 0xed, 0xe2, 0x1b, 0x6f, 0xe5, 0xf7, 0xd5,
+	0xa7, 0x50, 0xd6, 0x8a, 0x25, 0x9b, 0xa7, 0x24, 0x6c, 0xef, 0x5d, 0x3e, 0x43, 0xda, 0xe4, 0x0b,
+	0xa8, 0x5a, 0x06, 0x91, 0xfc, 0x2f, 0xe9, 0x8a, 0xcc, 0xba, 0x57, 0xde, 0x10, 0x71, 0xd7, 0x9f,
+	0xc2, 0xfa, 0xaa, 0x24, 0x48, 0x3f, 0x07, 0x7e, 0xa3, 0xec, 0xba, 0xd7, 0xcf, 0x41, 0xb8, 0xb4,
+	0x14, 0x5a, 0x2b, 0xbc, 0x26, 0xbd, 0x53, 0x25, 0xac, 0x6a, 0xa7, 0xdb, 0x3f, 0x1b, 0xe0, 0x72,
+	0x4a, 0xe8, 0x9c, 0x35, 0x3d, 0x72, 0x33, 0x3f, 0x8c, 0xf3, 0x19, 0xd9, 0xbd, 0xf5, 0x4e, 0x58,
+	0xfb, 0xe8, 0x56, 0xf9, 0xbb, 0x62, 0x32, 0x1e, 0x57, 0xcd, 0xaf, 0xff, 0x27, 0xff, 0x0c, 0x00,
+	0x7e, 0x5b, 0xaf, 0x8d, 0x6e, 0x0c, 0x00, 0x00,
 }
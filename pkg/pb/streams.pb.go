@@ -6,6 +6,7 @@ package pb
 import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -30,7 +31,7 @@ func (m *SegmentMeta) Reset()         { *m = SegmentMeta{} }
 func (m *SegmentMeta) String() string { return proto.CompactTextString(m) }
 func (*SegmentMeta) ProtoMessage()    {}
 func (*SegmentMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_streams_2b972859339b7bc3, []int{0}
+	return fileDescriptor_streams_efd7ab55754697a6, []int{0}
 }
 func (m *SegmentMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SegmentMeta.Unmarshal(m, b)
@@ -78,7 +79,7 @@ func (m *StreamInfo) Reset()         { *m = StreamInfo{} }
 func (m *StreamInfo) String() string { return proto.CompactTextString(m) }
 func (*StreamInfo) ProtoMessage()    {}
 func (*StreamInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_streams_2b972859339b7bc3, []int{1}
+	return fileDescriptor_streams_efd7ab55754697a6, []int{1}
 }
 func (m *StreamInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamInfo.Unmarshal(m, b)
@@ -127,20 +128,27 @@ func (m *StreamInfo) GetMetadata() []byte {
 }
 
 type StreamMeta struct {
-	EncryptedStreamInfo  []byte       `protobuf:"bytes,1,opt,name=encrypted_stream_info,json=encryptedStreamInfo,proto3" json:"encrypted_stream_info,omitempty"`
-	EncryptionType       int32        `protobuf:"varint,2,opt,name=encryption_type,json=encryptionType,proto3" json:"encryption_type,omitempty"`
-	EncryptionBlockSize  int32        `protobuf:"varint,3,opt,name=encryption_block_size,json=encryptionBlockSize,proto3" json:"encryption_block_size,omitempty"`
-	LastSegmentMeta      *SegmentMeta `protobuf:"bytes,4,opt,name=last_segment_meta,json=lastSegmentMeta" json:"last_segment_meta,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	EncryptedStreamInfo []byte       `protobuf:"bytes,1,opt,name=encrypted_stream_info,json=encryptedStreamInfo,proto3" json:"encrypted_stream_info,omitempty"`
+	EncryptionType      int32        `protobuf:"varint,2,opt,name=encryption_type,json=encryptionType,proto3" json:"encryption_type,omitempty"`
+	EncryptionBlockSize int32        `protobuf:"varint,3,opt,name=encryption_block_size,json=encryptionBlockSize,proto3" json:"encryption_block_size,omitempty"`
+	LastSegmentMeta     *SegmentMeta `protobuf:"bytes,4,opt,name=last_segment_meta,json=lastSegmentMeta" json:"last_segment_meta,omitempty"`
+	// nonce of the stream info if it was re-encrypted, the zero nonce otherwise
+	StreamInfoNonce []byte `protobuf:"bytes,5,opt,name=stream_info_nonce,json=streamInfoNonce,proto3" json:"stream_info_nonce,omitempty"`
+	// lock is kept unencrypted for pointerdb to enforce it
+	Lock *ObjectLock `protobuf:"bytes,6,opt,name=lock" json:"lock,omitempty"`
+	// number_of_segments is kept unencrypted for pointerdb to delete the
+	// segments of the stream
+	NumberOfSegments     int64    `protobuf:"varint,7,opt,name=number_of_segments,json=numberOfSegments,proto3" json:"number_of_segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StreamMeta) Reset()         { *m = StreamMeta{} }
 func (m *StreamMeta) String() string { return proto.CompactTextString(m) }
 func (*StreamMeta) ProtoMessage()    {}
 func (*StreamMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_streams_2b972859339b7bc3, []int{2}
+	return fileDescriptor_streams_efd7ab55754697a6, []int{2}
 }
 func (m *StreamMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StreamMeta.Unmarshal(m, b)
@@ -188,33 +196,110 @@ func (m *StreamMeta) GetLastSegmentMeta() *SegmentMeta {
 	return nil
 }
 
+func (m *StreamMeta) GetStreamInfoNonce() []byte {
+	if m != nil {
+		return m.StreamInfoNonce
+	}
+	return nil
+}
+
+func (m *StreamMeta) GetLock() *ObjectLock {
+	if m != nil {
+		return m.Lock
+	}
+	return nil
+}
+
+func (m *StreamMeta) GetNumberOfSegments() int64 {
+	if m != nil {
+		return m.NumberOfSegments
+	}
+	return 0
+}
+
+// ObjectLock prevents a stream from being overwritten or deleted
+type ObjectLock struct {
+	RetainUntil          *timestamp.Timestamp `protobuf:"bytes,1,opt,name=retain_until,json=retainUntil" json:"retain_until,omitempty"`
+	LegalHold            bool                 `protobuf:"varint,2,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ObjectLock) Reset()         { *m = ObjectLock{} }
+func (m *ObjectLock) String() string { return proto.CompactTextString(m) }
+func (*ObjectLock) ProtoMessage()    {}
+func (*ObjectLock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_streams_efd7ab55754697a6, []int{3}
+}
+func (m *ObjectLock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ObjectLock.Unmarshal(m, b)
+}
+func (m *ObjectLock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ObjectLock.Marshal(b, m, deterministic)
+}
+func (dst *ObjectLock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectLock.Merge(dst, src)
+}
+func (m *ObjectLock) XXX_Size() int {
+	return xxx_messageInfo_ObjectLock.Size(m)
+}
+func (m *ObjectLock) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectLock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectLock proto.InternalMessageInfo
+
+func (m *ObjectLock) GetRetainUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.RetainUntil
+	}
+	return nil
+}
+
+func (m *ObjectLock) GetLegalHold() bool {
+	if m != nil {
+		return m.LegalHold
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*SegmentMeta)(nil), "streams.SegmentMeta")
 	proto.RegisterType((*StreamInfo)(nil), "streams.StreamInfo")
 	proto.RegisterType((*StreamMeta)(nil), "streams.StreamMeta")
+	proto.RegisterType((*ObjectLock)(nil), "streams.ObjectLock")
 }
 
-func init() { proto.RegisterFile("streams.proto", fileDescriptor_streams_2b972859339b7bc3) }
-
-var fileDescriptor_streams_2b972859339b7bc3 = []byte{
-	// 304 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x51, 0xcb, 0x4e, 0xc3, 0x30,
-	0x10, 0x54, 0x5f, 0x50, 0xb6, 0x29, 0x05, 0x03, 0x52, 0x04, 0x17, 0x14, 0x0e, 0x20, 0x84, 0x7a,
-	0x28, 0x3f, 0x80, 0x7a, 0x43, 0x08, 0x2a, 0x25, 0x9c, 0xb8, 0x58, 0x4e, 0xba, 0x41, 0x51, 0x1a,
-	0x3b, 0x8a, 0xcd, 0xc1, 0xfd, 0x21, 0x3e, 0x8b, 0x5f, 0x41, 0xb6, 0xf3, 0x82, 0xe3, 0xce, 0x8c,
-	0x66, 0x67, 0x76, 0x61, 0x2e, 0x55, 0x85, 0xac, 0x90, 0xcb, 0xb2, 0x12, 0x4a, 0x90, 0xc3, 0x7a,
-	0x0c, 0x36, 0x30, 0x8b, 0xf0, 0xb3, 0x40, 0xae, 0x5e, 0x51, 0x31, 0x72, 0x03, 0x73, 0xe4, 0x49,
-	0xa5, 0x4b, 0x85, 0x5b, 0x9a, 0xa3, 0xf6, 0x07, 0xd7, 0x83, 0x3b, 0x2f, 0xf4, 0x5a, 0xf0, 0x05,
-	0x35, 0xb9, 0x82, 0xa3, 0x1c, 0x35, 0xe5, 0x82, 0x27, 0xe8, 0x0f, 0xad, 0x60, 0x9a, 0xa3, 0x7e,
-	0x33, 0x73, 0xf0, 0x3d, 0x00, 0x88, 0xac, 0xf9, 0x33, 0x4f, 0x05, 0x79, 0x00, 0xc2, 0xbf, 0x8a,
-	0x18, 0x2b, 0x2a, 0x52, 0x2a, 0xdd, 0x26, 0x69, 0x5d, 0x47, 0xe1, 0x89, 0x63, 0x36, 0x69, 0x9d,
-	0x40, 0x9a, 0xf5, 0x8d, 0x86, 0xca, 0x6c, 0xef, 0xdc, 0x47, 0xa1, 0xd7, 0x80, 0x51, 0xb6, 0x47,
-	0x72, 0x0f, 0xa7, 0x3b, 0x26, 0x55, 0xe3, 0xe6, 0x84, 0x23, 0x2b, 0x5c, 0x18, 0xa2, 0x76, 0xb3,
-	0xda, 0x4b, 0x98, 0x16, 0xa8, 0xd8, 0x96, 0x29, 0xe6, 0x8f, 0x5d, 0xd2, 0x66, 0x0e, 0x7e, 0xda,
-	0xa4, 0xb6, 0xfa, 0x0a, 0x2e, 0xba, 0xea, 0xee, 0x3c, 0x34, 0xe3, 0xa9, 0xa8, 0x4f, 0x70, 0xd6,
-	0x92, 0xbd, 0x76, 0xb7, 0xb0, 0xa8, 0xe1, 0x4c, 0x70, 0xaa, 0x74, 0xe9, 0x12, 0x4f, 0xc2, 0xe3,
-	0x0e, 0x7e, 0xd7, 0x25, 0xf6, 0xcc, 0x8d, 0x30, 0xde, 0x89, 0x24, 0xef, 0x72, 0x4f, 0x5a, 0xf3,
-	0x4c, 0xf0, 0xb5, 0xe1, 0x6c, 0xf6, 0xa7, 0x7f, 0x3d, 0x4d, 0x70, 0x5b, 0x62, 0xb6, 0x3a, 0x5f,
-	0x36, 0xef, 0xec, 0x3d, 0xef, 0x4f, 0x7b, 0x03, 0xac, 0xc7, 0x1f, 0xc3, 0x32, 0x8e, 0x0f, 0xec,
-	0xcb, 0x1f, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x5e, 0x32, 0x31, 0x03, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("streams.proto", fileDescriptor_streams_efd7ab55754697a6) }
+
+var fileDescriptor_streams_efd7ab55754697a6 = []byte{
+	// 428 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0xdd, 0x6e, 0xd3, 0x30,
+	0x14, 0x56, 0xff, 0xb6, 0xee, 0xb4, 0xa3, 0xcc, 0x03, 0xa9, 0x2a, 0x42, 0xa0, 0x72, 0x31, 0x34,
+	0xa1, 0x4c, 0x2a, 0xd7, 0x48, 0x68, 0x57, 0x20, 0x7e, 0x2a, 0xa5, 0xe3, 0x86, 0x1b, 0xcb, 0x49,
+	0x4f, 0x8a, 0x17, 0xc7, 0x8e, 0x62, 0xf7, 0x22, 0x7b, 0x21, 0xde, 0x8b, 0x27, 0x41, 0x39, 0x8e,
+	0xd3, 0x82, 0xe0, 0xd2, 0xe7, 0x7c, 0xfa, 0x7e, 0xce, 0x67, 0x38, 0xb7, 0xae, 0x42, 0x51, 0xd8,
+	0xa8, 0xac, 0x8c, 0x33, 0xec, 0xb4, 0x7d, 0x2e, 0x5e, 0xec, 0x8c, 0xd9, 0x29, 0xbc, 0xa1, 0x71,
+	0xb2, 0xcf, 0x6e, 0x9c, 0x2c, 0xd0, 0x3a, 0x51, 0x94, 0x1e, 0xb9, 0x5c, 0xc3, 0x64, 0x83, 0xbb,
+	0x02, 0xb5, 0xfb, 0x82, 0x4e, 0xb0, 0x57, 0x70, 0x8e, 0x3a, 0xad, 0xea, 0xd2, 0xe1, 0x96, 0xe7,
+	0x58, 0xcf, 0x7b, 0x2f, 0x7b, 0xaf, 0xa7, 0xf1, 0xb4, 0x1b, 0x7e, 0xc2, 0x9a, 0x3d, 0x83, 0xb3,
+	0x1c, 0x6b, 0xae, 0x8d, 0x4e, 0x71, 0xde, 0x27, 0xc0, 0x38, 0xc7, 0xfa, 0x6b, 0xf3, 0x5e, 0xfe,
+	0xec, 0x01, 0x6c, 0x48, 0xfd, 0xa3, 0xce, 0x0c, 0x7b, 0x03, 0x4c, 0xef, 0x8b, 0x04, 0x2b, 0x6e,
+	0x32, 0x6e, 0xbd, 0x92, 0x25, 0xd6, 0x41, 0xfc, 0xd8, 0x6f, 0xd6, 0x59, 0xeb, 0xc0, 0x36, 0xf2,
+	0x01, 0xc3, 0xad, 0x7c, 0xf0, 0xec, 0x83, 0x78, 0x1a, 0x86, 0x1b, 0xf9, 0x80, 0xec, 0x1a, 0x2e,
+	0x94, 0xb0, 0x2e, 0xb0, 0x79, 0xe0, 0x80, 0x80, 0xb3, 0x66, 0xd1, 0xb2, 0x11, 0x76, 0x01, 0xe3,
+	0x02, 0x9d, 0xd8, 0x0a, 0x27, 0xe6, 0x43, 0xef, 0x34, 0xbc, 0x97, 0xbf, 0xfa, 0xc1, 0x29, 0x45,
+	0x5f, 0xc1, 0xd3, 0x43, 0x74, 0x7f, 0x3f, 0x2e, 0x75, 0x66, 0xda, 0x13, 0x5c, 0x76, 0xcb, 0xa3,
+	0x74, 0x57, 0x30, 0x6b, 0xc7, 0xd2, 0x68, 0xee, 0xea, 0xd2, 0x3b, 0x1e, 0xc5, 0x8f, 0x0e, 0xe3,
+	0xbb, 0xba, 0xc4, 0x23, 0xf2, 0x06, 0x98, 0x28, 0x93, 0xe6, 0x07, 0xdf, 0xa3, 0x8e, 0x5c, 0x1a,
+	0x7d, 0xdb, 0xec, 0xc8, 0xfb, 0xfb, 0xbf, 0x72, 0x16, 0xd8, 0x86, 0x98, 0xac, 0x9e, 0x44, 0xa1,
+	0xef, 0xa3, 0xf2, 0xfe, 0x48, 0x4f, 0x91, 0xae, 0xe1, 0xe2, 0x28, 0x48, 0x5b, 0xd8, 0x88, 0xe2,
+	0xcc, 0x6c, 0x97, 0x82, 0x7a, 0x63, 0x57, 0x30, 0x6c, 0x94, 0xe7, 0x27, 0x24, 0x70, 0xd9, 0x09,
+	0xac, 0x93, 0x7b, 0x4c, 0xdd, 0x67, 0x93, 0xe6, 0x31, 0x01, 0xfe, 0xd3, 0xe8, 0xe9, 0xbf, 0x1b,
+	0x5d, 0xde, 0x03, 0x1c, 0x18, 0xd8, 0x3b, 0x98, 0x56, 0xe8, 0x84, 0xd4, 0x7c, 0xaf, 0x9d, 0x54,
+	0x74, 0xda, 0xc9, 0x6a, 0x11, 0xf9, 0x5f, 0x1a, 0x85, 0x5f, 0x1a, 0xdd, 0x85, 0x5f, 0x1a, 0x4f,
+	0x3c, 0xfe, 0x5b, 0x03, 0x67, 0xcf, 0x01, 0x14, 0xee, 0x84, 0xe2, 0x3f, 0x8c, 0xda, 0xd2, 0xa5,
+	0xc7, 0xf1, 0x19, 0x4d, 0x3e, 0x18, 0xb5, 0xbd, 0x1d, 0x7e, 0xef, 0x97, 0x49, 0x72, 0x42, 0x2c,
+	0x6f, 0x7f, 0x0f, 0x00, 0x49, 0x23, 0x49, 0x88, 0x13, 0x03, 0x00, 0x00,
 }
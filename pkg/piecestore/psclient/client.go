@@ -17,11 +17,17 @@ import (
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
 )
 
 // ClientError is any error returned by the client
@@ -39,9 +45,10 @@ var (
 // Client is an interface describing the functions for interacting with piecestore nodes
 type Client interface {
 	Meta(ctx context.Context, id PieceID) (*pb.PieceSummary, error)
-	Put(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) error
+	Put(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (*pb.PieceStoreReceipt, error)
 	Get(ctx context.Context, id PieceID, size int64, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (ranger.Ranger, error)
 	Delete(ctx context.Context, pieceID PieceID, authorization *pb.SignedMessage) error
+	DeleteMany(ctx context.Context, pieceIDs []PieceID, authorization *pb.SignedMessage) error
 	Stats(ctx context.Context) (*pb.StatSummary, error)
 	io.Closer
 }
@@ -111,11 +118,13 @@ func (ps *PieceStore) Meta(ctx context.Context, id PieceID) (*pb.PieceSummary, e
 	return ps.client.Piece(ctx, &pb.PieceId{Id: id.String()})
 }
 
-// Put uploads a Piece to a piece store Server
-func (ps *PieceStore) Put(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) error {
-	stream, err := ps.client.Store(ctx)
+// Put uploads a Piece to a piece store Server and returns the receipt the
+// server signed for it, or nil if the server does not sign receipts yet
+func (ps *PieceStore) Put(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (*pb.PieceStoreReceipt, error) {
+	var p peer.Peer
+	stream, err := ps.client.Store(ctx, grpc.Peer(&p))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	msg := &pb.PieceStore{
@@ -127,7 +136,7 @@ func (ps *PieceStore) Put(ctx context.Context, id PieceID, data io.Reader, ttl t
 			zap.S().Errorf("error closing stream %s :: %v.Send() = %v", closeErr, stream, closeErr)
 		}
 
-		return fmt.Errorf("%v.Send() = %v", stream, err)
+		return nil, fmt.Errorf("%v.Send() = %v", stream, err)
 	}
 
 	writer := &StreamWriter{signer: ps, stream: stream, pba: ba}
@@ -146,14 +155,33 @@ func (ps *PieceStore) Put(ctx context.Context, id PieceID, data io.Reader, ttl t
 		zap.S().Infof("Node cut from upload due to slow connection. Deleting piece %s...", id)
 		deleteErr := ps.Delete(ctx, id, authorization)
 		if deleteErr != nil {
-			return deleteErr
+			return nil, deleteErr
 		}
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return bufw.Flush()
+	if err = bufw.Flush(); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	// the receipt must be signed by the node the piece was uploaded to
+	pi, err := provider.PeerIdentityFromPeer(&p)
+	if err != nil {
+		return nil, err
+	}
+	receipt := writer.summary.GetReceipt()
+	if receipt == nil {
+		return nil, nil
+	}
+	if err = CheckReceiptKey(receipt, pi.Leaf.PublicKey); err != nil {
+		return nil, err
+	}
+	return receipt, nil
 }
 
 // Get begins downloading a Piece from a piece store Server
@@ -176,6 +204,39 @@ func (ps *PieceStore) Delete(ctx context.Context, id PieceID, authorization *pb.
 	return nil
 }
 
+// DeleteMany deletes several Pieces from a piece store Server with one
+// request
+func (ps *PieceStore) DeleteMany(ctx context.Context, ids []PieceID, authorization *pb.SignedMessage) error {
+	pieceIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pieceIDs[i] = id.String()
+	}
+	reply, err := ps.client.DeleteMany(ctx, &pb.PieceDeleteMany{Ids: pieceIDs, Authorization: authorization})
+	if err != nil {
+		return err
+	}
+	zap.S().Infof("DeleteMany request route summary: %v", reply)
+	return nil
+}
+
+// DeletePieces deletes the pieces with ids from the node of ps with one
+// request, or with one request for each if the node does not serve
+// DeleteMany yet
+func DeletePieces(ctx context.Context, ps Client, ids []PieceID, authorization *pb.SignedMessage) error {
+	err := ps.DeleteMany(ctx, ids, authorization)
+	if status.Code(err) != codes.Unimplemented {
+		return err
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := ps.Delete(ctx, id, authorization); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utils.CombineErrors(errs...)
+}
+
 // Stats will retrieve stats about a piece storage node
 func (ps *PieceStore) Stats(ctx context.Context) (*pb.StatSummary, error) {
 	return ps.client.Stats(ctx, &pb.StatsReq{})
@@ -23,6 +23,8 @@ type StreamWriter struct {
 	signer       *PieceStore // We need this for signing
 	totalWritten int64
 	pba          *pb.PayerBandwidthAllocation
+	summary      *pb.PieceStoreSummary // received when closing
+	closed       bool
 }
 
 // Write Piece data to a piece store server upload stream
@@ -74,12 +76,18 @@ func (s *StreamWriter) Write(b []byte) (int, error) {
 
 // Close the piece store Write Stream
 func (s *StreamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
 	reply, err := s.stream.CloseAndRecv()
 	if err != nil {
 		return err
 	}
 
 	zap.S().Infof("Stream close and recv summary: %v", reply)
+	s.summary = reply
 
 	return nil
 }
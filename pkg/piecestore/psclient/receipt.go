@@ -0,0 +1,119 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+)
+
+// ReceiptError is the error class for invalid upload receipts
+var ReceiptError = errs.Class("receipt error")
+
+// VerifyReceipt checks that receipt is signed with the public key it
+// contains, and that it covers the piece with id, size, hash and
+// expiration
+func VerifyReceipt(receipt *pb.PieceStoreReceipt, id PieceID, size int64, hash []byte, expiration time.Time) error {
+	data, err := ReceiptData(receipt)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case data.GetId() != id.String():
+		return ReceiptError.New("receipt for piece %s instead of %s", data.GetId(), id)
+	case data.GetPieceSize() != size:
+		return ReceiptError.New("receipt for %d bytes instead of %d", data.GetPieceSize(), size)
+	case !bytes.Equal(data.GetHash(), hash):
+		return ReceiptError.New("receipt for different data")
+	case data.GetExpirationUnixSec() != expiration.Unix():
+		return ReceiptError.New("receipt expiring at %d instead of %d", data.GetExpirationUnixSec(), expiration.Unix())
+	}
+	return nil
+}
+
+// ReceiptData verifies the signature of receipt and returns its data
+func ReceiptData(receipt *pb.PieceStoreReceipt) (*pb.PieceStoreReceipt_Data, error) {
+	if receipt == nil {
+		return nil, ReceiptError.New("missing receipt")
+	}
+
+	data := &pb.PieceStoreReceipt_Data{}
+	if err := proto.Unmarshal(receipt.GetData(), data); err != nil {
+		return nil, ReceiptError.Wrap(err)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(data.GetPubKey())
+	if err != nil {
+		return nil, ReceiptError.Wrap(err)
+	}
+	k, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", key)
+	}
+
+	if !cryptopasta.Verify(receipt.GetData(), receipt.GetSignature(), k) {
+		return nil, ReceiptError.New("failed to verify signature")
+	}
+	return data, nil
+}
+
+// CompactReceipt returns the parts of receipt that cannot be derived from
+// the pointer of the segment of its piece, to keep in the pointer. It
+// returns nil without a receipt.
+func CompactReceipt(receipt *pb.PieceStoreReceipt) (*pb.PieceReceipt, error) {
+	if receipt == nil {
+		return nil, nil
+	}
+	data, err := ReceiptData(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PieceReceipt{Hash: data.GetHash(), PubKey: data.GetPubKey(), Signature: receipt.GetSignature()}, nil
+}
+
+// ExpandReceipt returns the receipt that compact was kept of, for the piece
+// with id, size and expiration derived from the pointer of its segment
+func ExpandReceipt(compact *pb.PieceReceipt, id PieceID, size int64, expiration int64) (*pb.PieceStoreReceipt, error) {
+	if compact == nil {
+		return nil, ReceiptError.New("missing receipt")
+	}
+	data, err := proto.Marshal(&pb.PieceStoreReceipt_Data{
+		Id:                id.String(),
+		PieceSize:         size,
+		Hash:              compact.GetHash(),
+		ExpirationUnixSec: expiration,
+		PubKey:            compact.GetPubKey(),
+	})
+	if err != nil {
+		return nil, ReceiptError.Wrap(err)
+	}
+	return &pb.PieceStoreReceipt{Signature: compact.GetSignature(), Data: data}, nil
+}
+
+// CheckReceiptKey returns an error if receipt is not signed with key
+func CheckReceiptKey(receipt *pb.PieceStoreReceipt, key interface{}) error {
+	data, err := ReceiptData(receipt)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return ReceiptError.Wrap(err)
+	}
+	if !bytes.Equal(data.GetPubKey(), encoded) {
+		return ReceiptError.New("receipt not signed by the storage node")
+	}
+	return nil
+}
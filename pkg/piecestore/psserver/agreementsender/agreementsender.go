@@ -22,28 +22,47 @@ import (
 
 var (
 	defaultCheckInterval = flag.Duration("piecestore.agreementsender.check_interval", time.Hour, "number of seconds to sleep between agreement checks")
-	defaultOverlayAddr   = flag.String("piecestore.agreementsender.overlay_addr", "127.0.0.1:7777", "Overlay Address")
+	defaultOverlayAddr   = flag.String("piecestore.agreementsender.overlay_addr", "127.0.0.1:7777", "Overlay Address to look up satellites that are not trusted by address")
 
 	// ASError wraps errors returned from agreementsender package
 	ASError = errs.Class("agreement sender error")
 )
 
+// maxCorruptedPieces is how many corrupted pieces are reported at a time
+const maxCorruptedPieces = 1000
+
 // AgreementSender maintains variables required for reading bandwidth agreements from a DB and sending them to a Payers
 type AgreementSender struct {
-	DB       *psdb.DB
-	overlay  overlay.Client
-	identity *provider.FullIdentity
-	errs     []error
+	DB         *psdb.DB
+	overlay    overlay.Client
+	identity   *provider.FullIdentity
+	satellites map[storj.NodeID]string
+	errs       []error
 }
 
-// Initialize the Agreement Sender
-func Initialize(DB *psdb.DB, identity *provider.FullIdentity) (*AgreementSender, error) {
+// Initialize the Agreement Sender. The agreements and reports are sent to
+// the addresses of the trusted satellites directly, and the addresses of
+// the others are looked up on the overlay.
+func Initialize(DB *psdb.DB, identity *provider.FullIdentity, satellites map[storj.NodeID]string) (*AgreementSender, error) {
 	overlay, err := overlay.NewOverlayClient(identity, *defaultOverlayAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AgreementSender{DB: DB, identity: identity, overlay: overlay}, nil
+	return &AgreementSender{DB: DB, identity: identity, overlay: overlay, satellites: satellites}, nil
+}
+
+// satelliteAddress returns the address of the satellite with id
+func (as *AgreementSender) satelliteAddress(ctx context.Context, id storj.NodeID) (string, error) {
+	if address, ok := as.satellites[id]; ok {
+		return address, nil
+	}
+
+	satellite, err := as.overlay.Lookup(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return satellite.GetAddress().Address, nil
 }
 
 // Run the afreement sender with a context to cehck for cancel
@@ -71,6 +90,8 @@ func (as *AgreementSender) Run(ctx context.Context) error {
 			for satellite, agreements := range agreementGroups {
 				c <- &agreementGroup{satellite, agreements}
 			}
+
+			as.sendCorruptedPieces(ctx)
 		}
 	}()
 
@@ -82,21 +103,20 @@ func (as *AgreementSender) Run(ctx context.Context) error {
 			go func() {
 				zap.S().Infof("Sending %v agreements to satellite %s\n", len(agreementGroup.agreements), agreementGroup.satellite)
 
-				// Get satellite ip from overlay by Lookup agreementGroup.satellite
-				satellite, err := as.overlay.Lookup(ctx, agreementGroup.satellite)
+				address, err := as.satelliteAddress(ctx, agreementGroup.satellite)
 				if err != nil {
 					zap.S().Error(err)
 					return
 				}
 
 				// Create client from satellite ip
-				identOpt, err := as.identity.DialOption(storj.NodeID{})
+				identOpt, err := as.identity.DialOption(agreementGroup.satellite)
 				if err != nil {
 					zap.S().Error(err)
 					return
 				}
 
-				conn, err := grpc.Dial(satellite.GetAddress().Address, identOpt)
+				conn, err := grpc.Dial(address, identOpt)
 				if err != nil {
 					zap.S().Error(err)
 					return
@@ -118,8 +138,8 @@ func (as *AgreementSender) Run(ctx context.Context) error {
 						return
 					}
 
-					// Delete from PSDB by signature
-					if err = as.DB.DeleteBandwidthAllocationBySignature(agreement.Signature); err != nil {
+					// Delete from PSDB by signature, counting it as sent
+					if err = as.DB.MarkBandwidthAllocationSent(agreementGroup.satellite, agreement.Signature); err != nil {
 						zap.S().Error(err)
 						return
 					}
@@ -128,3 +148,57 @@ func (as *AgreementSender) Run(ctx context.Context) error {
 		}
 	}
 }
+
+// sendCorruptedPieces reports the pieces that were found corrupted to their
+// satellites, so that their segments are repaired
+func (as *AgreementSender) sendCorruptedPieces(ctx context.Context) {
+	corrupted, err := as.DB.GetCorrupted()
+	if err != nil {
+		zap.S().Error(err)
+		return
+	}
+
+	for satelliteID, pieceIDs := range corrupted {
+		zap.S().Infof("Reporting %v corrupted pieces to satellite %s\n", len(pieceIDs), satelliteID)
+		err := as.reportCorrupted(ctx, satelliteID, pieceIDs)
+		if err != nil {
+			zap.S().Errorf("Failed to report corrupted pieces to satellite: %+v", err)
+		}
+	}
+}
+
+func (as *AgreementSender) reportCorrupted(ctx context.Context, satelliteID storj.NodeID, pieceIDs []string) (err error) {
+	address, err := as.satelliteAddress(ctx, satelliteID)
+	if err != nil {
+		return ASError.Wrap(err)
+	}
+
+	identOpt, err := as.identity.DialOption(satelliteID)
+	if err != nil {
+		return ASError.Wrap(err)
+	}
+
+	conn, err := grpc.Dial(address, identOpt)
+	if err != nil {
+		return ASError.Wrap(err)
+	}
+	defer func() { err = utils.CombineErrors(err, conn.Close()) }()
+
+	client := pb.NewCorruptionClient(conn)
+	for len(pieceIDs) > 0 {
+		batch := pieceIDs
+		if len(batch) > maxCorruptedPieces {
+			batch = batch[:maxCorruptedPieces]
+		}
+		pieceIDs = pieceIDs[len(batch):]
+
+		_, err = client.ReportCorrupted(ctx, &pb.CorruptedPieces{PieceIds: batch})
+		if err != nil {
+			return ASError.Wrap(err)
+		}
+		if err = as.DB.DeleteCorrupted(satelliteID, batch); err != nil {
+			return ASError.Wrap(err)
+		}
+	}
+	return nil
+}
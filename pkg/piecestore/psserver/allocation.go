@@ -0,0 +1,229 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/piecestore/psserver/psdb"
+)
+
+// ErrAllocationExceeded is returned when a request would use more disk space
+// or bandwidth than the node has allocated
+var ErrAllocationExceeded = errs.Class("allocation exceeded")
+
+const (
+	// allocationRefreshInterval is how often the disk space used is reread
+	// from the database, to count the pieces expired since
+	allocationRefreshInterval = time.Minute
+	// spaceReconcileInterval is how often the disk space counted in the
+	// database is checked against the sizes of the pieces
+	spaceReconcileInterval = 24 * time.Hour
+)
+
+// allocation tracks the disk space and the bandwidth of the month that the
+// node has left. The space received by uploads in progress is reserved, so
+// that concurrent uploads cannot exceed the allocated disk space together.
+type allocation struct {
+	disk      int64
+	bandwidth int64
+
+	mu            sync.Mutex
+	usedDisk      int64
+	reservedDisk  int64
+	month         time.Time
+	usedBandwidth int64
+}
+
+func newAllocation(disk, bandwidth, usedDisk, usedBandwidth int64) *allocation {
+	return &allocation{
+		disk:          disk,
+		bandwidth:     bandwidth,
+		usedDisk:      usedDisk,
+		month:         getBeginningOfMonth(),
+		usedBandwidth: usedBandwidth,
+	}
+}
+
+// loadAllocation returns the allocation of disk and bandwidth bytes, with
+// the disk space and the bandwidth of the month used as recorded in db
+func loadAllocation(db *psdb.DB, disk, bandwidth int64) (*allocation, error) {
+	usedDisk, err := db.SpaceUsed()
+	if err != nil {
+		return nil, err
+	}
+	usedBandwidth, err := db.GetTotalBandwidthBetween(getBeginningOfMonth(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return newAllocation(disk, bandwidth, usedDisk, usedBandwidth), nil
+}
+
+// startMonth resets the bandwidth used when a new month has begun
+func (a *allocation) startMonth() {
+	if month := getBeginningOfMonth(); !month.Equal(a.month) {
+		a.month, a.usedBandwidth = month, 0
+	}
+}
+
+// available returns the disk space and the bandwidth that are left
+func (a *allocation) available() (disk, bandwidth int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startMonth()
+	return max64(a.disk-a.usedDisk-a.reservedDisk, 0), max64(a.bandwidth-a.usedBandwidth, 0)
+}
+
+// used returns the disk space used by the stored pieces and the bandwidth
+// used this month
+func (a *allocation) used() (disk, bandwidth int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startMonth()
+	return a.usedDisk, a.usedBandwidth
+}
+
+// checkAvailable returns an error if no disk space or no bandwidth is left,
+// so that uploads are rejected before any data is received
+func (a *allocation) checkAvailable(upload bool) error {
+	disk, bandwidth := a.available()
+	if upload && disk <= 0 {
+		return ErrAllocationExceeded.New("no disk space left")
+	}
+	if bandwidth <= 0 {
+		return ErrAllocationExceeded.New("no bandwidth left")
+	}
+	return nil
+}
+
+// reserve reserves size bytes of disk space and bandwidth for an upload in
+// progress
+func (a *allocation) reserve(size int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startMonth()
+
+	if a.usedDisk+a.reservedDisk+size > a.disk {
+		return ErrAllocationExceeded.New("disk space of %d bytes exceeded", a.disk)
+	}
+	if a.usedBandwidth+size > a.bandwidth {
+		return ErrAllocationExceeded.New("bandwidth of %d bytes exceeded", a.bandwidth)
+	}
+	a.reservedDisk += size
+	a.usedBandwidth += size
+	return nil
+}
+
+// release releases the size bytes reserved for an upload. The disk space is
+// used if the piece was stored, otherwise the bandwidth is given back.
+func (a *allocation) release(size int64, stored bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startMonth()
+
+	a.reservedDisk -= size
+	if stored {
+		a.usedDisk += size
+	} else {
+		a.usedBandwidth = max64(a.usedBandwidth-size, 0)
+	}
+}
+
+// useBandwidth uses size bytes of bandwidth for a download
+func (a *allocation) useBandwidth(size int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startMonth()
+
+	if a.usedBandwidth+size > a.bandwidth {
+		return ErrAllocationExceeded.New("bandwidth of %d bytes exceeded", a.bandwidth)
+	}
+	a.usedBandwidth += size
+	return nil
+}
+
+// refundBandwidth gives back size bytes of bandwidth that were not used
+func (a *allocation) refundBandwidth(size int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startMonth()
+	a.usedBandwidth = max64(a.usedBandwidth-size, 0)
+}
+
+// setUsedDisk sets the disk space used by the stored pieces
+func (a *allocation) setUsedDisk(size int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.usedDisk = size
+}
+
+// refreshAllocation rereads the disk space used from the database, as
+// pieces expire, and corrects the space counted in it from time to time,
+// until ctx is canceled
+func (s *Server) refreshAllocation(ctx context.Context) {
+	refresh := time.NewTicker(allocationRefreshInterval)
+	defer refresh.Stop()
+	reconcile := time.NewTicker(spaceReconcileInterval)
+	defer reconcile.Stop()
+
+	for {
+		select {
+		case <-refresh.C:
+			if err := s.refreshUsedDisk(); err != nil {
+				zap.S().Errorf("failed refreshing used disk space: %+v", err)
+			}
+		case <-reconcile.C:
+			if err := s.reconcileUsedDisk(ctx); err != nil {
+				zap.S().Errorf("failed reconciling used disk space: %+v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reconcileUsedDisk corrects the disk space counted in the database, which
+// drifts only if the database is changed by hand
+func (s *Server) reconcileUsedDisk(ctx context.Context) error {
+	drift, err := s.DB.ReconcileSpaceUsed(ctx)
+	if err != nil {
+		return err
+	}
+	if drift != 0 {
+		zap.S().Warnf("Corrected used disk space counted off by %d bytes", drift)
+	}
+	return s.refreshUsedDisk()
+}
+
+func (s *Server) refreshUsedDisk() error {
+	used, err := s.DB.SpaceUsed()
+	if err != nil {
+		return err
+	}
+	s.allocation.setUsedDisk(used)
+	return nil
+}
+
+// allocationStatus returns err as a gRPC status telling the client that the
+// node is full, if it is an ErrAllocationExceeded
+func allocationStatus(err error) error {
+	if ErrAllocationExceeded.Has(err) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return err
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/gtank/cryptopasta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+)
+
+func TestAllocation(t *testing.T) {
+	a := newAllocation(100, 200, 40, 50)
+
+	// concurrent uploads cannot exceed the disk space together
+	require.NoError(t, a.reserve(30))
+	require.NoError(t, a.reserve(30))
+	assert.True(t, ErrAllocationExceeded.Has(a.reserve(1)))
+	disk, bandwidth := a.available()
+	assert.Equal(t, int64(0), disk)
+	assert.Equal(t, int64(90), bandwidth)
+
+	// the space of a failed upload is available again, with its bandwidth
+	a.release(30, false)
+	a.release(30, true)
+	used, usedBandwidth := a.used()
+	assert.Equal(t, int64(70), used)
+	assert.Equal(t, int64(80), usedBandwidth)
+
+	require.NoError(t, a.useBandwidth(120))
+	assert.True(t, ErrAllocationExceeded.Has(a.useBandwidth(1)))
+	assert.True(t, ErrAllocationExceeded.Has(a.checkAvailable(false)))
+	a.refundBandwidth(20)
+	assert.NoError(t, a.checkAvailable(false))
+
+	// the bandwidth is allocated by month
+	a.month = a.month.AddDate(0, -1, 0)
+	_, usedBandwidth = a.used()
+	assert.Equal(t, int64(0), usedBandwidth)
+}
+
+func TestAllocationExceeded(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+	TS.s.allocation = newAllocation(10, 1<<40, 0, 0)
+
+	store := func(id string, content []byte) (*pb.PieceStoreSummary, error) {
+		stream, err := TS.c.Store(ctx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.PieceStore{PieceData: &pb.PieceStore_PieceData{Id: id}}))
+
+		msg := &pb.PieceStore{
+			PieceData: &pb.PieceStore_PieceData{Content: content},
+			BandwidthAllocation: &pb.RenterBandwidthAllocation{
+				Data: serializeData(&pb.RenterBandwidthAllocation_Data{
+					PayerAllocation: &pb.PayerBandwidthAllocation{},
+					Total:           int64(len(content)),
+				}),
+			},
+		}
+		msg.BandwidthAllocation.Signature, err = cryptopasta.Sign(msg.BandwidthAllocation.Data, TS.k.(*ecdsa.PrivateKey))
+		require.NoError(t, err)
+		_ = stream.Send(msg)
+		return stream.CloseAndRecv()
+	}
+
+	_, err := store("11111111111111111111", []byte("butts"))
+	require.NoError(t, err)
+
+	_, err = store("22222222222222222222", []byte("buttsbutts"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+	_, err = TS.s.openPiece(ctx, "22222222222222222222")
+	assert.True(t, ErrPieceNotFound.Has(err))
+
+	stats, err := TS.c.Stats(ctx, &pb.StatsReq{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.UsedSpace)
+	assert.Equal(t, int64(5), stats.AvailableSpace)
+	assert.Equal(t, int64(5), stats.UsedBandwidth)
+
+	// full nodes reject uploads before receiving any data
+	_, err = store("33333333333333333333", []byte("butts"))
+	require.NoError(t, err)
+	_, err = store("44444444444444444444", nil)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+
+	// deleted pieces free their space
+	_, err = TS.c.Delete(ctx, &pb.PieceDelete{Id: "11111111111111111111"})
+	require.NoError(t, err)
+	stats, err = TS.c.Stats(ctx, &pb.StatsReq{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.AvailableSpace)
+
+	// downloads are rejected without bandwidth left
+	TS.s.allocation = newAllocation(10, 10, 5, 10)
+	stream, err := TS.c.Retrieve(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.PieceRetrieval{PieceData: &pb.PieceRetrieval_PieceData{Id: "33333333333333333333", PieceSize: 5}}))
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), err)
+}
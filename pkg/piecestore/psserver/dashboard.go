@@ -0,0 +1,274 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storj"
+)
+
+// Version is the version of the storage node, which can be set when
+// building with -ldflags "-X storj.io/storj/pkg/piecestore/psserver.Version=..."
+var Version = "development"
+
+// dashboard serves the state of the node to its operator, as JSON on
+// /api/dashboard and as a web page rendering it on /
+type dashboard struct {
+	server  *Server
+	id      storj.NodeID
+	started time.Time
+}
+
+type dashboardUsage struct {
+	Used      int64 `json:"used"`
+	Allocated int64 `json:"allocated"`
+}
+
+type dashboardDay struct {
+	Date string `json:"date"`
+	Used int64  `json:"used"`
+}
+
+// dashboardSatellite is the usage of a satellite, with the bandwidth used
+// this month. The pieces stored before their satellites were recorded have
+// an empty satellite ID.
+type dashboardSatellite struct {
+	ID                string `json:"id"`
+	Pieces            int64  `json:"pieces"`
+	DiskUsed          int64  `json:"diskUsed"`
+	PutBandwidth      int64  `json:"putBandwidth"`
+	GetBandwidth      int64  `json:"getBandwidth"`
+	PendingAgreements int64  `json:"pendingAgreements"`
+	SentAgreements    int64  `json:"sentAgreements"`
+}
+
+type dashboardStats struct {
+	NodeID         string               `json:"nodeID"`
+	Version        string               `json:"version"`
+	UptimeSeconds  int64                `json:"uptimeSeconds"`
+	Pieces         int64                `json:"pieces"`
+	Disk           dashboardUsage       `json:"disk"`
+	Bandwidth      dashboardUsage       `json:"bandwidth"`
+	BandwidthByDay []dashboardDay       `json:"bandwidthByDay"`
+	Satellites     []dashboardSatellite `json:"satellites"`
+}
+
+func newDashboard(s *Server, id storj.NodeID) *dashboard {
+	return &dashboard{server: s, id: id, started: time.Now()}
+}
+
+// serve serves the dashboard on ln until ctx is canceled
+func (d *dashboard) serve(ctx context.Context, ln net.Listener) error {
+	server := &http.Server{Handler: d.handler()}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	err := server.Serve(ln)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// listenDashboard listens on addr for the dashboard. Only loopback
+// addresses are accepted, so that the dashboard is not exposed to the
+// network.
+func listenDashboard(addr string) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, ServerError.Wrap(err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, ServerError.New("%s is not a loopback address", addr)
+	}
+	return net.Listen("tcp", addr)
+}
+
+func (d *dashboard) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/dashboard", d.serveStats)
+	mux.HandleFunc("/", d.servePage)
+	return localOnly(mux)
+}
+
+// localOnly rejects the requests that are not from the node's host, as the
+// dashboard is only for its operator
+func localOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			http.Error(w, "the dashboard is only served locally", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (d *dashboard) serveStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := d.stats(r.Context())
+	if err != nil {
+		zap.S().Errorf("failed getting dashboard stats: %+v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		zap.S().Errorf("failed writing dashboard stats: %+v", err)
+	}
+}
+
+func (d *dashboard) servePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(dashboardPage))
+}
+
+func (d *dashboard) stats(ctx context.Context) (_ *dashboardStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stats := &dashboardStats{
+		NodeID:        d.id.String(),
+		Version:       Version,
+		UptimeSeconds: int64(time.Since(d.started) / time.Second),
+		Disk:          dashboardUsage{Allocated: d.server.allocation.disk},
+		Bandwidth:     dashboardUsage{Allocated: d.server.allocation.bandwidth},
+	}
+
+	// the bandwidth is allocated by month
+	now := time.Now()
+	for day := getBeginningOfMonth(); !day.After(now); day = day.AddDate(0, 0, 1) {
+		used, err := d.server.DB.GetBandwidthUsedByDay(day)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		stats.BandwidthByDay = append(stats.BandwidthByDay, dashboardDay{Date: day.Format("2006-01-02"), Used: used})
+		stats.Bandwidth.Used += used
+	}
+
+	satellites := make(map[storj.NodeID]*dashboardSatellite)
+	satellite := func(id storj.NodeID) *dashboardSatellite {
+		if satellites[id] == nil {
+			satellites[id] = &dashboardSatellite{}
+			if id != (storj.NodeID{}) {
+				satellites[id].ID = id.String()
+			}
+		}
+		return satellites[id]
+	}
+
+	usage, err := d.server.satelliteStats()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range usage {
+		satellite(u.SatelliteId).Pieces = u.Pieces
+		satellite(u.SatelliteId).DiskUsed = u.UsedSpace
+		satellite(u.SatelliteId).PutBandwidth = u.PutBandwidth
+		satellite(u.SatelliteId).GetBandwidth = u.GetBandwidth
+		stats.Pieces += u.Pieces
+		stats.Disk.Used += u.UsedSpace
+	}
+
+	counts, err := d.server.DB.CountBandwidthAllocations()
+	if err != nil {
+		return nil, err
+	}
+	for id, c := range counts {
+		satellite(id).PendingAgreements = c.Pending
+		satellite(id).SentAgreements = c.Sent
+	}
+
+	stats.Satellites = []dashboardSatellite{}
+	for _, s := range satellites {
+		stats.Satellites = append(stats.Satellites, *s)
+	}
+	sort.Slice(stats.Satellites, func(i, k int) bool {
+		return stats.Satellites[i].ID < stats.Satellites[k].ID
+	})
+
+	return stats, nil
+}
+
+const dashboardPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Storage Node</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 1em; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Storage Node</h1>
+<p id="node"></p>
+<table id="summary"></table>
+<h2>Satellites</h2>
+<table id="satellites"></table>
+<h2>Bandwidth this month</h2>
+<table id="days"></table>
+<script>
+function size(n) {
+	var units = ["B", "KiB", "MiB", "GiB", "TiB", "PiB"], i = 0;
+	while (n >= 1024 && i < units.length - 1) { n /= 1024; i++; }
+	return n.toFixed(i ? 1 : 0) + " " + units[i];
+}
+
+function fill(id, head, rows) {
+	var table = document.getElementById(id);
+	table.innerHTML = "";
+	[head].concat(rows).forEach(function(row, i) {
+		var tr = table.insertRow();
+		row.forEach(function(cell) {
+			var td = document.createElement(i ? "td" : "th");
+			td.textContent = cell;
+			tr.appendChild(td);
+		});
+	});
+}
+
+function refresh() {
+	fetch("/api/dashboard").then(function(resp) { return resp.json(); }).then(function(s) {
+		var up = s.uptimeSeconds;
+		document.getElementById("node").textContent = "Node " + s.nodeID + ", version " + s.version +
+			", up " + Math.floor(up / 86400) + "d " + Math.floor(up % 86400 / 3600) + "h " + Math.floor(up % 3600 / 60) + "m";
+		fill("summary", ["", "Used", "Allocated"], [
+			["Disk", size(s.disk.used), size(s.disk.allocated)],
+			["Bandwidth", size(s.bandwidth.used), size(s.bandwidth.allocated)],
+			["Pieces", s.pieces, ""]
+		]);
+		fill("satellites", ["Satellite", "Pieces", "Disk used", "PUT bandwidth", "GET bandwidth", "Pending agreements", "Sent agreements"],
+			s.satellites.map(function(sat) {
+				return [sat.id || "unknown", sat.pieces, size(sat.diskUsed), size(sat.putBandwidth), size(sat.getBandwidth),
+					sat.pendingAgreements, sat.sentAgreements];
+			}));
+		fill("days", ["Day", "Used"], s.bandwidthByDay.map(function(day) {
+			return [day.date, size(day.used)];
+		}));
+	});
+}
+
+refresh();
+setInterval(refresh, 10000);
+</script>
+</body>
+</html>
+`
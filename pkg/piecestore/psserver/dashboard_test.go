@@ -0,0 +1,104 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/pb"
+)
+
+func TestDashboard(t *testing.T) {
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+
+	satelliteID := teststorj.NodeIDFromString("satellite")
+	for i, id := range []string{"11111111111111111111", "22222222222222222222", "33333333333333333333"} {
+		require.NoError(t, writePiece(s, id))
+		require.NoError(t, s.DB.AddTTL(id, 0, 5))
+		// the satellite of the first piece is unknown
+		if i > 0 {
+			require.NoError(t, s.DB.SetBlobSatellite(id, satelliteID, "piece-"+id))
+		}
+	}
+
+	for _, signature := range []string{"sent", "pending"} {
+		require.NoError(t, s.DB.WriteBandwidthAllocToDB(&pb.RenterBandwidthAllocation{
+			Signature: []byte(signature),
+			Data: serializeData(&pb.RenterBandwidthAllocation_Data{
+				PayerAllocation: &pb.PayerBandwidthAllocation{
+					Data: serializePayerData(&pb.PayerBandwidthAllocation_Data{SatelliteId: satelliteID}),
+				},
+			}),
+		}))
+	}
+	require.NoError(t, s.DB.MarkBandwidthAllocationSent(satelliteID, []byte("sent")))
+	require.NoError(t, s.DB.AddBandwidthUsed(satelliteID, pb.PayerBandwidthAllocation_PUT, 70))
+	require.NoError(t, s.DB.AddBandwidthUsed(satelliteID, pb.PayerBandwidthAllocation_GET, 30))
+
+	handler := newDashboard(s, teststorj.NodeIDFromString("node")).handler()
+
+	// only the operator can see the dashboard
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/dashboard", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req := httptest.NewRequest("GET", "/api/dashboard", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	stats := &dashboardStats{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(stats))
+	assert.Equal(t, teststorj.NodeIDFromString("node").String(), stats.NodeID)
+	assert.Equal(t, Version, stats.Version)
+	assert.Equal(t, int64(3), stats.Pieces)
+	assert.Equal(t, dashboardUsage{Used: 15, Allocated: 1 << 30}, stats.Disk)
+	assert.Equal(t, dashboardUsage{Used: 100, Allocated: 1 << 40}, stats.Bandwidth)
+	assert.Equal(t, []dashboardSatellite{
+		{Pieces: 1, DiskUsed: 5},
+		{ID: satelliteID.String(), Pieces: 2, DiskUsed: 10, PutBandwidth: 70, GetBandwidth: 30, PendingAgreements: 1, SentAgreements: 1},
+	}, stats.Satellites)
+
+	require.Equal(t, time.Now().Day(), len(stats.BandwidthByDay))
+	today := stats.BandwidthByDay[len(stats.BandwidthByDay)-1]
+	assert.Equal(t, dashboardDay{Date: time.Now().Format("2006-01-02"), Used: 100}, today)
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "[::1]:1234"
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/api/dashboard")
+}
+
+func serializePayerData(pba *pb.PayerBandwidthAllocation_Data) []byte {
+	data, _ := proto.Marshal(pba)
+	return data
+}
+
+func TestListenDashboard(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:0", "localhost:0"} {
+		ln, err := listenDashboard(addr)
+		if assert.NoError(t, err, addr) {
+			assert.NoError(t, ln.Close())
+		}
+	}
+
+	// the dashboard is not exposed to the network
+	for _, addr := range []string{":0", "0.0.0.0:0", "[::]:0", "10.0.0.1:0", "example.com:0", "127.0.0.1"} {
+		_, err := listenDashboard(addr)
+		assert.True(t, ServerError.Has(err), addr)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/disk"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/piecestore/psserver/psdb"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+)
+
+// DataDir is a directory, usually on its own disk, that pieces are stored
+// in
+type DataDir struct {
+	Path      string
+	Allocated int64
+	Blobs     *filestore.Store
+}
+
+// dataDirConfig is a data directory before it is opened
+type dataDirConfig struct {
+	path      string
+	allocated int64
+}
+
+// parseDataDirs parses a comma separated list of directories, each
+// followed by an equal sign and the bytes allocated in it, like
+// "/mnt/disk1=500000000000,/mnt/disk2=1000000000000"
+func parseDataDirs(s string) (dirs []dataDirConfig, err error) {
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		i := strings.LastIndex(field, "=")
+		if i <= 0 {
+			return nil, ServerError.New("data directory %q has no allocated space", field)
+		}
+		allocated, err := strconv.ParseInt(field[i+1:], 10, 64)
+		if err != nil || allocated <= 0 {
+			return nil, ServerError.New("data directory %q has invalid allocated space", field)
+		}
+		dirs = append(dirs, dataDirConfig{path: field[:i], allocated: allocated})
+	}
+	return dirs, nil
+}
+
+// dirBlobs returns the blob stores of dirs by their paths
+func dirBlobs(dirs []*DataDir) map[string]storage.Blobs {
+	blobs := make(map[string]storage.Blobs, len(dirs))
+	for _, dir := range dirs {
+		blobs[dir.Path] = dir.Blobs
+	}
+	return blobs
+}
+
+// parseLostDataDirs parses a comma separated list of data directories
+// whose disks failed
+func parseLostDataDirs(s string) (lost []string) {
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			lost = append(lost, field)
+		}
+	}
+	return lost
+}
+
+// openDataDirs opens the data directories of configs, after reporting the
+// pieces of the directories in lost to their satellites. The other
+// directories with pieces must exist and be configured, so that a disk that
+// is not mounted or a directory dropped from the configuration by mistake
+// does not lose the pieces on it.
+func openDataDirs(ctx context.Context, db *psdb.DB, configs []dataDirConfig, lost []string) (dirs []*DataDir, err error) {
+	configured := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		configured[config.path] = true
+	}
+	for _, path := range lost {
+		if configured[path] {
+			return nil, ServerError.New("data directory %s is both configured and lost", path)
+		}
+	}
+
+	err = reportLostPieces(db, lost)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := db.Dirs()
+	if err != nil {
+		return nil, ServerError.Wrap(err)
+	}
+	hasPieces := make(map[string]bool, len(stored))
+	for _, path := range stored {
+		if !configured[path] {
+			return nil, ServerError.New("data directory %s has pieces but is not configured, configure it again or list it as lost if its disk failed", path)
+		}
+		hasPieces[path] = true
+	}
+
+	for _, config := range configs {
+		var blobs *filestore.Store
+		if hasPieces[config.path] {
+			blobs, err = filestore.OpenAt(config.path)
+			if err != nil {
+				return nil, ServerError.New("data directory %s has pieces but cannot be opened, check that its disk is mounted or list it as lost if the disk failed: %v", config.path, err)
+			}
+		} else {
+			blobs, err = filestore.NewAt(config.path)
+			if err != nil {
+				return nil, ServerError.Wrap(err)
+			}
+		}
+
+		// nothing is stored yet, so the temporary files are of crashed stores
+		err = blobs.DeleteTemporaryFiles(ctx)
+		if err != nil {
+			return nil, ServerError.Wrap(err)
+		}
+
+		dirs = append(dirs, &DataDir{Path: config.path, Allocated: config.allocated, Blobs: blobs})
+	}
+	return dirs, nil
+}
+
+// reportLostPieces forgets the pieces of the data directories in lost, and
+// records them to be reported to their satellites, so that the node loses
+// only them instead of failing as a whole
+func reportLostPieces(db *psdb.DB, lost []string) error {
+	for _, path := range lost {
+		ids, err := db.DeleteDirPieces(path)
+		if err != nil {
+			return ServerError.Wrap(err)
+		}
+		if len(ids) > 0 {
+			zap.S().Warnf("Lost %d pieces of data directory %s, reporting them to their satellites", len(ids), path)
+		}
+	}
+	return nil
+}
+
+// limitAllocated lowers the space allocated in dirs to what their disks
+// have free, and returns the total
+func limitAllocated(db *psdb.DB, dirs []*DataDir) (total int64, err error) {
+	used, err := db.SpaceUsedByDir()
+	if err != nil {
+		return 0, ServerError.Wrap(err)
+	}
+
+	for _, dir := range dirs {
+		usage, err := disk.Usage(dir.Path)
+		if err != nil {
+			return 0, ServerError.Wrap(err)
+		}
+		free := int64(usage.Free)
+
+		if used[dir.Path] >= dir.Allocated {
+			zap.S().Warnf("Used more space then allocated in %s, allocating = %d Bytes", dir.Path, dir.Allocated)
+		}
+
+		// the available diskspace is less than remaining allocated space
+		if free < dir.Allocated-used[dir.Path] {
+			dir.Allocated = used[dir.Path] + free
+			zap.S().Warnf("Disk space of %s is less than requested allocated space, allocating = %d Bytes", dir.Path, dir.Allocated)
+		}
+
+		total += dir.Allocated
+	}
+	return total, nil
+}
+
+// chooseDir returns the data directory with the most space available
+func (s *Server) chooseDir() (*DataDir, error) {
+	if len(s.Dirs) == 1 {
+		return s.Dirs[0], nil
+	}
+
+	used, err := s.DB.SpaceUsedByDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var best *DataDir
+	var bestAvailable int64
+	for _, dir := range s.Dirs {
+		available := dir.Allocated - used[dir.Path]
+		usage, err := disk.Usage(dir.Path)
+		if err != nil {
+			// the disk is failing, so store on the others
+			zap.S().Errorf("failed getting disk usage of %s: %+v", dir.Path, err)
+			continue
+		}
+		if free := int64(usage.Free); free < available {
+			available = free
+		}
+
+		if best == nil || available > bestAvailable {
+			best, bestAvailable = dir, available
+		}
+	}
+	if best == nil {
+		return nil, ServerError.New("no data directory is available")
+	}
+	return best, nil
+}
+
+// dir returns the data directory with path, or nil if it is not
+// configured
+func (s *Server) dir(path string) *DataDir {
+	for _, dir := range s.Dirs {
+		if dir.Path == path {
+			return dir
+		}
+	}
+	return nil
+}
+
+// garbageCollectDirs removes the deleted pieces that could not be removed
+// while they were read from all the data directories
+func (s *Server) garbageCollectDirs(ctx context.Context) error {
+	var errs []error
+	for _, dir := range s.Dirs {
+		if err := dir.Blobs.GarbageCollect(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utils.CombineErrors(errs...)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/piecestore/psserver/psdb"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestParseDataDirs(t *testing.T) {
+	dirs, err := parseDataDirs("")
+	assert.NoError(t, err)
+	assert.Empty(t, dirs)
+
+	dirs, err = parseDataDirs("/mnt/disk1=500, C:\\disk2=1000")
+	assert.NoError(t, err)
+	assert.Equal(t, []dataDirConfig{{"/mnt/disk1", 500}, {"C:\\disk2", 1000}}, dirs)
+
+	for _, s := range []string{"/mnt/disk1", "=500", "/mnt/disk1=", "/mnt/disk1=-1", "/mnt/disk1=1GB"} {
+		_, err = parseDataDirs(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDataDirs(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "storj-datadirs")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	db, err := psdb.OpenInMemory(ctx, nil)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, db.Close()) }()
+
+	full := filepath.Join(tmp, "full")
+	empty := filepath.Join(tmp, "empty")
+	configs := []dataDirConfig{{full, 1}, {empty, 1 << 30}}
+	dirs, err := openDataDirs(ctx, db, configs, nil)
+	require.NoError(t, err)
+	db.SetDirs(dirBlobs(dirs))
+
+	s := &Server{Dirs: dirs, DB: db}
+
+	// pieces are placed by available space
+	_, _, err = s.storePiece(ctx, "11111111111111111111", bytes.NewReader([]byte("butts")))
+	require.NoError(t, err)
+	path, _, err := db.GetBlob("11111111111111111111")
+	require.NoError(t, err)
+	assert.Equal(t, empty, path)
+
+	piece, err := s.openPiece(ctx, "11111111111111111111")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(piece)
+	assert.NoError(t, err)
+	assert.Equal(t, "butts", string(data))
+	assert.NoError(t, piece.Close())
+
+	// a directory with pieces is not recreated when its disk is not mounted,
+	// nor are its pieces forgotten when it is not configured
+	require.NoError(t, os.RemoveAll(empty))
+	_, err = openDataDirs(ctx, db, configs, nil)
+	assert.Error(t, err)
+	_, err = os.Stat(empty)
+	assert.True(t, os.IsNotExist(err), err)
+	_, err = openDataDirs(ctx, db, configs[:1], nil)
+	assert.Error(t, err)
+	_, err = openDataDirs(ctx, db, configs, []string{empty})
+	assert.Error(t, err, "a lost directory cannot be configured")
+	_, _, err = db.GetBlob("11111111111111111111")
+	assert.NoError(t, err)
+
+	// losing a directory loses only its pieces, which are reported
+	satelliteID := teststorj.NodeIDFromString("satellite")
+	require.NoError(t, db.SetBlobSatellite("11111111111111111111", satelliteID, "piece"))
+	require.NoError(t, db.AddBlob("22222222222222222222", full, [32]byte{}))
+	require.NoError(t, db.AddTTL("22222222222222222222", 0, 5))
+	dirs, err = openDataDirs(ctx, db, configs[:1], []string{empty})
+	require.NoError(t, err)
+	s.Dirs = dirs
+
+	_, err = s.openPiece(ctx, "11111111111111111111")
+	assert.True(t, ErrPieceNotFound.Has(err))
+	_, err = db.GetTTLByID("22222222222222222222")
+	assert.NoError(t, err)
+
+	corrupted, err := db.GetCorrupted()
+	require.NoError(t, err)
+	assert.Equal(t, map[storj.NodeID][]string{satelliteID: {"piece"}}, corrupted)
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+)
+
+// exiter runs the graceful exits of the node from satellites for its
+// operator
+type exiter struct {
+	server    *Server
+	identity  *provider.FullIdentity
+	transport transport.Client
+}
+
+func newExiter(s *Server, identity *provider.FullIdentity) *exiter {
+	return &exiter{server: s, identity: identity, transport: transport.NewClient(identity)}
+}
+
+// Exit transfers the pieces of the satellite to the replacement nodes it
+// chooses, streaming the progress, until it has no pieces left on the
+// node. Only the operator, who has the identity of the node, can call it.
+// The exit stops when the stream is closed and continues where it stopped
+// when Exit is called again.
+func (e *exiter) Exit(req *pb.NodeExitRequest, stream pb.NodeExit_ExitServer) (err error) {
+	ctx := stream.Context()
+	defer mon.Task()(&ctx)(&err)
+
+	pi, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if pi.ID != e.identity.ID {
+		return status.Error(codes.PermissionDenied, "only the operator of the node can exit")
+	}
+
+	conn, err := e.transport.DialNode(ctx, &pb.Node{
+		Id: req.SatelliteId,
+		Address: &pb.NodeAddress{
+			Transport: pb.NodeTransport_TCP_TLS_GRPC,
+			Address:   req.GetSatelliteAddress(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(conn)
+	satellite := pb.NewGracefulExitClient(conn)
+
+	for {
+		resp, err := satellite.Exit(ctx, &pb.ExitRequest{})
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(resp.GetProgress()); err != nil {
+			return err
+		}
+		if resp.GetProgress().GetFinished() {
+			zap.S().Infof("Exited satellite %s", req.SatelliteId)
+			return nil
+		}
+		if len(resp.GetTransfers()) == 0 {
+			return ServerError.New("satellite %s gave no pieces to transfer", req.SatelliteId)
+		}
+
+		for _, t := range resp.GetTransfers() {
+			confirmation := &pb.TransferConfirmation{Path: t.GetPath(), PieceNum: t.GetPieceNum()}
+			id, receipt, err := e.transfer(ctx, resp, t)
+			if err != nil {
+				zap.S().Warnf("Failed transferring piece %d of %s to %s: %v", t.GetPieceNum(), t.GetPath(), t.GetReplacement().Id, err)
+				confirmation.Error = err.Error()
+			} else {
+				confirmation.Receipt = receipt
+			}
+
+			confirmed, err := satellite.ConfirmTransfer(ctx, confirmation)
+			if err != nil {
+				return err
+			}
+			if confirmed.GetTransferred() {
+				if err := e.server.deleteByID(ctx, id); err != nil {
+					zap.S().Errorf("Failed deleting transferred piece %s: %v", id, err)
+				}
+			}
+			if err = stream.Send(confirmed.GetProgress()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// transfer uploads the piece of t to its replacement node and returns the
+// local ID of the piece and the receipt of the replacement node
+func (e *exiter) transfer(ctx context.Context, resp *pb.ExitResponse, t *pb.PieceTransfer) (id string, _ *pb.PieceStoreReceipt, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pieceID := psclient.PieceID(t.GetPieceId())
+	derived, err := pieceID.Derive(e.identity.ID.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+	id, err = getNamespacedPieceID([]byte(derived.String()), getNamespace(resp.GetAuthorization()))
+	if err != nil {
+		return "", nil, err
+	}
+
+	piece, err := e.server.openPiece(ctx, id)
+	if err != nil {
+		return "", nil, err
+	}
+	defer utils.LogClose(piece)
+
+	replacementID, err := pieceID.Derive(t.GetReplacement().Id.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+
+	ps, err := psclient.NewPSClient(ctx, e.transport, t.GetReplacement(), 0)
+	if err != nil {
+		return "", nil, err
+	}
+	defer utils.LogClose(ps)
+
+	expiration := time.Unix(t.GetExpirationUnixSec(), 0)
+	data := newHashingReader(piece)
+	receipt, err := ps.Put(ctx, replacementID, data, expiration, resp.GetPayerAllocation(), resp.GetAuthorization())
+	if err != nil {
+		return "", nil, err
+	}
+	return id, receipt, psclient.VerifyReceipt(receipt, replacementID, data.n, data.hash.Sum(nil), expiration)
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/piecestore/psserver/psdb"
+	"storj.io/storj/pkg/utils"
+)
+
+// legacyImportedFile marks the data directories whose pieces stored by
+// earlier versions of the node were imported into their blob store
+const legacyImportedFile = "legacy-imported"
+
+// importLegacyPieces imports the pieces that earlier versions of the node
+// stored in dir at pstore.PathByID into its blob store, once. The files
+// without a TTL are of stores that failed, so they are deleted.
+func importLegacyPieces(ctx context.Context, db *psdb.DB, dir *DataDir) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	marker := filepath.Join(dir.Path, legacyImportedFile)
+	if _, err := os.Stat(marker); err == nil {
+		return nil
+	}
+
+	var imported, deleted int
+	folders1, err := ioutil.ReadDir(dir.Path)
+	if err != nil {
+		return ServerError.Wrap(err)
+	}
+	for _, folder1 := range folders1 {
+		if !folder1.IsDir() || len(folder1.Name()) != 2 {
+			continue
+		}
+		path1 := filepath.Join(dir.Path, folder1.Name())
+		folders2, err := ioutil.ReadDir(path1)
+		if err != nil {
+			return ServerError.Wrap(err)
+		}
+		// the blobs of the blob store are in the first level of folders, so
+		// only the legacy pieces are in a second one
+		for _, folder2 := range folders2 {
+			if !folder2.IsDir() || len(folder2.Name()) != 2 {
+				continue
+			}
+			path2 := filepath.Join(path1, folder2.Name())
+			files, err := ioutil.ReadDir(path2)
+			if err != nil {
+				return ServerError.Wrap(err)
+			}
+			for _, file := range files {
+				id := folder1.Name() + folder2.Name() + file.Name()
+				ok, err := importLegacyPiece(ctx, db, dir, id, filepath.Join(path2, file.Name()))
+				if err != nil {
+					return ServerError.New("failed importing piece %s: %v", id, err)
+				}
+				if ok {
+					imported++
+				} else {
+					deleted++
+				}
+			}
+			// the folders left with other files are kept
+			_ = os.Remove(path2)
+		}
+		_ = os.Remove(path1)
+	}
+
+	if imported > 0 || deleted > 0 {
+		zap.S().Infof("Imported %d pieces stored by an earlier version in %s, deleted %d without TTL", imported, dir.Path, deleted)
+	}
+	return ServerError.Wrap(ioutil.WriteFile(marker, nil, 0600))
+}
+
+// importLegacyPiece imports the piece with id stored at path into the blob
+// store of dir and deletes the file, and returns whether the piece was kept
+func importLegacyPiece(ctx context.Context, db *psdb.DB, dir *DataDir, id, path string) (imported bool, err error) {
+	_, err = db.GetTTLByID(id)
+	if err == sql.ErrNoRows {
+		return false, os.Remove(path)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// the piece was imported before the node stopped
+	_, _, err = db.GetBlob(id)
+	if err == nil {
+		return true, os.Remove(path)
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false, utils.CombineErrors(err, file.Close())
+	}
+	ref, err := dir.Blobs.Store(ctx, file, info.Size())
+	if err := utils.CombineErrors(err, file.Close()); err != nil {
+		return false, err
+	}
+
+	if err := db.AddBlob(id, dir.Path, ref); err != nil {
+		return false, utils.CombineErrors(err, dir.Blobs.Delete(ctx, ref))
+	}
+	return true, os.Remove(path)
+}
@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/piecestore"
+)
+
+func TestImportLegacyPieces(t *testing.T) {
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+	dir := s.Dirs[0]
+
+	require.NoError(t, writePiece(s, "11111111111111111111"))
+	for _, id := range []string{"22222222222222222222", "33333333333333333333"} {
+		path, err := pstore.PathByID(id, dir.Path)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte("legacy"+id), 0600))
+	}
+	// the second piece was never stored completely
+	require.NoError(t, s.DB.AddTTL("22222222222222222222", 0, 26))
+
+	require.NoError(t, importLegacyPieces(ctx, s.DB, dir))
+
+	for id, content := range map[string]string{
+		"11111111111111111111": "butts",
+		"22222222222222222222": "legacy22222222222222222222",
+	} {
+		piece, err := s.openPiece(ctx, id)
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(piece)
+		assert.NoError(t, err)
+		assert.NoError(t, piece.Close())
+		assert.Equal(t, content, string(data))
+	}
+	_, err := s.openPiece(ctx, "33333333333333333333")
+	assert.True(t, ErrPieceNotFound.Has(err))
+
+	// the blobs may be in first level folders of the same names
+	for _, path := range []string{filepath.Join(dir.Path, "22", "22"), filepath.Join(dir.Path, "33", "33")} {
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err), path)
+	}
+
+	// the pieces are imported once
+	path, err := pstore.PathByID("44444444444444444444", dir.Path)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte("legacy"), 0600))
+	require.NoError(t, importLegacyPieces(ctx, s.DB, dir))
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+// garbageCollectInterval is how often the deleted pieces that were being
+// read are removed from the disk
+const garbageCollectInterval = time.Hour
+
+// ErrPieceNotFound is the error class for pieces that are not stored
+var ErrPieceNotFound = errs.Class("piece not found")
+
+// checkID returns an error if id cannot be the ID of a piece
+func checkID(id string) error {
+	if len(id) < pstore.IDLength {
+		return pstore.ArgError.New("invalid id length")
+	}
+	return nil
+}
+
+// storePiece stores the data read from r as the piece with id in the data
+// directory with the most space available and returns its size and SHA-256
+// hash. The data is written to a temporary file, so the piece becomes
+// visible only once all of it is stored.
+func (s *Server) storePiece(ctx context.Context, id string, r io.Reader) (size int64, hash []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := checkID(id); err != nil {
+		return 0, nil, err
+	}
+
+	// a retried upload must not replace the piece while it is stored
+	if !s.reserve(id) {
+		return 0, nil, StoreError.New("piece %s is being stored", id)
+	}
+	defer s.unreserve(id)
+
+	_, _, err = s.DB.GetBlob(id)
+	if err == nil {
+		return 0, nil, StoreError.New("piece %s already exists", id)
+	}
+	if err != sql.ErrNoRows {
+		return 0, nil, err
+	}
+
+	dir, err := s.chooseDir()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	hasher := newHashingReader(r)
+	ref, err := dir.Blobs.Store(ctx, hasher, -1)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = s.DB.AddBlob(id, dir.Path, ref)
+	if err != nil {
+		return 0, nil, utils.CombineErrors(err, dir.Blobs.Delete(ctx, ref))
+	}
+	return hasher.n, hasher.hash.Sum(nil), nil
+}
+
+// reserve marks the piece with id as being stored, unless it is already
+func (s *Server) reserve(id string) bool {
+	s.storingMu.Lock()
+	defer s.storingMu.Unlock()
+
+	if s.storing[id] {
+		return false
+	}
+	if s.storing == nil {
+		s.storing = make(map[string]bool)
+	}
+	s.storing[id] = true
+	return true
+}
+
+// unreserve marks the piece with id as no longer being stored
+func (s *Server) unreserve(id string) {
+	s.storingMu.Lock()
+	defer s.storingMu.Unlock()
+
+	delete(s.storing, id)
+}
+
+// openPiece opens the data of the piece with id
+func (s *Server) openPiece(ctx context.Context, id string) (_ storage.ReadSeekCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	path, ref, err := s.DB.GetBlob(id)
+	if err == sql.ErrNoRows {
+		return nil, ErrPieceNotFound.New("%s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dir := s.dir(path)
+	if dir == nil {
+		return nil, ErrPieceNotFound.New("%s in lost data directory %s", id, path)
+	}
+	return dir.Blobs.Load(ctx, ref)
+}
+
+func (s *Server) deleteByID(ctx context.Context, id string) error {
+	return s.deleteByIDs(ctx, []string{id})
+}
+
+// deleteByIDs deletes the TTLs and blob references of ids in one
+// transaction, and then the data that no other piece references. Pieces
+// that are being read are removed from the disk later.
+func (s *Server) deleteByIDs(ctx context.Context, ids []string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var errs []error
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if err := checkID(id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		valid = append(valid, id)
+	}
+
+	blobs, err := s.DB.DeletePieces(valid)
+	if err != nil {
+		return utils.CombineErrors(append(errs, err)...)
+	}
+	for _, blob := range blobs {
+		if dir := s.dir(blob.Dir); dir != nil {
+			if err := dir.Blobs.Delete(ctx, blob.Ref); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if err := s.refreshUsedDisk(); err != nil {
+		errs = append(errs, err)
+	}
+
+	zap.S().Infof("Deleted data of %d ids\n", len(valid))
+
+	return utils.CombineErrors(errs...)
+}
+
+// collectGarbage removes the deleted pieces that could not be removed
+// while they were read, until ctx is canceled
+func (s *Server) collectGarbage(ctx context.Context) {
+	ticker := time.NewTicker(garbageCollectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.garbageCollectDirs(ctx)
+			if err != nil {
+				zap.S().Errorf("failed collecting deleted pieces: %+v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// hashingReader counts and hashes the bytes read from r
+type hashingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	n    int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, hash: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (n int, err error) {
+	n, err = h.r.Read(p)
+	h.n += int64(n)
+	_, _ = h.hash.Write(p[:n])
+	return n, err
+}
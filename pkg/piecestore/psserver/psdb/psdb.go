@@ -20,9 +20,9 @@ import (
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/pb"
-	"storj.io/storj/pkg/piecestore"
 	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
 )
 
 var (
@@ -32,10 +32,17 @@ var (
 
 // DB is a piece store database
 type DB struct {
-	dataPath string
-	mu       sync.Mutex
-	DB       *sql.DB // TODO: hide
-	check    *time.Ticker
+	dirs  map[string]storage.Blobs
+	mu    sync.Mutex
+	DB    *sql.DB // TODO: hide
+	check *time.Ticker
+}
+
+// Blob is where the data of a piece is stored
+type Blob struct {
+	ID  string
+	Dir string
+	Ref storage.BlobRef
 }
 
 // Agreement is a struct that contains a bandwidth agreement and the associated signature
@@ -44,8 +51,29 @@ type Agreement struct {
 	Signature []byte
 }
 
-// Open opens DB at DBPath
-func Open(ctx context.Context, dataPath, DBPath string) (db *DB, err error) {
+// AgreementCounts is how many bandwidth agreements of a satellite are
+// waiting to be sent and were sent to it
+type AgreementCounts struct {
+	Pending int64
+	Sent    int64
+}
+
+// BandwidthUsage is the bandwidth used by a satellite, by action
+type BandwidthUsage struct {
+	Put int64
+	Get int64
+}
+
+// SatelliteUsage is how many pieces of a satellite are stored and their
+// total size
+type SatelliteUsage struct {
+	Pieces int64
+	Size   int64
+}
+
+// Open opens DB at DBPath. The expired pieces are deleted from the blob
+// stores of their data directories in dirs, unless it is nil.
+func Open(ctx context.Context, dirs map[string]storage.Blobs, DBPath string) (db *DB, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if err = os.MkdirAll(filepath.Dir(DBPath), 0700); err != nil {
@@ -57,9 +85,9 @@ func Open(ctx context.Context, dataPath, DBPath string) (db *DB, err error) {
 		return nil, err
 	}
 	db = &DB{
-		DB:       sqlite,
-		dataPath: dataPath,
-		check:    time.NewTicker(*defaultCheckInterval),
+		DB:    sqlite,
+		dirs:  dirs,
+		check: time.NewTicker(*defaultCheckInterval),
 	}
 	if err := db.init(); err != nil {
 		return nil, utils.CombineErrors(err, db.DB.Close())
@@ -71,7 +99,7 @@ func Open(ctx context.Context, dataPath, DBPath string) (db *DB, err error) {
 }
 
 // OpenInMemory opens sqlite DB inmemory
-func OpenInMemory(ctx context.Context, dataPath string) (db *DB, err error) {
+func OpenInMemory(ctx context.Context, dirs map[string]storage.Blobs) (db *DB, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	sqlite, err := sql.Open("sqlite3", ":memory:")
@@ -80,9 +108,9 @@ func OpenInMemory(ctx context.Context, dataPath string) (db *DB, err error) {
 	}
 
 	db = &DB{
-		DB:       sqlite,
-		dataPath: dataPath,
-		check:    time.NewTicker(*defaultCheckInterval),
+		DB:    sqlite,
+		dirs:  dirs,
+		check: time.NewTicker(*defaultCheckInterval),
 	}
 	if err := db.init(); err != nil {
 		return nil, utils.CombineErrors(err, db.DB.Close())
@@ -93,6 +121,62 @@ func OpenInMemory(ctx context.Context, dataPath string) (db *DB, err error) {
 	return db, nil
 }
 
+// usageQueries create the tables counting the pieces by data directory and
+// by satellite, with the triggers keeping them up to date. The pieces are
+// counted for the data directory of their blob, and for the satellite of
+// their blob or an empty ID if it has none. The rows counted are inserted
+// without OR IGNORE, which the conflict clause of an upsert firing the
+// triggers would override.
+var usageQueries = []string{
+	"CREATE TABLE IF NOT EXISTS `dir_space_used` (`dir` TEXT UNIQUE, `pieces` INT, `size` INT);",
+	"CREATE TABLE IF NOT EXISTS `satellite_space_used` (`satellite` BLOB UNIQUE, `pieces` INT, `size` INT);",
+	// the pieces stored before they were counted are counted once
+	`INSERT INTO dir_space_used (dir, pieces, size) SELECT blobs.dir, COUNT(*), COALESCE(SUM(ttl.size), 0) FROM blobs LEFT JOIN ttl ON ttl.id = blobs.id
+		WHERE NOT EXISTS (SELECT 1 FROM dir_space_used) GROUP BY blobs.dir;`,
+	`INSERT INTO satellite_space_used (satellite, pieces, size) SELECT COALESCE(blobs.satellite, X''), COUNT(*), COALESCE(SUM(ttl.size), 0) FROM ttl LEFT JOIN blobs ON ttl.id = blobs.id
+		WHERE NOT EXISTS (SELECT 1 FROM satellite_space_used) GROUP BY COALESCE(blobs.satellite, X'');`,
+	`CREATE TRIGGER IF NOT EXISTS ttl_insert_usage AFTER INSERT ON ttl BEGIN
+		INSERT INTO satellite_space_used (satellite, pieces, size) SELECT COALESCE((SELECT satellite FROM blobs WHERE id = NEW.id), X''), 0, 0 WHERE NOT EXISTS (SELECT 1 FROM satellite_space_used WHERE satellite = COALESCE((SELECT satellite FROM blobs WHERE id = NEW.id), X''));
+		UPDATE satellite_space_used SET pieces = pieces + 1, size = size + COALESCE(NEW.size, 0) WHERE satellite = COALESCE((SELECT satellite FROM blobs WHERE id = NEW.id), X'');
+		UPDATE dir_space_used SET size = size + COALESCE(NEW.size, 0) WHERE dir = (SELECT dir FROM blobs WHERE id = NEW.id);
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS ttl_update_usage AFTER UPDATE OF size ON ttl BEGIN
+		UPDATE satellite_space_used SET size = size - COALESCE(OLD.size, 0) + COALESCE(NEW.size, 0) WHERE satellite = COALESCE((SELECT satellite FROM blobs WHERE id = NEW.id), X'');
+		UPDATE dir_space_used SET size = size - COALESCE(OLD.size, 0) + COALESCE(NEW.size, 0) WHERE dir = (SELECT dir FROM blobs WHERE id = NEW.id);
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS ttl_delete_usage AFTER DELETE ON ttl BEGIN
+		UPDATE satellite_space_used SET pieces = pieces - 1, size = size - COALESCE(OLD.size, 0) WHERE satellite = COALESCE((SELECT satellite FROM blobs WHERE id = OLD.id), X'');
+		UPDATE dir_space_used SET size = size - COALESCE(OLD.size, 0) WHERE dir = (SELECT dir FROM blobs WHERE id = OLD.id);
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS blobs_insert_usage AFTER INSERT ON blobs BEGIN
+		INSERT INTO dir_space_used (dir, pieces, size) SELECT NEW.dir, 0, 0 WHERE NOT EXISTS (SELECT 1 FROM dir_space_used WHERE dir = NEW.dir);
+		UPDATE dir_space_used SET pieces = pieces + 1, size = size + COALESCE((SELECT size FROM ttl WHERE id = NEW.id), 0) WHERE dir = NEW.dir;
+		UPDATE satellite_space_used SET pieces = pieces - 1, size = size - COALESCE((SELECT size FROM ttl WHERE id = NEW.id), 0)
+			WHERE satellite = X'' AND EXISTS (SELECT 1 FROM ttl WHERE id = NEW.id);
+		INSERT INTO satellite_space_used (satellite, pieces, size) SELECT COALESCE(NEW.satellite, X''), 0, 0 WHERE NOT EXISTS (SELECT 1 FROM satellite_space_used WHERE satellite = COALESCE(NEW.satellite, X''));
+		UPDATE satellite_space_used SET pieces = pieces + 1, size = size + COALESCE((SELECT size FROM ttl WHERE id = NEW.id), 0)
+			WHERE satellite = COALESCE(NEW.satellite, X'') AND EXISTS (SELECT 1 FROM ttl WHERE id = NEW.id);
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS blobs_update_usage AFTER UPDATE OF dir, satellite ON blobs BEGIN
+		UPDATE dir_space_used SET pieces = pieces - 1, size = size - COALESCE((SELECT size FROM ttl WHERE id = OLD.id), 0) WHERE dir = OLD.dir;
+		INSERT INTO dir_space_used (dir, pieces, size) SELECT NEW.dir, 0, 0 WHERE NOT EXISTS (SELECT 1 FROM dir_space_used WHERE dir = NEW.dir);
+		UPDATE dir_space_used SET pieces = pieces + 1, size = size + COALESCE((SELECT size FROM ttl WHERE id = NEW.id), 0) WHERE dir = NEW.dir;
+		UPDATE satellite_space_used SET pieces = pieces - 1, size = size - COALESCE((SELECT size FROM ttl WHERE id = OLD.id), 0)
+			WHERE satellite = COALESCE(OLD.satellite, X'') AND EXISTS (SELECT 1 FROM ttl WHERE id = OLD.id);
+		INSERT INTO satellite_space_used (satellite, pieces, size) SELECT COALESCE(NEW.satellite, X''), 0, 0 WHERE NOT EXISTS (SELECT 1 FROM satellite_space_used WHERE satellite = COALESCE(NEW.satellite, X''));
+		UPDATE satellite_space_used SET pieces = pieces + 1, size = size + COALESCE((SELECT size FROM ttl WHERE id = NEW.id), 0)
+			WHERE satellite = COALESCE(NEW.satellite, X'') AND EXISTS (SELECT 1 FROM ttl WHERE id = NEW.id);
+	END;`,
+	`CREATE TRIGGER IF NOT EXISTS blobs_delete_usage AFTER DELETE ON blobs BEGIN
+		UPDATE dir_space_used SET pieces = pieces - 1, size = size - COALESCE((SELECT size FROM ttl WHERE id = OLD.id), 0) WHERE dir = OLD.dir;
+		UPDATE satellite_space_used SET pieces = pieces - 1, size = size - COALESCE((SELECT size FROM ttl WHERE id = OLD.id), 0)
+			WHERE satellite = COALESCE(OLD.satellite, X'') AND EXISTS (SELECT 1 FROM ttl WHERE id = OLD.id);
+		INSERT INTO satellite_space_used (satellite, pieces, size) SELECT X'', 0, 0 WHERE NOT EXISTS (SELECT 1 FROM satellite_space_used WHERE satellite = X'');
+		UPDATE satellite_space_used SET pieces = pieces + 1, size = size + COALESCE((SELECT size FROM ttl WHERE id = OLD.id), 0)
+			WHERE satellite = X'' AND EXISTS (SELECT 1 FROM ttl WHERE id = OLD.id);
+	END;`,
+}
+
 func (db *DB) init() (err error) {
 	tx, err := db.DB.Begin()
 	if err != nil {
@@ -106,21 +190,84 @@ func (db *DB) init() (err error) {
 		return err
 	}
 
+	// space_used counts the total size of the pieces in ttl, so that it
+	// does not need to be summed
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `space_used` (`size` INT);")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO space_used (size) SELECT COALESCE(SUM(size), 0) FROM ttl WHERE NOT EXISTS (SELECT 1 FROM space_used);")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TRIGGER IF NOT EXISTS ttl_insert_space_used AFTER INSERT ON ttl BEGIN UPDATE space_used SET size = size + COALESCE(NEW.size, 0); END;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TRIGGER IF NOT EXISTS ttl_update_space_used AFTER UPDATE OF size ON ttl BEGIN UPDATE space_used SET size = size - COALESCE(OLD.size, 0) + COALESCE(NEW.size, 0); END;")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TRIGGER IF NOT EXISTS ttl_delete_space_used AFTER DELETE ON ttl BEGIN UPDATE space_used SET size = size - COALESCE(OLD.size, 0); END;")
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `bandwidth_agreements` (`satellite` BLOB, `agreement` BLOB, `signature` BLOB);")
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `sent_agreements` (`satellite` BLOB UNIQUE, `count` INT);")
+	if err != nil {
+		return err
+	}
+
 	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS idx_ttl_expires ON ttl (expires);")
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `blobs` (`id` BLOB UNIQUE, `dir` TEXT, `ref` BLOB, `satellite` BLOB, `piece_id` TEXT);")
+	if err != nil {
+		return err
+	}
+
+	// several pieces may reference the same blob, which is deleted with the
+	// last of them
+	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS idx_blobs_ref ON blobs (dir, ref);")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `corrupted` (`satellite` BLOB, `piece_id` TEXT);")
+	if err != nil {
+		return err
+	}
+
+	// dir_space_used and satellite_space_used count the pieces and their
+	// sizes by data directory and by satellite, like space_used
+	for _, query := range usageQueries {
+		_, err = tx.Exec(query)
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `bwusagetbl` (`size` INT(10), `daystartdate` INT(10), `dayenddate` INT(10));")
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `bandwidth_usage` (`satellite` BLOB, `action` INT, `size` INT, `daystartdate` INT, UNIQUE (`satellite`, `action`, `daystartdate`));")
+	if err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
@@ -132,6 +279,13 @@ func (db *DB) init() (err error) {
 	return nil
 }
 
+// SetDirs sets the blob stores of the data directories that the expired
+// pieces are deleted from, when they are opened after the database
+func (db *DB) SetDirs(dirs map[string]storage.Blobs) {
+	defer db.locked()()
+	db.dirs = dirs
+}
+
 // Close the database
 func (db *DB) Close() error {
 	return db.DB.Close()
@@ -146,10 +300,16 @@ func (db *DB) locked() func() {
 func (db *DB) DeleteExpired(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var expired []string
+	var dirs map[string]storage.Blobs
+	var expired []blobLocation
 	err = func() error {
 		defer db.locked()()
 
+		dirs = db.dirs
+		if dirs == nil {
+			return nil
+		}
+
 		tx, err := db.DB.BeginTx(ctx, nil)
 		if err != nil {
 			return err
@@ -158,33 +318,53 @@ func (db *DB) DeleteExpired(ctx context.Context) (err error) {
 
 		now := time.Now().Unix()
 
-		rows, err := tx.Query("SELECT id FROM ttl WHERE 0 < expires AND ? < expires", now)
+		rows, err := tx.Query("SELECT blobs.dir, blobs.ref FROM ttl JOIN blobs ON ttl.id = blobs.id WHERE 0 < ttl.expires AND ttl.expires < ?", now)
 		if err != nil {
 			return err
 		}
 
 		for rows.Next() {
-			var id string
-			if err := rows.Scan(&id); err != nil {
-				return err
+			var blob blobLocation
+			var ref []byte
+			if err := rows.Scan(&blob.dir, &ref); err != nil {
+				return utils.CombineErrors(err, rows.Close())
 			}
-			expired = append(expired, id)
+			copy(blob.ref[:], ref)
+			expired = append(expired, blob)
 		}
 		if err := rows.Close(); err != nil {
 			return err
 		}
 
-		_, err = tx.Exec(`DELETE FROM ttl WHERE 0 < expires AND ? < expires`, now)
+		_, err = tx.Exec(`DELETE FROM blobs WHERE id IN (SELECT id FROM ttl WHERE 0 < expires AND expires < ?)`, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`DELETE FROM ttl WHERE 0 < expires AND expires < ?`, now)
+		if err != nil {
+			return err
+		}
+
+		expired, err = unreferenced(tx, expired)
 		if err != nil {
 			return err
 		}
 
 		return tx.Commit()
 	}()
+	if err != nil {
+		return err
+	}
 
 	var errs []error
-	for _, id := range expired {
-		err := pstore.Delete(id, db.dataPath)
+	for _, blob := range expired {
+		blobs, ok := dirs[blob.dir]
+		if !ok {
+			// the data directory is gone with the blob
+			continue
+		}
+		err := blobs.Delete(ctx, blob.ref)
 		if err != nil {
 			errs = append(errs, err)
 		}
@@ -197,6 +377,67 @@ func (db *DB) DeleteExpired(ctx context.Context) (err error) {
 	return nil
 }
 
+// blobLocation is where the data of a piece is stored
+type blobLocation struct {
+	dir string
+	ref storage.BlobRef
+}
+
+// unreferenced returns the blobs of deleted pieces that no other piece
+// references, so their data can be deleted. It must run in the transaction
+// that deleted the pieces.
+func unreferenced(tx *sql.Tx, blobs []blobLocation) (_ []blobLocation, err error) {
+	stmt, err := tx.Prepare(`SELECT COUNT(*) FROM blobs WHERE dir=? AND ref=?`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, stmt.Close()) }()
+
+	var result []blobLocation
+	seen := make(map[blobLocation]bool)
+	for _, blob := range blobs {
+		if seen[blob] {
+			continue
+		}
+		seen[blob] = true
+
+		var refs int
+		err = stmt.QueryRow(blob.dir, blob.ref[:]).Scan(&refs)
+		if err != nil {
+			return nil, err
+		}
+		if refs == 0 {
+			result = append(result, blob)
+		}
+	}
+	return result, nil
+}
+
+// blobsOf returns the blobs of the pieces with ids
+func blobsOf(tx *sql.Tx, ids []string) (_ []blobLocation, err error) {
+	stmt, err := tx.Prepare(`SELECT dir, ref FROM blobs WHERE id=?`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, stmt.Close()) }()
+
+	var blobs []blobLocation
+	for _, id := range ids {
+		var blob blobLocation
+		var ref []byte
+		err = stmt.QueryRow(id).Scan(&blob.dir, &ref)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		copy(blob.ref[:], ref)
+		blobs = append(blobs, blob)
+	}
+	return blobs, nil
+}
+
 // garbageCollect will periodically run DeleteExpired
 func (db *DB) garbageCollect(ctx context.Context) {
 	for range db.check.C {
@@ -240,6 +481,87 @@ func (db *DB) DeleteBandwidthAllocationBySignature(signature []byte) error {
 	return err
 }
 
+// MarkBandwidthAllocationSent deletes the allocation with signature, which
+// was sent to the satellite, and counts it as sent
+func (db *DB) MarkBandwidthAllocationSent(satelliteID storj.NodeID, signature []byte) error {
+	defer db.locked()()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.Exec(`DELETE FROM bandwidth_agreements WHERE signature=?`, signature)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT OR IGNORE INTO sent_agreements (satellite, count) VALUES (?, 0)`, satelliteID.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE sent_agreements SET count = count + ? WHERE satellite=?`, deleted, satelliteID.Bytes())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CountBandwidthAllocations returns how many bandwidth allocations are
+// pending and were sent, by satellite
+func (db *DB) CountBandwidthAllocations() (counts map[storj.NodeID]*AgreementCounts, err error) {
+	defer db.locked()()
+
+	counts = make(map[storj.NodeID]*AgreementCounts)
+	get := func(satellite []byte) (*AgreementCounts, error) {
+		satelliteID, err := storj.NodeIDFromBytes(satellite)
+		if err != nil {
+			return nil, err
+		}
+		if counts[satelliteID] == nil {
+			counts[satelliteID] = &AgreementCounts{}
+		}
+		return counts[satelliteID], nil
+	}
+
+	for _, query := range []string{
+		`SELECT satellite, COUNT(*), 0 FROM bandwidth_agreements GROUP BY satellite`,
+		`SELECT satellite, 0, count FROM sent_agreements`,
+	} {
+		err = func() (err error) {
+			rows, err := db.DB.Query(query)
+			if err != nil {
+				return err
+			}
+			defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+			for rows.Next() {
+				var satellite []byte
+				var pending, sent int64
+				if err := rows.Scan(&satellite, &pending, &sent); err != nil {
+					return err
+				}
+				c, err := get(satellite)
+				if err != nil {
+					return err
+				}
+				c.Pending += pending
+				c.Sent += sent
+			}
+			return rows.Err()
+		}()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
 // GetBandwidthAllocationBySignature finds allocation info by signature
 func (db *DB) GetBandwidthAllocationBySignature(signature []byte) ([][]byte, error) {
 	defer db.locked()()
@@ -306,7 +628,11 @@ func (db *DB) AddTTL(id string, expiration, size int64) error {
 	defer db.locked()()
 
 	created := time.Now().Unix()
-	_, err := db.DB.Exec("INSERT OR REPLACE INTO ttl (id, created, expires, size) VALUES (?, ?, ?, ?)", id, created, expiration, size)
+	// an upsert rather than a replace, which would not count the size of
+	// the replaced row as deleted
+	_, err := db.DB.Exec(`INSERT INTO ttl (id, created, expires, size) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET created = excluded.created, expires = excluded.expires, size = excluded.size`,
+		id, created, expiration, size)
 	return err
 }
 
@@ -318,23 +644,53 @@ func (db *DB) GetTTLByID(id string) (expiration int64, err error) {
 	return expiration, err
 }
 
-// SumTTLSizes sums the size column on the ttl table
-func (db *DB) SumTTLSizes() (sum int64, err error) {
+// SpaceUsed returns the total size of the pieces, as counted when they
+// are added and deleted
+func (db *DB) SpaceUsed() (size int64, err error) {
 	defer db.locked()()
 
-	var count int
-	rows := db.DB.QueryRow("SELECT COUNT(*) as count FROM ttl")
-	err = rows.Scan(&count)
+	err = db.DB.QueryRow(`SELECT size FROM space_used`).Scan(&size)
+	return size, err
+}
+
+// ReconcileSpaceUsed corrects the counted total size of the pieces to their
+// summed size, and returns by how much the count was off. The counts by
+// data directory and by satellite are corrected too.
+func (db *DB) ReconcileSpaceUsed(ctx context.Context) (drift int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.locked()()
+
+	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	if count == 0 {
-		return 0, nil
+	var counted, summed int64
+	err = tx.QueryRow(`SELECT size FROM space_used`).Scan(&counted)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.QueryRow(`SELECT COALESCE(SUM(size), 0) FROM ttl`).Scan(&summed)
+	if err != nil {
+		return 0, err
+	}
+
+	if counted != summed {
+		_, err = tx.Exec(`UPDATE space_used SET size = ?`, summed)
+		if err != nil {
+			return 0, err
+		}
 	}
 
-	err = db.DB.QueryRow(`SELECT SUM(size) FROM ttl;`).Scan(&sum)
-	return sum, err
+	// the counts by data directory and by satellite are recounted
+	for _, query := range []string{`DELETE FROM dir_space_used`, `DELETE FROM satellite_space_used`, usageQueries[2], usageQueries[3]} {
+		_, err = tx.Exec(query)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return counted - summed, tx.Commit()
 }
 
 // DeleteTTLByID finds the TTL in the database by id and delete it
@@ -348,30 +704,400 @@ func (db *DB) DeleteTTLByID(id string) error {
 	return err
 }
 
-// AddBandwidthUsed adds bandwidth usage into database by date
-func (db *DB) AddBandwidthUsed(size int64) (err error) {
+// AddBlob records that the data of the piece with id is stored in the blob
+// with ref in the data directory dir
+func (db *DB) AddBlob(id, dir string, ref storage.BlobRef) error {
+	defer db.locked()()
+
+	// an upsert rather than a replace, which would not count the replaced
+	// row as deleted
+	_, err := db.DB.Exec(`INSERT INTO blobs (id, dir, ref) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET dir = excluded.dir, ref = excluded.ref, satellite = NULL, piece_id = NULL`,
+		id, dir, ref[:])
+	return err
+}
+
+// GetBlob returns the data directory and the reference of the blob storing
+// the piece with id. It returns sql.ErrNoRows if there is none.
+func (db *DB) GetBlob(id string) (dir string, ref storage.BlobRef, err error) {
+	defer db.locked()()
+
+	var data []byte
+	err = db.DB.QueryRow(`SELECT dir, ref FROM blobs WHERE id=?`, id).Scan(&dir, &data)
+	copy(ref[:], data)
+	return dir, ref, err
+}
+
+// SetBlobSatellite records the satellite of the piece with id, and the ID
+// that the satellite knows the piece by
+func (db *DB) SetBlobSatellite(id string, satelliteID storj.NodeID, pieceID string) error {
+	defer db.locked()()
+
+	_, err := db.DB.Exec(`UPDATE blobs SET satellite=?, piece_id=? WHERE id=?`, satelliteID.Bytes(), pieceID, id)
+	return err
+}
+
+// ListBlobs returns up to limit of the blobs of the pieces with ids after
+// the given one, ordered by id
+func (db *DB) ListBlobs(after string, limit int) (blobs []Blob, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT id, dir, ref FROM blobs WHERE id > ? ORDER BY id LIMIT ?`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	for rows.Next() {
+		var blob Blob
+		var ref []byte
+		if err := rows.Scan(&blob.ID, &blob.Dir, &ref); err != nil {
+			return nil, err
+		}
+		copy(blob.Ref[:], ref)
+		blobs = append(blobs, blob)
+	}
+	return blobs, rows.Err()
+}
+
+// QuarantinePiece deletes the TTL and the blob reference of the corrupted
+// piece with id, and keeps it to be reported to its satellite, in one
+// transaction. It returns whether no other piece references the blob, so
+// that it can be moved out of the data directory.
+func (db *DB) QuarantinePiece(id string) (unreferencedBlob bool, err error) {
+	defer db.locked()()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	blobs, err := blobsOf(tx, []string{id})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO corrupted (satellite, piece_id) SELECT satellite, piece_id FROM blobs WHERE id=? AND satellite IS NOT NULL`, id)
+	if err != nil {
+		return false, err
+	}
+
+	for _, query := range []string{`DELETE FROM ttl WHERE id=?`, `DELETE FROM blobs WHERE id=?`} {
+		err = deleteIDs(tx, query, []string{id})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	blobs, err = unreferenced(tx, blobs)
+	if err != nil {
+		return false, err
+	}
+	return len(blobs) > 0, tx.Commit()
+}
+
+// GetCorrupted returns the IDs of the corrupted pieces to report, by
+// satellite
+func (db *DB) GetCorrupted() (corrupted map[storj.NodeID][]string, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT satellite, piece_id FROM corrupted ORDER BY satellite`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	corrupted = make(map[storj.NodeID][]string)
+	for rows.Next() {
+		var satellite []byte
+		var pieceID string
+		if err := rows.Scan(&satellite, &pieceID); err != nil {
+			return nil, err
+		}
+		satelliteID, err := storj.NodeIDFromBytes(satellite)
+		if err != nil {
+			return nil, err
+		}
+		corrupted[satelliteID] = append(corrupted[satelliteID], pieceID)
+	}
+	return corrupted, rows.Err()
+}
+
+// DeleteCorrupted deletes the corrupted pieces that were reported to the
+// satellite
+func (db *DB) DeleteCorrupted(satelliteID storj.NodeID, pieceIDs []string) error {
+	defer db.locked()()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`DELETE FROM corrupted WHERE satellite=? AND piece_id=?`)
+	if err != nil {
+		return err
+	}
+	for _, pieceID := range pieceIDs {
+		_, err = stmt.Exec(satelliteID.Bytes(), pieceID)
+		if err != nil {
+			return utils.CombineErrors(err, stmt.Close())
+		}
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SpaceUsedByDir returns the total size of the pieces in each data
+// directory, as counted when they are added and deleted
+func (db *DB) SpaceUsedByDir() (sizes map[string]int64, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT dir, size FROM dir_space_used WHERE pieces > 0`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	sizes = make(map[string]int64)
+	for rows.Next() {
+		var dir string
+		var size int64
+		if err := rows.Scan(&dir, &size); err != nil {
+			return nil, err
+		}
+		sizes[dir] = size
+	}
+	return sizes, rows.Err()
+}
+
+// SpaceUsedBySatellite returns how many pieces of each satellite are stored
+// and their total size, as counted when they are added and deleted. The
+// pieces whose satellite is unknown are counted for the zero ID.
+func (db *DB) SpaceUsedBySatellite() (usage map[storj.NodeID]*SatelliteUsage, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT satellite, pieces, size FROM satellite_space_used WHERE pieces > 0`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	usage = make(map[storj.NodeID]*SatelliteUsage)
+	for rows.Next() {
+		var satellite []byte
+		u := &SatelliteUsage{}
+		if err := rows.Scan(&satellite, &u.Pieces, &u.Size); err != nil {
+			return nil, err
+		}
+		var satelliteID storj.NodeID
+		if len(satellite) > 0 {
+			satelliteID, err = storj.NodeIDFromBytes(satellite)
+			if err != nil {
+				return nil, err
+			}
+		}
+		usage[satelliteID] = u
+	}
+	return usage, rows.Err()
+}
+
+// Dirs returns the data directories that pieces are stored in
+func (db *DB) Dirs() (dirs []string, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT dir FROM dir_space_used WHERE pieces > 0`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	for rows.Next() {
+		var dir string
+		if err := rows.Scan(&dir); err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, rows.Err()
+}
+
+// DeleteDirPieces deletes the TTLs and the blob references of all the
+// pieces in the data directory dir, when it is lost, and returns their ids.
+// The pieces are reported to their satellites like the corrupted ones.
+func (db *DB) DeleteDirPieces(dir string) (ids []string, err error) {
+	defer db.locked()()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.Query(`SELECT id FROM blobs WHERE dir=?`, dir)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, utils.CombineErrors(err, rows.Close())
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO corrupted (satellite, piece_id) SELECT satellite, piece_id FROM blobs WHERE dir=? AND satellite IS NOT NULL`, dir)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`DELETE FROM ttl WHERE id IN (SELECT id FROM blobs WHERE dir=?)`, dir)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`DELETE FROM blobs WHERE dir=?`, dir)
+	if err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+// DeletePieces deletes the TTLs and the blob references of several pieces
+// in one transaction. It returns the blobs of the pieces that no other piece
+// references, whose data is to be deleted.
+func (db *DB) DeletePieces(ids []string) (_ []Blob, err error) {
+	defer db.locked()()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	blobs, err := blobsOf(tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range []string{`DELETE FROM ttl WHERE id=?`, `DELETE FROM blobs WHERE id=?`} {
+		err = deleteIDs(tx, query, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	blobs, err = unreferenced(tx, blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Blob
+	for _, blob := range blobs {
+		result = append(result, Blob{Dir: blob.dir, Ref: blob.ref})
+	}
+	return result, tx.Commit()
+}
+
+// deleteIDs runs the delete query for each of ids
+func deleteIDs(tx *sql.Tx, query string, ids []string) (err error) {
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, stmt.Close()) }()
+
+	for _, id := range ids {
+		_, err = stmt.Exec(id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddBandwidthUsed adds bandwidth usage into database by date, and by the
+// satellite paying for it and its action
+func (db *DB) AddBandwidthUsed(satelliteID storj.NodeID, action pb.PayerBandwidthAllocation_Action, size int64) (err error) {
 	defer db.locked()()
 
 	t := time.Now()
 	daystartunixtime := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Unix()
 	dayendunixtime := time.Date(t.Year(), t.Month(), t.Day(), 24, 0, 0, 0, t.Location()).Unix()
 
+	tx, err := db.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
 	var getSize int64
-	if (t.Unix() >= daystartunixtime) && (t.Unix() <= dayendunixtime) {
-		err = db.DB.QueryRow(`SELECT size FROM bwusagetbl WHERE daystartdate <= ? AND ? <= dayenddate`, t.Unix(), t.Unix()).Scan(&getSize)
-		switch {
-		case err == sql.ErrNoRows:
-			_, err = db.DB.Exec("INSERT INTO bwusagetbl (size, daystartdate, dayenddate) VALUES (?, ?, ?)", size, daystartunixtime, dayendunixtime)
-			return err
-		case err != nil:
-			return err
-		default:
-			getSize = size + getSize
-			_, err = db.DB.Exec("UPDATE bwusagetbl SET size = ? WHERE daystartdate = ?", getSize, daystartunixtime)
-			return err
+	err = tx.QueryRow(`SELECT size FROM bwusagetbl WHERE daystartdate <= ? AND ? <= dayenddate`, t.Unix(), t.Unix()).Scan(&getSize)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = tx.Exec("INSERT INTO bwusagetbl (size, daystartdate, dayenddate) VALUES (?, ?, ?)", size, daystartunixtime, dayendunixtime)
+	case err != nil:
+		return err
+	default:
+		getSize = size + getSize
+		_, err = tx.Exec("UPDATE bwusagetbl SET size = ? WHERE daystartdate = ?", getSize, daystartunixtime)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT OR IGNORE INTO bandwidth_usage (satellite, action, size, daystartdate) VALUES (?, ?, 0, ?)`, satelliteID.Bytes(), action, daystartunixtime)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE bandwidth_usage SET size = size + ? WHERE satellite=? AND action=? AND daystartdate=?`, size, satelliteID.Bytes(), action, daystartunixtime)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetBandwidthUsedBySatellite sums the bandwidth used by each satellite and
+// action in the days from startdate to enddate
+func (db *DB) GetBandwidthUsedBySatellite(startdate time.Time, enddate time.Time) (usage map[storj.NodeID]*BandwidthUsage, err error) {
+	defer db.locked()()
+
+	startTimeUnix := time.Date(startdate.Year(), startdate.Month(), startdate.Day(), 0, 0, 0, 0, startdate.Location()).Unix()
+	endTimeUnix := time.Date(enddate.Year(), enddate.Month(), enddate.Day(), 0, 0, 0, 0, enddate.Location()).Unix()
+
+	rows, err := db.DB.Query(`SELECT satellite, action, SUM(size) FROM bandwidth_usage WHERE daystartdate BETWEEN ? AND ? GROUP BY satellite, action`, startTimeUnix, endTimeUnix)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	usage = make(map[storj.NodeID]*BandwidthUsage)
+	for rows.Next() {
+		var satellite []byte
+		var action pb.PayerBandwidthAllocation_Action
+		var size int64
+		if err := rows.Scan(&satellite, &action, &size); err != nil {
+			return nil, err
+		}
+		satelliteID, err := storj.NodeIDFromBytes(satellite)
+		if err != nil {
+			return nil, err
+		}
+		if usage[satelliteID] == nil {
+			usage[satelliteID] = &BandwidthUsage{}
+		}
+		switch action {
+		case pb.PayerBandwidthAllocation_PUT:
+			usage[satelliteID].Put += size
+		case pb.PayerBandwidthAllocation_GET:
+			usage[satelliteID].Get += size
 		}
 	}
-	return err
+	return usage, rows.Err()
 }
 
 // GetBandwidthUsedByDay finds the so far bw used by day and return it
@@ -406,6 +1132,6 @@ func (db *DB) GetTotalBandwidthBetween(startdate time.Time, enddate time.Time) (
 		return 0, nil
 	}
 
-	err = db.DB.QueryRow(`SELECT SUM(size) FROM bwusagetbl WHERE daystartdate BETWEEN ? AND ?`, startTimeUnix, endTimeUnix).Scan(&totalbwusage)
+	err = db.DB.QueryRow(`SELECT COALESCE(SUM(size), 0) FROM bwusagetbl WHERE daystartdate BETWEEN ? AND ?`, startTimeUnix, endTimeUnix).Scan(&totalbwusage)
 	return totalbwusage, err
 }
@@ -6,9 +6,11 @@ package psdb
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
@@ -19,6 +21,8 @@ import (
 	"storj.io/storj/internal/teststorj"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
 )
 
 var ctx = context.Background()
@@ -32,7 +36,7 @@ func newDB(t testing.TB) (*DB, func()) {
 	}
 	dbpath := filepath.Join(tmpdir, "psdb.db")
 
-	db, err := Open(ctx, "", dbpath)
+	db, err := Open(ctx, nil, dbpath)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -51,7 +55,7 @@ func newDB(t testing.TB) (*DB, func()) {
 }
 
 func TestNewInmemory(t *testing.T) {
-	db, err := OpenInMemory(context.Background(), "")
+	db, err := OpenInMemory(context.Background(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -121,6 +125,194 @@ func TestHappyPath(t *testing.T) {
 		}
 	})
 
+	t.Run("Delete Pieces", func(t *testing.T) {
+		ids := make([]string, len(tests))
+		for i, ttl := range tests {
+			ids[i] = ttl.ID
+			err := db.AddTTL(ttl.ID, ttl.Expiration, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = db.AddBlob(ttl.ID, "data", storage.BlobRef{byte(i)})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for i, id := range ids {
+			dir, ref, err := db.GetBlob(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dir != "data" || ref != (storage.BlobRef{byte(i)}) {
+				t.Fatalf("expected blob %d in data got %x in %s", i, ref, dir)
+			}
+		}
+
+		blobs, err := db.DeletePieces(ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blobs) != len(ids) {
+			t.Fatalf("expected %d blobs to delete got %v", len(ids), blobs)
+		}
+
+		for _, id := range ids {
+			_, _, err := db.GetBlob(id)
+			if err != sql.ErrNoRows {
+				t.Fatalf("expected no rows got %v", err)
+			}
+		}
+	})
+
+	t.Run("Delete Shared Blob", func(t *testing.T) {
+		shared := storage.BlobRef{42}
+		for _, id := range []string{"shared-a", "shared-b"} {
+			if err := db.AddBlob(id, "data", shared); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the blob is still referenced by the other piece
+		blobs, err := db.DeletePieces([]string{"shared-a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blobs) != 0 {
+			t.Fatalf("expected no blobs to delete got %v", blobs)
+		}
+		unreferenced, err := db.QuarantinePiece("shared-b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !unreferenced {
+			t.Fatal("expected the blob of the last piece to be unreferenced")
+		}
+
+		for _, id := range []string{"shared-a", "shared-b"} {
+			if err := db.AddBlob(id, "data", shared); err != nil {
+				t.Fatal(err)
+			}
+		}
+		blobs, err = db.DeletePieces([]string{"shared-a", "shared-b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blobs) != 1 || blobs[0].Dir != "data" || blobs[0].Ref != shared {
+			t.Fatalf("expected the shared blob to delete once got %v", blobs)
+		}
+	})
+
+	t.Run("Delete Dir Pieces", func(t *testing.T) {
+		dirs := []string{"disk1", "disk2"}
+		for i, ttl := range tests {
+			err := db.AddTTL(ttl.ID, ttl.Expiration, int64(i+1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = db.AddBlob(ttl.ID, dirs[i%2], storage.BlobRef{byte(i)})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sums, err := db.SpaceUsedByDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var total int64
+		for _, sum := range sums {
+			total += sum
+		}
+		if len(sums) != len(dirs) || total != int64(len(tests)*(len(tests)+1)/2) {
+			t.Fatalf("unexpected sizes %v", sums)
+		}
+
+		lost, err := db.DeleteDirPieces("disk1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lost) != (len(tests)+1)/2 {
+			t.Fatalf("expected %d lost pieces got %v", (len(tests)+1)/2, lost)
+		}
+		for _, id := range lost {
+			if _, err := db.GetTTLByID(id); err != sql.ErrNoRows {
+				t.Fatalf("expected no ttl got %v", err)
+			}
+		}
+
+		left, err := db.Dirs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(left) != 1 || left[0] != "disk2" {
+			t.Fatalf("expected only disk2 got %v", left)
+		}
+
+		_, err = db.DeleteDirPieces("disk2")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Quarantine", func(t *testing.T) {
+		satelliteID := teststorj.NodeIDFromString("satellite")
+		for i, id := range []string{"a", "b", "c"} {
+			if err := db.AddTTL(id, 0, 5); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.AddBlob(id, "data", storage.BlobRef{byte(i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// the satellite of c is unknown
+		for _, id := range []string{"a", "b"} {
+			if err := db.SetBlobSatellite(id, satelliteID, "piece-"+id); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		blobs, err := db.ListBlobs("a", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blobs) != 2 || blobs[0] != (Blob{"b", "data", storage.BlobRef{1}}) || blobs[1].ID != "c" {
+			t.Fatalf("unexpected blobs %v", blobs)
+		}
+
+		for _, id := range []string{"b", "c"} {
+			if unreferenced, err := db.QuarantinePiece(id); err != nil || !unreferenced {
+				t.Fatalf("expected unreferenced blob got %v, %v", unreferenced, err)
+			}
+			if _, _, err := db.GetBlob(id); err != sql.ErrNoRows {
+				t.Fatalf("expected no rows got %v", err)
+			}
+		}
+
+		corrupted, err := db.GetCorrupted()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(corrupted) != 1 || len(corrupted[satelliteID]) != 1 || corrupted[satelliteID][0] != "piece-b" {
+			t.Fatalf("unexpected corrupted pieces %v", corrupted)
+		}
+
+		if err := db.DeleteCorrupted(satelliteID, corrupted[satelliteID]); err != nil {
+			t.Fatal(err)
+		}
+		corrupted, err = db.GetCorrupted()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(corrupted) != 0 {
+			t.Fatalf("unexpected corrupted pieces %v", corrupted)
+		}
+
+		if _, err := db.DeletePieces([]string{"a"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
 	t.Run("Get Deleted", func(t *testing.T) {
 		for P := 0; P < concurrency; P++ {
 			t.Run("#"+strconv.Itoa(P), func(t *testing.T) {
@@ -231,6 +423,207 @@ func TestHappyPath(t *testing.T) {
 	})
 }
 
+func TestDeleteExpired(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "storj-psdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpdir) }()
+
+	blobs, err := filestore.NewAt(filepath.Join(tmpdir, "blobs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(ctx, map[string]storage.Blobs{"blobs": blobs}, filepath.Join(tmpdir, "psdb.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	refs := make(map[string]storage.BlobRef)
+	for id, expiration := range map[string]int64{
+		"expired": time.Now().Add(-time.Hour).Unix(),
+		"live":    time.Now().Add(time.Hour).Unix(),
+	} {
+		refs[id], err = blobs.Store(ctx, bytes.NewReader([]byte(id)), int64(len(id)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.AddTTL(id, expiration, int64(len(id))); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.AddBlob(id, "blobs", refs[id]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.DeleteExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := db.GetBlob("expired"); err != sql.ErrNoRows {
+		t.Fatalf("expected no rows got %v", err)
+	}
+	if _, err := blobs.Load(ctx, refs["expired"]); err == nil {
+		t.Fatal("expected the expired blob to be deleted")
+	}
+
+	if _, _, err := db.GetBlob("live"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := blobs.Load(ctx, refs["live"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpaceUsed(t *testing.T) {
+	db, cleanup := newDB(t)
+	defer cleanup()
+
+	checkSpaceUsed := func(expected int64) {
+		t.Helper()
+		used, err := db.SpaceUsed()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if used != expected {
+			t.Fatalf("expected %d bytes used got %d", expected, used)
+		}
+	}
+	checkSpaceUsed(0)
+
+	ids := []string{"11111111111111111111", "22222222222222222222", "33333333333333333333"}
+	for _, id := range ids {
+		if err := db.AddTTL(id, 0, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkSpaceUsed(30)
+
+	// a piece stored again replaces its size
+	if err := db.AddTTL(ids[0], 0, 5); err != nil {
+		t.Fatal(err)
+	}
+	checkSpaceUsed(25)
+
+	if err := db.DeleteTTLByID(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DeletePieces(ids[2:]); err != nil {
+		t.Fatal(err)
+	}
+	checkSpaceUsed(5)
+
+	if _, err := db.DB.Exec(`UPDATE space_used SET size = 100`); err != nil {
+		t.Fatal(err)
+	}
+	drift, err := db.ReconcileSpaceUsed(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift != 95 {
+		t.Fatalf("expected a drift of 95 bytes got %d", drift)
+	}
+	checkSpaceUsed(5)
+
+	// the space used by the pieces stored before it was counted is summed
+	if _, err := db.DB.Exec(`DROP TABLE space_used`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.init(); err != nil {
+		t.Fatal(err)
+	}
+	checkSpaceUsed(5)
+}
+
+func TestSpaceUsedByDirAndSatellite(t *testing.T) {
+	db, cleanup := newDB(t)
+	defer cleanup()
+
+	satelliteID := teststorj.NodeIDFromString("AB")
+	checkUsed := func(dirs map[string]int64, pieces map[storj.NodeID]int64, sizes map[storj.NodeID]int64) {
+		t.Helper()
+		byDir, err := db.SpaceUsedByDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(byDir, dirs) {
+			t.Fatalf("expected sizes by dir %v got %v", dirs, byDir)
+		}
+		bySatellite, err := db.SpaceUsedBySatellite()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bySatellite) != len(pieces) {
+			t.Fatalf("expected usage of %d satellites got %d", len(pieces), len(bySatellite))
+		}
+		for id, usage := range bySatellite {
+			if usage.Pieces != pieces[id] || usage.Size != sizes[id] {
+				t.Fatalf("expected %d pieces of %d bytes for %s got %+v", pieces[id], sizes[id], id, usage)
+			}
+		}
+	}
+	checkUsed(map[string]int64{}, nil, nil)
+
+	ids := []string{"11111111111111111111", "22222222222222222222", "33333333333333333333"}
+	for i, id := range ids {
+		if err := db.AddTTL(id, 0, 10); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.AddBlob(id, "disk1", storage.BlobRef{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the pieces whose satellite is unknown are counted for the zero ID
+	checkUsed(map[string]int64{"disk1": 30}, map[storj.NodeID]int64{{}: 3}, map[storj.NodeID]int64{{}: 30})
+
+	if err := db.SetBlobSatellite(ids[0], satelliteID, "piece"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddTTL(ids[0], 0, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddBlob(ids[1], "disk2", storage.BlobRef{1}); err != nil {
+		t.Fatal(err)
+	}
+	checkUsed(map[string]int64{"disk1": 15, "disk2": 10},
+		map[storj.NodeID]int64{{}: 2, satelliteID: 1}, map[storj.NodeID]int64{{}: 20, satelliteID: 5})
+
+	if _, err := db.DeletePieces(ids[2:]); err != nil {
+		t.Fatal(err)
+	}
+	checkUsed(map[string]int64{"disk1": 5, "disk2": 10},
+		map[storj.NodeID]int64{{}: 1, satelliteID: 1}, map[storj.NodeID]int64{{}: 10, satelliteID: 5})
+
+	if _, err := db.DB.Exec(`UPDATE dir_space_used SET size = 100`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DB.Exec(`DELETE FROM satellite_space_used`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ReconcileSpaceUsed(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkUsed(map[string]int64{"disk1": 5, "disk2": 10},
+		map[storj.NodeID]int64{{}: 1, satelliteID: 1}, map[storj.NodeID]int64{{}: 10, satelliteID: 5})
+
+	// the pieces stored before they were counted are counted
+	for _, table := range []string{"dir_space_used", "satellite_space_used"} {
+		if _, err := db.DB.Exec(`DROP TABLE ` + table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.init(); err != nil {
+		t.Fatal(err)
+	}
+	checkUsed(map[string]int64{"disk1": 5, "disk2": 10},
+		map[storj.NodeID]int64{{}: 1, satelliteID: 1}, map[storj.NodeID]int64{{}: 10, satelliteID: 5})
+}
+
 func TestBandwidthUsage(t *testing.T) {
 	db, cleanup := newDB(t)
 	defer cleanup()
@@ -243,6 +636,7 @@ func TestBandwidthUsage(t *testing.T) {
 	bwtests := []BWUSAGE{
 		{size: 1000, timenow: time.Now()},
 	}
+	satelliteID := teststorj.NodeIDFromString("AB")
 
 	var bwTotal int64
 	t.Run("AddBandwidthUsed", func(t *testing.T) {
@@ -251,7 +645,7 @@ func TestBandwidthUsage(t *testing.T) {
 			t.Run("#"+strconv.Itoa(P), func(t *testing.T) {
 				t.Parallel()
 				for _, bw := range bwtests {
-					err := db.AddBandwidthUsed(bw.size)
+					err := db.AddBandwidthUsed(satelliteID, pb.PayerBandwidthAllocation_PUT, bw.size)
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -293,6 +687,33 @@ func TestBandwidthUsage(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("GetBandwidthUsedBySatellite", func(t *testing.T) {
+		if err := db.AddBandwidthUsed(satelliteID, pb.PayerBandwidthAllocation_GET, 10); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, bw := range bwtests {
+			usage, err := db.GetBandwidthUsedBySatellite(bw.timenow, bw.timenow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(usage) != 1 || usage[satelliteID] == nil {
+				t.Fatalf("expected usage of %s got %v", satelliteID, usage)
+			}
+			if *usage[satelliteID] != (BandwidthUsage{Put: bwTotal, Get: 10}) {
+				t.Fatalf("expected %d PUT and 10 GET got %+v", bwTotal, *usage[satelliteID])
+			}
+
+			usage, err = db.GetBandwidthUsedBySatellite(bw.timenow.AddDate(0, 0, -2), bw.timenow.AddDate(0, 0, -1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(usage) != 0 {
+				t.Fatalf("expected no usage got %v", usage)
+			}
+		}
+	})
 }
 
 func BenchmarkWriteBandwidthAllocation(b *testing.B) {
@@ -4,6 +4,8 @@
 package psserver
 
 import (
+	"bytes"
+
 	"github.com/gogo/protobuf/proto"
 
 	"storj.io/storj/pkg/pb"
@@ -36,6 +38,10 @@ type StreamReader struct {
 	src                 *utils.ReaderSource
 	bandwidthAllocation *pb.RenterBandwidthAllocation
 	currentTotal        int64
+	// the signature of the payer allocation that was verified last
+	payerSignature []byte
+	// the disk space and bandwidth reserved for the data received
+	reserved int64
 }
 
 // NewStreamReader returns a new StreamReader for Server.Store
@@ -62,6 +68,14 @@ func NewStreamReader(s *Server, stream pb.PieceStoreRoutes_StoreServer) *StreamR
 				return nil, err
 			}
 
+			payer := deserializedData.GetPayerAllocation()
+			if sr.payerSignature == nil || !bytes.Equal(payer.GetSignature(), sr.payerSignature) {
+				if err = s.trust.verifyPayerAllocation(stream.Context(), payer); err != nil {
+					return nil, err
+				}
+				sr.payerSignature = payer.GetSignature()
+			}
+
 			// Update bandwidthallocation to be stored
 			if deserializedData.GetTotal() > sr.currentTotal {
 				sr.bandwidthAllocation = ba
@@ -69,7 +83,18 @@ func NewStreamReader(s *Server, stream pb.PieceStoreRoutes_StoreServer) *StreamR
 			}
 		}
 
-		return pd.GetContent(), nil
+		// only the satellites that are trusted pay for storing data
+		if s.trust != nil && sr.payerSignature == nil && len(pd.GetContent()) > 0 {
+			return nil, ErrUntrustedSatellite.New("data sent without bandwidth allocation")
+		}
+
+		content := pd.GetContent()
+		if err = s.allocation.reserve(int64(len(content))); err != nil {
+			return nil, err
+		}
+		sr.reserved += int64(len(content))
+
+		return content, nil
 	})
 
 	return sr
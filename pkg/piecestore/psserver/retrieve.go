@@ -4,11 +4,11 @@
 package psserver
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"sync/atomic"
 
 	"github.com/gogo/protobuf/proto"
@@ -18,6 +18,7 @@ import (
 	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/utils"
 )
 
@@ -55,54 +56,53 @@ func (s *Server) Retrieve(stream pb.PieceStoreRoutes_RetrieveServer) (err error)
 		return err
 	}
 
-	// Get path to data being retrieved
-	path, err := pstore.PathByID(id, s.DataDir)
-	if err != nil {
-		return err
-	}
-
-	// Verify that the path exists
-	fileInfo, err := os.Stat(path)
+	piece, err := s.openPiece(ctx, id)
 	if err != nil {
 		return RetrieveError.Wrap(err)
 	}
+	defer utils.LogClose(piece)
 
 	// Read the size specified
 	totalToRead := pd.GetPieceSize()
-	fileSize := fileInfo.Size()
+	fileSize := piece.Size()
+
+	if pd.GetOffset() >= fileSize || pd.GetOffset() < 0 {
+		return pstore.ArgError.New("invalid offset: %v", pd.GetOffset())
+	}
 
 	// Read the entire file if specified -1 but make sure we do it from the correct offset
 	if pd.GetPieceSize() <= -1 || totalToRead+pd.GetOffset() > fileSize {
 		totalToRead = fileSize - pd.GetOffset()
 	}
 
-	retrieved, allocated, err := s.retrieveData(ctx, stream, id, pd.GetOffset(), totalToRead)
+	if err := s.allocation.checkAvailable(false); err != nil {
+		return allocationStatus(err)
+	}
+
+	data := io.NewSectionReader(piece, pd.GetOffset(), totalToRead)
+	retrieved, allocated, err := s.retrieveData(ctx, stream, data, totalToRead)
 	if err != nil {
-		return err
+		return allocationStatus(err)
 	}
 
 	zap.S().Infof("Successfully retrieved %s: Allocated: %v, Retrieved: %v\n", pd.GetId(), allocated, retrieved)
 	return nil
 }
 
-func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_RetrieveServer, id string, offset, length int64) (retrieved, allocated int64, err error) {
+func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_RetrieveServer, data io.Reader, length int64) (retrieved, allocated int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	storeFile, err := pstore.RetrieveReader(ctx, id, offset, length, s.DataDir)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	defer utils.LogClose(storeFile)
-
 	writer := NewStreamWriter(s, stream)
 	allocationTracking := sync2.NewThrottle()
 	totalAllocated := int64(0)
+	// the satellite paying for the bandwidth, from the first allocation
+	var satellite atomic.Value
 
 	// Bandwidth Allocation recv loop
 	go func() {
 		var lastTotal int64
 		var lastAllocation *pb.RenterBandwidthAllocation
+		var lastPayerSignature []byte
 		defer func() {
 			if lastAllocation == nil {
 				return
@@ -133,6 +133,24 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 				return
 			}
 
+			payer := allocData.GetPayerAllocation()
+			if lastAllocation == nil || !bytes.Equal(payer.GetSignature(), lastPayerSignature) {
+				if err = s.trust.verifyPayerAllocation(ctx, payer); err != nil {
+					allocationTracking.Fail(err)
+					return
+				}
+				lastPayerSignature = payer.GetSignature()
+			}
+
+			if lastAllocation == nil {
+				satelliteID, err := getSatelliteID(alloc)
+				if err != nil {
+					allocationTracking.Fail(err)
+					return
+				}
+				satellite.Store(satelliteID)
+			}
+
 			// TODO: break when lastTotal >= allocData.GetPayer_allocation().GetData().GetMax_size()
 
 			if lastTotal > allocData.GetTotal() {
@@ -162,14 +180,20 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 			break
 		}
 
+		if err := s.allocation.useBandwidth(nextMessageSize); err != nil {
+			allocationTracking.Fail(err)
+			break
+		}
+
 		used += nextMessageSize
-		n, err := io.CopyN(writer, storeFile, nextMessageSize)
+		n, err := io.CopyN(writer, data, nextMessageSize)
 		// correct errors when needed
 		if n != nextMessageSize {
 			if pErr := allocationTracking.Produce(nextMessageSize - n); pErr != nil {
 				break
 			}
 			used -= nextMessageSize - n
+			s.allocation.refundBandwidth(nextMessageSize - n)
 		}
 		// break on error
 		if err != nil {
@@ -179,7 +203,8 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 	}
 
 	// write to bandwidth usage table
-	if err = s.DB.AddBandwidthUsed(used); err != nil {
+	satelliteID, _ := satellite.Load().(storj.NodeID)
+	if err = s.DB.AddBandwidthUsed(satelliteID, pb.PayerBandwidthAllocation_GET, used); err != nil {
 		return retrieved, allocated, StoreError.New("failed to write bandwidth info to database: %v", err)
 	}
 
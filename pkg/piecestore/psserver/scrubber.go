@@ -0,0 +1,110 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/pkg/piecestore/psserver/psdb"
+	"storj.io/storj/storage/filestore"
+)
+
+// scrubBatchSize is how many pieces are looked up at a time for scrubbing
+const scrubBatchSize = 100
+
+// scrub rereads the stored pieces every interval, at most rate bytes per
+// second, until ctx is canceled. The corrupted pieces are quarantined and
+// reported to their satellites, so that their segments are repaired before
+// an audit finds them.
+func (s *Server) scrub(ctx context.Context, rate int64, interval time.Duration) {
+	throttle := sync2.NewThrottle()
+	go produceEverySecond(ctx, throttle, rate)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.scrubPass(ctx, throttle)
+		if err != nil && ctx.Err() == nil {
+			zap.S().Errorf("failed scrubbing pieces: %+v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// produceEverySecond adds rate to throttle every second, until ctx is
+// canceled, without letting more than a second of it pile up
+func produceEverySecond(ctx context.Context, throttle *sync2.Throttle, rate int64) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	go func() {
+		<-ctx.Done()
+		throttle.Fail(ctx.Err())
+	}()
+
+	for {
+		if err := throttle.ProduceAndWaitUntilBelow(rate, rate); err != nil {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scrubPass rereads all the stored pieces once
+func (s *Server) scrubPass(ctx context.Context, throttle *sync2.Throttle) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var after string
+	for {
+		blobs, err := s.DB.ListBlobs(after, scrubBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, blob := range blobs {
+			dir := s.dir(blob.Dir)
+			if dir == nil {
+				continue
+			}
+
+			err := dir.Blobs.Verify(ctx, blob.Ref, throttle)
+			if filestore.ErrCorrupted.Has(err) {
+				zap.S().Warnf("Piece %s is corrupted: %v", blob.ID, err)
+				err = s.quarantine(ctx, dir, blob)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(blobs) < scrubBatchSize {
+			return nil
+		}
+		after = blobs[len(blobs)-1].ID
+	}
+}
+
+// quarantine moves the data of the corrupted piece of blob out of dir and
+// keeps the piece to be reported to its satellite
+func (s *Server) quarantine(ctx context.Context, dir *DataDir, blob psdb.Blob) error {
+	unreferenced, err := s.DB.QuarantinePiece(blob.ID)
+	if err != nil || !unreferenced {
+		return err
+	}
+	return dir.Blobs.Quarantine(ctx, blob.Ref)
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/sync2"
+	"storj.io/storj/internal/teststorj"
+)
+
+func TestScrubPass(t *testing.T) {
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+
+	satelliteID := teststorj.NodeIDFromString("satellite")
+	for _, id := range []string{"11111111111111111111", "22222222222222222222"} {
+		require.NoError(t, writePiece(s, id))
+		require.NoError(t, s.DB.AddTTL(id, 0, 5))
+		require.NoError(t, s.DB.SetBlobSatellite(id, satelliteID, "piece-"+id))
+	}
+
+	throttle := sync2.NewThrottle()
+	require.NoError(t, throttle.Produce(1<<20))
+
+	// nothing is corrupted yet
+	require.NoError(t, s.scrubPass(ctx, throttle))
+	corrupted, err := s.DB.GetCorrupted()
+	require.NoError(t, err)
+	assert.Empty(t, corrupted)
+
+	// flip a bit of the data of the second piece
+	dir, ref, err := s.DB.GetBlob("22222222222222222222")
+	require.NoError(t, err)
+	path := filepath.Join(dir, hex.EncodeToString(ref[:])[:2], hex.EncodeToString(ref[:])[2:])
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 1
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	require.NoError(t, s.scrubPass(ctx, throttle))
+
+	piece, err := s.openPiece(ctx, "11111111111111111111")
+	require.NoError(t, err)
+	assert.NoError(t, piece.Close())
+
+	_, err = s.openPiece(ctx, "22222222222222222222")
+	assert.True(t, ErrPieceNotFound.Has(err))
+
+	corrupted, err = s.DB.GetCorrupted()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"piece-22222222222222222222"}, corrupted[satelliteID])
+}
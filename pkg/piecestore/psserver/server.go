@@ -8,16 +8,15 @@ import (
 	"crypto/ecdsa"
 	"crypto/hmac"
 	"crypto/sha512"
-	"errors"
 	"log"
-	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/gtank/cryptopasta"
 	"github.com/mr-tron/base58/base58"
-	"github.com/shirou/gopsutil/disk"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
@@ -26,10 +25,11 @@ import (
 	"storj.io/storj/pkg/auth"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/peertls"
-	"storj.io/storj/pkg/piecestore"
 	as "storj.io/storj/pkg/piecestore/psserver/agreementsender"
 	"storj.io/storj/pkg/piecestore/psserver/psdb"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
 )
 
 var (
@@ -39,11 +39,20 @@ var (
 	ServerError = errs.Class("PSServer error")
 )
 
+// maxDeleteManyPieces is how many pieces one DeleteMany request can delete
+const maxDeleteManyPieces = 1000
+
 // Config contains everything necessary for a server
 type Config struct {
-	Path               string `help:"path to store data in" default:"$CONFDIR"`
-	AllocatedDiskSpace int64  `help:"total allocated disk space, default(1GB)" default:"1073741824"`
-	AllocatedBandwidth int64  `help:"total allocated bandwidth, default(100GB)" default:"107374182400"`
+	Path               string        `help:"path to store data in" default:"$CONFDIR"`
+	AllocatedDiskSpace int64         `help:"total allocated disk space, default(1GB)" default:"1073741824"`
+	AllocatedBandwidth int64         `help:"total allocated bandwidth, default(100GB)" default:"107374182400"`
+	DataDirs           string        `help:"a comma-separated list of <path>=<allocated bytes> of additional directories to store data in, such as on other disks" default:""`
+	LostDataDirs       string        `help:"a comma-separated list of data directories whose disks failed, so that their pieces are reported to the satellites as lost" default:""`
+	ScrubRate          int64         `help:"bytes per second to reread stored pieces at to find corrupted ones, 0 to disable" default:"1048576"`
+	ScrubInterval      time.Duration `help:"how often to start rereading all stored pieces" default:"168h"`
+	DashboardAddr      string        `help:"loopback address to serve the operator dashboard on, empty to disable" default:"127.0.0.1:7778"`
+	TrustedSatellites  string        `help:"a comma-separated list of <satellite>@<address> of the satellites to store data for, where <satellite> is the node ID or the path to the certificate chain of the satellite; empty to store data for any satellite" default:""`
 }
 
 // Run implements provider.Responsibility
@@ -52,15 +61,30 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 
 	ctx, cancel := context.WithCancel(ctx)
 
-	s, err := Initialize(ctx, c, server.Identity().Key)
+	s, err := Initialize(ctx, c, server.Identity())
 	if err != nil {
 		return err
 	}
 
 	pb.RegisterPieceStoreRoutesServer(server.GRPC(), s)
+	pb.RegisterNodeExitServer(server.GRPC(), newExiter(s, server.Identity()))
+
+	// the node serves the network without its dashboard
+	if c.DashboardAddr != "" {
+		ln, err := listenDashboard(c.DashboardAddr)
+		if err != nil {
+			zap.S().Errorf("failed to serve the dashboard on %s: %+v", c.DashboardAddr, err)
+		} else {
+			go func() {
+				if err := newDashboard(s, server.Identity().ID).serve(ctx, ln); err != nil {
+					zap.S().Errorf("dashboard server died: %+v", err)
+				}
+			}()
+		}
+	}
 
 	// Run the agreement sender process
-	asProcess, err := as.Initialize(s.DB, server.Identity())
+	asProcess, err := as.Initialize(s.DB, server.Identity(), s.trust.addresses())
 	if err != nil {
 		return err
 	}
@@ -77,116 +101,115 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 	return server.Run(ctx)
 }
 
-//DirSize returns the total size of the files in that directory
-func DirSize(path string) (int64, error) {
-	var size int64
-	_, err := os.Stat(path)
-	if err != nil {
-		return 0, errors.New("path doesn't exists")
-	}
-	adjSize := func(_ string, info os.FileInfo, err error) error {
-		if !info.IsDir() {
-			size += info.Size()
-		}
-		return err
-	}
-	err = filepath.Walk(path, adjSize)
-
-	return size, err
-}
-
 // Server -- GRPC server meta data used in route calls
 type Server struct {
-	DataDir          string
-	DB               *psdb.DB
-	pkey             crypto.PrivateKey
-	totalAllocated   int64
-	totalBwAllocated int64
-	verifier         auth.SignedMessageVerifier
+	Dirs       []*DataDir
+	DB         *psdb.DB
+	pkey       crypto.PrivateKey
+	allocation *allocation
+	verifier   auth.SignedMessageVerifier
+	trust      *trust
+
+	storingMu sync.Mutex
+	storing   map[string]bool // ids of the pieces being stored
 }
 
 // Initialize -- initializes a server struct
-func Initialize(ctx context.Context, config Config, pkey crypto.PrivateKey) (*Server, error) {
+func Initialize(ctx context.Context, config Config, identity *provider.FullIdentity) (*Server, error) {
 	dbPath := filepath.Join(config.Path, "piecestore.db")
 	dataDir := filepath.Join(config.Path, "piece-store-data")
 
-	// read the allocated disk space from the config file
-	allocatedDiskSpace := config.AllocatedDiskSpace
-	allocatedBandwidth := config.AllocatedBandwidth
+	satellites, err := parseTrustedSatellites(config.TrustedSatellites)
+	if err != nil {
+		return nil, err
+	}
+	if len(satellites) == 0 {
+		zap.S().Warn("No trusted satellites configured, storing data for any satellite")
+	}
 
-	// get the disk space details
-	// The returned path ends in a slash only if it represents a root directory, such as "/" on Unix or `C:\` on Windows.
-	rootPath := filepath.Dir(filepath.Clean(config.Path))
-	diskSpace, err := disk.Usage(rootPath)
+	// read the data directories and allocated space from the config file
+	extraDirs, err := parseDataDirs(config.DataDirs)
 	if err != nil {
-		return nil, ServerError.Wrap(err)
+		return nil, err
 	}
-	freeDiskSpace := int64(diskSpace.Free)
+	dirConfigs := append([]dataDirConfig{{path: dataDir, allocated: config.AllocatedDiskSpace}}, extraDirs...)
+	allocatedBandwidth := config.AllocatedBandwidth
 
-	db, err := psdb.Open(ctx, dataDir, dbPath)
+	db, err := psdb.Open(ctx, nil, dbPath)
 	if err != nil {
 		return nil, ServerError.Wrap(err)
 	}
 
-	// get how much is currently used, if for the first time totalUsed = 0
-	totalUsed, err := db.SumTTLSizes()
+	// a lost disk loses only the pieces on it
+	dirs, err := openDataDirs(ctx, db, dirConfigs, parseLostDataDirs(config.LostDataDirs))
 	if err != nil {
-		//first time setup
-		totalUsed = 0x00
+		return nil, utils.CombineErrors(err, db.Close())
 	}
+	db.SetDirs(dirBlobs(dirs))
 
-	// get used bandwidth from the beginning of the month to till date
-	usedBandwidth, err := db.GetTotalBandwidthBetween(getBeginningOfMonth(), time.Now())
+	// earlier versions stored the pieces in the first directory
+	err = importLegacyPieces(ctx, db, dirs[0])
 	if err != nil {
-		return nil, ServerError.Wrap(err)
+		return nil, utils.CombineErrors(err, db.Close())
 	}
 
-	if usedBandwidth > allocatedBandwidth {
-		zap.S().Warnf("Exceed the allowed Bandwidth setting")
-	} else {
-		zap.S().Info("Remaining Bandwidth ", allocatedBandwidth-usedBandwidth)
+	// check the hard drives are big enough
+	allocatedDiskSpace, err := limitAllocated(db, dirs)
+	if err != nil {
+		return nil, utils.CombineErrors(err, db.Close())
 	}
 
-	// check your hard drive is big enough
-	// first time setup as a piece node server
-	if (totalUsed == 0x00) && (freeDiskSpace < allocatedDiskSpace) {
-		allocatedDiskSpace = freeDiskSpace
-		zap.S().Warnf("Disk space is less than requested allocated space, allocating = %d Bytes", allocatedDiskSpace)
+	// get used space and bandwidth from the beginning of the month to till date
+	alloc, err := loadAllocation(db, allocatedDiskSpace, allocatedBandwidth)
+	if err != nil {
+		return nil, ServerError.Wrap(utils.CombineErrors(err, db.Close()))
 	}
 
-	// on restarting the Piece node server, assuming already been working as a node
-	// used above the alloacated space, user changed the allocation space setting
-	// before restarting
-	if totalUsed >= allocatedDiskSpace {
-		zap.S().Warnf("Used more space then allocated, allocating = %d Bytes", allocatedDiskSpace)
+	availableDisk, availableBandwidth := alloc.available()
+	if availableDisk <= 0 {
+		zap.S().Warn("No disk space left, rejecting uploads")
+	}
+	if availableBandwidth <= 0 {
+		zap.S().Warn("No bandwidth left this month, rejecting uploads and downloads")
+	} else {
+		zap.S().Info("Remaining Bandwidth ", availableBandwidth)
 	}
 
-	// the available diskspace is less than remaining allocated space,
-	// due to change of setting before restarting
-	if freeDiskSpace < (allocatedDiskSpace - totalUsed) {
-		allocatedDiskSpace = freeDiskSpace
-		zap.S().Warnf("Disk space is less than requested allocated space, allocating = %d Bytes", allocatedDiskSpace)
+	s := &Server{
+		Dirs:       dirs,
+		DB:         db,
+		pkey:       identity.Key,
+		allocation: alloc,
+		verifier:   auth.NewSignedMessageVerifier(),
+		trust:      newTrust(identity, satellites),
+	}
+	go s.collectGarbage(ctx)
+	go s.refreshAllocation(ctx)
+	if config.ScrubRate > 0 {
+		go s.scrub(ctx, config.ScrubRate, config.ScrubInterval)
 	}
 
-	return &Server{
-		DataDir:          dataDir,
-		DB:               db,
-		pkey:             pkey,
-		totalAllocated:   allocatedDiskSpace,
-		totalBwAllocated: allocatedBandwidth,
-		verifier:         auth.NewSignedMessageVerifier(),
-	}, nil
+	return s, nil
 }
 
-// New creates a Server with custom db
-func New(dataDir string, db *psdb.DB, config Config, pkey crypto.PrivateKey) *Server {
+// New creates a Server with custom db, which must delete the expired
+// pieces from the blob stores of dirs. It stores data for any satellite.
+func New(dirs []*DataDir, db *psdb.DB, config Config, pkey crypto.PrivateKey) *Server {
+	var allocated int64
+	for _, dir := range dirs {
+		allocated += dir.Allocated
+	}
+	alloc, err := loadAllocation(db, allocated, config.AllocatedBandwidth)
+	if err != nil {
+		zap.S().Errorf("failed reading used space and bandwidth: %+v", err)
+		alloc = newAllocation(allocated, config.AllocatedBandwidth, 0, 0)
+	}
 	return &Server{
-		DataDir:          dataDir,
-		DB:               db,
-		pkey:             pkey,
-		totalAllocated:   config.AllocatedDiskSpace,
-		totalBwAllocated: config.AllocatedBandwidth,
-		verifier:         auth.NewSignedMessageVerifier(),
+		Dirs:       dirs,
+		DB:         db,
+		pkey:       pkey,
+		allocation: alloc,
+		verifier:   auth.NewSignedMessageVerifier(),
 	}
 }
 
@@ -209,8 +232,7 @@ func (s *Server) Piece(ctx context.Context, in *pb.PieceId) (*pb.PieceSummary, e
 		return nil, err
 	}
 
-	path, err := pstore.PathByID(id, s.DataDir)
-	if err != nil {
+	if err := checkID(id); err != nil {
 		return nil, err
 	}
 
@@ -223,10 +245,14 @@ func (s *Server) Piece(ctx context.Context, in *pb.PieceId) (*pb.PieceSummary, e
 		return nil, ServerError.New("invalid ID")
 	}
 
-	fileInfo, err := os.Stat(path)
+	piece, err := s.openPiece(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	size := piece.Size()
+	if err := piece.Close(); err != nil {
+		return nil, err
+	}
 
 	// Read database to calculate expiration
 	ttl, err := s.DB.GetTTLByID(id)
@@ -235,24 +261,66 @@ func (s *Server) Piece(ctx context.Context, in *pb.PieceId) (*pb.PieceSummary, e
 	}
 
 	zap.S().Infof("Successfully retrieved meta for %s.", in.GetId())
-	return &pb.PieceSummary{Id: in.GetId(), PieceSize: fileInfo.Size(), ExpirationUnixSec: ttl}, nil
+	return &pb.PieceSummary{Id: in.GetId(), PieceSize: size, ExpirationUnixSec: ttl}, nil
 }
 
 // Stats will return statistics about the Server
 func (s *Server) Stats(ctx context.Context, in *pb.StatsReq) (*pb.StatSummary, error) {
 	zap.S().Infof("Getting Stats...\n")
 
-	totalUsed, err := s.DB.SumTTLSizes()
+	// the space reserved for uploads in progress is not available
+	totalUsed, totalUsedBandwidth := s.allocation.used()
+	availableSpace, availableBandwidth := s.allocation.available()
+
+	satellites, err := s.satelliteStats()
 	if err != nil {
 		return nil, err
 	}
 
-	totalUsedBandwidth, err := s.DB.GetTotalBandwidthBetween(getBeginningOfMonth(), time.Now())
+	return &pb.StatSummary{UsedSpace: totalUsed, AvailableSpace: availableSpace, UsedBandwidth: totalUsedBandwidth, AvailableBandwidth: availableBandwidth, Satellites: satellites}, nil
+}
+
+// satelliteStats returns the usage of the node by each satellite, ordered
+// by satellite ID. The pieces whose satellite is unknown are counted for the
+// zero ID.
+func (s *Server) satelliteStats() ([]*pb.SatelliteStats, error) {
+	usage, err := s.DB.SpaceUsedBySatellite()
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.StatSummary{UsedSpace: totalUsed, AvailableSpace: (s.totalAllocated - totalUsed), UsedBandwidth: totalUsedBandwidth, AvailableBandwidth: (s.totalBwAllocated - totalUsedBandwidth)}, nil
+	bandwidth, err := s.DB.GetBandwidthUsedBySatellite(getBeginningOfMonth(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[storj.NodeID]*pb.SatelliteStats)
+	get := func(id storj.NodeID) *pb.SatelliteStats {
+		if stats[id] == nil {
+			stats[id] = &pb.SatelliteStats{SatelliteId: id}
+		}
+		return stats[id]
+	}
+	for id, u := range usage {
+		get(id).Pieces = u.Pieces
+		get(id).UsedSpace = u.Size
+	}
+	for id, bw := range bandwidth {
+		get(id).PutBandwidth = bw.Put
+		get(id).GetBandwidth = bw.Get
+	}
+
+	ids := storj.NodeIDList{}
+	for id := range stats {
+		ids = append(ids, id)
+	}
+	sort.Sort(ids)
+
+	sorted := make([]*pb.SatelliteStats, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, stats[id])
+	}
+	return sorted, nil
 }
 
 // Delete -- Delete data by Id from piecestore
@@ -268,7 +336,7 @@ func (s *Server) Delete(ctx context.Context, in *pb.PieceDelete) (*pb.PieceDelet
 	if err != nil {
 		return nil, err
 	}
-	if err := s.deleteByID(id); err != nil {
+	if err := s.deleteByID(ctx, id); err != nil {
 		return nil, err
 	}
 
@@ -276,18 +344,33 @@ func (s *Server) Delete(ctx context.Context, in *pb.PieceDelete) (*pb.PieceDelet
 	return &pb.PieceDeleteSummary{Message: OK}, nil
 }
 
-func (s *Server) deleteByID(id string) error {
-	if err := pstore.Delete(id, s.DataDir); err != nil {
-		return err
+// DeleteMany -- Delete several pieces from piece store with one request
+func (s *Server) DeleteMany(ctx context.Context, in *pb.PieceDeleteMany) (*pb.PieceDeleteSummary, error) {
+	zap.S().Infof("Deleting %d pieces...", len(in.GetIds()))
+
+	authorization := in.GetAuthorization()
+	if err := s.verifier(authorization); err != nil {
+		return nil, ServerError.Wrap(err)
 	}
 
-	if err := s.DB.DeleteTTLByID(id); err != nil {
-		return err
+	if len(in.GetIds()) > maxDeleteManyPieces {
+		return nil, ServerError.New("too many pieces to delete: %d > %d", len(in.GetIds()), maxDeleteManyPieces)
 	}
 
-	zap.S().Infof("Deleted data of id (%s)\n", id)
+	ids := make([]string, 0, len(in.GetIds()))
+	for _, pieceID := range in.GetIds() {
+		id, err := getNamespacedPieceID([]byte(pieceID), getNamespace(authorization))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := s.deleteByIDs(ctx, ids); err != nil {
+		return nil, err
+	}
 
-	return nil
+	zap.S().Infof("Successfully deleted %d pieces.", len(ids))
+	return &pb.PieceDeleteSummary{Message: OK}, nil
 }
 
 func (s *Server) verifySignature(ctx context.Context, ba *pb.RenterBandwidthAllocation) error {
@@ -7,59 +7,53 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net"
 	"os"
-	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gtank/cryptopasta"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"storj.io/storj/internal/identity"
+	"storj.io/storj/internal/teststorj"
 	"storj.io/storj/pkg/pb"
-	"storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/piecestore/psclient"
 	"storj.io/storj/pkg/piecestore/psserver/psdb"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage/filestore"
 )
 
 var ctx = context.Background()
 
-func writeFileToDir(name, dir string) error {
-	file, err := pstore.StoreWriter(name, dir)
-	if err != nil {
-		return err
-	}
-
-	// Close when finished
-	_, err = io.Copy(file, bytes.NewReader([]byte("butts")))
-	if err != nil {
-		_ = file.Close()
-		return err
-	}
-	return file.Close()
+func writePiece(s *Server, id string) error {
+	_, _, err := s.storePiece(ctx, id, bytes.NewReader([]byte("butts")))
+	return err
 }
 
 func TestPiece(t *testing.T) {
 	TS := NewTestServer(t)
 	defer TS.Stop()
 
-	if err := writeFileToDir("11111111111111111111", TS.s.DataDir); err != nil {
+	if err := writePiece(TS.s, "11111111111111111111"); err != nil {
 		t.Errorf("Error: %v\nCould not create test piece", err)
 		return
 	}
 
-	defer func() { _ = pstore.Delete("11111111111111111111", TS.s.DataDir) }()
+	defer func() { _ = TS.s.deleteByID(ctx, "11111111111111111111") }()
 
 	// set up test cases
 	tests := []struct {
@@ -84,7 +78,7 @@ func TestPiece(t *testing.T) {
 			id:         "22222222222222222222",
 			size:       5,
 			expiration: 9999999999,
-			err:        fmt.Sprintf("rpc error: code = Unknown desc = stat %s: no such file or directory", path.Join(TS.s.DataDir, "/22/22/2222222222222222")),
+			err:        "rpc error: code = Unknown desc = piece not found: 22222222222222222222",
 		},
 		{ // server should err with invalid TTL
 			id:         "22222222222222222222;DELETE*FROM TTL;;;;",
@@ -136,12 +130,12 @@ func TestRetrieve(t *testing.T) {
 	defer TS.Stop()
 
 	// simulate piece stored with storagenode
-	if err := writeFileToDir("11111111111111111111", TS.s.DataDir); err != nil {
+	if err := writePiece(TS.s, "11111111111111111111"); err != nil {
 		t.Errorf("Error: %v\nCould not create test piece", err)
 		return
 	}
 
-	defer func() { _ = pstore.Delete("11111111111111111111", TS.s.DataDir) }()
+	defer func() { _ = TS.s.deleteByID(ctx, "11111111111111111111") }()
 
 	// set up test cases
 	tests := []struct {
@@ -205,7 +199,7 @@ func TestRetrieve(t *testing.T) {
 			allocSize: 5,
 			offset:    0,
 			content:   []byte("butts"),
-			err:       fmt.Sprintf("rpc error: code = Unknown desc = retrieve error: stat %s: no such file or directory", path.Join(TS.s.DataDir, "/22/22/2222222222222222")),
+			err:       "rpc error: code = Unknown desc = retrieve error: piece not found: 22222222222222222222",
 		},
 		{ // server should return expected content and respSize with offset and excess reqSize
 			id:        "11111111111111111111",
@@ -401,6 +395,10 @@ func TestStore(t *testing.T) {
 
 			assert.Equal(tt.message, resp.Message)
 			assert.Equal(tt.totalReceived, resp.TotalReceived)
+
+			hash := sha256.Sum256(tt.content)
+			err = psclient.VerifyReceipt(resp.Receipt, psclient.PieceID(tt.id), tt.totalReceived, hash[:], time.Unix(tt.ttl, 0))
+			assert.NoError(err)
 		})
 	}
 }
@@ -439,7 +437,7 @@ func TestDelete(t *testing.T) {
 			assert := assert.New(t)
 
 			// simulate piece stored with storagenode
-			if err := writeFileToDir("11111111111111111111", TS.s.DataDir); err != nil {
+			if err := writePiece(TS.s, "11111111111111111111"); err != nil {
 				t.Errorf("Error: %v\nCould not create test piece", err)
 				return
 			}
@@ -454,7 +452,7 @@ func TestDelete(t *testing.T) {
 			}()
 
 			defer func() {
-				assert.NoError(pstore.Delete("11111111111111111111", TS.s.DataDir))
+				assert.NoError(TS.s.deleteByID(ctx, "11111111111111111111"))
 			}()
 
 			req := &pb.PieceDelete{Id: tt.id}
@@ -468,17 +466,127 @@ func TestDelete(t *testing.T) {
 			assert.NoError(err)
 			assert.Equal(tt.message, resp.GetMessage())
 
-			// if test passes, check if file was indeed deleted
-			filePath, err := pstore.PathByID(tt.id, TS.s.DataDir)
-			assert.NoError(err)
-			if _, err = os.Stat(filePath); os.IsExist(err) {
-				t.Errorf("File not deleted")
-				return
-			}
+			// if test passes, check if piece was indeed deleted
+			_, err = TS.s.openPiece(ctx, tt.id)
+			assert.True(ErrPieceNotFound.Has(err), "piece not deleted")
 		})
 	}
 }
 
+func TestDeleteMany(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+
+	db := TS.s.DB.DB
+
+	ids := []string{"11111111111111111111", "22222222222222222222", "33333333333333333333"}
+	for _, id := range ids[:2] {
+		// simulate piece stored with storagenode
+		require.NoError(t, writePiece(TS.s, id))
+
+		// simulate piece TTL entry
+		_, err := db.Exec(fmt.Sprintf(`INSERT INTO ttl (id, created, expires) VALUES ("%s", "%d", "%d")`, id, 1234567890, 1234567890))
+		require.NoError(t, err)
+	}
+
+	// nonexistent pieces count as deleted
+	resp, err := TS.c.DeleteMany(ctx, &pb.PieceDeleteMany{Ids: ids})
+	require.NoError(t, err)
+	assert.Equal(t, "OK", resp.GetMessage())
+
+	for _, id := range ids {
+		_, err = TS.s.openPiece(ctx, id)
+		assert.True(t, ErrPieceNotFound.Has(err), "piece not deleted")
+
+		_, err = TS.s.DB.GetTTLByID(id)
+		assert.Error(t, err, "ttl not deleted")
+	}
+
+	_, err = TS.c.DeleteMany(ctx, &pb.PieceDeleteMany{Ids: []string{"123"}})
+	assert.EqualError(t, err, "rpc error: code = Unknown desc = argError: invalid id length")
+
+	_, err = TS.c.DeleteMany(ctx, &pb.PieceDeleteMany{Ids: make([]string, maxDeleteManyPieces+1)})
+	assert.Error(t, err)
+}
+
+func TestDeleteSharedBlob(t *testing.T) {
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+
+	victim, other := "11111111111111111111", "22222222222222222222"
+	require.NoError(t, writePiece(s, victim))
+
+	// another piece referencing the same blob is deleted without the data
+	// of the victim
+	dir, ref, err := s.DB.GetBlob(victim)
+	require.NoError(t, err)
+	require.NoError(t, s.DB.AddBlob(other, dir, ref))
+	require.NoError(t, s.deleteByID(ctx, other))
+
+	piece, err := s.openPiece(ctx, victim)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(piece)
+	assert.NoError(t, err)
+	assert.NoError(t, piece.Close())
+	assert.Equal(t, "butts", string(data))
+
+	require.NoError(t, s.deleteByID(ctx, victim))
+	_, err = s.openPiece(ctx, victim)
+	assert.True(t, ErrPieceNotFound.Has(err))
+}
+
+func TestStorePieceReserved(t *testing.T) {
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+
+	id := "11111111111111111111"
+
+	// a piece is stored by one upload at a time
+	require.True(t, s.reserve(id))
+	assert.Error(t, writePiece(s, id))
+	s.unreserve(id)
+
+	require.NoError(t, writePiece(s, id))
+	assert.Error(t, writePiece(s, id), "piece exists")
+}
+
+func TestStats(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+	satellite1 := teststorj.NodeIDFromString("satellite1")
+	satellite2 := teststorj.NodeIDFromString("satellite2")
+	for id, satelliteID := range map[string]storj.NodeID{
+		"11111111111111111111": satellite1,
+		"22222222222222222222": satellite1,
+		"33333333333333333333": satellite2,
+	} {
+		require.NoError(t, writePiece(TS.s, id))
+		require.NoError(t, TS.s.DB.AddTTL(id, 0, 100))
+		require.NoError(t, TS.s.DB.SetBlobSatellite(id, satelliteID, "piece-"+id))
+	}
+	require.NoError(t, TS.s.DB.AddBandwidthUsed(satellite1, pb.PayerBandwidthAllocation_PUT, 200))
+	require.NoError(t, TS.s.DB.AddBandwidthUsed(satellite2, pb.PayerBandwidthAllocation_PUT, 100))
+	require.NoError(t, TS.s.DB.AddBandwidthUsed(satellite2, pb.PayerBandwidthAllocation_GET, 50))
+
+	var err error
+	TS.s.allocation, err = loadAllocation(TS.s.DB, 1000, 2000)
+	require.NoError(t, err)
+	// the space reserved for uploads in progress is not available
+	require.NoError(t, TS.s.allocation.reserve(100))
+
+	stats, err := TS.c.Stats(ctx, &pb.StatsReq{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), stats.UsedSpace)
+	assert.Equal(t, int64(600), stats.AvailableSpace)
+	assert.Equal(t, int64(450), stats.UsedBandwidth)
+	assert.Equal(t, int64(1550), stats.AvailableBandwidth)
+
+	assert.Equal(t, []*pb.SatelliteStats{
+		{SatelliteId: satellite1, Pieces: 2, UsedSpace: 200, PutBandwidth: 200},
+		{SatelliteId: satellite2, Pieces: 1, UsedSpace: 100, PutBandwidth: 100, GetBandwidth: 50},
+	}, stats.Satellites)
+}
+
 func newTestServerStruct(t *testing.T) (*Server, func()) {
 	tmp, err := ioutil.TempDir("", "storj-piecestore")
 	if err != nil {
@@ -488,7 +596,14 @@ func newTestServerStruct(t *testing.T) (*Server, func()) {
 	tempDBPath := filepath.Join(tmp, "test.db")
 	tempDir := filepath.Join(tmp, "test-data", "3000")
 
-	psDB, err := psdb.Open(ctx, tempDir, tempDBPath)
+	blobs, err := filestore.NewAt(tempDir)
+	if err != nil {
+		t.Fatalf("failed open blob store: %v", err)
+	}
+
+	dirs := []*DataDir{{Path: tempDir, Allocated: 1 << 30, Blobs: blobs}}
+
+	psDB, err := psdb.Open(ctx, dirBlobs(dirs), tempDBPath)
 	if err != nil {
 		t.Fatalf("failed open psdb: %v", err)
 	}
@@ -496,7 +611,7 @@ func newTestServerStruct(t *testing.T) (*Server, func()) {
 	verifier := func(authorization *pb.SignedMessage) error {
 		return nil
 	}
-	server := &Server{DataDir: tempDir, DB: psDB, verifier: verifier}
+	server := &Server{Dirs: dirs, DB: psDB, allocation: newAllocation(1<<30, 1<<40, 0, 0), verifier: verifier}
 	return server, func() {
 		if serr := server.Stop(ctx); serr != nil {
 			t.Fatal(serr)
@@ -551,6 +666,7 @@ func NewTestServer(t *testing.T) *TestServer {
 	check(err)
 
 	s, cleanup := newTestServerStruct(t)
+	s.pkey = fiS.Key
 	grpcs := grpc.NewServer(so)
 
 	k, ok := fiC.Key.(*ecdsa.PrivateKey)
@@ -5,13 +5,17 @@ package psserver
 
 import (
 	"context"
-	"io"
+	"crypto/ecdsa"
+	"crypto/x509"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/pb"
-	"storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/storj"
 	"storj.io/storj/pkg/utils"
 )
 
@@ -54,58 +58,112 @@ func (s *Server) Store(reqStream pb.PieceStoreRoutes_StoreServer) (err error) {
 	if err != nil {
 		return err
 	}
-	total, err := s.storeData(ctx, reqStream, id)
+
+	if err := s.allocation.checkAvailable(true); err != nil {
+		return allocationStatus(err)
+	}
+
+	satelliteID, total, hash, err := s.storeData(ctx, reqStream, id, pd.GetId())
 	if err != nil {
-		return err
+		return allocationStatus(err)
 	}
 
 	if err = s.DB.AddTTL(id, pd.GetExpirationUnixSec(), total); err != nil {
-		deleteErr := s.deleteByID(id)
+		deleteErr := s.deleteByID(ctx, id)
 		return StoreError.New("failed to write piece meta data to database: %v", utils.CombineErrors(err, deleteErr))
 	}
 
-	if err = s.DB.AddBandwidthUsed(total); err != nil {
+	if err = s.DB.AddBandwidthUsed(satelliteID, pb.PayerBandwidthAllocation_PUT, total); err != nil {
 		return StoreError.New("failed to write bandwidth info to database: %v", err)
 	}
 	zap.S().Infof("Successfully stored %s.", pd.GetId())
 
-	return reqStream.SendAndClose(&pb.PieceStoreSummary{Message: OK, TotalReceived: total})
+	receipt, err := s.newReceipt(pd.GetId(), total, hash, pd.GetExpirationUnixSec())
+	if err != nil {
+		return StoreError.Wrap(err)
+	}
+
+	return reqStream.SendAndClose(&pb.PieceStoreSummary{Message: OK, TotalReceived: total, Receipt: receipt})
 }
 
-func (s *Server) storeData(ctx context.Context, stream pb.PieceStoreRoutes_StoreServer, id string) (total int64, err error) {
+// storeData stores the data of the piece with id, as received from stream,
+// and returns the satellite paying for it. The space reserved while
+// receiving the data is used by the piece once it is stored.
+func (s *Server) storeData(ctx context.Context, stream pb.PieceStoreRoutes_StoreServer, id, pieceID string) (satelliteID storj.NodeID, total int64, hash []byte, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	// Delete data if we error
+	reader := NewStreamReader(s, stream)
+	defer func() { s.allocation.release(reader.reserved, err == nil) }()
+
 	defer func() {
-		if err != nil && err != io.EOF {
-			if deleteErr := s.deleteByID(id); deleteErr != nil {
-				zap.S().Errorf("Failed on deleteByID in Store: %s", deleteErr.Error())
-			}
+		baWriteErr := s.DB.WriteBandwidthAllocToDB(reader.bandwidthAllocation)
+		if baWriteErr != nil {
+			zap.S().Errorf("Error while writing Bandwidth Alloc to DB: %s\n", baWriteErr.Error())
 		}
 	}()
 
-	// Initialize file for storing data
-	storeFile, err := pstore.StoreWriter(id, s.DataDir)
+	// nothing is left behind if the data is not received completely
+	total, hash, err = s.storePiece(ctx, id, reader)
 	if err != nil {
-		return 0, err
+		return satelliteID, 0, nil, err
+	}
+
+	// the satellite is told when the piece is found corrupted
+	if reader.bandwidthAllocation != nil {
+		satelliteID, err = getSatelliteID(reader.bandwidthAllocation)
+		if err == nil {
+			err = s.DB.SetBlobSatellite(id, satelliteID, pieceID)
+		}
+		if err != nil {
+			zap.S().Errorf("Error while writing satellite of %s to DB: %s\n", pieceID, err.Error())
+		}
 	}
+	return satelliteID, total, hash, nil
+}
 
-	defer utils.LogClose(storeFile)
+// newReceipt signs the receipt for the piece with id, as the uploader
+// requested it, proving that this node stored size bytes hashing to hash
+// until expiration
+func (s *Server) newReceipt(id string, size int64, hash []byte, expiration int64) (*pb.PieceStoreReceipt, error) {
+	key, ok := s.pkey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", s.pkey)
+	}
 
-	reader := NewStreamReader(s, stream)
+	pubKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
 
-	defer func() {
-		baWriteErr := s.DB.WriteBandwidthAllocToDB(reader.bandwidthAllocation)
-		if baWriteErr != nil {
-			zap.S().Errorf("Error while writing Bandwidth Alloc to DB: %s\n", baWriteErr.Error())
-		}
-	}()
+	data, err := proto.Marshal(&pb.PieceStoreReceipt_Data{
+		Id:                id,
+		PieceSize:         size,
+		Hash:              hash,
+		ExpirationUnixSec: expiration,
+		PubKey:            pubKey,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	total, err = io.Copy(storeFile, reader)
+	signature, err := cryptopasta.Sign(data, key)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PieceStoreReceipt{Signature: signature, Data: data}, nil
+}
 
-	if err != nil && err != io.EOF {
-		return 0, err
+// getSatelliteID returns the ID of the satellite that allocated the
+// bandwidth of ba
+func getSatelliteID(ba *pb.RenterBandwidthAllocation) (storj.NodeID, error) {
+	rbad := &pb.RenterBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(ba.GetData(), rbad); err != nil {
+		return storj.NodeID{}, err
 	}
 
-	return total, nil
+	pbad := &pb.PayerBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(rbad.GetPayerAllocation().GetData(), pbad); err != nil {
+		return storj.NodeID{}, err
+	}
+	return pbad.SatelliteId, nil
 }
@@ -0,0 +1,299 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storj"
+)
+
+// ErrUntrustedSatellite is returned for bandwidth allocations that are not
+// signed by a trusted satellite
+var ErrUntrustedSatellite = errs.Class("untrusted satellite")
+
+const (
+	// satelliteDialTimeout is how long fetching the key of a satellite may
+	// take
+	satelliteDialTimeout = 10 * time.Second
+	// minKeyRetryInterval and maxKeyRetryInterval bound how long a failure
+	// to fetch the key of a satellite is returned before fetching it again
+	minKeyRetryInterval = time.Second
+	maxKeyRetryInterval = 5 * time.Minute
+)
+
+// trustedSatellite is a satellite that the node stores data for
+type trustedSatellite struct {
+	id      storj.NodeID
+	address string
+
+	mu sync.Mutex
+	// key signs the allocations of the satellite. It is fetched from the
+	// satellite when it is nil.
+	key crypto.PublicKey
+	// fetching is closed when the key being fetched is fetched
+	fetching chan struct{}
+	// err is the last failure to fetch the key, returned until retry
+	err     error
+	retry   time.Time
+	backoff time.Duration
+}
+
+// parseTrustedSatellites parses a comma separated list of satellites, each
+// followed by an at sign and its address. A satellite is its node ID, or
+// the path to its certificate chain, like
+// "<node ID>@sat1.example.com:7777,/etc/storj/sat2.cert@sat2.example.com:7777"
+func parseTrustedSatellites(s string) (satellites []*trustedSatellite, err error) {
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		i := strings.LastIndex(field, "@")
+		if i <= 0 || i == len(field)-1 {
+			return nil, ServerError.New("trusted satellite %q has no address", field)
+		}
+		satellite := &trustedSatellite{address: field[i+1:]}
+
+		satellite.id, err = storj.NodeIDFromString(field[:i])
+		if err != nil {
+			peer, err := loadCertChain(field[:i])
+			if err != nil {
+				return nil, ServerError.New("trusted satellite %q is neither a node ID nor a certificate chain: %v", field, err)
+			}
+			satellite.id, satellite.key = peer.ID, peer.Leaf.PublicKey
+		}
+		satellites = append(satellites, satellite)
+	}
+	return satellites, nil
+}
+
+// loadCertChain loads the identity of a node from its certificate chain
+// file
+func loadCertChain(path string) (*provider.PeerIdentity, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain [][]byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type == peertls.BlockTypeCertificate {
+			chain = append(chain, block.Bytes)
+		}
+	}
+	if len(chain) < 2 {
+		return nil, errs.New("too few certificates in chain")
+	}
+
+	certs, err := provider.ParseCertChain(chain)
+	if err != nil {
+		return nil, err
+	}
+	if err := peertls.VerifyPeerCertChains(chain, [][]*x509.Certificate{certs}); err != nil {
+		return nil, err
+	}
+	return provider.PeerIdentityFromCerts(certs[0], certs[1], certs[2:])
+}
+
+// trust checks that bandwidth allocations are signed by trusted satellites
+type trust struct {
+	identity   *provider.FullIdentity
+	satellites map[storj.NodeID]*trustedSatellite
+}
+
+// newTrust returns the trust of the node in satellites, or nil to trust
+// any satellite if there are none
+func newTrust(identity *provider.FullIdentity, satellites []*trustedSatellite) *trust {
+	if len(satellites) == 0 {
+		return nil
+	}
+	t := &trust{identity: identity, satellites: make(map[storj.NodeID]*trustedSatellite)}
+	for _, satellite := range satellites {
+		t.satellites[satellite.id] = satellite
+	}
+	return t
+}
+
+// addresses returns the addresses of the trusted satellites by their IDs
+func (t *trust) addresses() map[storj.NodeID]string {
+	if t == nil {
+		return nil
+	}
+	addresses := make(map[storj.NodeID]string, len(t.satellites))
+	for id, satellite := range t.satellites {
+		addresses[id] = satellite.address
+	}
+	return addresses
+}
+
+// verifyPayerAllocation checks that pba was signed by a trusted satellite.
+// Any satellite is trusted when t is nil.
+func (t *trust) verifyPayerAllocation(ctx context.Context, pba *pb.PayerBandwidthAllocation) (err error) {
+	if t == nil {
+		return nil
+	}
+	defer mon.Task()(&ctx)(&err)
+
+	pbad := &pb.PayerBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(pba.GetData(), pbad); err != nil {
+		return ErrUntrustedSatellite.Wrap(err)
+	}
+
+	satellite, ok := t.satellites[pbad.SatelliteId]
+	if !ok {
+		return ErrUntrustedSatellite.New("%s", pbad.SatelliteId)
+	}
+
+	key, err := satellite.signingKey(ctx, t.identity)
+	if err != nil {
+		return ErrUntrustedSatellite.New("failed getting key of %s: %v", satellite.id, err)
+	}
+	k, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return peertls.ErrUnsupportedKey.New("%T", key)
+	}
+	if !cryptopasta.Verify(pba.GetData(), pba.GetSignature(), k) {
+		return ErrUntrustedSatellite.New("allocation not signed by %s", satellite.id)
+	}
+	return nil
+}
+
+// signingKey returns the key that the satellite signs with, fetching it
+// from the satellite the first time. A failure to fetch it is returned
+// until it is fetched again after a backoff, so that allocations of an
+// unreachable satellite do not wait for it each.
+func (satellite *trustedSatellite) signingKey(ctx context.Context, identity *provider.FullIdentity) (crypto.PublicKey, error) {
+	for {
+		satellite.mu.Lock()
+		if satellite.key != nil {
+			defer satellite.mu.Unlock()
+			return satellite.key, nil
+		}
+		if satellite.err != nil && time.Now().Before(satellite.retry) {
+			defer satellite.mu.Unlock()
+			return nil, satellite.err
+		}
+		fetching := satellite.fetching
+		if fetching == nil {
+			break
+		}
+		satellite.mu.Unlock()
+
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	fetching := make(chan struct{})
+	satellite.fetching = fetching
+	satellite.mu.Unlock()
+
+	key, err := satellite.fetchKey(ctx, identity)
+
+	satellite.mu.Lock()
+	defer satellite.mu.Unlock()
+	satellite.fetching = nil
+	close(fetching)
+
+	if err != nil {
+		// a canceled request says nothing about the satellite
+		if ctx.Err() == nil {
+			satellite.backoff *= 2
+			if satellite.backoff < minKeyRetryInterval {
+				satellite.backoff = minKeyRetryInterval
+			}
+			if satellite.backoff > maxKeyRetryInterval {
+				satellite.backoff = maxKeyRetryInterval
+			}
+			satellite.err, satellite.retry = err, time.Now().Add(satellite.backoff)
+		}
+		return nil, err
+	}
+	satellite.key, satellite.err = key, nil
+	return key, nil
+}
+
+// fetchKey fetches the key of the satellite from it
+func (satellite *trustedSatellite) fetchKey(ctx context.Context, identity *provider.FullIdentity) (crypto.PublicKey, error) {
+	peer, err := fetchPeerIdentity(ctx, identity, satellite.address)
+	if err != nil {
+		return nil, err
+	}
+	if peer.ID != satellite.id {
+		return nil, errs.New("node at %s is %s", satellite.address, peer.ID)
+	}
+	return peer.Leaf.PublicKey, nil
+}
+
+// fetchPeerIdentity shakes hands with the node at address to get its
+// identity
+func fetchPeerIdentity(ctx context.Context, identity *provider.FullIdentity, address string) (peer *provider.PeerIdentity, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	chain := append([][]byte{identity.Leaf.Raw, identity.CA.Raw}, identity.RestChainRaw()...)
+	cert, err := peertls.TLSCert(chain, identity.Leaf, identity.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates:       []tls.Certificate{*cert},
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2"},
+		VerifyPeerCertificate: peertls.VerifyPeerFunc(
+			peertls.VerifyPeerCertChains,
+			func(_ [][]byte, parsedChains [][]*x509.Certificate) error {
+				if len(parsedChains[0]) < 2 {
+					return errs.New("too few certificates in chain")
+				}
+				peer, err = provider.PeerIdentityFromCerts(parsedChains[0][0], parsedChains[0][1], parsedChains[0][2:])
+				return err
+			},
+		),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, satelliteDialTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	tlsConn := tls.Client(conn, config)
+	defer func() { _ = tlsConn.Close() }()
+
+	deadline, _ := ctx.Deadline()
+	if err := tlsConn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
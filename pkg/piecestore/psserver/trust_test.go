@@ -0,0 +1,130 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package psserver
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"storj.io/storj/internal/identity"
+	"storj.io/storj/pkg/auth"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+)
+
+func newTestIdentity(t *testing.T) *provider.FullIdentity {
+	ca, err := testidentity.NewTestCA(ctx)
+	require.NoError(t, err)
+	identity, err := ca.NewIdentity()
+	require.NoError(t, err)
+	return identity
+}
+
+func newPayerAllocation(t *testing.T, satellite *provider.FullIdentity) *pb.PayerBandwidthAllocation {
+	data, err := proto.Marshal(&pb.PayerBandwidthAllocation_Data{SatelliteId: satellite.ID, MaxSize: 1000})
+	require.NoError(t, err)
+	signature, err := auth.GenerateSignature(data, satellite)
+	require.NoError(t, err)
+	return &pb.PayerBandwidthAllocation{Data: data, Signature: signature}
+}
+
+func TestParseTrustedSatellites(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "storj-trust")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	satellite1, satellite2 := newTestIdentity(t), newTestIdentity(t)
+	certPath := filepath.Join(tmp, "satellite.cert")
+	file, err := os.Create(certPath)
+	require.NoError(t, err)
+	require.NoError(t, peertls.WriteChain(file, satellite2.Leaf, satellite2.CA))
+	require.NoError(t, file.Close())
+
+	satellites, err := parseTrustedSatellites(" " + satellite1.ID.String() + "@127.0.0.1:7777, " + certPath + "@[::1]:7778,")
+	require.NoError(t, err)
+	require.Len(t, satellites, 2)
+	assert.Equal(t, satellite1.ID, satellites[0].id)
+	assert.Equal(t, "127.0.0.1:7777", satellites[0].address)
+	assert.Nil(t, satellites[0].key)
+	assert.Equal(t, satellite2.ID, satellites[1].id)
+	assert.Equal(t, "[::1]:7778", satellites[1].address)
+	assert.Equal(t, satellite2.Leaf.PublicKey, satellites[1].key)
+
+	satellites, err = parseTrustedSatellites("")
+	assert.NoError(t, err)
+	assert.Empty(t, satellites)
+
+	for _, s := range []string{
+		satellite1.ID.String(),
+		satellite1.ID.String() + "@",
+		"@127.0.0.1:7777",
+		filepath.Join(tmp, "missing.cert") + "@127.0.0.1:7777",
+	} {
+		_, err := parseTrustedSatellites(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestVerifyPayerAllocation(t *testing.T) {
+	node, satellite, untrusted := newTestIdentity(t), newTestIdentity(t), newTestIdentity(t)
+
+	// the key of the satellite is fetched from it
+	so, err := satellite.ServerOption()
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(so)
+	defer server.Stop()
+	go func() { _ = server.Serve(lis) }()
+
+	trust := newTrust(node, []*trustedSatellite{{id: satellite.ID, address: lis.Addr().String()}})
+	assert.Equal(t, lis.Addr().String(), trust.addresses()[satellite.ID])
+
+	pba := newPayerAllocation(t, satellite)
+	assert.NoError(t, trust.verifyPayerAllocation(ctx, pba))
+
+	err = trust.verifyPayerAllocation(ctx, newPayerAllocation(t, untrusted))
+	assert.True(t, ErrUntrustedSatellite.Has(err), err)
+
+	// an allocation of the satellite signed by another one is rejected
+	forged := newPayerAllocation(t, untrusted)
+	forged.Data = pba.Data
+	err = trust.verifyPayerAllocation(ctx, forged)
+	assert.True(t, ErrUntrustedSatellite.Has(err), err)
+
+	// the node at the address of the satellite must be the satellite
+	trust = newTrust(node, []*trustedSatellite{{id: untrusted.ID, address: lis.Addr().String()}})
+	err = trust.verifyPayerAllocation(ctx, newPayerAllocation(t, untrusted))
+	assert.True(t, ErrUntrustedSatellite.Has(err), err)
+
+	// a failure to fetch the key is returned until it is fetched again
+	unreachable := &trustedSatellite{id: satellite.ID, address: lis.Addr().String()}
+	server.Stop()
+	trust = newTrust(node, []*trustedSatellite{unreachable})
+	err = trust.verifyPayerAllocation(ctx, pba)
+	assert.True(t, ErrUntrustedSatellite.Has(err), err)
+	require.Error(t, unreachable.err)
+	assert.Equal(t, minKeyRetryInterval, unreachable.backoff)
+	_, err = unreachable.signingKey(ctx, node)
+	assert.Equal(t, unreachable.err, err)
+	unreachable.retry = time.Now()
+	_, err = unreachable.signingKey(ctx, node)
+	assert.Error(t, err)
+	assert.Equal(t, 2*minKeyRetryInterval, unreachable.backoff)
+
+	// any satellite is trusted without trusted satellites
+	trust = newTrust(node, nil)
+	assert.Nil(t, trust)
+	assert.NoError(t, trust.verifyPayerAllocation(ctx, newPayerAllocation(t, untrusted)))
+}
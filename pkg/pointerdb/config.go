@@ -8,6 +8,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/provider"
@@ -23,8 +24,11 @@ type CtxKeyPointerdb int
 
 const (
 	// BoltPointerBucket is the string representing the bucket used for `PointerEntries` in BoltDB
-	BoltPointerBucket                 = "pointers"
-	ctxKey            CtxKeyPointerdb = iota
+	BoltPointerBucket = "pointers"
+	// BoltDeletionBucket is the bucket used for the pieces to delete in
+	// BoltDB
+	BoltDeletionBucket                 = "deletions"
+	ctxKey             CtxKeyPointerdb = iota
 )
 
 // Config is a configuration struct that is everything you need to start a
@@ -34,15 +38,17 @@ type Config struct {
 	MinRemoteSegmentSize int    `default:"1240" help:"minimum remote segment size"`
 	MaxInlineSegmentSize int    `default:"8000" help:"maximum inline segment size"`
 	Overlay              bool   `default:"false" help:"toggle flag if overlay is enabled"`
+	DeletionQueueURL     string `help:"the database connection string of the queue of pieces to delete from the storage nodes" default:"bolt://$CONFDIR/deletions.db"`
+	DeletionMaxAttempts  int    `help:"how many times to try deleting a piece before giving up" default:"10"`
 }
 
-func newKeyValueStore(dbURLString string) (db storage.KeyValueStore, err error) {
+func newKeyValueStore(dbURLString, boltBucket string) (db storage.KeyValueStore, err error) {
 	dburl, err := utils.ParseURL(dbURLString)
 	if err != nil {
 		return nil, err
 	}
 	if dburl.Scheme == "bolt" {
-		db, err = boltdb.New(dburl.Path, BoltPointerBucket)
+		db, err = boltdb.New(dburl.Path, boltBucket)
 	} else if dburl.Scheme == "postgresql" || dburl.Scheme == "postgres" {
 		db, err = postgreskv.New(dbURLString)
 	} else {
@@ -53,16 +59,32 @@ func newKeyValueStore(dbURLString string) (db storage.KeyValueStore, err error)
 
 // Run implements the provider.Responsibility interface
 func (c Config) Run(ctx context.Context, server *provider.Provider) error {
-	db, err := newKeyValueStore(c.DatabaseURL)
+	db, err := newKeyValueStore(c.DatabaseURL, BoltPointerBucket)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = db.Close() }()
 
+	deletions, err := newKeyValueStore(c.DeletionQueueURL, BoltDeletionBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = deletions.Close() }()
+
 	cache := overlay.LoadFromContext(ctx)
 	dblogged := storelogger.New(zap.L(), db)
 	s := NewServer(dblogged, cache, zap.L(), c, server.Identity())
+	s.notifier = notification.LoadFromContext(ctx)
+	if cache != nil {
+		s.EnableDeletion(deletions)
+	}
 	pb.RegisterPointerDBServer(server.GRPC(), s)
+	go func() {
+		err := s.Run(ctx)
+		if err != nil && err != context.Canceled {
+			zap.L().Error("err deleting pieces", zap.Error(err))
+		}
+	}()
 	// add the server to the context
 	ctx = context.WithValue(ctx, ctxKey, s)
 	return server.Run(ctx)
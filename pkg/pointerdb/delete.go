@@ -0,0 +1,215 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/notification"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+// deleteBatchSize is how many objects under a prefix are looked up at a
+// time for deleting them
+const deleteBatchSize = 1000
+
+// DeleteObjects deletes the streams of the objects at the paths and of all
+// the objects under the prefixes. The pieces are deleted from the storage
+// nodes in the background. Locked objects are skipped and returned.
+func (s *Server) DeleteObjects(ctx context.Context, req *pb.DeleteObjectsRequest) (resp *pb.DeleteObjectsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = s.validateAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	resp = &pb.DeleteObjectsResponse{}
+	for _, path := range req.GetPaths() {
+		err = s.deleteStream(ctx, path, resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, prefix := range req.GetPrefixes() {
+		err = s.deletePrefix(ctx, prefix, resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// deletePrefix deletes the streams of all the objects under prefix in
+// batches
+func (s *Server) deletePrefix(ctx context.Context, prefix string, resp *pb.DeleteObjectsResponse) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if prefix == "" {
+		return status.Error(codes.InvalidArgument, "prefix without bucket")
+	}
+
+	// the pointers cannot be deleted while iterating over them
+	lastPrefix := storage.Key("l/" + prefix + "/")
+	first := lastPrefix
+	for {
+		var paths []string
+		err = s.DB.Iterate(storage.IterateOptions{Prefix: lastPrefix, First: first, Recurse: true},
+			func(it storage.Iterator) error {
+				var item storage.ListItem
+				for len(paths) < deleteBatchSize && it.Next(&item) {
+					paths = append(paths, string(item.Key[len("l/"):]))
+				}
+				return nil
+			})
+		if err != nil {
+			s.logger.Error("err listing pointers", zap.Error(err))
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		for _, path := range paths {
+			err = s.deleteStream(ctx, path, resp)
+			if err != nil {
+				return err
+			}
+		}
+		if len(paths) < deleteBatchSize {
+			return nil
+		}
+		first = storage.NextKey(storage.Key("l/" + paths[len(paths)-1]))
+	}
+}
+
+// deleteStream deletes the segments of the object at path, the last one
+// last, and adds it to resp. The segments of an upload that was canceled
+// before its last segment was stored are deleted without adding it.
+func (s *Server) deleteStream(ctx context.Context, path string, resp *pb.DeleteObjectsResponse) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lastPath := "l/" + path
+	err = s.checkUnlocked(lastPath)
+	if status.Code(err) == codes.PermissionDenied {
+		resp.Locked = append(resp.Locked, path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	last, err := s.getPointer(lastPath)
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		return s.deleteSegmentsUntilMissing(ctx, path)
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(last.GetMetadata(), &streamMeta)
+	if err != nil || streamMeta.NumberOfSegments == 0 {
+		// streams uploaded before the number of segments was recorded
+		err = s.deleteSegmentsUntilMissing(ctx, path)
+	} else {
+		err = s.deleteSegments(ctx, path, streamMeta.NumberOfSegments-1)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.deletePointer(ctx, lastPath, last)
+	if err != nil {
+		return err
+	}
+	s.notify(ctx, notification.ObjectDeleted, lastPath, nil)
+
+	resp.Deleted++
+	return nil
+}
+
+// deleteSegments deletes the first count segments of the stream at path.
+// Segments that are missing already are skipped.
+func (s *Server) deleteSegments(ctx context.Context, path string, count int64) error {
+	for i := int64(0); i < count; i++ {
+		segmentPath := fmt.Sprintf("s%d/%s", i, path)
+		pointer, err := s.getPointer(segmentPath)
+		if err != nil {
+			return err
+		}
+		if pointer == nil {
+			continue
+		}
+		err = s.deletePointer(ctx, segmentPath, pointer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSegmentsUntilMissing deletes the segments of the stream at path from
+// the first one up to the first missing one
+func (s *Server) deleteSegmentsUntilMissing(ctx context.Context, path string) error {
+	for i := 0; ; i++ {
+		segmentPath := fmt.Sprintf("s%d/%s", i, path)
+		pointer, err := s.getPointer(segmentPath)
+		if err != nil {
+			return err
+		}
+		if pointer == nil {
+			return nil
+		}
+		err = s.deletePointer(ctx, segmentPath, pointer)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// getPointer returns the pointer at path, or nil if there is none
+func (s *Server) getPointer(path string) (*pb.Pointer, error) {
+	pointerBytes, err := s.DB.Get([]byte(path))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, nil
+		}
+		s.logger.Error("err getting pointer", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pointer := &pb.Pointer{}
+	err = proto.Unmarshal(pointerBytes, pointer)
+	if err != nil {
+		s.logger.Error("err unmarshaling pointer", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return pointer, nil
+}
+
+// deletePointer deletes the pointer at path and schedules its pieces to be
+// deleted
+func (s *Server) deletePointer(ctx context.Context, path string, pointer *pb.Pointer) error {
+	err := s.DB.Delete([]byte(path))
+	if err != nil {
+		s.logger.Error("err deleting pointer", zap.Error(err))
+		return status.Error(codes.Internal, err.Error())
+	}
+	s.enqueuePieces(ctx, path, pointer)
+	return nil
+}
+
+// enqueuePieces schedules the pieces of the deleted pointer at path to be
+// deleted. The pointer is gone already, so pieces that cannot be queued
+// are left to garbage collection.
+func (s *Server) enqueuePieces(ctx context.Context, path string, pointer *pb.Pointer) {
+	err := s.deleter.enqueue(ctx, pointer)
+	if err != nil {
+		s.logger.Error("err queueing pieces for deletion", zap.String("path", path), zap.Error(err))
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// deletion is a piece waiting to be deleted from a storage node
+type deletion struct {
+	Key         storage.Key  `json:"-"`
+	NodeID      storj.NodeID `json:"-"`
+	PieceID     string       `json:"piece_id"` // derived for the node
+	Sequence    string       `json:"sequence"`
+	Attempts    int          `json:"attempts"`
+	NextAttempt time.Time    `json:"next_attempt"`
+}
+
+// deletionKey returns the key of d. The keys are the node ID, the time of
+// the next attempt and a sequence number, so the pieces of a node are
+// listed together in the order they are due.
+func deletionKey(d deletion) storage.Key {
+	return storage.Key(fmt.Sprintf("%s/%020d/%s", d.NodeID.String(), d.NextAttempt.UnixNano(), d.Sequence))
+}
+
+// deleteQueue keeps the pieces to delete from the storage nodes until they
+// are deleted, so that they survive restarts
+type deleteQueue struct {
+	db storage.KeyValueStore
+
+	mu   sync.Mutex
+	last int64
+}
+
+func newDeleteQueue(db storage.KeyValueStore) *deleteQueue {
+	return &deleteQueue{db: db}
+}
+
+// add stores a new deletion of the piece with pieceID from the node
+func (q *deleteQueue) add(nodeID storj.NodeID, pieceID string, now time.Time) error {
+	d := deletion{
+		NodeID:      nodeID,
+		PieceID:     pieceID,
+		Sequence:    q.nextSequence(now),
+		NextAttempt: now,
+	}
+	d.Key = deletionKey(d)
+	return q.put(d)
+}
+
+// nextSequence returns a sequence number that sorts after all the previous
+// ones
+func (q *deleteQueue) nextSequence(now time.Time) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	seq := now.UnixNano()
+	if seq <= q.last {
+		seq = q.last + 1
+	}
+	q.last = seq
+	return fmt.Sprintf("%020d", seq)
+}
+
+// put stores d under its key
+func (q *deleteQueue) put(d deletion) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(q.db.Put(d.Key, data))
+}
+
+// reschedule moves d to its next attempt at next. The new entry is stored
+// before the old one is removed, so the deletion is not lost in between.
+func (q *deleteQueue) reschedule(d deletion, next time.Time) error {
+	old := d.Key
+	d.NextAttempt = next
+	d.Key = deletionKey(d)
+	if err := q.put(d); err != nil {
+		return err
+	}
+	return Error.Wrap(q.db.Delete(old))
+}
+
+// remove removes d from the queue
+func (q *deleteQueue) remove(d deletion) error {
+	return Error.Wrap(q.db.Delete(d.Key))
+}
+
+// nodes returns the nodes that have pieces waiting to be deleted
+func (q *deleteQueue) nodes() (nodeIDs storj.NodeIDList, err error) {
+	err = q.db.Iterate(storage.IterateOptions{}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			nodeID, err := storj.NodeIDFromString(strings.TrimSuffix(string(item.Key), "/"))
+			if err != nil {
+				return err
+			}
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+		return nil
+	})
+	return nodeIDs, Error.Wrap(err)
+}
+
+// list returns up to limit of the deletions of the node that are due at
+// now, the longest due first
+func (q *deleteQueue) list(nodeID storj.NodeID, now time.Time, limit int) (deletions []deletion, err error) {
+	prefix := storage.Key(nodeID.String() + "/")
+	err = q.db.Iterate(storage.IterateOptions{Prefix: prefix, First: prefix, Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for len(deletions) < limit && it.Next(&item) {
+			d := deletion{Key: storage.CloneKey(item.Key), NodeID: nodeID}
+			err := json.Unmarshal(item.Value, &d)
+			if err != nil {
+				return err
+			}
+			// the entries after it are not due either
+			if d.NextAttempt.After(now) {
+				return nil
+			}
+			deletions = append(deletions, d)
+		}
+		return nil
+	})
+	return deletions, Error.Wrap(err)
+}
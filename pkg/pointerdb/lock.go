@@ -0,0 +1,125 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
+)
+
+// lastSegmentPath returns the path of the last segment of the stream that
+// the segment at path belongs to, which has the lock of the stream. It
+// returns "" if path is not the path of a segment.
+func lastSegmentPath(path string) string {
+	i := strings.IndexByte(path, '/')
+	if i < 0 {
+		return ""
+	}
+	segment, rest := path[:i], path[i+1:]
+	if segment == "l" {
+		return path
+	}
+	if len(segment) < 2 || segment[0] != 's' {
+		return ""
+	}
+	if _, err := strconv.ParseUint(segment[1:], 10, 64); err != nil {
+		return ""
+	}
+	return "l/" + rest
+}
+
+// streamLock returns the lock in the stream metadata of the last segment
+// with metadata
+func streamLock(metadata []byte) (storj.ObjectLock, error) {
+	streamMeta := pb.StreamMeta{}
+	err := proto.Unmarshal(metadata, &streamMeta)
+	if err != nil {
+		return storj.ObjectLock{}, err
+	}
+	return pb.ObjectLockToStorj(streamMeta.Lock)
+}
+
+// checkUnlocked returns an error if the segment at path belongs to a stream
+// that is locked
+func (s *Server) checkUnlocked(path string) error {
+	lastPath := lastSegmentPath(path)
+	if lastPath == "" {
+		return nil
+	}
+
+	pointerBytes, err := s.DB.Get([]byte(lastPath))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil
+		}
+		s.logger.Error("err getting pointer", zap.Error(err))
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	pointer := &pb.Pointer{}
+	err = proto.Unmarshal(pointerBytes, pointer)
+	if err != nil {
+		s.logger.Error("err unmarshaling pointer", zap.Error(err))
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	lock, err := streamLock(pointer.GetMetadata())
+	if err != nil {
+		// the pointer does not have stream metadata, so it cannot be locked
+		return nil
+	}
+	if lock.Active(time.Now()) {
+		return status.Error(codes.PermissionDenied, storj.ErrObjectLocked.New("%s", lastPath).Error())
+	}
+	return nil
+}
+
+// checkLockUpdate returns an error if the metadata of the last segment at
+// path would shorten a retention period that has not ended, or change the
+// stream while it is locked. Only the lock of a locked stream can change, as
+// the rest of its metadata has the keys to decrypt it.
+func checkLockUpdate(path string, current, metadata []byte) error {
+	if lastSegmentPath(path) != path {
+		return nil
+	}
+
+	currentMeta := pb.StreamMeta{}
+	if err := proto.Unmarshal(current, &currentMeta); err != nil {
+		return nil
+	}
+	currentLock, err := pb.ObjectLockToStorj(currentMeta.Lock)
+	if err != nil || !currentLock.Active(time.Now()) {
+		return nil
+	}
+
+	streamMeta := pb.StreamMeta{}
+	if err := proto.Unmarshal(metadata, &streamMeta); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	lock, err := pb.ObjectLockToStorj(streamMeta.Lock)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// legal holds can be removed at any time
+	if time.Now().Before(currentLock.RetainUntil) && lock.RetainUntil.Before(currentLock.RetainUntil) {
+		return status.Error(codes.PermissionDenied, storj.ErrObjectLocked.New("retention of %s cannot be shortened", path).Error())
+	}
+
+	currentMeta.Lock, streamMeta.Lock = nil, nil
+	if !proto.Equal(&currentMeta, &streamMeta) {
+		return status.Error(codes.PermissionDenied, storj.ErrObjectLocked.New("%s", path).Error())
+	}
+	return nil
+}
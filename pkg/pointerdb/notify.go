@@ -0,0 +1,69 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/notification"
+	"storj.io/storj/pkg/pb"
+)
+
+// splitObjectPath returns the bucket and the encrypted path of the object
+// that the last segment at path belongs to
+func splitObjectPath(path string) (bucket, objectPath string, ok bool) {
+	if !strings.HasPrefix(path, "l/") {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(path, "l/"), "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		// the last segments of buckets themselves have no object path
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// notify emits an event if path is the last segment of an object. The
+// pointer is the new pointer of created objects.
+func (s *Server) notify(ctx context.Context, eventType notification.EventType, path string, pointer *pb.Pointer) {
+	if s.notifier == nil {
+		return
+	}
+	bucket, objectPath, ok := splitObjectPath(path)
+	if !ok {
+		return
+	}
+
+	var size int64
+	if pointer != nil {
+		size = s.streamSize(bucket, objectPath, pointer)
+	}
+
+	err := s.notifier.Notify(ctx, eventType, bucket, objectPath, size)
+	if err != nil {
+		s.logger.Error("err emitting event", zap.Error(err))
+	}
+}
+
+// streamSize returns the size of the stream with the last segment pointer,
+// adding up the sizes of the segments before it
+func (s *Server) streamSize(bucket, objectPath string, pointer *pb.Pointer) int64 {
+	size := pointer.GetSegmentSize()
+	for i := 0; ; i++ {
+		pointerBytes, err := s.DB.Get([]byte(fmt.Sprintf("s%d/%s/%s", i, bucket, objectPath)))
+		if err != nil {
+			return size
+		}
+		segment := &pb.Pointer{}
+		if proto.Unmarshal(pointerBytes, segment) != nil {
+			return size
+		}
+		size += segment.GetSegmentSize()
+	}
+}
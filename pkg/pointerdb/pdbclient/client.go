@@ -27,6 +27,7 @@ var (
 
 // PointerDB creates a grpcClient
 type PointerDB struct {
+	conn          *grpc.ClientConn
 	client        pb.PointerDBClient
 	authorization unsafe.Pointer // *pb.SignedMessage
 }
@@ -52,6 +53,8 @@ type Client interface {
 	Get(ctx context.Context, path storj.Path) (*pb.Pointer, []*pb.Node, *pb.PayerBandwidthAllocation, error)
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
 	Delete(ctx context.Context, path storj.Path) error
+	UpdateMetadata(ctx context.Context, path storj.Path, metadata []byte) (*pb.Pointer, error)
+	DeleteObjects(ctx context.Context, options storj.DeleteOptions) (storj.DeleteResult, error)
 
 	SignedMessage() *pb.SignedMessage
 	PayerBandwidthAllocation(context.Context, pb.PayerBandwidthAllocation_Action) (*pb.PayerBandwidthAllocation, error)
@@ -72,7 +75,15 @@ func NewClient(identity *provider.FullIdentity, address string, APIKey string) (
 		return nil, err
 	}
 
-	return &PointerDB{client: pb.NewPointerDBClient(conn)}, nil
+	return &PointerDB{conn: conn, client: pb.NewPointerDBClient(conn)}, nil
+}
+
+// Disconnect closes the connection of a client created with NewClient
+func (pdb *PointerDB) Disconnect() error {
+	if pdb.conn == nil {
+		return nil
+	}
+	return pdb.conn.Close()
 }
 
 // a compiler trick to make sure *PointerDB implements Client
@@ -83,6 +94,9 @@ func (pdb *PointerDB) Put(ctx context.Context, path storj.Path, pointer *pb.Poin
 	defer mon.Task()(&ctx)(&err)
 
 	_, err = pdb.client.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer})
+	if status.Code(err) == codes.PermissionDenied {
+		return storj.ErrObjectLocked.Wrap(err)
+	}
 
 	return err
 }
@@ -138,10 +152,45 @@ func (pdb *PointerDB) Delete(ctx context.Context, path storj.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	_, err = pdb.client.Delete(ctx, &pb.DeleteRequest{Path: path})
+	if status.Code(err) == codes.PermissionDenied {
+		return storj.ErrObjectLocked.Wrap(err)
+	}
 
 	return err
 }
 
+// UpdateMetadata replaces the metadata of the pointer at path without
+// touching its segment
+func (pdb *PointerDB) UpdateMetadata(ctx context.Context, path storj.Path, metadata []byte) (pointer *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := pdb.client.UpdateMetadata(ctx, &pb.UpdateMetadataRequest{Path: path, Metadata: metadata})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			return nil, storage.ErrKeyNotFound.Wrap(err)
+		case codes.PermissionDenied:
+			return nil, storj.ErrObjectLocked.Wrap(err)
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	return res.GetPointer(), nil
+}
+
+// DeleteObjects deletes the streams of the objects selected by options,
+// whose paths start with the bucket
+func (pdb *PointerDB) DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := pdb.client.DeleteObjects(ctx, &pb.DeleteObjectsRequest{Paths: options.Paths, Prefixes: options.Prefixes})
+	if err != nil {
+		return result, Error.Wrap(err)
+	}
+
+	return storj.DeleteResult{Deleted: res.GetDeleted(), Locked: res.GetLocked()}, nil
+}
+
 // PayerBandwidthAllocation gets payer bandwidth allocation message
 func (pdb *PointerDB) PayerBandwidthAllocation(ctx context.Context, action pb.PayerBandwidthAllocation_Action) (resp *pb.PayerBandwidthAllocation, err error) {
 	defer mon.Task()(&ctx)(&err)
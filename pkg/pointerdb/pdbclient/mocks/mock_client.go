@@ -12,6 +12,7 @@ import (
 
 	pb "storj.io/storj/pkg/pb"
 	pdbclient "storj.io/storj/pkg/pointerdb/pdbclient"
+	storj "storj.io/storj/pkg/storj"
 )
 
 // MockClient is a mock of Client interface
@@ -49,6 +50,19 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClient)(nil).Delete), arg0, arg1)
 }
 
+// DeleteObjects mocks base method
+func (m *MockClient) DeleteObjects(arg0 context.Context, arg1 storj.DeleteOptions) (storj.DeleteResult, error) {
+	ret := m.ctrl.Call(m, "DeleteObjects", arg0, arg1)
+	ret0, _ := ret[0].(storj.DeleteResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteObjects indicates an expected call of DeleteObjects
+func (mr *MockClientMockRecorder) DeleteObjects(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjects", reflect.TypeOf((*MockClient)(nil).DeleteObjects), arg0, arg1)
+}
+
 // Get mocks base method
 func (m *MockClient) Get(arg0 context.Context, arg1 string) (*pb.Pointer, []*pb.Node, *pb.PayerBandwidthAllocation, error) {
 	ret := m.ctrl.Call(m, "Get", arg0, arg1)
@@ -114,3 +128,16 @@ func (m *MockClient) SignedMessage() *pb.SignedMessage {
 func (mr *MockClientMockRecorder) SignedMessage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignedMessage", reflect.TypeOf((*MockClient)(nil).SignedMessage))
 }
+
+// UpdateMetadata mocks base method
+func (m *MockClient) UpdateMetadata(arg0 context.Context, arg1 string, arg2 []byte) (*pb.Pointer, error) {
+	ret := m.ctrl.Call(m, "UpdateMetadata", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pb.Pointer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMetadata indicates an expected call of UpdateMetadata
+func (mr *MockClientMockRecorder) UpdateMetadata(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMetadata", reflect.TypeOf((*MockClient)(nil).UpdateMetadata), arg0, arg1, arg2)
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+)
+
+const (
+	// deleteNodeBatchSize is how many pieces are deleted from a node at a
+	// time
+	deleteNodeBatchSize = 100
+	// deleteConcurrency is how many nodes pieces are deleted from at the
+	// same time
+	deleteConcurrency = 10
+)
+
+// pieceDeleter deletes the pieces of deleted segments from the storage
+// nodes in the background, so that deletes do not wait for the nodes. The
+// pieces wait in a durable queue and are retried with exponential backoff
+// until the nodes delete them. Pieces that fail too many times are left
+// for garbage collection.
+type pieceDeleter struct {
+	queue     *deleteQueue
+	cache     *overlay.Cache
+	transport transport.Client
+	sign      func() (*pb.SignedMessage, error)
+	logger    *zap.Logger
+
+	interval    time.Duration
+	maxBackoff  time.Duration
+	maxAttempts int
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+func newPieceDeleter(queue *deleteQueue, cache *overlay.Cache, tc transport.Client, sign func() (*pb.SignedMessage, error), logger *zap.Logger, maxAttempts int) *pieceDeleter {
+	return &pieceDeleter{
+		queue:       queue,
+		cache:       cache,
+		transport:   tc,
+		sign:        sign,
+		logger:      logger,
+		interval:    time.Minute,
+		maxBackoff:  24 * time.Hour,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+}
+
+// enqueue schedules the pieces of the pointer to be deleted. It is safe to
+// call on a nil pieceDeleter, which leaves the pieces to garbage
+// collection.
+func (d *pieceDeleter) enqueue(ctx context.Context, pointer *pb.Pointer) (err error) {
+	remote := pointer.GetRemote()
+	if d == nil || pointer.GetType() != pb.Pointer_REMOTE || remote == nil {
+		return nil
+	}
+	defer mon.Task()(&ctx)(&err)
+
+	now := d.now()
+	for _, piece := range remote.GetRemotePieces() {
+		pieceID, err := psclient.PieceID(remote.GetPieceId()).Derive(piece.NodeId.Bytes())
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		err = d.queue.add(piece.NodeId, pieceID.String(), now)
+		if err != nil {
+			return err
+		}
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// run deletes the queued pieces until ctx is canceled
+func (d *pieceDeleter) run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		err := d.deleteDue(ctx)
+		if err != nil {
+			d.logger.Error("err deleting pieces", zap.Error(err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// deleteDue deletes the pieces whose next attempt is due, from several
+// nodes at a time
+func (d *pieceDeleter) deleteDue(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodeIDs, err := d.queue.nodes()
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	limiter := make(chan struct{}, deleteConcurrency)
+	for _, nodeID := range nodeIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func(nodeID storj.NodeID) {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			err := d.deleteFromNode(ctx, nodeID)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(nodeID)
+	}
+	wg.Wait()
+
+	return utils.CombineErrors(errs...)
+}
+
+// deleteFromNode deletes a batch of the due pieces of the node
+func (d *pieceDeleter) deleteFromNode(ctx context.Context, nodeID storj.NodeID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := d.now()
+	due, err := d.queue.list(nodeID, now, deleteNodeBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	failed := d.deletePieces(ctx, nodeID, due)
+	if failed != nil {
+		d.logger.Debug("err deleting pieces from node", zap.Stringer("node id", nodeID), zap.Error(failed))
+	}
+
+	for _, deletion := range due {
+		if failed == nil {
+			err = d.queue.remove(deletion)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		deletion.Attempts++
+		if deletion.Attempts >= d.maxAttempts {
+			d.logger.Info("giving up deleting piece",
+				zap.Stringer("node id", nodeID), zap.String("piece id", deletion.PieceID), zap.Int("attempts", deletion.Attempts))
+			err = d.queue.remove(deletion)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = d.queue.reschedule(deletion, now.Add(d.backoff(deletion.Attempts)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePieces deletes the pieces of deletions from the node with one
+// request, or one for each piece on nodes without DeleteMany
+func (d *pieceDeleter) deletePieces(ctx context.Context, nodeID storj.NodeID, deletions []deletion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	node, err := d.cache.Get(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return Error.New("node %s not found", nodeID)
+	}
+
+	authorization, err := d.sign()
+	if err != nil {
+		return err
+	}
+
+	ps, err := psclient.NewPSClient(ctx, d.transport, node, 0)
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(ps)
+
+	pieceIDs := make([]psclient.PieceID, len(deletions))
+	for i, deletion := range deletions {
+		pieceIDs[i] = psclient.PieceID(deletion.PieceID)
+	}
+	return psclient.DeletePieces(ctx, ps, pieceIDs, authorization)
+}
+
+// backoff returns how long to wait after the failed attempts to delete a
+// piece
+func (d *pieceDeleter) backoff(attempts int) time.Duration {
+	backoff := d.interval
+	for i := 1; i < attempts && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.maxBackoff {
+		backoff = d.maxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/storj/internal/teststorj"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/psclient"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage/teststore"
+)
+
+// farFuture lists all the deletions of the queue
+var farFuture = time.Now().Add(100 * 365 * 24 * time.Hour)
+
+func TestDeleteQueue(t *testing.T) {
+	queue := newDeleteQueue(teststore.New())
+	node1, node2 := teststorj.NodeIDFromString("node1"), teststorj.NodeIDFromString("node2")
+
+	now := time.Now()
+	require.NoError(t, queue.add(node2, "a", now))
+	require.NoError(t, queue.add(node1, "b", now))
+	require.NoError(t, queue.add(node2, "c", now))
+
+	nodes, err := queue.nodes()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, storj.NodeIDList{node1, node2}, nodes)
+
+	deletions, err := queue.list(node2, now, 10)
+	require.NoError(t, err)
+	require.Len(t, deletions, 2)
+	assert.Equal(t, "a", deletions[0].PieceID)
+	assert.Equal(t, "c", deletions[1].PieceID)
+	assert.Equal(t, node2, deletions[0].NodeID)
+
+	deletions[0].Attempts = 2
+	require.NoError(t, queue.reschedule(deletions[0], now.Add(time.Hour)))
+	require.NoError(t, queue.remove(deletions[1]))
+
+	// the deletions that are not due are not listed
+	deletions, err = queue.list(node2, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deletions)
+
+	deletions, err = queue.list(node2, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	assert.Equal(t, 2, deletions[0].Attempts)
+
+	deletions, err = queue.list(node1, now, 10)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	assert.Equal(t, "b", deletions[0].PieceID)
+}
+
+func TestDeleteQueueDueFirst(t *testing.T) {
+	queue := newDeleteQueue(teststore.New())
+	nodeID := teststorj.NodeIDFromString("node")
+
+	now := time.Now()
+	for _, pieceID := range []string{"a", "b", "c"} {
+		require.NoError(t, queue.add(nodeID, pieceID, now))
+	}
+
+	// the deletions that are backed off do not hide the due ones behind them
+	deletions, err := queue.list(nodeID, now, 2)
+	require.NoError(t, err)
+	require.Len(t, deletions, 2)
+	for _, d := range deletions {
+		require.NoError(t, queue.reschedule(d, now.Add(time.Hour)))
+	}
+
+	deletions, err = queue.list(nodeID, now, 2)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	assert.Equal(t, "c", deletions[0].PieceID)
+}
+
+func TestPieceDeleterRetries(t *testing.T) {
+	ctx := context.Background()
+
+	queue := newDeleteQueue(teststore.New())
+	// the node is not in the cache, so it cannot be reached
+	cache := overlay.NewOverlayCache(teststore.New(), nil, nil)
+	deleter := newPieceDeleter(queue, cache, nil, nil, zap.NewNop(), 3)
+	now := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	deleter.now = func() time.Time { return now }
+
+	nodeID := teststorj.NodeIDFromString("node")
+	require.NoError(t, deleter.enqueue(ctx, &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			PieceId:      "piece",
+			RemotePieces: []*pb.RemotePiece{{NodeId: nodeID}},
+		},
+	}))
+	// inline segments have no pieces to delete
+	require.NoError(t, deleter.enqueue(ctx, &pb.Pointer{Type: pb.Pointer_INLINE}))
+
+	derived, err := psclient.PieceID("piece").Derive(nodeID.Bytes())
+	require.NoError(t, err)
+	deletions, err := queue.list(nodeID, farFuture, 10)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	assert.Equal(t, derived.String(), deletions[0].PieceID)
+
+	// a failed delete is retried after a backoff
+	require.NoError(t, deleter.deleteDue(ctx))
+	deletions, err = queue.list(nodeID, farFuture, 10)
+	require.NoError(t, err)
+	require.Len(t, deletions, 1)
+	assert.Equal(t, 1, deletions[0].Attempts)
+	assert.Equal(t, now.Add(deleter.interval), deletions[0].NextAttempt.UTC())
+
+	// the piece is not tried before its next attempt
+	require.NoError(t, deleter.deleteDue(ctx))
+	deletions, err = queue.list(nodeID, farFuture, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deletions[0].Attempts)
+
+	// the piece is given up after too many attempts
+	for i := 1; i < deleter.maxAttempts; i++ {
+		now = now.Add(deleter.maxBackoff)
+		require.NoError(t, deleter.deleteDue(ctx))
+	}
+	deletions, err = queue.list(nodeID, farFuture, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deletions)
+}
+
+func TestPieceDeleterBackoff(t *testing.T) {
+	deleter := newPieceDeleter(nil, nil, nil, nil, zap.NewNop(), 10)
+	deleter.interval = time.Minute
+	deleter.maxBackoff = 5 * time.Minute
+
+	for attempts, backoff := range []time.Duration{
+		time.Minute, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute,
+	} {
+		assert.Equal(t, backoff, deleter.backoff(attempts), attempts)
+	}
+}
@@ -16,11 +16,13 @@ import (
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/auth"
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
 	pointerdbAuth "storj.io/storj/pkg/pointerdb/auth"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/transport"
 	"storj.io/storj/storage"
 )
 
@@ -36,6 +38,8 @@ type Server struct {
 	config   Config
 	cache    *overlay.Cache
 	identity *provider.FullIdentity
+	notifier *notification.Notifier
+	deleter  *pieceDeleter
 }
 
 // NewServer creates instance of Server
@@ -49,6 +53,23 @@ func NewServer(db storage.KeyValueStore, cache *overlay.Cache, logger *zap.Logge
 	}
 }
 
+// EnableDeletion makes the server delete the pieces of the deleted
+// segments from the storage nodes, queueing them in db until Run deletes
+// them. Without it the pieces are left to garbage collection.
+func (s *Server) EnableDeletion(db storage.KeyValueStore) {
+	s.deleter = newPieceDeleter(newDeleteQueue(db), s.cache, transport.NewClient(s.identity),
+		s.SignedMessage, s.logger, s.config.DeletionMaxAttempts)
+}
+
+// Run deletes the pieces of the deleted segments from the storage nodes
+// until ctx is canceled
+func (s *Server) Run(ctx context.Context) error {
+	if s.deleter == nil {
+		return nil
+	}
+	return s.deleter.run(ctx)
+}
+
 func (s *Server) validateAuth(ctx context.Context) error {
 	APIKey, ok := auth.GetAPIKey(ctx)
 	if !ok || !pointerdbAuth.ValidateAPIKey(string(APIKey)) {
@@ -90,6 +111,10 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 		return nil, err
 	}
 
+	if err = s.checkUnlocked(req.GetPath()); err != nil {
+		return nil, err
+	}
+
 	// Update the pointer with the creation date
 	req.GetPointer().CreationDate = ptypes.TimestampNow()
 
@@ -106,6 +131,7 @@ func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutRespo
 		s.logger.Error("err putting pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
+	s.notify(ctx, notification.ObjectCreated, req.GetPath(), req.GetPointer())
 
 	return &pb.PutResponse{}, nil
 }
@@ -140,7 +166,7 @@ func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (resp *pb.GetRespo
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
-	authorization, err := s.getSignedMessage()
+	authorization, err := s.SignedMessage()
 	if err != nil {
 		s.logger.Error("err getting signed message", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
@@ -271,15 +297,77 @@ func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (resp *pb.De
 		return nil, err
 	}
 
+	if err = s.checkUnlocked(req.GetPath()); err != nil {
+		return nil, err
+	}
+
+	// the pieces of the pointer are deleted after it
+	pointer, err := s.getPointer(req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.DB.Delete([]byte(req.GetPath()))
 	if err != nil {
 		s.logger.Error("err deleting path and pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
+	s.enqueuePieces(ctx, req.GetPath(), pointer)
+	s.notify(ctx, notification.ObjectDeleted, req.GetPath(), nil)
 
 	return &pb.DeleteResponse{}, nil
 }
 
+// UpdateMetadata replaces the metadata of the pointer at a path, keeping
+// its segment and creation date
+func (s *Server) UpdateMetadata(ctx context.Context, req *pb.UpdateMetadataRequest) (resp *pb.UpdateMetadataResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = s.validateAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	pointerBytes, err := s.DB.Get([]byte(req.GetPath()))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		s.logger.Error("err getting pointer", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	pointer := &pb.Pointer{}
+	err = proto.Unmarshal(pointerBytes, pointer)
+	if err != nil {
+		s.logger.Error("err unmarshaling pointer", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// the segments of a locked stream other than its last cannot change
+	if lastSegmentPath(req.GetPath()) != req.GetPath() {
+		err = s.checkUnlocked(req.GetPath())
+	} else {
+		err = checkLockUpdate(req.GetPath(), pointer.Metadata, req.GetMetadata())
+	}
+	if err != nil {
+		return nil, err
+	}
+	pointer.Metadata = req.GetMetadata()
+
+	pointerBytes, err = proto.Marshal(pointer)
+	if err != nil {
+		s.logger.Error("err marshaling pointer", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if err = s.DB.Put([]byte(req.GetPath()), pointerBytes); err != nil {
+		s.logger.Error("err putting pointer", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.UpdateMetadataResponse{Pointer: pointer}, nil
+}
+
 // Iterate iterates over items based on IterateRequest
 func (s *Server) Iterate(ctx context.Context, req *pb.IterateRequest, f func(it storage.Iterator) error) error {
 	opts := storage.IterateOptions{
@@ -318,7 +406,9 @@ func (s *Server) PayerBandwidthAllocation(ctx context.Context, req *pb.PayerBand
 	return &pb.PayerBandwidthAllocationResponse{Pba: &pb.PayerBandwidthAllocation{Signature: signature, Data: data}}, nil
 }
 
-func (s *Server) getSignedMessage() (*pb.SignedMessage, error) {
+// SignedMessage returns the authorization of the satellite for storage
+// node requests
+func (s *Server) SignedMessage() (*pb.SignedMessage, error) {
 	signature, err := auth.GenerateSignature(s.identity.ID.Bytes(), s.identity)
 	if err != nil {
 		return nil, err
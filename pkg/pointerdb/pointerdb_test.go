@@ -10,11 +10,13 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -22,7 +24,9 @@ import (
 	"google.golang.org/grpc/status"
 
 	"storj.io/storj/internal/identity"
+	"storj.io/storj/internal/teststorj"
 	"storj.io/storj/pkg/auth"
+	"storj.io/storj/pkg/notification"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/storage"
@@ -141,7 +145,7 @@ func TestServiceDelete(t *testing.T) {
 		path := "a/b/c"
 
 		db := teststore.New()
-		_ = db.Put(storage.Key(path), storage.Value("hello"))
+		_ = db.Put(storage.Key(path), mustMarshal(t, &pb.Pointer{}))
 		s := Server{DB: db, logger: zap.NewNop()}
 
 		if tt.err != nil {
@@ -159,6 +163,111 @@ func TestServiceDelete(t *testing.T) {
 	}
 }
 
+func TestServiceUpdateMetadata(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), nil)
+
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop()}
+
+	path := "a/b/c"
+	created := ptypes.TimestampNow()
+	pr := &pb.Pointer{SegmentSize: 123, CreationDate: created, Metadata: []byte("old")}
+	prBytes, err := proto.Marshal(pr)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(storage.Key(path), storage.Value(prBytes)))
+
+	resp, err := s.UpdateMetadata(ctx, &pb.UpdateMetadataRequest{Path: path, Metadata: []byte("new")})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new"), resp.GetPointer().GetMetadata())
+
+	got, err := db.Get(storage.Key(path))
+	assert.NoError(t, err)
+	updated := &pb.Pointer{}
+	assert.NoError(t, proto.Unmarshal(got, updated))
+	pr.Metadata = []byte("new")
+	assert.True(t, proto.Equal(pr, updated), "only the metadata changes")
+
+	_, err = s.UpdateMetadata(ctx, &pb.UpdateMetadataRequest{Path: "a/b/d", Metadata: []byte("new")})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.UpdateMetadata(auth.WithAPIKey(context.Background(), []byte("wrong key")),
+		&pb.UpdateMetadataRequest{Path: path})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServiceObjectLock(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), nil)
+
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop()}
+
+	streamMeta := func(retainUntil time.Time, legalHold bool) []byte {
+		retain, err := ptypes.TimestampProto(retainUntil)
+		assert.NoError(t, err)
+		data, err := proto.Marshal(&pb.StreamMeta{Lock: &pb.ObjectLock{RetainUntil: retain, LegalHold: legalHold}})
+		assert.NoError(t, err)
+		return data
+	}
+	put := func(path string, metadata []byte) error {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{Metadata: metadata}})
+		return err
+	}
+	del := func(path string) error {
+		_, err := s.Delete(ctx, &pb.DeleteRequest{Path: path})
+		return err
+	}
+	update := func(path string, metadata []byte) error {
+		_, err := s.UpdateMetadata(ctx, &pb.UpdateMetadataRequest{Path: path, Metadata: metadata})
+		return err
+	}
+
+	retainUntil := time.Now().Add(time.Hour)
+	assert.NoError(t, put("s0/bucket/object", nil))
+	assert.NoError(t, put("l/bucket/object", streamMeta(retainUntil, false)))
+
+	// the segments of a locked stream cannot change
+	for _, path := range []string{"s0/bucket/object", "s1/bucket/object", "l/bucket/object"} {
+		assert.Equal(t, codes.PermissionDenied, status.Code(put(path, nil)), path)
+		assert.Equal(t, codes.PermissionDenied, status.Code(del(path)), path)
+	}
+	assert.NoError(t, put("s0/bucket/other", nil))
+	assert.NoError(t, put("sx/bucket/object", nil))
+
+	// nor can their metadata, which has the keys to decrypt them
+	assert.Equal(t, codes.PermissionDenied, status.Code(update("s0/bucket/object", []byte("garbage"))))
+	for _, meta := range []*pb.StreamMeta{
+		{EncryptedStreamInfo: []byte("garbage")},
+		{LastSegmentMeta: &pb.SegmentMeta{EncryptedKey: []byte("garbage")}},
+		{EncryptionType: 1, EncryptionBlockSize: 1024},
+	} {
+		retain, err := ptypes.TimestampProto(retainUntil)
+		assert.NoError(t, err)
+		meta.Lock = &pb.ObjectLock{RetainUntil: retain}
+		assert.Equal(t, codes.PermissionDenied, status.Code(update("l/bucket/object", mustMarshal(t, meta))), meta.String())
+	}
+
+	// the retention can be extended but not shortened
+	assert.Equal(t, codes.PermissionDenied, status.Code(update("l/bucket/object", streamMeta(retainUntil.Add(-time.Minute), false))))
+	assert.Equal(t, codes.PermissionDenied, status.Code(update("l/bucket/object", nil)))
+	assert.NoError(t, update("l/bucket/object", streamMeta(retainUntil.Add(time.Minute), true)))
+
+	// a legal hold locks the stream after its retention period
+	assert.NoError(t, db.Put(storage.Key("l/bucket/object"), mustMarshal(t, &pb.Pointer{Metadata: streamMeta(time.Now().Add(-time.Hour), true)})))
+	assert.Equal(t, codes.PermissionDenied, status.Code(del("s0/bucket/object")))
+	assert.Equal(t, codes.PermissionDenied, status.Code(update("s0/bucket/object", nil)))
+	assert.Equal(t, codes.PermissionDenied, status.Code(update("l/bucket/object",
+		mustMarshal(t, &pb.StreamMeta{EncryptedStreamInfo: []byte("garbage"), Lock: &pb.ObjectLock{LegalHold: true}}))))
+	assert.NoError(t, update("l/bucket/object", streamMeta(time.Time{}, false)))
+	assert.NoError(t, del("s0/bucket/object"))
+	assert.NoError(t, del("l/bucket/object"))
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+	return data
+}
+
 func TestServiceList(t *testing.T) {
 	db := teststore.New()
 	server := Server{DB: db, logger: zap.NewNop()}
@@ -342,3 +451,100 @@ func TestServiceList(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceNotify(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), nil)
+
+	outbox := notification.NewOutbox(teststore.New())
+	rules := notification.Rules{{Bucket: "bucket"}}
+	s := Server{
+		DB:       teststore.New(),
+		logger:   zap.NewNop(),
+		notifier: notification.NewNotifier(rules, outbox, "http://localhost", "", zap.NewNop()),
+	}
+
+	put := func(path string, size int64) {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{SegmentSize: size}})
+		assert.NoError(t, err)
+	}
+	put("l/bucket", 0)
+	put("s0/bucket/enc/path", 100)
+	put("s1/bucket/enc/path", 100)
+	put("l/bucket/enc/path", 50)
+	put("l/other/enc/path", 50)
+	_, err := s.Delete(ctx, &pb.DeleteRequest{Path: "l/bucket/enc/path"})
+	assert.NoError(t, err)
+
+	entries, err := outbox.List(time.Now(), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		created, deleted := entries[0].Event, entries[1].Event
+		assert.Equal(t, notification.ObjectCreated, created.Type)
+		assert.Equal(t, "bucket", created.Bucket)
+		assert.Equal(t, "enc/path", created.Path)
+		assert.Equal(t, int64(250), created.Size)
+		assert.Equal(t, notification.ObjectDeleted, deleted.Type)
+		assert.Equal(t, "enc/path", deleted.Path)
+	}
+}
+
+func TestServiceDeleteObjects(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), nil)
+
+	db := teststore.New()
+	queue := newDeleteQueue(teststore.New())
+	s := Server{DB: db, logger: zap.NewNop(), deleter: newPieceDeleter(queue, nil, nil, nil, zap.NewNop(), 10)}
+
+	nodeID := teststorj.NodeIDFromString("node")
+	remote := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{
+		PieceId:      "piece",
+		RemotePieces: []*pb.RemotePiece{{NodeId: nodeID}},
+	}}
+	for _, path := range []string{
+		"l/bucket",
+		"s0/bucket/a", "l/bucket/a",
+		"s0/bucket/canceled", "s1/bucket/canceled",
+		"s0/bucket/dir/b", "s1/bucket/dir/b", "l/bucket/dir/b",
+		"l/bucket/dir/sub/c",
+		"l/bucket/dirty",
+		"l/other/dir/d",
+	} {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: remote})
+		require.NoError(t, err)
+	}
+
+	retainUntil, err := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	locked := &pb.Pointer{Metadata: mustMarshal(t, &pb.StreamMeta{Lock: &pb.ObjectLock{RetainUntil: retainUntil}})}
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/bucket/dir/locked", Pointer: locked})
+	require.NoError(t, err)
+
+	// the segments are found by the number in the stream metadata, even
+	// after a missing one
+	counted := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: remote.Remote,
+		Metadata: mustMarshal(t, &pb.StreamMeta{NumberOfSegments: 4})}
+	for _, path := range []string{"s0/bucket/dir/e", "s2/bucket/dir/e", "l/bucket/dir/e"} {
+		_, err = s.Put(ctx, &pb.PutRequest{Path: path, Pointer: counted})
+		require.NoError(t, err)
+	}
+
+	resp, err := s.DeleteObjects(ctx, &pb.DeleteObjectsRequest{
+		Paths:    []string{"bucket/a", "bucket/canceled", "bucket/missing"},
+		Prefixes: []string{"bucket/dir"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), resp.Deleted)
+	assert.Equal(t, []string{"bucket/dir/locked"}, resp.Locked)
+
+	keys, err := storage.ListKeys(db, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"l/bucket", "l/bucket/dir/locked", "l/bucket/dirty", "l/other/dir/d"}, keys.Strings())
+
+	// the pieces of all the deleted remote segments are queued
+	deletions, err := queue.list(nodeID, time.Now(), 100)
+	require.NoError(t, err)
+	assert.Len(t, deletions, 11)
+
+	_, err = s.DeleteObjects(ctx, &pb.DeleteObjectsRequest{Prefixes: []string{""}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
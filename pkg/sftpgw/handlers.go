@@ -0,0 +1,541 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sftpgw
+
+import (
+	"context"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/sftp"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+const (
+	// readWindow is the amount of already read data kept for serving reads
+	// that arrive out of order
+	readWindow = 4 << 20
+
+	// maxPendingWrites is the amount of data received ahead of the upload
+	// position that is buffered before the upload fails
+	maxPendingWrites = 16 << 20
+)
+
+// handlers serves the SFTP requests of a user from the objects under prefix
+type handlers struct {
+	store  objects.Store
+	prefix storj.Path
+	conn   *sessionConn
+}
+
+// newHandlers returns the sftp.Handlers serving the objects of store under
+// prefix on conn. The prefix is the root directory of the SFTP session.
+func newHandlers(store objects.Store, prefix storj.Path, conn *sessionConn) sftp.Handlers {
+	h := &handlers{store: store, prefix: strings.Trim(prefix, "/"), conn: conn}
+	return sftp.Handlers{FileGet: h, FilePut: h, FileCmd: h, FileList: h}
+}
+
+// sessionConn is the connection of an SFTP session. It records whether the
+// client is gone: the request server closes the files that are still open
+// after the connection is lost, which must not complete their uploads.
+type sessionConn struct {
+	io.ReadWriteCloser
+	lost int32
+}
+
+func (c *sessionConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	if err != nil {
+		atomic.StoreInt32(&c.lost, 1)
+	}
+	return n, err
+}
+
+// isLost returns whether the client is gone
+func (c *sessionConn) isLost() bool {
+	return atomic.LoadInt32(&c.lost) != 0
+}
+
+// objectPath converts an SFTP path to an object path
+func (h *handlers) objectPath(name string) storj.Path {
+	p := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if h.prefix == "" {
+		return p
+	}
+	if p == "" {
+		return h.prefix
+	}
+	return storj.JoinPaths(h.prefix, p)
+}
+
+// Fileread implements sftp.FileReader
+func (h *handlers) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	ctx := r.Context()
+	rr, _, err := h.store.Get(ctx, h.objectPath(r.Filepath))
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return newReaderAt(ctx, rr), nil
+}
+
+// Filewrite implements sftp.FileWriter. The object is replaced by the
+// uploaded data when the client closes the file.
+func (h *handlers) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	p := h.objectPath(r.Filepath)
+	if p == h.prefix {
+		return nil, sftp.ErrSshFxPermissionDenied
+	}
+
+	metadata := pb.SerializableMeta{ContentType: mime.TypeByExtension(path.Ext(p))}
+	return newWriterAt(r.Context(), h.store, p, metadata, h.conn), nil
+}
+
+// Filecmd implements sftp.FileCmder
+func (h *handlers) Filecmd(r *sftp.Request) error {
+	ctx := r.Context()
+	p := h.objectPath(r.Filepath)
+
+	switch r.Method {
+	case "Setstat":
+		// objects have no permissions and their timestamps are set by the
+		// network, so attribute changes are accepted and ignored
+		return nil
+	case "Rename":
+		return h.rename(ctx, p, h.objectPath(r.Target))
+	case "Rmdir":
+		return h.rmdir(ctx, p)
+	case "Mkdir":
+		return h.mkdir(ctx, p)
+	case "Remove":
+		return convertError(h.store.Delete(ctx, p))
+	}
+
+	return sftp.ErrSshFxOpUnsupported
+}
+
+// Filelist implements sftp.FileLister
+func (h *handlers) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	ctx := r.Context()
+	p := h.objectPath(r.Filepath)
+
+	switch r.Method {
+	case "List":
+		infos, err := h.list(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt(infos), nil
+	case "Stat":
+		info, err := h.stat(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		return listerAt{info}, nil
+	}
+
+	return nil, sftp.ErrSshFxOpUnsupported
+}
+
+// stat returns the file info of the object or directory p
+func (h *handlers) stat(ctx context.Context, p storj.Path) (os.FileInfo, error) {
+	if p == h.prefix {
+		return &fileInfo{name: "/", dir: true}, nil
+	}
+
+	m, err := h.store.Meta(ctx, p)
+	if err == nil {
+		return objectInfo(path.Base(p), m), nil
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return nil, err
+	}
+
+	exists, err := h.hasChildren(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sftp.ErrSshFxNoSuchFile
+	}
+	return &fileInfo{name: path.Base(p), dir: true}, nil
+}
+
+// list returns the objects and prefixes directly under the directory p
+func (h *handlers) list(ctx context.Context, p storj.Path) (infos []os.FileInfo, err error) {
+	err = objects.ListAll(ctx, h.store, p, false, meta.Modified|meta.Size, func(items []objects.ListItem) error {
+		for _, item := range items {
+			name := strings.TrimSuffix(item.Path, "/")
+			if name == "" {
+				// the marker of this directory
+				continue
+			}
+
+			if item.IsPrefix {
+				infos = append(infos, &fileInfo{name: name, dir: true})
+				continue
+			}
+
+			infos = append(infos, objectInfo(name, item.Meta))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(infos) == 0 && p != h.prefix {
+		// an empty listing is fine for an empty directory, but not for
+		// something that is not a directory at all
+		info, err := h.stat(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, sftp.ErrSshFxFailure
+		}
+	}
+
+	return infos, nil
+}
+
+// hasChildren returns whether any object has the prefix p
+func (h *handlers) hasChildren(ctx context.Context, p storj.Path) (bool, error) {
+	items, _, err := h.store.List(ctx, p, "", "", false, 1, meta.None)
+	if err != nil {
+		return false, err
+	}
+	return len(items) > 0, nil
+}
+
+// mkdir creates an empty directory marker object
+func (h *handlers) mkdir(ctx context.Context, p storj.Path) error {
+	if _, err := h.stat(ctx, p); err == nil {
+		return sftp.ErrSshFxFailure
+	}
+
+	return objects.PutDirectory(ctx, h.store, p)
+}
+
+// rmdir deletes the marker of an empty directory
+func (h *handlers) rmdir(ctx context.Context, p storj.Path) error {
+	if p == h.prefix {
+		return sftp.ErrSshFxPermissionDenied
+	}
+
+	items, _, err := h.store.List(ctx, p, "", "", true, 2, meta.None)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Path != "" {
+			// not empty
+			return sftp.ErrSshFxFailure
+		}
+	}
+	if len(items) == 0 {
+		return sftp.ErrSshFxNoSuchFile
+	}
+
+	return convertError(h.store.Delete(ctx, p+"/"))
+}
+
+// rename moves the object oldPath, or all objects under the prefix oldPath,
+// to newPath. Objects are moved by copying them and deleting the originals.
+func (h *handlers) rename(ctx context.Context, oldPath, newPath storj.Path) error {
+	if oldPath == h.prefix || newPath == h.prefix || strings.HasPrefix(newPath+"/", oldPath+"/") {
+		return sftp.ErrSshFxPermissionDenied
+	}
+
+	err := objects.Move(ctx, h.store, oldPath, newPath)
+	if !storj.ErrObjectNotFound.Has(err) {
+		return err
+	}
+
+	// not an object, so move everything under the prefix
+	moved := false
+	err = objects.ListAll(ctx, h.store, oldPath, true, meta.None, func(items []objects.ListItem) error {
+		for _, item := range items {
+			err := objects.Move(ctx, h.store, storj.JoinPaths(oldPath, item.Path), storj.JoinPaths(newPath, item.Path))
+			if err != nil {
+				return err
+			}
+			moved = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return sftp.ErrSshFxNoSuchFile
+	}
+	return nil
+}
+
+// convertError converts the errors of the objects.Store to SFTP status
+// errors where possible
+func convertError(err error) error {
+	if storj.ErrObjectNotFound.Has(err) {
+		return sftp.ErrSshFxNoSuchFile
+	}
+	return err
+}
+
+// listerAt implements sftp.ListerAt for a complete listing
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(infos []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+
+	n := copy(infos, l[offset:])
+	if n < len(infos) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fileInfo is the os.FileInfo of an object or a directory
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func objectInfo(name string, m objects.Meta) *fileInfo {
+	return &fileInfo{name: name, size: m.Size, modTime: m.Modified}
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.dir }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// readerAt serves the reads of an SFTP file from a single ranged request.
+// SFTP clients send many reads in parallel, so they arrive slightly out of
+// order. The most recently read data is kept to serve them without starting
+// a new request.
+type readerAt struct {
+	ctx context.Context
+	rr  ranger.Ranger
+
+	mu     sync.Mutex
+	reader io.ReadCloser // reads the data following the window
+	start  int64         // offset of the first byte in window
+	window []byte
+}
+
+func newReaderAt(ctx context.Context, rr ranger.Ranger) *readerAt {
+	return &readerAt{ctx: ctx, rr: rr}
+}
+
+func (r *readerAt) ReadAt(p []byte, off int64) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	size := r.rr.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+
+	end := off + int64(len(p))
+	if end > size {
+		end = size
+	}
+
+	if off < r.start || off > r.start+int64(len(r.window))+readWindow {
+		r.reset(off)
+	}
+
+	err = r.fill(end)
+	if err != nil {
+		return 0, err
+	}
+
+	n = copy(p, r.window[off-r.start:end-r.start])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fill reads from the stream until the window ends at end
+func (r *readerAt) fill(end int64) (err error) {
+	pos := r.start + int64(len(r.window))
+	if pos >= end {
+		return nil
+	}
+
+	if r.reader == nil {
+		r.reader, err = r.rr.Range(r.ctx, pos, r.rr.Size()-pos)
+		if err != nil {
+			return err
+		}
+	}
+
+	n := len(r.window)
+	r.window = append(r.window, make([]byte, end-pos)...)
+	_, err = io.ReadFull(r.reader, r.window[n:])
+	if err != nil {
+		r.reset(r.start)
+		return err
+	}
+
+	// keep at most readWindow bytes, moving them to the front only
+	// occasionally to avoid copying on every read
+	if len(r.window) > 2*readWindow {
+		drop := len(r.window) - readWindow
+		n := copy(r.window, r.window[drop:])
+		r.window = r.window[:n]
+		r.start += int64(drop)
+	}
+
+	return nil
+}
+
+// reset discards the window and the stream, so the next read starts a new
+// request at off
+func (r *readerAt) reset(off int64) {
+	if r.reader != nil {
+		utils.LogClose(r.reader)
+		r.reader = nil
+	}
+	r.start = off
+	r.window = r.window[:0]
+}
+
+func (r *readerAt) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reset(0)
+	return nil
+}
+
+// writerAt streams the writes of an SFTP file to the store. Writes ahead of
+// the upload position are buffered until the data before them arrives.
+type writerAt struct {
+	pw   *io.PipeWriter
+	done chan error
+	conn *sessionConn
+
+	mu           sync.Mutex
+	written      int64
+	pending      map[int64][]byte
+	pendingBytes int
+}
+
+func newWriterAt(ctx context.Context, store objects.Store, p storj.Path, metadata pb.SerializableMeta, conn *sessionConn) *writerAt {
+	pr, pw := io.Pipe()
+	w := &writerAt{
+		pw:      pw,
+		done:    make(chan error, 1),
+		conn:    conn,
+		pending: make(map[int64][]byte),
+	}
+
+	go func() {
+		_, err := store.Put(ctx, p, pr, metadata, time.Time{})
+		// unblock the writer if the upload failed before reading all data
+		_ = pr.CloseWithError(err)
+		w.done <- err
+	}()
+
+	return w
+}
+
+func (w *writerAt) WriteAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if off < w.written {
+		return 0, Error.New("overwriting uploaded data is not supported")
+	}
+
+	if off > w.written {
+		if w.pendingBytes+len(p) > maxPendingWrites {
+			return 0, Error.New("too many out of order writes")
+		}
+		w.pending[off] = append([]byte(nil), p...)
+		w.pendingBytes += len(p)
+		return len(p), nil
+	}
+
+	err := w.write(p)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, ok := w.pending[w.written]
+		if !ok {
+			return len(p), nil
+		}
+		delete(w.pending, w.written)
+		w.pendingBytes -= len(next)
+
+		err = w.write(next)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (w *writerAt) write(p []byte) error {
+	n, err := w.pw.Write(p)
+	w.written += int64(n)
+	return err
+}
+
+// Close completes the upload, unless the client is gone without closing
+// the file or the file has gaps, which abort it
+func (w *writerAt) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	switch {
+	case w.conn != nil && w.conn.isLost():
+		err = Error.New("connection lost during the upload")
+	case len(w.pending) > 0:
+		err = Error.New("file has gaps")
+	}
+	if err != nil {
+		_ = w.pw.CloseWithError(err)
+		<-w.done
+		return err
+	}
+
+	err = w.pw.Close()
+	if err != nil {
+		return err
+	}
+	return <-w.done
+}
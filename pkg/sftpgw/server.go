@@ -0,0 +1,180 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sftpgw
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pkg/sftp"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+// Error is the errs class of the SFTP gateway
+var Error = errs.Class("sftp gateway error")
+
+// Server serves the objects of its users over SFTP
+type Server struct {
+	config *ssh.ServerConfig
+
+	mu    sync.RWMutex
+	users map[string]*user
+}
+
+// user is an SFTP user with the keys it authenticates with and the
+// objects it can access
+type user struct {
+	keys   [][]byte // wire format of the authorized public keys
+	store  objects.Store
+	prefix storj.Path
+}
+
+// NewServer creates an SFTP server identified by hostKey
+func NewServer(hostKey ssh.Signer) *Server {
+	s := &Server{users: make(map[string]*user)}
+	s.config = &ssh.ServerConfig{PublicKeyCallback: s.authenticate}
+	s.config.AddHostKey(hostKey)
+	return s
+}
+
+// AddUser allows the user name to log in with any of keys. The root
+// directory of the user is prefix in store.
+func (s *Server) AddUser(name string, keys []ssh.PublicKey, store objects.Store, prefix storj.Path) {
+	u := &user{store: store, prefix: prefix}
+	for _, key := range keys {
+		u.keys = append(u.keys, key.Marshal())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[name] = u
+}
+
+func (s *Server) getUser(name string) *user {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.users[name]
+}
+
+// authenticate accepts the public keys authorized for the user
+func (s *Server) authenticate(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	if u := s.getUser(conn.User()); u != nil {
+		marshaled := key.Marshal()
+		for _, authorized := range u.keys {
+			if bytes.Equal(marshaled, authorized) {
+				return &ssh.Permissions{}, nil
+			}
+		}
+	}
+	return nil, Error.New("unknown public key for %q", conn.User())
+}
+
+// Serve accepts connections on lis until ctx is canceled
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		_ = lis.Close()
+	}()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return Error.Wrap(err)
+		}
+
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// serveConn serves the SFTP sessions of a single SSH connection
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	sconn, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		zap.S().Debugf("ssh handshake failed: %v", err)
+		utils.LogClose(conn)
+		return
+	}
+	defer utils.LogClose(sconn)
+
+	// closing the connection when ctx is canceled ends the sessions
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			utils.LogClose(sconn)
+		case <-done:
+		}
+	}()
+
+	go ssh.DiscardRequests(requests)
+
+	u := s.getUser(sconn.User())
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			zap.S().Debugf("failed to accept channel: %v", err)
+			continue
+		}
+
+		go serveSession(u, channel, requests)
+	}
+}
+
+// serveSession serves the sftp subsystem on a session channel. Shells and
+// commands are refused.
+func serveSession(u *user, channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer utils.LogClose(channel)
+
+	for req := range requests {
+		ok := req.Type == "subsystem" && subsystem(req.Payload) == "sftp"
+		if req.WantReply {
+			_ = req.Reply(ok, nil)
+		}
+		if !ok {
+			continue
+		}
+
+		conn := &sessionConn{ReadWriteCloser: channel}
+		server := sftp.NewRequestServer(conn, newHandlers(u.store, u.prefix, conn))
+		err := server.Serve()
+		if err != nil && err != io.EOF {
+			zap.S().Debugf("sftp session ended: %v", err)
+		}
+		return
+	}
+}
+
+// subsystem returns the name in the payload of a subsystem request
+func subsystem(payload []byte) string {
+	if len(payload) < 4 {
+		return ""
+	}
+	length := binary.BigEndian.Uint32(payload)
+	if uint64(length) > uint64(len(payload)-4) {
+		return ""
+	}
+	return string(payload[4 : 4+length])
+}
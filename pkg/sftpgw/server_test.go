@@ -0,0 +1,201 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sftpgw
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testobjects"
+	"storj.io/storj/pkg/pb"
+)
+
+func newSigner(t *testing.T) ssh.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return signer
+}
+
+func dial(addr string, name string, signer ssh.Signer) (*sftp.Client, error) {
+	conn, err := ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            name,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sftp.NewClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store := testobjects.NewStore()
+	userKey := newSigner(t)
+
+	server := NewServer(newSigner(t))
+	server.AddUser("partner", []ssh.PublicKey{userKey.PublicKey()}, store, "incoming")
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	serveCtx, cancel := context.WithCancel(ctx)
+	ctx.Go(func() error { return server.Serve(serveCtx, lis) })
+	defer cancel()
+
+	_, err = dial(lis.Addr().String(), "partner", newSigner(t))
+	assert.Error(t, err, "unknown key")
+	_, err = dial(lis.Addr().String(), "unknown", userKey)
+	assert.Error(t, err, "unknown user")
+
+	client, err := dial(lis.Addr().String(), "partner", userKey)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, client.Close()) }()
+
+	// large enough for the client to send writes and reads in parallel
+	data := make([]byte, 5<<20)
+	_, err = rand.Read(data)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Mkdir("/dir"))
+	assert.Error(t, client.Mkdir("/dir"))
+
+	file, err := client.Create("/dir/data.bin")
+	require.NoError(t, err)
+	_, err = file.ReadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, []string{"incoming/dir/", "incoming/dir/data.bin"}, store.Paths())
+
+	info, err := client.Stat("/dir/data.bin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), info.Size())
+	assert.False(t, info.IsDir())
+
+	file, err = client.Open("/dir/data.bin")
+	require.NoError(t, err)
+	var downloaded bytes.Buffer
+	_, err = file.WriteTo(&downloaded)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	assert.True(t, bytes.Equal(data, downloaded.Bytes()))
+
+	infos, err := client.ReadDir("/")
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, "dir", infos[0].Name())
+	assert.True(t, infos[0].IsDir())
+
+	require.NoError(t, client.Rename("/dir", "/renamed"))
+	assert.Equal(t, []string{"incoming/renamed/", "incoming/renamed/data.bin"}, store.Paths())
+
+	assert.Error(t, client.RemoveDirectory("/renamed"), "not empty")
+	require.NoError(t, client.Remove("/renamed/data.bin"))
+	require.NoError(t, client.RemoveDirectory("/renamed"))
+	assert.Empty(t, store.Paths())
+
+	_, err = client.Stat("/renamed")
+	assert.Error(t, err)
+}
+
+func TestLoadHostKey(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	path := filepath.Join(ctx.Dir("keys"), "host_key")
+
+	generated, err := LoadHostKey(path)
+	require.NoError(t, err)
+
+	loaded, err := LoadHostKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, generated.PublicKey().Marshal(), loaded.PublicKey().Marshal())
+}
+
+func TestLoadUsers(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("users")
+	path := filepath.Join(dir, "users.json")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "alice.key"), []byte("secret\n"), 0600))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[
+		{"name": "alice", "bucket": "photos", "enc_key_file": "alice.key"},
+		{"name": "bob", "bucket": "music"}
+	]`), 0600))
+	users, err := LoadUsers(path)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "secret", users[0].EncKey)
+	assert.Equal(t, "", users[1].EncKey)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[
+		{"name": "alice", "bucket": "photos", "enc_key": "secret"}
+	]`), 0600))
+	_, err = LoadUsers(path)
+	assert.Error(t, err, "plaintext keys are rejected")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`[
+		{"name": "alice", "bucket": "photos", "enc_key_file": "missing.key"}
+	]`), 0600))
+	_, err = LoadUsers(path)
+	assert.Error(t, err)
+}
+
+func TestWriterAtLostConnection(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store := testobjects.NewStore()
+	_, err := store.Put(ctx, "file", bytes.NewReader([]byte("old")), pb.SerializableMeta{}, time.Time{})
+	require.NoError(t, err)
+
+	client, server := net.Pipe()
+	conn := &sessionConn{ReadWriteCloser: server}
+
+	// the upload is completed when the client closes the file
+	w := newWriterAt(ctx, store, "file", pb.SerializableMeta{}, conn)
+	_, err = w.WriteAt([]byte("new"), 0)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	// the request server closes the files left open by a lost client,
+	// which must not replace the object with the partial data
+	w = newWriterAt(ctx, store, "file", pb.SerializableMeta{}, conn)
+	_, err = w.WriteAt([]byte("partial"), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+	assert.Error(t, w.Close())
+
+	rr, _, err := store.Get(ctx, "file")
+	require.NoError(t, err)
+	r, err := rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sftpgw
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// User is the configuration of an SFTP user
+type User struct {
+	Name string `json:"name"`
+	// AuthorizedKeys are the public keys the user logs in with, in the
+	// format of the OpenSSH authorized_keys file
+	AuthorizedKeys []string `json:"authorized_keys"`
+	Bucket         string   `json:"bucket"`
+	// Prefix is the path inside the bucket that is the user's root directory
+	Prefix string `json:"prefix"`
+	// EncKeyFile is the path of a file with the root key for encrypting the
+	// user's data. Relative paths are resolved against the directory of the
+	// users file. The key of the gateway is used if it is empty.
+	EncKeyFile string `json:"enc_key_file"`
+
+	// EncKey is the key read from EncKeyFile
+	EncKey string `json:"-"`
+}
+
+// PublicKeys parses the authorized keys of the user
+func (u *User) PublicKeys() ([]ssh.PublicKey, error) {
+	var keys []ssh.PublicKey
+	for _, line := range u.AuthorizedKeys {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, Error.New("invalid authorized key for %q: %v", u.Name, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// LoadUsers reads the users from a JSON file
+func LoadUsers(path string) ([]User, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var users []User
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&users)
+	if err != nil {
+		return nil, Error.New("invalid users file %q: %v", path, err)
+	}
+
+	names := make(map[string]bool)
+	for i, u := range users {
+		if u.Name == "" || u.Bucket == "" {
+			return nil, Error.New("users need a name and a bucket")
+		}
+		if names[u.Name] {
+			return nil, Error.New("duplicate user %q", u.Name)
+		}
+		names[u.Name] = true
+
+		if u.EncKeyFile == "" {
+			continue
+		}
+		keyPath := u.EncKeyFile
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(path), keyPath)
+		}
+		key, err := ioutil.ReadFile(keyPath)
+		if err != nil {
+			return nil, Error.New("reading encryption key of %q: %v", u.Name, err)
+		}
+		users[i].EncKey = strings.TrimRight(string(key), "\r\n")
+		if users[i].EncKey == "" {
+			return nil, Error.New("empty encryption key file for %q", u.Name)
+		}
+	}
+
+	return users, nil
+}
+
+// LoadHostKey reads the private host key of the server from path. A new key
+// is generated and saved if the file does not exist.
+func LoadHostKey(path string) (ssh.Signer, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = generateHostKey(path)
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return signer, nil
+}
+
+// generateHostKey creates an ECDSA host key and saves it to path in PEM
+// format
+func generateHostKey(path string) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, ioutil.WriteFile(path, data, 0600)
+}
@@ -6,11 +6,13 @@ package buckets
 import (
 	"context"
 	"io"
+	"strings"
 	"time"
 
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storage/streams"
 	"storj.io/storj/pkg/storj"
 )
 
@@ -49,6 +51,36 @@ func (o *prefixedObjStore) Put(ctx context.Context, path storj.Path, data io.Rea
 	return o.store.Put(ctx, storj.JoinPaths(o.prefix, path), data, metadata, expiration)
 }
 
+func (o *prefixedObjStore) PutResumable(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time, journal streams.Journal) (meta objects.Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return objects.Meta{}, storj.ErrNoPath.New("")
+	}
+
+	return o.store.PutResumable(ctx, storj.JoinPaths(o.prefix, path), data, metadata, expiration, journal)
+}
+
+func (o *prefixedObjStore) UpdateMeta(ctx context.Context, path storj.Path, metadata pb.SerializableMeta) (meta objects.Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return objects.Meta{}, storj.ErrNoPath.New("")
+	}
+
+	return o.store.UpdateMeta(ctx, storj.JoinPaths(o.prefix, path), metadata)
+}
+
+func (o *prefixedObjStore) SetLock(ctx context.Context, path storj.Path, lock storj.ObjectLock) (meta objects.Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return objects.Meta{}, storj.ErrNoPath.New("")
+	}
+
+	return o.store.SetLock(ctx, storj.JoinPaths(o.prefix, path), lock)
+}
+
 func (o *prefixedObjStore) Delete(ctx context.Context, path storj.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -59,6 +91,27 @@ func (o *prefixedObjStore) Delete(ctx context.Context, path storj.Path) (err err
 	return o.store.Delete(ctx, storj.JoinPaths(o.prefix, path))
 }
 
+func (o *prefixedObjStore) DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var prefixed storj.DeleteOptions
+	for _, path := range options.Paths {
+		if len(path) == 0 {
+			return result, storj.ErrNoPath.New("")
+		}
+		prefixed.Paths = append(prefixed.Paths, storj.JoinPaths(o.prefix, path))
+	}
+	for _, prefix := range options.Prefixes {
+		prefixed.Prefixes = append(prefixed.Prefixes, storj.JoinPaths(o.prefix, prefix))
+	}
+
+	result, err = o.store.DeleteObjects(ctx, prefixed)
+	for i, path := range result.Locked {
+		result.Locked[i] = strings.TrimPrefix(path, o.prefix+"/")
+	}
+	return result, err
+}
+
 func (o *prefixedObjStore) List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (items []objects.ListItem, more bool, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -26,6 +26,7 @@ var mon = monkit.Package()
 type Store interface {
 	Get(ctx context.Context, bucket string) (meta Meta, err error)
 	Put(ctx context.Context, bucket string, pathCipher storj.Cipher) (meta Meta, err error)
+	Update(ctx context.Context, bucket string, meta Meta) (updated Meta, err error)
 	Delete(ctx context.Context, bucket string) (err error)
 	List(ctx context.Context, startAfter, endBefore string, limit int) (items []ListItem, more bool, err error)
 	GetObjectStore(ctx context.Context, bucketName string) (store objects.Store, err error)
@@ -47,6 +48,10 @@ type BucketStore struct {
 type Meta struct {
 	Created            time.Time
 	PathEncryptionType storj.Cipher
+	// PublicRead allows anyone to read the objects in the bucket
+	PublicRead bool
+	// ObjectLock allows the objects in the bucket to be locked
+	ObjectLock bool
 }
 
 // NewStore instantiates BucketStore
@@ -119,6 +124,41 @@ func (b *BucketStore) Put(ctx context.Context, bucket string, pathCipher storj.C
 	return convertMeta(m)
 }
 
+// Update changes the policies of an existing bucket to the ones in meta.
+// The creation time and path encryption of a bucket cannot change, and
+// object lock cannot be disabled once it is enabled.
+func (b *BucketStore) Update(ctx context.Context, bucket string, meta Meta) (updated Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if bucket == "" {
+		return Meta{}, storj.ErrNoBucket.New("")
+	}
+
+	current, err := b.Get(ctx, bucket)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	r := bytes.NewReader(nil)
+	userMeta := map[string]string{
+		"path-enc-type": strconv.Itoa(int(current.PathEncryptionType)),
+		// rewriting the bucket changes its modification time
+		"created": current.Created.Format(time.RFC3339Nano),
+	}
+	if meta.PublicRead {
+		userMeta["public-read"] = "true"
+	}
+	if meta.ObjectLock || current.ObjectLock {
+		userMeta["object-lock"] = "true"
+	}
+	var exp time.Time
+	m, err := b.store.Put(ctx, bucket, r, pb.SerializableMeta{UserDefined: userMeta}, exp)
+	if err != nil {
+		return Meta{}, err
+	}
+	return convertMeta(m)
+}
+
 // Delete calls objects store Delete
 func (b *BucketStore) Delete(ctx context.Context, bucket string) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -179,8 +219,19 @@ func convertMeta(m objects.Meta) (Meta, error) {
 		cipher = storj.Cipher(pet)
 	}
 
+	created := m.Modified
+	if c := m.UserDefined["created"]; c != "" {
+		var err error
+		created, err = time.Parse(time.RFC3339Nano, c)
+		if err != nil {
+			return Meta{}, err
+		}
+	}
+
 	return Meta{
-		Created:            m.Modified,
+		Created:            created,
 		PathEncryptionType: cipher,
+		PublicRead:         m.UserDefined["public-read"] == "true",
+		ObjectLock:         m.UserDefined["object-lock"] == "true",
 	}, nil
 }
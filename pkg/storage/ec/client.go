@@ -5,6 +5,8 @@ package ecclient
 
 import (
 	"context"
+	"crypto/sha256"
+	"hash"
 	"io"
 	"io/ioutil"
 	"sort"
@@ -28,10 +30,11 @@ var mon = monkit.Package()
 // Client defines an interface for storing erasure coded data to piece store nodes
 type Client interface {
 	Put(ctx context.Context, nodes []*pb.Node, rs eestream.RedundancyStrategy,
-		pieceID psclient.PieceID, data io.Reader, expiration time.Time, pba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (successfulNodes []*pb.Node, err error)
+		pieceID psclient.PieceID, data io.Reader, expiration time.Time, pba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (successfulNodes []*pb.Node, receipts []*pb.PieceReceipt, err error)
 	Get(ctx context.Context, nodes []*pb.Node, es eestream.ErasureScheme,
 		pieceID psclient.PieceID, size int64, pba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (ranger.Ranger, error)
 	Delete(ctx context.Context, nodes []*pb.Node, pieceID psclient.PieceID, authorization *pb.SignedMessage) error
+	DeleteMany(ctx context.Context, nodes [][]*pb.Node, pieceIDs []psclient.PieceID, authorization *pb.SignedMessage) error
 }
 
 type psClientFunc func(context.Context, transport.Client, *pb.Node, int) (psclient.Client, error)
@@ -58,25 +61,26 @@ func (ec *ecClient) newPSClient(ctx context.Context, n *pb.Node) (psclient.Clien
 }
 
 func (ec *ecClient) Put(ctx context.Context, nodes []*pb.Node, rs eestream.RedundancyStrategy,
-	pieceID psclient.PieceID, data io.Reader, expiration time.Time, pba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (successfulNodes []*pb.Node, err error) {
+	pieceID psclient.PieceID, data io.Reader, expiration time.Time, pba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (successfulNodes []*pb.Node, receipts []*pb.PieceReceipt, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if len(nodes) != rs.TotalCount() {
-		return nil, Error.New("number of nodes (%d) do not match total count (%d) of erasure scheme", len(nodes), rs.TotalCount())
+		return nil, nil, Error.New("number of nodes (%d) do not match total count (%d) of erasure scheme", len(nodes), rs.TotalCount())
 	}
 	if !unique(nodes) {
-		return nil, Error.New("duplicated nodes are not allowed")
+		return nil, nil, Error.New("duplicated nodes are not allowed")
 	}
 
 	padded := eestream.PadReader(ioutil.NopCloser(data), rs.StripeSize())
 	readers, err := eestream.EncodeReader(ctx, padded, rs, ec.memoryLimit)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	type info struct {
-		i   int
-		err error
+		i       int
+		receipt *pb.PieceReceipt
+		err     error
 	}
 	infos := make(chan info, len(nodes))
 
@@ -102,26 +106,38 @@ func (ec *ecClient) Put(ctx context.Context, nodes []*pb.Node, rs eestream.Redun
 				infos <- info{i: i, err: err}
 				return
 			}
-			err = ps.Put(ctx, derivedPieceID, readers[i], expiration, pba, authorization)
+			piece := newHashingReader(readers[i])
+			receipt, err := ps.Put(ctx, derivedPieceID, piece, expiration, pba, authorization)
 			// normally the bellow call should be deferred, but doing so fails
 			// randomly the unit tests
 			utils.LogClose(ps)
+			// the node must have signed for exactly what was sent to it, if
+			// it signs receipts already
+			if err == nil && receipt != nil {
+				err = psclient.VerifyReceipt(receipt, derivedPieceID, piece.size, piece.hash.Sum(nil), expiration)
+			}
+			var compact *pb.PieceReceipt
+			if err == nil {
+				compact, err = psclient.CompactReceipt(receipt)
+			}
 			// io.ErrUnexpectedEOF means the piece upload was interrupted due to slow connection.
 			// No error logging for this case.
 			if err != nil && err != io.ErrUnexpectedEOF {
 				zap.S().Errorf("Failed putting piece %s -> %s to node %s: %v",
 					pieceID, derivedPieceID, n.Id, err)
 			}
-			infos <- info{i: i, err: err}
+			infos <- info{i: i, receipt: compact, err: err}
 		}(i, n)
 	}
 
 	successfulNodes = make([]*pb.Node, len(nodes))
+	receipts = make([]*pb.PieceReceipt, len(nodes))
 	var successfulCount int
 	for range nodes {
 		info := <-infos
 		if info.err == nil {
 			successfulNodes[info.i] = nodes[info.i]
+			receipts[info.i] = info.receipt
 			successfulCount++
 		}
 	}
@@ -139,10 +155,10 @@ func (ec *ecClient) Put(ctx context.Context, nodes []*pb.Node, rs eestream.Redun
 	}()
 
 	if successfulCount < rs.RepairThreshold() {
-		return nil, Error.New("successful puts (%d) less than repair threshold (%d)", successfulCount, rs.RepairThreshold())
+		return nil, nil, Error.New("successful puts (%d) less than repair threshold (%d)", successfulCount, rs.RepairThreshold())
 	}
 
-	return successfulNodes, nil
+	return successfulNodes, receipts, nil
 }
 
 func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.ErasureScheme,
@@ -210,43 +226,71 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.Erasu
 func (ec *ecClient) Delete(ctx context.Context, nodes []*pb.Node, pieceID psclient.PieceID, authorization *pb.SignedMessage) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	errs := make(chan error, len(nodes))
+	return ec.DeleteMany(ctx, [][]*pb.Node{nodes}, []psclient.PieceID{pieceID}, authorization)
+}
 
-	for _, n := range nodes {
-		if n == nil {
-			errs <- nil
-			continue
-		}
+// DeleteMany deletes the pieces of several segments, where nodes[i] are the
+// nodes of the segment with pieceIDs[i]. Each node gets one request for all
+// of its pieces.
+func (ec *ecClient) DeleteMany(ctx context.Context, nodes [][]*pb.Node, pieceIDs []psclient.PieceID, authorization *pb.SignedMessage) (err error) {
+	defer mon.Task()(&ctx)(&err)
 
-		go func(n *pb.Node) {
-			derivedPieceID, err := pieceID.Derive(n.Id.Bytes())
+	if len(nodes) != len(pieceIDs) {
+		return Error.New("number of node lists (%d) do not match number of piece ids (%d)", len(nodes), len(pieceIDs))
+	}
+
+	type nodePieces struct {
+		node     *pb.Node
+		pieceIDs []psclient.PieceID
+	}
+
+	var batches []*nodePieces
+	byNode := make(map[storj.NodeID]*nodePieces)
+	for i, segmentNodes := range nodes {
+		for _, n := range segmentNodes {
+			if n == nil {
+				continue
+			}
+			derivedPieceID, err := pieceIDs[i].Derive(n.Id.Bytes())
 			if err != nil {
-				zap.S().Errorf("Failed deriving piece id for %s: %v", pieceID, err)
-				errs <- err
-				return
+				return Error.Wrap(err)
 			}
-			ps, err := ec.newPSClient(ctx, n)
+			batch, ok := byNode[n.Id]
+			if !ok {
+				batch = &nodePieces{node: n}
+				byNode[n.Id] = batch
+				batches = append(batches, batch)
+			}
+			batch.pieceIDs = append(batch.pieceIDs, derivedPieceID)
+		}
+	}
+
+	errs := make(chan error, len(batches))
+
+	for _, batch := range batches {
+		go func(batch *nodePieces) {
+			ps, err := ec.newPSClient(ctx, batch.node)
 			if err != nil {
-				zap.S().Errorf("Failed dialing for deleting piece %s -> %s from node %s: %v",
-					pieceID, derivedPieceID, n.Id, err)
+				zap.S().Errorf("Failed dialing for deleting %d pieces from node %s: %v",
+					len(batch.pieceIDs), batch.node.Id, err)
 				errs <- err
 				return
 			}
-			err = ps.Delete(ctx, derivedPieceID, authorization)
+			err = psclient.DeletePieces(ctx, ps, batch.pieceIDs, authorization)
 			// normally the bellow call should be deferred, but doing so fails
 			// randomly the unit tests
 			utils.LogClose(ps)
 			if err != nil {
-				zap.S().Errorf("Failed deleting piece %s -> %s from node %s: %v",
-					pieceID, derivedPieceID, n.Id, err)
+				zap.S().Errorf("Failed deleting %d pieces from node %s: %v",
+					len(batch.pieceIDs), batch.node.Id, err)
 			}
 			errs <- err
-		}(n)
+		}(batch)
 	}
 
-	allerrs := collectErrors(errs, len(nodes))
+	allerrs := collectErrors(errs, len(batches))
 
-	if len(allerrs) > 0 && len(allerrs) == len(nodes) {
+	if len(allerrs) > 0 && len(allerrs) == len(batches) {
 		return allerrs[0]
 	}
 
@@ -336,3 +380,22 @@ func validCount(nodes []*pb.Node) int {
 	}
 	return total
 }
+
+// hashingReader counts and hashes the bytes read through it, to check them
+// against the receipt of the node they are sent to
+type hashingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{r: r, hash: sha256.New()}
+}
+
+func (hr *hashingReader) Read(p []byte) (n int, err error) {
+	n, err = hr.r.Read(p)
+	hr.size += int64(n)
+	_, _ = hr.hash.Write(p[:n])
+	return n, err
+}
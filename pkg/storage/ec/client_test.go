@@ -8,16 +8,22 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/golang/mock/gomock"
+	"github.com/gtank/cryptopasta"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vivint/infectious"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"storj.io/storj/internal/teststorj"
 	"storj.io/storj/pkg/eestream"
@@ -36,6 +42,11 @@ const (
 var (
 	ErrDialFailed = errors.New(dialFailed)
 	ErrOpFailed   = errors.New(opFailed)
+	// ErrBadReceipt makes the mocked node return a receipt for other data
+	ErrBadReceipt = errors.New("bad receipt")
+	// ErrNoReceipt makes the mocked node return no receipt, as the nodes
+	// that do not sign receipts yet
+	ErrNoReceipt = errors.New("no receipt")
 )
 
 var (
@@ -111,11 +122,18 @@ TestLoop:
 			"ecclient error: successful puts (1) less than repair threshold (2)"},
 		{[]*pb.Node{nil, nil, node2, node3}, 0, 0, false,
 			[]error{nil, nil, nil, nil}, ""},
+		{[]*pb.Node{node0, node1, node2, node3}, 0, 0, false,
+			[]error{nil, ErrBadReceipt, nil, nil},
+			"ecclient error: successful puts (3) less than repair threshold (4)"},
+		{[]*pb.Node{node0, node1, node2, node3}, 0, 0, false,
+			[]error{nil, ErrNoReceipt, nil, nil}, ""},
 	} {
 		errTag := fmt.Sprintf("Test case #%d", i)
 
 		id := psclient.NewPieceID()
 		ttl := time.Now()
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err, errTag)
 
 		errs := make(map[*pb.Node]error, len(tt.nodes))
 		for i, n := range tt.nodes {
@@ -133,11 +151,22 @@ TestLoop:
 			}
 			ps := NewMockPSClient(ctrl)
 			gomock.InOrder(
-				ps.EXPECT().Put(gomock.Any(), derivedID, gomock.Any(), ttl, gomock.Any(), gomock.Any()).Return(errs[n]).
-					Do(func(ctx context.Context, id psclient.PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) {
+				ps.EXPECT().Put(gomock.Any(), derivedID, gomock.Any(), ttl, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id psclient.PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, authorization *pb.SignedMessage) (*pb.PieceStoreReceipt, error) {
 						// simulate that the mocked piece store client is reading the data
-						_, err := io.Copy(ioutil.Discard, data)
+						h := sha256.New()
+						size, err := io.Copy(h, data)
 						assert.NoError(t, err, errTag)
+						switch errs[n] {
+						case nil:
+						case ErrBadReceipt:
+							size++
+						case ErrNoReceipt:
+							return nil, nil
+						default:
+							return nil, errs[n]
+						}
+						return signReceipt(t, key, id, size, h.Sum(nil), ttl), nil
 					}),
 				ps.EXPECT().Close().Return(nil),
 			)
@@ -150,24 +179,46 @@ TestLoop:
 		r := io.LimitReader(rand.Reader, int64(size))
 		ec := ecClient{newPSClientFunc: mockNewPSClient(clients), memoryLimit: tt.mbm}
 
-		successfulNodes, err := ec.Put(ctx, tt.nodes, rs, id, r, ttl, nil, nil)
+		successfulNodes, receipts, err := ec.Put(ctx, tt.nodes, rs, id, r, ttl, nil, nil)
 
 		if tt.errString != "" {
 			assert.EqualError(t, err, tt.errString, errTag)
 		} else {
 			assert.NoError(t, err, errTag)
 			assert.Equal(t, len(tt.nodes), len(successfulNodes), errTag)
+			assert.Equal(t, len(tt.nodes), len(receipts), errTag)
 			for i := range tt.nodes {
-				if tt.errs[i] != nil {
+				if tt.errs[i] == ErrNoReceipt {
+					assert.Equal(t, tt.nodes[i], successfulNodes[i], errTag)
+					assert.Nil(t, receipts[i], errTag)
+				} else if tt.errs[i] != nil || tt.nodes[i] == nil {
 					assert.Nil(t, successfulNodes[i], errTag)
+					assert.Nil(t, receipts[i], errTag)
 				} else {
 					assert.Equal(t, tt.nodes[i], successfulNodes[i], errTag)
+					assert.NotNil(t, receipts[i], errTag)
 				}
 			}
 		}
 	}
 }
 
+func signReceipt(t *testing.T, key *ecdsa.PrivateKey, id psclient.PieceID, size int64, hash []byte, ttl time.Time) *pb.PieceStoreReceipt {
+	pubKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	data, err := proto.Marshal(&pb.PieceStoreReceipt_Data{
+		Id:                id.String(),
+		PieceSize:         size,
+		Hash:              hash,
+		ExpirationUnixSec: ttl.Unix(),
+		PubKey:            pubKey,
+	})
+	require.NoError(t, err)
+	signature, err := cryptopasta.Sign(data, key)
+	require.NoError(t, err)
+	return &pb.PieceStoreReceipt{Signature: signature, Data: data}
+}
+
 func mockNewPSClient(clients map[*pb.Node]psclient.Client) psClientFunc {
 	return func(_ context.Context, _ transport.Client, n *pb.Node, _ int) (psclient.Client, error) {
 		c, ok := clients[n]
@@ -295,7 +346,7 @@ TestLoop:
 				}
 				ps := NewMockPSClient(ctrl)
 				gomock.InOrder(
-					ps.EXPECT().Delete(gomock.Any(), derivedID, gomock.Any()).Return(errs[n]),
+					ps.EXPECT().DeleteMany(gomock.Any(), []psclient.PieceID{derivedID}, gomock.Any()).Return(errs[n]),
 					ps.EXPECT().Close().Return(nil),
 				)
 				clients[n] = ps
@@ -313,6 +364,51 @@ TestLoop:
 	}
 }
 
+func TestDeleteMany(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id0, id1 := psclient.NewPieceID(), psclient.NewPieceID()
+	derive := func(id psclient.PieceID, n *pb.Node) psclient.PieceID {
+		derivedID, err := id.Derive(n.Id.Bytes())
+		require.NoError(t, err)
+		return derivedID
+	}
+
+	// node0 holds pieces of both segments and gets them in one request
+	ps0 := NewMockPSClient(ctrl)
+	gomock.InOrder(
+		ps0.EXPECT().DeleteMany(gomock.Any(), []psclient.PieceID{derive(id0, node0), derive(id1, node0)}, gomock.Any()).Return(nil),
+		ps0.EXPECT().Close().Return(nil),
+	)
+	ps1 := NewMockPSClient(ctrl)
+	gomock.InOrder(
+		ps1.EXPECT().DeleteMany(gomock.Any(), []psclient.PieceID{derive(id1, node1)}, gomock.Any()).Return(ErrOpFailed),
+		ps1.EXPECT().Close().Return(nil),
+	)
+
+	ec := ecClient{newPSClientFunc: mockNewPSClient(map[*pb.Node]psclient.Client{node0: ps0, node1: ps1})}
+	err := ec.DeleteMany(ctx, [][]*pb.Node{{node0, nil}, {node1, node0}}, []psclient.PieceID{id0, id1}, nil)
+	assert.NoError(t, err)
+
+	err = ec.DeleteMany(ctx, [][]*pb.Node{{node0}}, nil, nil)
+	assert.Error(t, err)
+
+	// the pieces are deleted one by one from nodes without DeleteMany
+	ps0 = NewMockPSClient(ctrl)
+	gomock.InOrder(
+		ps0.EXPECT().DeleteMany(gomock.Any(), []psclient.PieceID{derive(id0, node0), derive(id1, node0)}, gomock.Any()).
+			Return(status.Error(codes.Unimplemented, "unknown method DeleteMany")),
+		ps0.EXPECT().Delete(gomock.Any(), derive(id0, node0), gomock.Any()).Return(nil),
+		ps0.EXPECT().Delete(gomock.Any(), derive(id1, node0), gomock.Any()).Return(nil),
+		ps0.EXPECT().Close().Return(nil),
+	)
+	ec = ecClient{newPSClientFunc: mockNewPSClient(map[*pb.Node]psclient.Client{node0: ps0})}
+	err = ec.DeleteMany(ctx, [][]*pb.Node{{node0}, {node0}}, []psclient.PieceID{id0, id1}, nil)
+	assert.NoError(t, err)
+}
+
 func TestUnique(t *testing.T) {
 	for i, tt := range []struct {
 		nodes  []*pb.Node
@@ -0,0 +1,76 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package objects
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+// DirectoryContentType is the content type of the empty objects marking
+// directories created by the file system front-ends
+const DirectoryContentType = "application/directory"
+
+// PutDirectory creates the empty object marking the directory p, so that it
+// is listed while it has no objects
+func PutDirectory(ctx context.Context, store Store, p storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	metadata := pb.SerializableMeta{ContentType: DirectoryContentType}
+	_, err = store.Put(ctx, p+"/", bytes.NewReader(nil), metadata, time.Time{})
+	return err
+}
+
+// Move copies the object oldPath with its metadata to newPath and deletes
+// the original
+func Move(ctx context.Context, store Store, oldPath, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rr, m, err := store.Get(ctx, oldPath)
+	if err != nil {
+		return err
+	}
+
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	_, err = store.Put(ctx, newPath, r, m.SerializableMeta, m.Expiration)
+	if err != nil {
+		return err
+	}
+
+	return store.Delete(ctx, oldPath)
+}
+
+// ListAll calls handler with every page of the listing of prefix
+func ListAll(ctx context.Context, store Store, prefix storj.Path, recursive bool, metaFlags uint32, handler func([]ListItem) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	startAfter := ""
+	for {
+		items, more, err := store.List(ctx, prefix, startAfter, "", recursive, 0, metaFlags)
+		if err != nil {
+			return err
+		}
+
+		err = handler(items)
+		if err != nil {
+			return err
+		}
+
+		if !more || len(items) == 0 {
+			return nil
+		}
+
+		startAfter = items[len(items)-1].Path
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package objects_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testobjects"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+)
+
+func TestPutDirectory(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store := testobjects.NewStore()
+	require.NoError(t, objects.PutDirectory(ctx, store, "dir"))
+
+	m, err := store.Meta(ctx, "dir/")
+	require.NoError(t, err)
+	assert.Equal(t, objects.DirectoryContentType, m.ContentType)
+	assert.Equal(t, int64(0), m.Size)
+}
+
+func TestMove(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store := testobjects.NewStore()
+	expiration := time.Now().Add(time.Hour)
+	_, err := store.Put(ctx, "old", strings.NewReader("data"), pb.SerializableMeta{ContentType: "text/plain"}, expiration)
+	require.NoError(t, err)
+
+	require.NoError(t, objects.Move(ctx, store, "old", "new"))
+	assert.Equal(t, []string{"new"}, store.Paths())
+
+	rr, m, err := store.Get(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", m.ContentType)
+	assert.Equal(t, expiration, m.Expiration)
+	r, err := rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	err = objects.Move(ctx, store, "old", "new")
+	assert.True(t, storj.ErrObjectNotFound.Has(err))
+}
+
+func TestListAll(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store := &pagedStore{Store: testobjects.NewStore()}
+	var paths []string
+	for i := 0; i < 5; i++ {
+		paths = append(paths, fmt.Sprintf("dir/sub/%d", i))
+		_, err := store.Put(ctx, paths[i], strings.NewReader(""), pb.SerializableMeta{}, time.Time{})
+		require.NoError(t, err)
+	}
+
+	// every page is handed over
+	var listed []string
+	pages := 0
+	err := objects.ListAll(ctx, store, "dir", true, meta.None, func(items []objects.ListItem) error {
+		pages++
+		for _, item := range items {
+			listed = append(listed, "dir/"+item.Path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, paths, listed)
+	assert.Equal(t, 3, pages)
+
+	// the listing stops at the first error of handler
+	pages = 0
+	err = objects.ListAll(ctx, store, "dir", true, meta.None, func(items []objects.ListItem) error {
+		pages++
+		return fmt.Errorf("stop")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, pages)
+}
+
+// pagedStore lists at most two items at a time
+type pagedStore struct {
+	*testobjects.Store
+}
+
+func (s *pagedStore) List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) ([]objects.ListItem, bool, error) {
+	return s.Store.List(ctx, prefix, startAfter, endBefore, recursive, 2, metaFlags)
+}
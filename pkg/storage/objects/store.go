@@ -28,6 +28,7 @@ type Meta struct {
 	Expiration time.Time
 	Size       int64
 	Checksum   string
+	Lock       storj.ObjectLock
 }
 
 // ListItem is a single item in a listing
@@ -42,7 +43,11 @@ type Store interface {
 	Meta(ctx context.Context, path storj.Path) (meta Meta, err error)
 	Get(ctx context.Context, path storj.Path) (rr ranger.Ranger, meta Meta, err error)
 	Put(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time) (meta Meta, err error)
+	PutResumable(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time, journal streams.Journal) (meta Meta, err error)
+	UpdateMeta(ctx context.Context, path storj.Path, metadata pb.SerializableMeta) (meta Meta, err error)
+	SetLock(ctx context.Context, path storj.Path, lock storj.ObjectLock) (meta Meta, err error)
 	Delete(ctx context.Context, path storj.Path) (err error)
+	DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error)
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
 }
 
@@ -107,6 +112,57 @@ func (o *objStore) Put(ctx context.Context, path storj.Path, data io.Reader, met
 	return convertMeta(m), err
 }
 
+func (o *objStore) PutResumable(ctx context.Context, path storj.Path, data io.Reader, metadata pb.SerializableMeta, expiration time.Time, journal streams.Journal) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return Meta{}, storj.ErrNoPath.New("")
+	}
+
+	b, err := proto.Marshal(&metadata)
+	if err != nil {
+		return Meta{}, err
+	}
+	m, err := o.store.PutResumable(ctx, path, o.pathCipher, data, b, expiration, journal)
+	return convertMeta(m), err
+}
+
+func (o *objStore) UpdateMeta(ctx context.Context, path storj.Path, metadata pb.SerializableMeta) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return Meta{}, storj.ErrNoPath.New("")
+	}
+
+	b, err := proto.Marshal(&metadata)
+	if err != nil {
+		return Meta{}, err
+	}
+	m, err := o.store.UpdateMeta(ctx, path, o.pathCipher, b)
+
+	if storage.ErrKeyNotFound.Has(err) {
+		err = storj.ErrObjectNotFound.Wrap(err)
+	}
+
+	return convertMeta(m), err
+}
+
+func (o *objStore) SetLock(ctx context.Context, path storj.Path, lock storj.ObjectLock) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return Meta{}, storj.ErrNoPath.New("")
+	}
+
+	m, err := o.store.SetLock(ctx, path, o.pathCipher, lock)
+
+	if storage.ErrKeyNotFound.Has(err) {
+		err = storj.ErrObjectNotFound.Wrap(err)
+	}
+
+	return convertMeta(m), err
+}
+
 func (o *objStore) Delete(ctx context.Context, path storj.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -123,6 +179,18 @@ func (o *objStore) Delete(ctx context.Context, path storj.Path) (err error) {
 	return err
 }
 
+func (o *objStore) DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, path := range options.Paths {
+		if len(path) == 0 {
+			return result, storj.ErrNoPath.New("")
+		}
+	}
+
+	return o.store.DeleteObjects(ctx, options, o.pathCipher)
+}
+
 func (o *objStore) List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (
 	items []ListItem, more bool, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -156,5 +224,6 @@ func convertMeta(m streams.Meta) Meta {
 		Expiration:       m.Expiration,
 		Size:             m.Size,
 		SerializableMeta: ser,
+		Lock:             m.Lock,
 	}
 }
@@ -78,6 +78,19 @@ func (mr *MockStoreMockRecorder) Repair(ctx, path, lostPieces interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repair", reflect.TypeOf((*MockStore)(nil).Repair), ctx, path, lostPieces)
 }
 
+// UpdateMeta mocks base method
+func (m *MockStore) UpdateMeta(ctx context.Context, path storj.Path, metadata []byte) (Meta, error) {
+	ret := m.ctrl.Call(m, "UpdateMeta", ctx, path, metadata)
+	ret0, _ := ret[0].(Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMeta indicates an expected call of UpdateMeta
+func (mr *MockStoreMockRecorder) UpdateMeta(ctx, path, metadata interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMeta", reflect.TypeOf((*MockStore)(nil).UpdateMeta), ctx, path, metadata)
+}
+
 // Put mocks base method
 func (m *MockStore) Put(ctx context.Context, data io.Reader, expiration time.Time, segmentInfo func() (storj.Path, []byte, error)) (Meta, error) {
 	ret := m.ctrl.Call(m, "Put", ctx, data, expiration, segmentInfo)
@@ -103,6 +116,19 @@ func (mr *MockStoreMockRecorder) Delete(ctx, path interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStore)(nil).Delete), ctx, path)
 }
 
+// DeleteObjects mocks base method
+func (m *MockStore) DeleteObjects(ctx context.Context, options storj.DeleteOptions) (storj.DeleteResult, error) {
+	ret := m.ctrl.Call(m, "DeleteObjects", ctx, options)
+	ret0, _ := ret[0].(storj.DeleteResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteObjects indicates an expected call of DeleteObjects
+func (mr *MockStoreMockRecorder) DeleteObjects(ctx, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjects", reflect.TypeOf((*MockStore)(nil).DeleteObjects), ctx, options)
+}
+
 // List mocks base method
 func (m *MockStore) List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) ([]ListItem, bool, error) {
 	ret := m.ctrl.Call(m, "List", ctx, prefix, startAfter, endBefore, recursive, limit, metaFlags)
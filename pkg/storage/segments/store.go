@@ -50,7 +50,9 @@ type Store interface {
 	Get(ctx context.Context, path storj.Path) (rr ranger.Ranger, meta Meta, err error)
 	Repair(ctx context.Context, path storj.Path, lostPieces []int32) (err error)
 	Put(ctx context.Context, data io.Reader, expiration time.Time, segmentInfo func() (storj.Path, []byte, error)) (meta Meta, err error)
+	UpdateMeta(ctx context.Context, path storj.Path, metadata []byte) (meta Meta, err error)
 	Delete(ctx context.Context, path storj.Path) (err error)
+	DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error)
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
 }
 
@@ -79,6 +81,18 @@ func (s *segmentStore) Meta(ctx context.Context, path storj.Path) (meta Meta, er
 	return convertMeta(pr), nil
 }
 
+// UpdateMeta replaces the metadata of the segment without uploading it again
+func (s *segmentStore) UpdateMeta(ctx context.Context, path storj.Path, metadata []byte) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pr, err := s.pdb.UpdateMetadata(ctx, path, metadata)
+	if err != nil {
+		return Meta{}, Error.Wrap(err)
+	}
+
+	return convertMeta(pr), nil
+}
+
 // Put uploads a segment to an erasure code client
 func (s *segmentStore) Put(ctx context.Context, data io.Reader, expiration time.Time, segmentInfo func() (storj.Path, []byte, error)) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -125,7 +139,7 @@ func (s *segmentStore) Put(ctx context.Context, data io.Reader, expiration time.
 			return Meta{}, Error.Wrap(err)
 		}
 		// puts file to ecclient
-		successfulNodes, err := s.ec.Put(ctx, nodes, s.rs, pieceID, sizedReader, expiration, pba, authorization)
+		successfulNodes, receipts, err := s.ec.Put(ctx, nodes, s.rs, pieceID, sizedReader, expiration, pba, authorization)
 		if err != nil {
 			return Meta{}, Error.Wrap(err)
 		}
@@ -136,7 +150,7 @@ func (s *segmentStore) Put(ctx context.Context, data io.Reader, expiration time.
 		}
 		path = p
 
-		pointer, err = s.makeRemotePointer(successfulNodes, pieceID, sizedReader.Size(), exp, metadata)
+		pointer, err = s.makeRemotePointer(successfulNodes, receipts, pieceID, sizedReader.Size(), exp, metadata)
 		if err != nil {
 			return Meta{}, err
 		}
@@ -156,8 +170,9 @@ func (s *segmentStore) Put(ctx context.Context, data io.Reader, expiration time.
 	return m, nil
 }
 
-// makeRemotePointer creates a pointer of type remote
-func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, pieceID psclient.PieceID, readerSize int64, exp *timestamp.Timestamp, metadata []byte) (pointer *pb.Pointer, err error) {
+// makeRemotePointer creates a pointer of type remote, keeping the receipts
+// the nodes signed for their pieces
+func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, receipts []*pb.PieceReceipt, pieceID psclient.PieceID, readerSize int64, exp *timestamp.Timestamp, metadata []byte) (pointer *pb.Pointer, err error) {
 	var remotePieces []*pb.RemotePiece
 	for i := range nodes {
 		if nodes[i] == nil {
@@ -166,6 +181,7 @@ func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, pieceID psclient.Piec
 		remotePieces = append(remotePieces, &pb.RemotePiece{
 			PieceNum: int32(i),
 			NodeId:   nodes[i].Id,
+			Receipt:  receipts[i],
 		})
 	}
 
@@ -251,37 +267,22 @@ func makeErasureScheme(rs *pb.RedundancyScheme) (eestream.ErasureScheme, error)
 	return es, nil
 }
 
-// Delete tells piece stores to delete a segment and deletes pointer from pointerdb
+// Delete deletes the pointer of a segment from pointerdb, which deletes its
+// pieces from the storage nodes
 func (s *segmentStore) Delete(ctx context.Context, path storj.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	pr, nodes, _, err := s.pdb.Get(ctx, path)
-	if err != nil {
-		return Error.Wrap(err)
-	}
-
-	if pr.GetType() == pb.Pointer_REMOTE {
-		seg := pr.GetRemote()
-		pid := psclient.PieceID(seg.PieceId)
-
-		// fall back if nodes are not available
-		if nodes == nil {
-			nodes, err = s.lookupNodes(ctx, seg)
-			if err != nil {
-				return Error.Wrap(err)
-			}
-		}
+	// pointerdb deletes the pieces from the storage nodes in the background
+	return Error.Wrap(s.pdb.Delete(ctx, path))
+}
 
-		authorization := s.pdb.SignedMessage()
-		// ecclient sends delete request
-		err = s.ec.Delete(ctx, nodes, pid, authorization)
-		if err != nil {
-			return Error.Wrap(err)
-		}
-	}
+// DeleteObjects deletes all the segments of the objects selected by options
+// with one request to pointerdb, which deletes the pieces in the background
+func (s *segmentStore) DeleteObjects(ctx context.Context, options storj.DeleteOptions) (result storj.DeleteResult, err error) {
+	defer mon.Task()(&ctx)(&err)
 
-	// deletes pointer from pointerdb
-	return s.pdb.Delete(ctx, path)
+	result, err = s.pdb.DeleteObjects(ctx, options)
+	return result, Error.Wrap(err)
 }
 
 // Repair retrieves an at-risk segment and repairs and stores lost pieces on new nodes
@@ -318,6 +319,14 @@ func (s *segmentStore) Repair(ctx context.Context, path storj.Path, lostPieces [
 
 	healthyNodes := make([]*pb.Node, len(originalNodes))
 
+	// the pieces marked corrupted are repaired too
+	corrupted := make(map[int32]bool)
+	for _, piece := range seg.GetRemotePieces() {
+		if piece.GetCorrupted() {
+			corrupted[piece.GetPieceNum()] = true
+		}
+	}
+
 	// populate healthyNodes with all nodes from originalNodes except those correlating to indices in lostPieces
 	for i, v := range originalNodes {
 		if v == nil {
@@ -328,7 +337,7 @@ func (s *segmentStore) Repair(ctx context.Context, path storj.Path, lostPieces [
 		excludeNodeIDs = append(excludeNodeIDs, v.Id)
 
 		// If node index exists in lostPieces, skip adding it to healthyNodes
-		if contains(lostPieces, i) {
+		if contains(lostPieces, i) || corrupted[int32(i)] {
 			totalNilNodes++
 		} else {
 			healthyNodes[i] = v
@@ -392,11 +401,18 @@ func (s *segmentStore) Repair(ctx context.Context, path storj.Path, lostPieces [
 	// puts file to ecclient
 	exp := pr.GetExpirationDate()
 
-	successfulNodes, err := s.ec.Put(ctx, repairNodesList, s.rs, pid, r, time.Unix(exp.GetSeconds(), 0), pba, signedMessage)
+	successfulNodes, receipts, err := s.ec.Put(ctx, repairNodesList, s.rs, pid, r, time.Unix(exp.GetSeconds(), 0), pba, signedMessage)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
+	// the healthy nodes keep the receipts of their original upload
+	for _, piece := range pr.GetRemote().GetRemotePieces() {
+		if healthyNodes[piece.GetPieceNum()] != nil {
+			receipts[piece.GetPieceNum()] = piece.GetReceipt()
+		}
+	}
+
 	// merge the successful nodes list into the healthy nodes list
 	for i, v := range healthyNodes {
 		if v == nil {
@@ -406,7 +422,7 @@ func (s *segmentStore) Repair(ctx context.Context, path storj.Path, lostPieces [
 	}
 
 	metadata := pr.GetMetadata()
-	pointer, err := s.makeRemotePointer(healthyNodes, pid, rr.Size(), exp, metadata)
+	pointer, err := s.makeRemotePointer(healthyNodes, receipts, pid, rr.Size(), exp, metadata)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ package segments
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -309,7 +310,7 @@ func TestSegmentStoreRepairRemote(t *testing.T) {
 			).Return(ranger.ByteRanger([]byte(tt.data)), nil),
 			mockEC.EXPECT().Put(
 				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
-			).Return(tt.newNodes, nil),
+			).Return(tt.newNodes, make([]*pb.PieceReceipt, len(tt.newNodes)), nil),
 			mockES.EXPECT().RequiredCount().Return(1),
 			mockES.EXPECT().TotalCount().Return(1),
 			mockES.EXPECT().ErasureShareSize().Return(1),
@@ -386,23 +387,16 @@ func TestSegmentStoreGetRemote(t *testing.T) {
 	}
 }
 
-func TestSegmentStoreDeleteInline(t *testing.T) {
+func TestSegmentStoreDelete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	ti := time.Unix(0, 0).UTC()
-	someTime, err := ptypes.TimestampProto(ti)
-	assert.NoError(t, err)
-
 	for _, tt := range []struct {
-		pathInput     string
-		thresholdSize int
-		pointerType   pb.Pointer_DataType
-		inlineContent []byte
-		size          int64
-		metadata      []byte
+		pathInput string
+		deleteErr error
 	}{
-		{"path/1/2/3", 10, pb.Pointer_INLINE, []byte("000"), int64(3), []byte("metadata")},
+		{"path/1/2/3", nil},
+		{"path/1/2/4", errors.New("locked")},
 	} {
 		mockOC := mock_overlay.NewMockClient(ctrl)
 		mockEC := mock_ecclient.NewMockClient(ctrl)
@@ -412,93 +406,18 @@ func TestSegmentStoreDeleteInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{mockOC, mockEC, mockPDB, rs, 10}
 		assert.NotNil(t, ss)
 
-		calls := []*gomock.Call{
-			mockPDB.EXPECT().Get(
-				gomock.Any(), gomock.Any(),
-			).Return(&pb.Pointer{
-				Type:           tt.pointerType,
-				InlineSegment:  tt.inlineContent,
-				CreationDate:   someTime,
-				ExpirationDate: someTime,
-				SegmentSize:    tt.size,
-				Metadata:       tt.metadata,
-			}, nil, nil, nil),
-			mockPDB.EXPECT().Delete(
-				gomock.Any(), gomock.Any(),
-			),
-		}
-		gomock.InOrder(calls...)
+		// the pieces are deleted by pointerdb, not by the uplink
+		mockPDB.EXPECT().Delete(gomock.Any(), tt.pathInput).Return(tt.deleteErr)
 
 		err := ss.Delete(ctx, tt.pathInput)
-		assert.NoError(t, err)
-	}
-}
-
-func TestSegmentStoreDeleteRemote(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	ti := time.Unix(0, 0).UTC()
-	someTime, err := ptypes.TimestampProto(ti)
-	assert.NoError(t, err)
-
-	for _, tt := range []struct {
-		pathInput     string
-		thresholdSize int
-		pointerType   pb.Pointer_DataType
-		size          int64
-		metadata      []byte
-	}{
-		{"path/1/2/3", 10, pb.Pointer_REMOTE, int64(3), []byte("metadata")},
-	} {
-		mockOC := mock_overlay.NewMockClient(ctrl)
-		mockEC := mock_ecclient.NewMockClient(ctrl)
-		mockPDB := mock_pointerdb.NewMockClient(ctrl)
-		mockES := mock_eestream.NewMockErasureScheme(ctrl)
-		rs := eestream.RedundancyStrategy{
-			ErasureScheme: mockES,
-		}
-
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
-		assert.NotNil(t, ss)
-
-		calls := []*gomock.Call{
-			mockPDB.EXPECT().Get(
-				gomock.Any(), gomock.Any(),
-			).Return(&pb.Pointer{
-				Type: tt.pointerType,
-				Remote: &pb.RemoteSegment{
-					Redundancy: &pb.RedundancyScheme{
-						Type:             pb.RedundancyScheme_RS,
-						MinReq:           1,
-						Total:            2,
-						RepairThreshold:  1,
-						SuccessThreshold: 2,
-					},
-					PieceId:      "here's my piece id",
-					RemotePieces: []*pb.RemotePiece{},
-				},
-				CreationDate:   someTime,
-				ExpirationDate: someTime,
-				SegmentSize:    tt.size,
-				Metadata:       tt.metadata,
-			}, nil, nil, nil),
-			mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
-			mockPDB.EXPECT().SignedMessage(),
-			mockEC.EXPECT().Delete(
-				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
-			),
-			mockPDB.EXPECT().Delete(
-				gomock.Any(), gomock.Any(),
-			),
+		if tt.deleteErr != nil {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
 		}
-		gomock.InOrder(calls...)
-
-		err := ss.Delete(ctx, tt.pathInput)
-		assert.NoError(t, err)
 	}
 }
 
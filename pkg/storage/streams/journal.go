@@ -0,0 +1,23 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"github.com/zeebo/errs"
+)
+
+// ErrResume is the errs class for failures to resume an interrupted upload
+var ErrResume = errs.Class("resume error")
+
+// Journal records the progress of a stream upload, so that an interrupted
+// upload can be continued without sending the already stored segments again
+type Journal interface {
+	// Resume returns the segment size and the number of segments that have
+	// been committed by previous attempts of the upload. A new upload
+	// returns zero committed segments.
+	Resume() (segmentSize int64, committed int64)
+	// Commit records that the first committed segments of the stream have
+	// been stored in pointerdb.
+	Commit(segmentSize int64, committed int64) error
+}
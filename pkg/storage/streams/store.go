@@ -36,6 +36,19 @@ type Meta struct {
 	Expiration time.Time
 	Size       int64
 	Data       []byte
+	Lock       storj.ObjectLock
+}
+
+// withLock sets the lock of meta to the one in the stream metadata of the
+// last segment
+func withLock(meta Meta, lastSegmentData []byte) (Meta, error) {
+	streamMeta := pb.StreamMeta{}
+	err := proto.Unmarshal(lastSegmentData, &streamMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+	meta.Lock, err = pb.ObjectLockToStorj(streamMeta.Lock)
+	return meta, err
 }
 
 // convertMeta converts segment metadata to stream metadata
@@ -59,7 +72,11 @@ type Store interface {
 	Meta(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (Meta, error)
 	Get(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (ranger.Ranger, Meta, error)
 	Put(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (Meta, error)
+	PutResumable(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time, journal Journal) (Meta, error)
+	UpdateMeta(ctx context.Context, path storj.Path, pathCipher storj.Cipher, metadata []byte) (Meta, error)
+	SetLock(ctx context.Context, path storj.Path, pathCipher storj.Cipher, lock storj.ObjectLock) (Meta, error)
 	Delete(ctx context.Context, path storj.Path, pathCipher storj.Cipher) error
+	DeleteObjects(ctx context.Context, options storj.DeleteOptions, pathCipher storj.Cipher) (storj.DeleteResult, error)
 	List(ctx context.Context, prefix, startAfter, endBefore storj.Path, pathCipher storj.Cipher, recursive bool, limit int, metaFlags uint32) (items []ListItem, more bool, err error)
 }
 
@@ -107,7 +124,7 @@ func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj
 		return Meta{}, err
 	}
 
-	m, lastSegment, err := s.upload(ctx, path, pathCipher, data, metadata, expiration)
+	m, lastSegment, err := s.upload(ctx, path, pathCipher, data, metadata, expiration, nil)
 	if err != nil {
 		s.cancelHandler(context.Background(), lastSegment, path, pathCipher)
 	}
@@ -115,14 +132,73 @@ func (s *streamStore) Put(ctx context.Context, path storj.Path, pathCipher storj
 	return m, err
 }
 
-func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time) (m Meta, lastSegment int64, err error) {
+// PutResumable works like Put, but records every stored segment in the
+// journal and keeps the stored segments if the upload fails. If the journal
+// reports segments committed by a previous attempt, their pointers are
+// verified and the upload continues with the first missing segment. In that
+// case data must start at the first byte of that segment.
+func (s *streamStore) PutResumable(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time, journal Journal) (m Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segmentSize, committed := journal.Resume()
+	if committed == 0 {
+		// previously file uploaded?
+		err = s.Delete(ctx, path, pathCipher)
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return Meta{}, err
+		}
+	} else {
+		err = s.verifyCommitted(ctx, path, pathCipher, segmentSize, committed)
+		if err != nil {
+			return Meta{}, err
+		}
+	}
+
+	m, _, err = s.upload(ctx, path, pathCipher, data, metadata, expiration, journal)
+	return m, err
+}
+
+// verifyCommitted checks that the segments committed by a previous attempt
+// of an upload are still stored and can be continued with this store
+func (s *streamStore) verifyCommitted(ctx context.Context, path storj.Path, pathCipher storj.Cipher, segmentSize, committed int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if segmentSize != s.segmentSize {
+		return ErrResume.New("segment size changed from %d to %d", segmentSize, s.segmentSize)
+	}
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+	if err != nil {
+		return err
+	}
+
+	for i := int64(0); i < committed; i++ {
+		_, err = s.segments.Meta(ctx, getSegmentPath(encPath, i))
+		if err != nil {
+			return ErrResume.New("segment %d is not stored: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher storj.Cipher, data io.Reader, metadata []byte, expiration time.Time, journal Journal) (m Meta, lastSegment int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	var currentSegment int64
 	var streamSize int64
 	var putMeta segments.Meta
 
+	if journal != nil {
+		_, currentSegment = journal.Resume()
+		streamSize = currentSegment * s.segmentSize
+	}
+
 	defer func() {
+		if journal != nil {
+			// keep the stored segments, so the upload can be resumed
+			return
+		}
 		select {
 		case <-ctx.Done():
 			s.cancelHandler(context.Background(), currentSegment, path, pathCipher)
@@ -240,6 +316,7 @@ func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher st
 				EncryptedStreamInfo: encryptedStreamInfo,
 				EncryptionType:      int32(s.cipher),
 				EncryptionBlockSize: int32(s.encBlockSize),
+				NumberOfSegments:    currentSegment + 1,
 			}
 
 			if s.cipher != storj.Unencrypted {
@@ -262,6 +339,13 @@ func (s *streamStore) upload(ctx context.Context, path storj.Path, pathCipher st
 
 		currentSegment++
 		streamSize += sizeReader.Size()
+
+		if journal != nil && !eofReader.isEOF() {
+			err = journal.Commit(s.segmentSize, currentSegment)
+			if err != nil {
+				return Meta{}, currentSegment, err
+			}
+		}
 	}
 
 	if eofReader.hasError() {
@@ -371,6 +455,10 @@ func (s *streamStore) Get(ctx context.Context, path storj.Path, pathCipher storj
 	if err != nil {
 		return nil, Meta{}, err
 	}
+	meta.Lock, err = pb.ObjectLockToStorj(streamMeta.Lock)
+	if err != nil {
+		return nil, Meta{}, err
+	}
 
 	return catRangers, meta, nil
 }
@@ -394,52 +482,200 @@ func (s *streamStore) Meta(ctx context.Context, path storj.Path, pathCipher stor
 		return Meta{}, err
 	}
 
+	lockData := lastSegmentMeta.Data
 	lastSegmentMeta.Data = streamInfo
 	newStreamMeta, err := convertMeta(lastSegmentMeta)
 	if err != nil {
 		return Meta{}, err
 	}
 
-	return newStreamMeta, nil
+	return withLock(newStreamMeta, lockData)
 }
 
-// Delete all the segments, with the last one last
-func (s *streamStore) Delete(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (err error) {
+// UpdateMeta replaces the metadata of the stream, leaving its segments as
+// they are
+func (s *streamStore) UpdateMeta(ctx context.Context, path storj.Path, pathCipher storj.Cipher, metadata []byte) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
 	if err != nil {
-		return err
+		return Meta{}, err
 	}
-	lastSegmentMeta, err := s.segments.Meta(ctx, storj.JoinPaths("l", encPath))
+	lastSegmentPath := storj.JoinPaths("l", encPath)
+
+	lastSegmentMeta, err := s.segments.Meta(ctx, lastSegmentPath)
 	if err != nil {
-		return err
+		return Meta{}, err
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(lastSegmentMeta.Data, &streamMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	contentKey, err := decryptContentKey(&streamMeta, path, s.rootKey)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	cipher := storj.Cipher(streamMeta.EncryptionType)
+	streamInfoData, err := encryption.Decrypt(streamMeta.EncryptedStreamInfo, cipher, contentKey, streamInfoNonce(&streamMeta))
+	if err != nil {
+		return Meta{}, err
+	}
+
+	streamInfo := pb.StreamInfo{}
+	err = proto.Unmarshal(streamInfoData, &streamInfo)
+	if err != nil {
+		return Meta{}, err
+	}
+	streamInfo.Metadata = metadata
+
+	streamInfoData, err = proto.Marshal(&streamInfo)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	// the content key is not new, so the stream info gets a random nonce
+	// instead of the zero nonce
+	var nonce storj.Nonce
+	_, err = rand.Read(nonce[:])
+	if err != nil {
+		return Meta{}, err
+	}
+
+	streamMeta.EncryptedStreamInfo, err = encryption.Encrypt(streamInfoData, cipher, contentKey, &nonce)
+	if err != nil {
+		return Meta{}, err
+	}
+	streamMeta.StreamInfoNonce = nonce[:]
+
+	lastSegmentMetaData, err := proto.Marshal(&streamMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	lastSegmentMeta, err = s.segments.UpdateMeta(ctx, lastSegmentPath, lastSegmentMetaData)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	lastSegmentMeta.Data = streamInfoData
+	meta, err = convertMeta(lastSegmentMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+	meta.Lock, err = pb.ObjectLockToStorj(streamMeta.Lock)
+	return meta, err
+}
+
+// SetLock replaces the lock of the stream. pointerdb refuses to shorten the
+// retention period of a locked stream.
+func (s *streamStore) SetLock(ctx context.Context, path storj.Path, pathCipher storj.Cipher, lock storj.ObjectLock) (meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+	if err != nil {
+		return Meta{}, err
+	}
+	lastSegmentPath := storj.JoinPaths("l", encPath)
+
+	lastSegmentMeta, err := s.segments.Meta(ctx, lastSegmentPath)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(lastSegmentMeta.Data, &streamMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	streamMeta.Lock, err = pb.ObjectLockFromStorj(lock)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	lastSegmentMetaData, err := proto.Marshal(&streamMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	lastSegmentMeta, err = s.segments.UpdateMeta(ctx, lastSegmentPath, lastSegmentMetaData)
+	if err != nil {
+		return Meta{}, err
 	}
 
 	streamInfo, err := DecryptStreamInfo(ctx, lastSegmentMeta, path, s.rootKey)
 	if err != nil {
-		return err
+		return Meta{}, err
 	}
 
-	stream := pb.StreamInfo{}
-	err = proto.Unmarshal(streamInfo, &stream)
+	lastSegmentMeta.Data = streamInfo
+	meta, err = convertMeta(lastSegmentMeta)
+	if err != nil {
+		return Meta{}, err
+	}
+	meta.Lock = lock
+	return meta, nil
+}
+
+// Delete deletes all the segments of the stream with one request to
+// pointerdb, which deletes their pieces from each storage node at once
+func (s *streamStore) Delete(ctx context.Context, path storj.Path, pathCipher storj.Cipher) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
 	if err != nil {
 		return err
 	}
 
-	for i := 0; i < int(stream.NumberOfSegments-1); i++ {
-		encPath, err = EncryptAfterBucket(path, pathCipher, s.rootKey)
+	result, err := s.segments.DeleteObjects(ctx, storj.DeleteOptions{Paths: []storj.Path{encPath}})
+	switch {
+	case err != nil:
+		return err
+	case len(result.Locked) > 0:
+		return storj.ErrObjectLocked.New("%s", path)
+	case result.Deleted == 0:
+		return storage.ErrKeyNotFound.New("%s", path)
+	}
+	return nil
+}
+
+// DeleteObjects deletes the streams selected by options, whose paths start
+// with the bucket, with one request for all of their segments
+func (s *streamStore) DeleteObjects(ctx context.Context, options storj.DeleteOptions, pathCipher storj.Cipher) (result storj.DeleteResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var encOptions storj.DeleteOptions
+	for _, path := range options.Paths {
+		encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
 		if err != nil {
-			return err
+			return result, err
 		}
-		currentPath := getSegmentPath(encPath, int64(i))
-		err := s.segments.Delete(ctx, currentPath)
+		encOptions.Paths = append(encOptions.Paths, encPath)
+	}
+	for _, prefix := range options.Prefixes {
+		encPrefix, err := EncryptAfterBucket(strings.TrimSuffix(prefix, "/"), pathCipher, s.rootKey)
 		if err != nil {
-			return err
+			return result, err
 		}
+		encOptions.Prefixes = append(encOptions.Prefixes, encPrefix)
+	}
+
+	result, err = s.segments.DeleteObjects(ctx, encOptions)
+	if err != nil {
+		return result, err
 	}
 
-	return s.segments.Delete(ctx, storj.JoinPaths("l", encPath))
+	for i, encPath := range result.Locked {
+		result.Locked[i], err = DecryptAfterBucket(encPath, pathCipher, s.rootKey)
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
 }
 
 // ListItem is a single item in a listing
@@ -498,11 +734,16 @@ func (s *streamStore) List(ctx context.Context, prefix, startAfter, endBefore st
 			return nil, false, err
 		}
 
+		lockData := item.Meta.Data
 		item.Meta.Data = streamInfo
 		newMeta, err := convertMeta(item.Meta)
 		if err != nil {
 			return nil, false, err
 		}
+		newMeta, err = withLock(newMeta, lockData)
+		if err != nil {
+			return nil, false, err
+		}
 
 		items[i] = ListItem{Path: path, Meta: newMeta, IsPrefix: item.IsPrefix}
 	}
@@ -640,17 +881,21 @@ func DecryptAfterBucket(path storj.Path, cipher storj.Cipher, key *storj.Key) (d
 
 // CancelHandler handles clean up of segments on receiving CTRL+C
 func (s *streamStore) cancelHandler(ctx context.Context, totalSegments int64, path storj.Path, pathCipher storj.Cipher) {
-	for i := int64(0); i < totalSegments; i++ {
-		encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
-		if err != nil {
-			zap.S().Warnf("Failed deleting a segment due to encryption path %v %v", i, err)
-		}
+	if totalSegments == 0 {
+		return
+	}
 
-		currentPath := getSegmentPath(encPath, i)
-		err = s.segments.Delete(ctx, currentPath)
-		if err != nil {
-			zap.S().Warnf("Failed deleting a segment %v %v", currentPath, err)
-		}
+	encPath, err := EncryptAfterBucket(path, pathCipher, s.rootKey)
+	if err != nil {
+		zap.S().Warnf("Failed deleting the segments due to encryption path %v", err)
+		return
+	}
+
+	// pointerdb deletes the segments of the canceled upload without a last
+	// segment too
+	_, err = s.segments.DeleteObjects(ctx, storj.DeleteOptions{Paths: []storj.Path{encPath}})
+	if err != nil {
+		zap.S().Warnf("Failed deleting the segments of %v %v", encPath, err)
 	}
 }
 
@@ -673,18 +918,32 @@ func DecryptStreamInfo(ctx context.Context, item segments.Meta, path storj.Path,
 		return nil, err
 	}
 
-	derivedKey, err := encryption.DeriveContentKey(path, rootKey)
+	contentKey, err := decryptContentKey(&streamMeta, path, rootKey)
 	if err != nil {
 		return nil, err
 	}
 
 	cipher := storj.Cipher(streamMeta.EncryptionType)
-	encryptedKey, keyNonce := getEncryptedKeyAndNonce(streamMeta.LastSegmentMeta)
-	contentKey, err := encryption.DecryptKey(encryptedKey, cipher, derivedKey, keyNonce)
+	return encryption.Decrypt(streamMeta.EncryptedStreamInfo, cipher, contentKey, streamInfoNonce(&streamMeta))
+}
+
+// decryptContentKey decrypts the content key of the last segment of the
+// stream at path
+func decryptContentKey(streamMeta *pb.StreamMeta, path storj.Path, rootKey *storj.Key) (*storj.Key, error) {
+	derivedKey, err := encryption.DeriveContentKey(path, rootKey)
 	if err != nil {
 		return nil, err
 	}
 
-	// decrypt metadata with the content encryption key and zero nonce
-	return encryption.Decrypt(streamMeta.EncryptedStreamInfo, cipher, contentKey, &storj.Nonce{})
+	cipher := storj.Cipher(streamMeta.EncryptionType)
+	encryptedKey, keyNonce := getEncryptedKeyAndNonce(streamMeta.LastSegmentMeta)
+	return encryption.DecryptKey(encryptedKey, cipher, derivedKey, keyNonce)
+}
+
+// streamInfoNonce returns the nonce the stream info is encrypted with. It
+// is the zero nonce unless the metadata of the stream was updated.
+func streamInfoNonce(streamMeta *pb.StreamMeta) *storj.Nonce {
+	var nonce storj.Nonce
+	copy(nonce[:], streamMeta.StreamInfoNonce)
+	return &nonce
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
@@ -14,11 +15,14 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/zeebo/errs"
 
+	"storj.io/storj/pkg/encryption"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/segments"
 	"storj.io/storj/pkg/storj"
+	"storj.io/storj/storage"
 )
 
 var (
@@ -105,6 +109,99 @@ func TestStreamStoreMeta(t *testing.T) {
 	}
 }
 
+func TestStreamStoreUpdateMeta(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSegmentStore := segments.NewMockStore(ctrl)
+
+	path := "bucket/object"
+	rootKey := &storj.Key{1, 2, 3}
+	derivedKey, err := encryption.DeriveContentKey(path, rootKey)
+	assert.NoError(t, err)
+
+	contentKey := storj.Key{4, 5, 6}
+	keyNonce := storj.Nonce{7}
+	encryptedKey, err := encryption.EncryptKey(&contentKey, storj.AESGCM, derivedKey, &keyNonce)
+	assert.NoError(t, err)
+
+	streamInfo, err := proto.Marshal(&pb.StreamInfo{
+		NumberOfSegments: 1,
+		SegmentsSize:     10,
+		LastSegmentSize:  5,
+		Metadata:         []byte("old"),
+	})
+	assert.NoError(t, err)
+	encryptedStreamInfo, err := encryption.Encrypt(streamInfo, storj.AESGCM, &contentKey, &storj.Nonce{})
+	assert.NoError(t, err)
+
+	lastSegmentMeta, err := proto.Marshal(&pb.StreamMeta{
+		EncryptedStreamInfo: encryptedStreamInfo,
+		EncryptionType:      int32(storj.AESGCM),
+		EncryptionBlockSize: 10,
+		LastSegmentMeta:     &pb.SegmentMeta{EncryptedKey: encryptedKey, KeyNonce: keyNonce[:]},
+	})
+	assert.NoError(t, err)
+
+	var updated []byte
+	mockSegmentStore.EXPECT().Meta(gomock.Any(), "l/"+path).Return(segments.Meta{Data: lastSegmentMeta}, nil)
+	mockSegmentStore.EXPECT().UpdateMeta(gomock.Any(), "l/"+path, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, path storj.Path, metadata []byte) (segments.Meta, error) {
+			updated = metadata
+			return segments.Meta{Data: metadata}, nil
+		})
+
+	streamStore, err := NewStreamStore(mockSegmentStore, 10, rootKey, 10, storj.AESGCM)
+	assert.NoError(t, err)
+
+	meta, err := streamStore.UpdateMeta(ctx, path, storj.Unencrypted, []byte("new"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("new"), meta.Data)
+	assert.Equal(t, int64(5), meta.Size)
+
+	// the stream info is encrypted again with a new nonce
+	streamMeta := pb.StreamMeta{}
+	assert.NoError(t, proto.Unmarshal(updated, &streamMeta))
+	assert.NotEqual(t, make([]byte, storj.NonceSize), streamMeta.StreamInfoNonce)
+
+	decrypted, err := DecryptStreamInfo(ctx, segments.Meta{Data: updated}, path, rootKey)
+	assert.NoError(t, err)
+	info := pb.StreamInfo{}
+	assert.NoError(t, proto.Unmarshal(decrypted, &info))
+	assert.Equal(t, []byte("new"), info.Metadata)
+	assert.Equal(t, int64(5), info.LastSegmentSize)
+
+	// the lock is stored unencrypted for pointerdb and kept by updates
+	lock := storj.ObjectLock{RetainUntil: time.Unix(1600000000, 0).UTC(), LegalHold: true}
+	mockSegmentStore.EXPECT().Meta(gomock.Any(), "l/"+path).Return(segments.Meta{Data: updated}, nil)
+	mockSegmentStore.EXPECT().UpdateMeta(gomock.Any(), "l/"+path, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, path storj.Path, metadata []byte) (segments.Meta, error) {
+			updated = metadata
+			return segments.Meta{Data: metadata}, nil
+		})
+
+	meta, err = streamStore.SetLock(ctx, path, storj.Unencrypted, lock)
+	assert.NoError(t, err)
+	assert.Equal(t, lock, meta.Lock)
+	assert.Equal(t, []byte("new"), meta.Data)
+
+	streamMeta = pb.StreamMeta{}
+	assert.NoError(t, proto.Unmarshal(updated, &streamMeta))
+	stored, err := pb.ObjectLockToStorj(streamMeta.Lock)
+	assert.NoError(t, err)
+	assert.Equal(t, lock, stored)
+
+	mockSegmentStore.EXPECT().Meta(gomock.Any(), "l/"+path).Return(segments.Meta{Data: updated}, nil)
+	mockSegmentStore.EXPECT().UpdateMeta(gomock.Any(), "l/"+path, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, path storj.Path, metadata []byte) (segments.Meta, error) {
+			return segments.Meta{Data: metadata}, nil
+		})
+
+	meta, err = streamStore.UpdateMeta(ctx, path, storj.Unencrypted, []byte("newer"))
+	assert.NoError(t, err)
+	assert.Equal(t, lock, meta.Lock)
+}
+
 func TestStreamStorePut(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -157,11 +254,8 @@ func TestStreamStorePut(t *testing.T) {
 			})
 
 		mockSegmentStore.EXPECT().
-			Meta(gomock.Any(), gomock.Any()).
-			Return(test.segmentMeta, test.segmentError)
-		mockSegmentStore.EXPECT().
-			Delete(gomock.Any(), gomock.Any()).
-			Return(test.segmentError)
+			DeleteObjects(gomock.Any(), gomock.Any()).
+			Return(storj.DeleteResult{Deleted: 1}, test.segmentError)
 
 		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
 		if err != nil {
@@ -177,6 +271,89 @@ func TestStreamStorePut(t *testing.T) {
 	}
 }
 
+type testJournal struct {
+	segmentSize int64
+	committed   int64
+	commits     []int64
+}
+
+func (j *testJournal) Resume() (int64, int64) {
+	return j.segmentSize, j.committed
+}
+
+func (j *testJournal) Commit(segmentSize int64, committed int64) error {
+	j.segmentSize, j.committed = segmentSize, committed
+	j.commits = append(j.commits, committed)
+	return nil
+}
+
+func TestStreamStorePutResumable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSegmentStore := segments.NewMockStore(ctrl)
+
+	readAll := func(ctx context.Context, data io.Reader, expiration time.Time, info func() (storj.Path, []byte, error)) {
+		_, err := ioutil.ReadAll(data)
+		assert.NoError(t, err)
+		_, _, err = info()
+		assert.NoError(t, err)
+	}
+
+	streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a new upload deletes the previous object and commits every full segment
+	journal := &testJournal{}
+	mockSegmentStore.EXPECT().
+		DeleteObjects(gomock.Any(), gomock.Any()).
+		Return(storj.DeleteResult{}, nil)
+	mockSegmentStore.EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(segments.Meta{}, nil).
+		Do(readAll).
+		Times(3)
+
+	meta, err := streamStore.PutResumable(ctx, "bucket/path", storj.AESGCM, strings.NewReader("0123456789abcdefghijklmno"), nil, time.Time{}, journal)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(25), meta.Size)
+		assert.Equal(t, []int64{1, 2}, journal.commits)
+	}
+
+	// a resumed upload verifies the committed segments and continues after them
+	journal = &testJournal{segmentSize: 10, committed: 2}
+	mockSegmentStore.EXPECT().
+		Meta(gomock.Any(), gomock.Any()).
+		Return(segments.Meta{}, nil).
+		Times(2)
+	mockSegmentStore.EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(segments.Meta{}, nil).
+		Do(readAll)
+
+	meta, err = streamStore.PutResumable(ctx, "bucket/path", storj.AESGCM, strings.NewReader("klmno"), nil, time.Time{}, journal)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(25), meta.Size)
+		assert.Empty(t, journal.commits)
+	}
+
+	// resuming fails if a committed segment is missing
+	journal = &testJournal{segmentSize: 10, committed: 1}
+	mockSegmentStore.EXPECT().
+		Meta(gomock.Any(), gomock.Any()).
+		Return(segments.Meta{}, storage.ErrKeyNotFound.New("s0/bucket/path"))
+
+	_, err = streamStore.PutResumable(ctx, "bucket/path", storj.AESGCM, strings.NewReader("klmno"), nil, time.Time{}, journal)
+	assert.True(t, ErrResume.Has(err))
+
+	// resuming fails if the segment size has changed
+	journal = &testJournal{segmentSize: 20, committed: 1}
+	_, err = streamStore.PutResumable(ctx, "bucket/path", storj.AESGCM, strings.NewReader("klmno"), nil, time.Time{}, journal)
+	assert.True(t, ErrResume.Has(err))
+}
+
 type stubRanger struct {
 	len    int64
 	closer io.ReadCloser
@@ -284,47 +461,43 @@ func TestStreamStoreDelete(t *testing.T) {
 
 	mockSegmentStore := segments.NewMockStore(ctrl)
 
-	staticTime := time.Now()
-	segmentMeta := segments.Meta{
-		Modified:   staticTime,
-		Expiration: staticTime,
-		Size:       10,
-		Data:       []byte{},
-	}
-
 	for i, test := range []struct {
-		// input for test function
-		path string
-		// output for mock functions
-		segmentMeta  segments.Meta
+		// output for mock function
+		result       storj.DeleteResult
 		segmentError error
 		// assert on output of test function
-		streamError error
+		errClass *errs.Class
 	}{
-		{"bucket", segmentMeta, nil, nil},
+		{storj.DeleteResult{Deleted: 1}, nil, nil},
+		{storj.DeleteResult{}, nil, &storage.ErrKeyNotFound},
+		{storj.DeleteResult{Locked: []storj.Path{"bucket/path"}}, nil, &storj.ErrObjectLocked},
+		{storj.DeleteResult{}, segments.Error.New("unavailable"), &segments.Error},
 	} {
 		errTag := fmt.Sprintf("Test case #%d", i)
 
+		// all the segments of the stream are deleted with one request
 		mockSegmentStore.EXPECT().
-			Meta(gomock.Any(), gomock.Any()).
-			Return(test.segmentMeta, test.segmentError)
-		mockSegmentStore.EXPECT().
-			Delete(gomock.Any(), gomock.Any()).
-			Return(test.segmentError)
+			DeleteObjects(gomock.Any(), gomock.Any()).
+			Return(test.result, test.segmentError).
+			Do(func(ctx context.Context, options storj.DeleteOptions) {
+				assert.Len(t, options.Paths, 1, errTag)
+				assert.Empty(t, options.Prefixes, errTag)
+			})
 
 		streamStore, err := NewStreamStore(mockSegmentStore, 10, new(storj.Key), 10, 0)
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = streamStore.Delete(ctx, test.path, storj.AESGCM)
-		if err != nil {
-			t.Fatal(err)
+		err = streamStore.Delete(ctx, "bucket/path", storj.AESGCM)
+		if test.errClass == nil {
+			assert.NoError(t, err, errTag)
+		} else {
+			assert.True(t, test.errClass.Has(err), errTag)
 		}
-
-		assert.Equal(t, test.streamError, err, errTag)
 	}
 }
+
 func TestStreamStoreList(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -34,6 +34,8 @@ type Metainfo interface {
 	ModifyObject(ctx context.Context, bucket string, path Path) (MutableObject, error)
 	// DeleteObject deletes an object from database
 	DeleteObject(ctx context.Context, bucket string, path Path) error
+	// DeleteObjects deletes several objects with one request to the database
+	DeleteObjects(ctx context.Context, bucket string, options DeleteOptions) (DeleteResult, error)
 	// ListObjects lists objects in bucket based on the ListOptions
 	ListObjects(ctx context.Context, bucket string, options ListOptions) (ObjectList, error)
 
@@ -134,6 +136,25 @@ func (opts ListOptions) NextPage(list ObjectList) ListOptions {
 	return ListOptions{}
 }
 
+// DeleteOptions selects the objects to delete
+type DeleteOptions struct {
+	// Paths are the paths of single objects
+	Paths []Path
+	// Prefixes are the directories to delete all the objects under,
+	// recursively. The empty prefix is the whole bucket.
+	Prefixes []Path
+}
+
+// DeleteResult is the outcome of deleting several objects. Objects that do
+// not exist are skipped.
+type DeleteResult struct {
+	// Deleted is how many objects were deleted
+	Deleted int64
+	// Locked are the paths of the objects that were not deleted because
+	// they are locked
+	Locked []Path
+}
+
 // BucketListOptions lists objects
 type BucketListOptions struct {
 	Cursor    string
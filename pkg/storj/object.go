@@ -21,6 +21,9 @@ var (
 
 	// ErrObjectNotFound is an error class for non-existing object
 	ErrObjectNotFound = errs.Class("object not found")
+
+	// ErrObjectLocked is an error class for changing a locked object
+	ErrObjectLocked = errs.Class("object locked")
 )
 
 // Bucket contains information about a specific bucket
@@ -30,6 +33,18 @@ type Bucket struct {
 	PathCipher Cipher
 }
 
+// ObjectLock keeps an object from being overwritten or deleted while it
+// has a legal hold or until its retention period ends
+type ObjectLock struct {
+	RetainUntil time.Time
+	LegalHold   bool
+}
+
+// Active returns whether the lock protects the object at now
+func (lock ObjectLock) Active(now time.Time) bool {
+	return lock.LegalHold || now.Before(lock.RetainUntil)
+}
+
 // Object contains information about a specific object
 type Object struct {
 	Version  uint32
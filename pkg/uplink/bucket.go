@@ -0,0 +1,122 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	"storj.io/storj/internal/readcloser"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/stream"
+	"storj.io/storj/pkg/utils"
+)
+
+// Bucket is a bucket of a Project
+type Bucket struct {
+	storj.Bucket
+	project *Project
+}
+
+// UploadOptions are the optional settings of an upload
+type UploadOptions struct {
+	ContentType string
+	// Metadata is user defined metadata stored with the object
+	Metadata map[string]string
+	// Expires is when the object is deleted. The zero value never expires.
+	Expires time.Time
+}
+
+// Upload stores the data read from data as the object at path, replacing
+// any object there
+func (b *Bucket) Upload(ctx context.Context, path storj.Path, data io.Reader, options *UploadOptions) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if options == nil {
+		options = &UploadOptions{}
+	}
+
+	// the metadata is stored like the gateway stores it
+	metadata, err := proto.Marshal(&pb.SerializableMeta{
+		ContentType: options.ContentType,
+		UserDefined: options.Metadata,
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	object, err := b.project.metainfo.CreateObject(ctx, b.Name, path, &storj.CreateObject{
+		Metadata:         metadata,
+		ContentType:      options.ContentType,
+		Expires:          options.Expires,
+		RedundancyScheme: b.project.config.Redundancy,
+		EncryptionScheme: b.project.config.Encryption,
+	})
+	if err != nil {
+		return err
+	}
+
+	mutableStream, err := object.CreateStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	upload := stream.NewUpload(ctx, mutableStream, b.project.streams, b.PathCipher)
+	_, err = io.Copy(upload, data)
+	err = utils.CombineErrors(err, upload.Close())
+	if err != nil {
+		return err
+	}
+
+	return object.Commit(ctx)
+}
+
+// Download returns a reader of length bytes of the object at path, from
+// offset on. A negative length reads to the end of the object.
+func (b *Bucket) Download(ctx context.Context, path storj.Path, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	readOnly, err := b.project.metainfo.GetObjectStream(ctx, b.Name, path)
+	if err != nil {
+		return nil, err
+	}
+
+	size := readOnly.Info().Size
+	if offset < 0 || offset > size {
+		return nil, Error.New("offset %d out of the object size %d", offset, size)
+	}
+	if length < 0 || offset+length > size {
+		length = size - offset
+	}
+
+	download := stream.NewDownload(ctx, readOnly, b.project.streams)
+	if offset > 0 {
+		_, err = download.Seek(offset, io.SeekStart)
+		if err != nil {
+			return nil, utils.CombineErrors(err, download.Close())
+		}
+	}
+	return readcloser.LimitReadCloser(download, length), nil
+}
+
+// List lists the objects of the bucket
+func (b *Bucket) List(ctx context.Context, options storj.ListOptions) (list storj.ObjectList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if options.Direction == 0 {
+		options.Direction = storj.After
+	}
+	return b.project.metainfo.ListObjects(ctx, b.Name, options)
+}
+
+// Delete deletes the object at path
+func (b *Bucket) Delete(ctx context.Context, path storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return b.project.metainfo.DeleteObject(ctx, b.Name, path)
+}
@@ -0,0 +1,202 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package uplink is a small client library for storing data in the
+// network. It hides the stores, clients and erasure coding behind
+// projects and buckets:
+//
+//	project, err := uplink.Open(ctx, satelliteAddr, apiKey, encKey, nil)
+//	bucket, err := project.OpenBucket(ctx, "photos")
+//	err = bucket.Upload(ctx, "cat.jpg", file, nil)
+package uplink
+
+import (
+	"context"
+
+	"github.com/vivint/infectious"
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/internal/memory"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/metainfo/kvmetainfo"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storage/segments"
+	"storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the errs class of uplink errors
+	Error = errs.Class("uplink error")
+)
+
+// Config configures how a Project stores data
+type Config struct {
+	// Identity is the identity the client connects with. A new one is
+	// created when it is nil.
+	Identity *provider.FullIdentity
+
+	// MaxInlineSize is the size up to which segments are stored in the
+	// satellite instead of the storage nodes
+	MaxInlineSize memory.Size
+	// SegmentSize is the size of the segments objects are split into
+	SegmentSize memory.Size
+	// MaxBufferMem is the memory for buffering the pieces of downloads
+	MaxBufferMem memory.Size
+
+	// Redundancy is the erasure coding of the segments
+	Redundancy storj.RedundancyScheme
+	// Encryption is the encryption of the data. The share size times the
+	// required shares must be a multiple of the block size.
+	Encryption storj.EncryptionScheme
+	// PathCipher encrypts the paths of the objects in new buckets
+	PathCipher storj.Cipher
+}
+
+// DefaultConfig returns the configuration Open uses when none is given
+func DefaultConfig() Config {
+	return Config{
+		MaxInlineSize: 4 * memory.KB,
+		SegmentSize:   64 * memory.MB,
+		MaxBufferMem:  4 * memory.MB,
+		Redundancy: storj.RedundancyScheme{
+			Algorithm:      storj.ReedSolomon,
+			ShareSize:      memory.KB.Int32(),
+			RequiredShares: 29,
+			RepairShares:   35,
+			OptimalShares:  80,
+			TotalShares:    95,
+		},
+		Encryption: storj.EncryptionScheme{
+			Cipher:    storj.AESGCM,
+			BlockSize: memory.KB.Int32(),
+		},
+		PathCipher: storj.AESGCM,
+	}
+}
+
+// Project is the data of an API key in the network, encrypted with one
+// encryption key
+type Project struct {
+	config   Config
+	overlay  *grpc.ClientConn
+	pdb      *pdbclient.PointerDB
+	streams  streams.Store
+	metainfo storj.Metainfo
+}
+
+// Open connects to the satellite at satelliteAddr with apiKey. The data is
+// encrypted with encKey. A nil config selects DefaultConfig.
+func Open(ctx context.Context, satelliteAddr, apiKey, encKey string, config *Config) (p *Project, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if config == nil {
+		defaults := DefaultConfig()
+		config = &defaults
+	}
+	rs := config.Redundancy
+	if config.Encryption.BlockSize <= 0 || int(rs.ShareSize)*int(rs.RequiredShares)%int(config.Encryption.BlockSize) != 0 {
+		return nil, Error.New("share size * required shares must be a multiple of the encryption block size")
+	}
+
+	identity := config.Identity
+	if identity == nil {
+		identity, err = provider.NewFullIdentity(ctx, 0, 1)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	// the overlay client does not close its connection, so the project keeps it
+	conn, err := transport.NewClient(identity).DialAddress(ctx, satelliteAddr)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	oc := overlay.NewClientFrom(pb.NewOverlayClient(conn))
+	defer func() {
+		if err != nil {
+			err = utils.CombineErrors(err, conn.Close())
+		}
+	}()
+
+	fc, err := infectious.NewFEC(int(rs.RequiredShares), int(rs.TotalShares))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	strategy, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, int(rs.ShareSize)),
+		int(rs.RepairShares), int(rs.OptimalShares))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	pdb, err := pdbclient.NewClient(identity, satelliteAddr, apiKey)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	ss := segments.NewSegmentStore(oc, ecclient.NewClient(identity, config.MaxBufferMem.Int()), pdb, strategy, config.MaxInlineSize.Int())
+
+	key := new(storj.Key)
+	copy(key[:], encKey)
+
+	streams, err := streams.NewStreamStore(ss, config.SegmentSize.Int64(), key, int(config.Encryption.BlockSize), config.Encryption.Cipher)
+	if err != nil {
+		return nil, utils.CombineErrors(Error.Wrap(err), pdb.Disconnect())
+	}
+
+	return &Project{
+		config:   *config,
+		overlay:  conn,
+		pdb:      pdb,
+		streams:  streams,
+		metainfo: kvmetainfo.New(buckets.NewStore(streams), streams, ss, pdb, key),
+	}, nil
+}
+
+// Close closes the connections to the satellite
+func (p *Project) Close() error {
+	return Error.Wrap(utils.CombineErrors(p.pdb.Disconnect(), p.overlay.Close()))
+}
+
+// CreateBucket creates the bucket called name
+func (p *Project) CreateBucket(ctx context.Context, name string) (bucket storj.Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return p.metainfo.CreateBucket(ctx, name, &storj.Bucket{PathCipher: p.config.PathCipher})
+}
+
+// DeleteBucket deletes the empty bucket called name
+func (p *Project) DeleteBucket(ctx context.Context, name string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return p.metainfo.DeleteBucket(ctx, name)
+}
+
+// ListBuckets lists the buckets of the project
+func (p *Project) ListBuckets(ctx context.Context, options storj.BucketListOptions) (list storj.BucketList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return p.metainfo.ListBuckets(ctx, options)
+}
+
+// OpenBucket returns the existing bucket called name
+func (p *Project) OpenBucket(ctx context.Context, name string) (bucket *Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := p.metainfo.GetBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Bucket{Bucket: info, project: p}, nil
+}
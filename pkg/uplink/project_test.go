@@ -0,0 +1,88 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testcontext"
+	"storj.io/storj/internal/testplanet"
+	"storj.io/storj/pkg/storj"
+)
+
+const testAPIKey = "test-api-key"
+
+func TestProject(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	planet, err := testplanet.New(t, 1, 4, 1)
+	require.NoError(t, err)
+	defer ctx.Check(planet.Shutdown)
+
+	planet.Start(context.Background())
+
+	// TODO: configure the API key of the satellite without the flag
+	require.NoError(t, flag.Set("pointer-db.auth.api-key", testAPIKey))
+
+	config := DefaultConfig()
+	config.Identity = planet.Uplinks[0].Identity
+	project, err := Open(ctx, planet.Satellites[0].Addr(), testAPIKey, "test-encryption-key", &config)
+	require.NoError(t, err)
+	defer ctx.Check(project.Close)
+
+	_, err = project.OpenBucket(ctx, "bucket")
+	assert.True(t, storj.ErrBucketNotFound.Has(err))
+
+	_, err = project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+	bucket, err := project.OpenBucket(ctx, "bucket")
+	require.NoError(t, err)
+	assert.Equal(t, storj.AESGCM, bucket.PathCipher)
+
+	// the data is stored inline, so the storage nodes are not needed
+	data := []byte("some data that fits into the pointer")
+	require.True(t, len(data) < config.MaxInlineSize.Int())
+	err = bucket.Upload(ctx, "dir/file", bytes.NewReader(data), &UploadOptions{ContentType: "text/plain"})
+	require.NoError(t, err)
+
+	download := func(offset, length int64) []byte {
+		reader, err := bucket.Download(ctx, "dir/file", offset, length)
+		require.NoError(t, err)
+		defer ctx.Check(reader.Close)
+		downloaded, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		return downloaded
+	}
+	assert.Equal(t, data, download(0, -1))
+	assert.Equal(t, data[5:9], download(5, 4))
+	assert.Equal(t, data[5:], download(5, 1000))
+
+	_, err = bucket.Download(ctx, "dir/file", int64(len(data))+1, -1)
+	assert.Error(t, err)
+
+	list, err := bucket.List(ctx, storj.ListOptions{Recursive: true})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "dir/file", list.Items[0].Path)
+	assert.Equal(t, "text/plain", list.Items[0].ContentType)
+	assert.Equal(t, int64(len(data)), list.Items[0].Size)
+
+	require.NoError(t, bucket.Delete(ctx, "dir/file"))
+	_, err = bucket.Download(ctx, "dir/file", 0, -1)
+	assert.True(t, storj.ErrObjectNotFound.Has(err))
+
+	require.NoError(t, project.DeleteBucket(ctx, "bucket"))
+	buckets, err := project.ListBuckets(ctx, storj.BucketListOptions{Direction: storj.After})
+	require.NoError(t, err)
+	assert.Empty(t, buckets.Items)
+
+}
@@ -0,0 +1,474 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdavgw
+
+import (
+	"context"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/net/webdav"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storj"
+	"storj.io/storj/pkg/utils"
+)
+
+var mon = monkit.Package()
+
+// FileSystem is a webdav.FileSystem serving the objects of a bucket.
+// Directories are the prefixes of the object paths.
+type FileSystem struct {
+	store objects.Store
+}
+
+// NewFileSystem creates a FileSystem backed by store
+func NewFileSystem(store objects.Store) *FileSystem {
+	return &FileSystem{store: store}
+}
+
+// objectPath converts a slash separated WebDAV name to an object path
+func objectPath(name string) storj.Path {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// Mkdir creates an empty directory marker object
+func (fs *FileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	p := objectPath(name)
+	if p == "" {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+
+	_, err = fs.Stat(ctx, p)
+	if err == nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	if parent := path.Dir(p); parent != "." {
+		_, err = fs.Stat(ctx, parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	return objects.PutDirectory(ctx, fs.store, p)
+}
+
+// OpenFile opens the object or directory name. Files opened for writing are
+// uploaded when closed and always replace the whole object.
+func (fs *FileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (_ webdav.File, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	p := objectPath(name)
+
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+		return fs.create(ctx, p, flag)
+	}
+
+	if p == "" {
+		return newDirFile(ctx, fs.store, p, rootInfo()), nil
+	}
+
+	rr, m, err := fs.store.Get(ctx, p)
+	if err == nil {
+		return &readFile{ctx: ctx, rr: rr, info: objectInfo(p, m)}, nil
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return nil, err
+	}
+
+	info, err := fs.dirInfo(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return newDirFile(ctx, fs.store, p, info), nil
+}
+
+// create starts uploading a new version of the object p
+func (fs *FileSystem) create(ctx context.Context, p storj.Path, flag int) (webdav.File, error) {
+	if p == "" {
+		return nil, &os.PathError{Op: "open", Path: "/", Err: os.ErrPermission}
+	}
+
+	if flag&(os.O_CREATE|os.O_EXCL) != os.O_CREATE {
+		_, err := fs.Stat(ctx, p)
+		if err != nil && (flag&os.O_CREATE == 0 || !os.IsNotExist(err)) {
+			return nil, err
+		}
+		if err == nil && flag&os.O_EXCL != 0 {
+			return nil, &os.PathError{Op: "open", Path: p, Err: os.ErrExist}
+		}
+	}
+
+	metadata := pb.SerializableMeta{ContentType: mime.TypeByExtension(path.Ext(p))}
+	return newWriteFile(ctx, fs.store, p, metadata), nil
+}
+
+// RemoveAll deletes the object name and all objects under the prefix name
+func (fs *FileSystem) RemoveAll(ctx context.Context, name string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	p := objectPath(name)
+	if p == "" {
+		return &os.PathError{Op: "remove", Path: "/", Err: os.ErrPermission}
+	}
+
+	err = fs.store.Delete(ctx, p)
+	if err != nil && !storj.ErrObjectNotFound.Has(err) {
+		return err
+	}
+
+	return objects.ListAll(ctx, fs.store, p, true, meta.None, func(items []objects.ListItem) error {
+		for _, item := range items {
+			err := fs.store.Delete(ctx, storj.JoinPaths(p, item.Path))
+			if err != nil && !storj.ErrObjectNotFound.Has(err) {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rename moves the object oldName, or all objects under the prefix oldName,
+// to newName. Objects are moved by copying them and deleting the originals.
+func (fs *FileSystem) Rename(ctx context.Context, oldName, newName string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	oldPath, newPath := objectPath(oldName), objectPath(newName)
+	if oldPath == "" || newPath == "" {
+		return &os.PathError{Op: "rename", Path: oldName, Err: os.ErrPermission}
+	}
+	if strings.HasPrefix(newPath+"/", oldPath+"/") {
+		return &os.PathError{Op: "rename", Path: oldName, Err: os.ErrInvalid}
+	}
+
+	err = objects.Move(ctx, fs.store, oldPath, newPath)
+	if !storj.ErrObjectNotFound.Has(err) {
+		return err
+	}
+
+	// not an object, so move everything under the prefix
+	moved := false
+	err = objects.ListAll(ctx, fs.store, oldPath, true, meta.None, func(items []objects.ListItem) error {
+		for _, item := range items {
+			err := objects.Move(ctx, fs.store, storj.JoinPaths(oldPath, item.Path), storj.JoinPaths(newPath, item.Path))
+			if err != nil {
+				return err
+			}
+			moved = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return &os.PathError{Op: "rename", Path: oldName, Err: os.ErrNotExist}
+	}
+	return nil
+}
+
+// Stat returns the file info of the object or directory name
+func (fs *FileSystem) Stat(ctx context.Context, name string) (_ os.FileInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	p := objectPath(name)
+	if p == "" {
+		return rootInfo(), nil
+	}
+
+	m, err := fs.store.Meta(ctx, p)
+	if err == nil {
+		return objectInfo(p, m), nil
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return nil, err
+	}
+
+	return fs.dirInfo(ctx, p)
+}
+
+// dirInfo returns the file info of the prefix p if any object has it
+func (fs *FileSystem) dirInfo(ctx context.Context, p storj.Path) (*fileInfo, error) {
+	items, _, err := fs.store.List(ctx, p, "", "", false, 1, meta.None)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, &os.PathError{Op: "stat", Path: p, Err: os.ErrNotExist}
+	}
+	return &fileInfo{name: path.Base(p), dir: true}, nil
+}
+
+// fileInfo is the os.FileInfo of an object or a directory
+type fileInfo struct {
+	name        string
+	size        int64
+	modTime     time.Time
+	dir         bool
+	contentType string
+}
+
+func rootInfo() *fileInfo {
+	return &fileInfo{name: "/", dir: true}
+}
+
+func objectInfo(p storj.Path, m objects.Meta) *fileInfo {
+	return &fileInfo{
+		name:        path.Base(p),
+		size:        m.Size,
+		modTime:     m.Modified,
+		contentType: m.ContentType,
+	}
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.dir }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ContentType implements webdav.ContentTyper, so listings do not have to
+// download the objects to detect their content type
+func (fi *fileInfo) ContentType(ctx context.Context) (string, error) {
+	if fi.contentType == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.contentType, nil
+}
+
+// readFile is an object opened for reading. Reads are served by ranged
+// requests starting at the current offset.
+type readFile struct {
+	ctx    context.Context
+	rr     ranger.Ranger
+	info   *fileInfo
+	offset int64
+	reader io.ReadCloser // nil until the next read
+}
+
+func (f *readFile) Read(p []byte) (n int, err error) {
+	if f.offset >= f.rr.Size() {
+		return 0, io.EOF
+	}
+
+	if f.reader == nil {
+		f.reader, err = f.rr.Range(f.ctx, f.offset, f.rr.Size()-f.offset)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = f.reader.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *readFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.rr.Size()
+	}
+	if offset < 0 {
+		return f.offset, &os.PathError{Op: "seek", Path: f.info.name, Err: os.ErrInvalid}
+	}
+
+	if offset != f.offset {
+		f.closeReader()
+		f.offset = offset
+	}
+	return f.offset, nil
+}
+
+func (f *readFile) closeReader() {
+	if f.reader != nil {
+		utils.LogClose(f.reader)
+		f.reader = nil
+	}
+}
+
+func (f *readFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: f.info.name, Err: os.ErrInvalid}
+}
+
+func (f *readFile) Write(p []byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.info.name, Err: os.ErrPermission}
+}
+
+func (f *readFile) Stat() (os.FileInfo, error) { return f.info, nil }
+
+func (f *readFile) Close() error {
+	f.closeReader()
+	return nil
+}
+
+// dirFile is a directory opened for listing
+type dirFile struct {
+	ctx     context.Context
+	store   objects.Store
+	path    storj.Path
+	info    *fileInfo
+	entries []os.FileInfo // nil until the first Readdir
+	pos     int
+}
+
+func newDirFile(ctx context.Context, store objects.Store, p storj.Path, info *fileInfo) *dirFile {
+	return &dirFile{ctx: ctx, store: store, path: p, info: info}
+}
+
+func (f *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	if f.entries == nil {
+		err := f.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rest := f.entries[f.pos:]
+	if count <= 0 {
+		f.pos = len(f.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(rest) {
+		count = len(rest)
+	}
+	f.pos += count
+	return rest[:count], nil
+}
+
+// load lists the objects and prefixes directly under the directory
+func (f *dirFile) load() error {
+	f.entries = []os.FileInfo{}
+	return objects.ListAll(f.ctx, f.store, f.path, false, meta.Modified|meta.Size|meta.UserDefined, func(items []objects.ListItem) error {
+		for _, item := range items {
+			name := strings.TrimSuffix(item.Path, "/")
+			if name == "" {
+				// the marker of this directory
+				continue
+			}
+
+			if item.IsPrefix {
+				f.entries = append(f.entries, &fileInfo{name: name, dir: true})
+				continue
+			}
+
+			f.entries = append(f.entries, objectInfo(name, item.Meta))
+		}
+		return nil
+	})
+}
+
+func (f *dirFile) Read(p []byte) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: f.info.name, Err: os.ErrInvalid}
+}
+
+func (f *dirFile) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, &os.PathError{Op: "seek", Path: f.info.name, Err: os.ErrInvalid}
+	}
+	f.pos = 0
+	return 0, nil
+}
+
+func (f *dirFile) Write(p []byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.info.name, Err: os.ErrInvalid}
+}
+
+func (f *dirFile) Stat() (os.FileInfo, error) { return f.info, nil }
+
+func (f *dirFile) Close() error { return nil }
+
+// writeFile is an object opened for writing. The written data is streamed
+// to the store while it is written and the upload completes on Close. If
+// the file is written from the body of a request that was not read to its
+// end, the upload is aborted instead.
+type writeFile struct {
+	info *fileInfo
+	body *requestBody
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func newWriteFile(ctx context.Context, store objects.Store, p storj.Path, metadata pb.SerializableMeta) *writeFile {
+	pr, pw := io.Pipe()
+	body, _ := ctx.Value(requestBodyKey{}).(*requestBody)
+	f := &writeFile{
+		info: &fileInfo{name: path.Base(p), modTime: time.Now(), contentType: metadata.ContentType},
+		body: body,
+		pw:   pw,
+		done: make(chan error, 1),
+	}
+
+	go func() {
+		_, err := store.Put(ctx, p, pr, metadata, time.Time{})
+		// unblock the writer if the upload failed before reading all data
+		_ = pr.CloseWithError(err)
+		f.done <- err
+	}()
+
+	return f
+}
+
+func (f *writeFile) Write(p []byte) (int, error) {
+	n, err := f.pw.Write(p)
+	f.info.size += int64(n)
+	return n, err
+}
+
+func (f *writeFile) Close() error {
+	if f.body != nil {
+		if err := f.body.complete(); err != nil {
+			// the upload fails reading the pipe, so no truncated object
+			// is committed
+			_ = f.pw.CloseWithError(err)
+			<-f.done
+			return err
+		}
+	}
+
+	err := f.pw.Close()
+	if err != nil {
+		return err
+	}
+	return <-f.done
+}
+
+func (f *writeFile) Stat() (os.FileInfo, error) { return f.info, nil }
+
+func (f *writeFile) Read(p []byte) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: f.info.name, Err: os.ErrPermission}
+}
+
+func (f *writeFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, &os.PathError{Op: "seek", Path: f.info.name, Err: os.ErrInvalid}
+}
+
+func (f *writeFile) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: f.info.name, Err: os.ErrInvalid}
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdavgw
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/internal/testobjects"
+)
+
+func request(t *testing.T, server *httptest.Server, method, path string, body string, header map[string]string) (*http.Response, string) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestHandler(t *testing.T) {
+	store := testobjects.NewStore()
+	server := httptest.NewServer(NewHandler(store, "", ""))
+	defer server.Close()
+
+	resp, _ := request(t, server, "PUT", "/dir/file.txt", "hello world", nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{"dir/file.txt"}, store.Paths())
+
+	resp, body := request(t, server, "GET", "/dir/file.txt", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hello world", body)
+	assert.Equal(t, "text/plain; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	resp, body = request(t, server, "GET", "/dir/file.txt", "", map[string]string{"Range": "bytes=6-"})
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "world", body)
+
+	resp, _ = request(t, server, "MKCOL", "/empty", "", nil)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = request(t, server, "MKCOL", "/empty", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	resp, _ = request(t, server, "MKCOL", "/missing/child", "", nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, body = request(t, server, "PROPFIND", "/", "", map[string]string{"Depth": "1"})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.Contains(t, body, "<D:href>/dir</D:href>")
+	assert.Contains(t, body, "<D:href>/empty</D:href>")
+
+	resp, body = request(t, server, "PROPFIND", "/dir", "", map[string]string{"Depth": "1"})
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.Contains(t, body, "<D:href>/dir/file.txt</D:href>")
+	assert.Contains(t, body, "<D:getcontentlength>11</D:getcontentlength>")
+
+	resp, _ = request(t, server, "MOVE", "/dir", "", map[string]string{"Destination": server.URL + "/moved"})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []string{"empty/", "moved/file.txt"}, store.Paths())
+
+	resp, _ = request(t, server, "DELETE", "/moved", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp, _ = request(t, server, "DELETE", "/empty", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, store.Paths())
+
+	resp, _ = request(t, server, "GET", "/moved/file.txt", "", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandlerInterruptedPut(t *testing.T) {
+	store := testobjects.NewStore()
+	handler := NewHandler(store, "", "")
+
+	body := io.MultiReader(strings.NewReader("hello"), iotest.TimeoutReader(strings.NewReader("world")))
+	req := httptest.NewRequest("PUT", "/file.txt", ioutil.NopCloser(body))
+	req.ContentLength = 10
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.NotEqual(t, http.StatusCreated, w.Code)
+	assert.Empty(t, store.Paths())
+}
+
+func TestHandlerAuth(t *testing.T) {
+	server := httptest.NewServer(NewHandler(testobjects.NewStore(), "access", "secret"))
+	defer server.Close()
+
+	resp, _ := request(t, server, "PROPFIND", "/", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+
+	req, err := http.NewRequest("PROPFIND", server.URL+"/", nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("access", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package webdavgw
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/webdav"
+
+	"storj.io/storj/pkg/storage/objects"
+)
+
+// NewHandler returns an http.Handler serving the objects of store over
+// WebDAV. If accessKey is not empty, requests have to authenticate with
+// HTTP basic authentication using accessKey and secretKey.
+func NewHandler(store objects.Store, accessKey, secretKey string) http.Handler {
+	handler := &webdav.Handler{
+		FileSystem: NewFileSystem(store),
+		LockSystem: webdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				zap.S().Debugf("%s %s: %v", r.Method, r.URL.Path, err)
+			}
+		},
+	}
+
+	if accessKey == "" {
+		return &putBodies{handler: handler}
+	}
+
+	return &basicAuth{
+		handler:   &putBodies{handler: handler},
+		accessKey: []byte(accessKey),
+		secretKey: []byte(secretKey),
+	}
+}
+
+// basicAuth rejects requests without valid basic authentication credentials
+type basicAuth struct {
+	handler   http.Handler
+	accessKey []byte
+	secretKey []byte
+}
+
+func (a *basicAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, password, ok := r.BasicAuth()
+	validUser := subtle.ConstantTimeCompare([]byte(user), a.accessKey) == 1
+	validPassword := subtle.ConstantTimeCompare([]byte(password), a.secretKey) == 1
+	if !ok || !validUser || !validPassword {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Storj"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	a.handler.ServeHTTP(w, r)
+}
+
+// putBodies passes the bodies of PUT requests to the file system in their
+// context. webdav.Handler closes the uploaded file even if copying the body
+// failed, so the file checks that the body was read to its end before
+// committing the object.
+type putBodies struct {
+	handler http.Handler
+}
+
+func (h *putBodies) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		body := &requestBody{ReadCloser: r.Body}
+		r.Body = body
+		r = r.WithContext(context.WithValue(r.Context(), requestBodyKey{}, body))
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+type requestBodyKey struct{}
+
+// requestBody records the last error reading a request body
+type requestBody struct {
+	io.ReadCloser
+	err error
+}
+
+func (b *requestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// complete returns an error unless the body was read to its end
+func (b *requestBody) complete() error {
+	switch b.err {
+	case io.EOF:
+		return nil
+	case nil:
+		return io.ErrUnexpectedEOF
+	default:
+		return b.err
+	}
+}
@@ -39,15 +39,37 @@ func NewDir(path string) (*Dir, error) {
 		os.MkdirAll(dir.blobdir(), dirPermission),
 		os.MkdirAll(dir.tempdir(), dirPermission),
 		os.MkdirAll(dir.trashdir(), dirPermission),
+		os.MkdirAll(dir.quarantinedir(), dirPermission),
 	)
 }
 
+// OpenDir returns the existing folder for storing blobs at path, without
+// creating it, so that a disk that is not mounted is not mistaken for an
+// empty folder
+func OpenDir(path string) (*Dir, error) {
+	dir := &Dir{
+		path: path,
+	}
+
+	for _, path := range []string{dir.blobdir(), dir.tempdir(), dir.trashdir(), dir.quarantinedir()} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, Error.New("%s is not a directory", path)
+		}
+	}
+	return dir, nil
+}
+
 // Path returns the directory path
 func (dir *Dir) Path() string { return dir.path }
 
-func (dir *Dir) blobdir() string  { return filepath.Join(dir.path) }
-func (dir *Dir) tempdir() string  { return filepath.Join(dir.path, "tmp") }
-func (dir *Dir) trashdir() string { return filepath.Join(dir.path, "trash") }
+func (dir *Dir) blobdir() string       { return filepath.Join(dir.path) }
+func (dir *Dir) tempdir() string       { return filepath.Join(dir.path, "tmp") }
+func (dir *Dir) trashdir() string      { return filepath.Join(dir.path, "trash") }
+func (dir *Dir) quarantinedir() string { return filepath.Join(dir.path, "quarantine") }
 
 // CreateTemporaryFile creates a preallocated temporary file in the temp directory
 // prealloc preallocates file to make writing faster
@@ -76,6 +98,13 @@ func (dir *Dir) DeleteTemporary(file *os.File) error {
 	return utils.CombineErrors(closeErr, os.Remove(file.Name()))
 }
 
+// DeleteTemporaryFiles deletes the temporary files left by stores that
+// did not finish, such as after a crash. It must not be called while
+// storing.
+func (dir *Dir) DeleteTemporaryFiles() error {
+	return removeAllContent(dir.tempdir())
+}
+
 // refToPath converts blob reference to a filepath
 func (dir *Dir) refToPath(ref storage.BlobRef) string {
 	hex := hex.EncodeToString(ref[:])
@@ -120,6 +149,18 @@ func (dir *Dir) Open(ref storage.BlobRef) (*os.File, error) {
 	return os.OpenFile(path, os.O_RDONLY, blobPermission)
 }
 
+// Quarantine moves the file with the specified ref out of the blobs, so
+// that it can be inspected
+func (dir *Dir) Quarantine(ref storage.BlobRef) error {
+	path := dir.refToPath(ref)
+	quarantinePath := filepath.Join(dir.quarantinedir(), hex.EncodeToString(ref[:]))
+	err := os.Rename(path, quarantinePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
 // Delete deletes file with the specified ref
 func (dir *Dir) Delete(ref storage.BlobRef) error {
 	path := dir.refToPath(ref)
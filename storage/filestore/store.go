@@ -13,6 +13,7 @@ import (
 
 	"github.com/zeebo/errs"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 )
@@ -20,11 +21,16 @@ import (
 // Error is the default filestore error class
 var Error = errs.Class("filestore error")
 
+// ErrCorrupted is the error class for blobs whose content no longer hashes
+// to their reference
+var ErrCorrupted = errs.Class("blob corrupted")
+
 const (
 	headerSize = 32
 
 	// TODO: implement readBufferSize  = 64 << 10 // 64 KB
-	writeBufferSize = 64 << 10 // 64 KB
+	writeBufferSize  = 64 << 10 // 64 KB
+	verifyBufferSize = 64 << 10 // 64 KB
 )
 
 var _ storage.Blobs = (*Store)(nil)
@@ -48,6 +54,15 @@ func NewAt(path string) (*Store, error) {
 	return &Store{dir}, nil
 }
 
+// OpenAt opens the existing disk blob store in the specified directory
+func OpenAt(path string) (*Store, error) {
+	dir, err := OpenDir(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Store{dir}, nil
+}
+
 // Load loads blob with the specified hash
 func (store *Store) Load(ctx context.Context, hash storage.BlobRef) (storage.ReadSeekCloser, error) {
 	file, openErr := store.dir.Open(hash)
@@ -75,6 +90,62 @@ func (store *Store) Delete(ctx context.Context, hash storage.BlobRef) error {
 	return nil
 }
 
+// Verify rehashes the content of the blob with the specified hash and
+// returns ErrCorrupted if it does not match. The content is read as fast as
+// throttle allows, unless it is nil.
+func (store *Store) Verify(ctx context.Context, hash storage.BlobRef, throttle *sync2.Throttle) error {
+	file, err := store.dir.Open(hash)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrCorrupted.New("%x is missing", hash[:])
+		}
+		return Error.Wrap(err)
+	}
+	defer utils.LogClose(file)
+
+	hasher := sha256.New()
+	buf := make([]byte, verifyBufferSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n := int64(len(buf))
+		if throttle != nil {
+			n, err = throttle.ConsumeOrWait(n)
+			if err != nil {
+				return err
+			}
+		}
+
+		read, err := file.Read(buf[:n])
+		_, _ = hasher.Write(buf[:read])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	var actual storage.BlobRef
+	copy(actual[:], hasher.Sum(nil))
+	if actual != hash {
+		return ErrCorrupted.New("%x hashes to %x", hash[:], actual[:])
+	}
+	return nil
+}
+
+// Quarantine moves the blob with the specified hash out of the store, such
+// as when it is corrupted
+func (store *Store) Quarantine(ctx context.Context, hash storage.BlobRef) error {
+	err := store.dir.Quarantine(hash)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
 // GarbageCollect tries to delete any files that haven't yet been deleted
 func (store *Store) GarbageCollect(ctx context.Context) error {
 	err := store.dir.GarbageCollect()
@@ -84,6 +155,16 @@ func (store *Store) GarbageCollect(ctx context.Context) error {
 	return nil
 }
 
+// DeleteTemporaryFiles deletes the blobs that were not completely stored,
+// such as after a crash. It must not be called while storing.
+func (store *Store) DeleteTemporaryFiles(ctx context.Context) error {
+	err := store.dir.DeleteTemporaryFiles()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
 // Store stores r to disk, optionally takes a size argument, -1 is unknown size
 func (store *Store) Store(ctx context.Context, r io.Reader, size int64) (storage.BlobRef, error) {
 	file, err := store.dir.CreateTemporaryFile(size)
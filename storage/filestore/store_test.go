@@ -6,6 +6,7 @@ package filestore_test
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -14,6 +15,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"storj.io/storj/internal/sync2"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
 )
@@ -197,6 +199,81 @@ func TestDeleteWhileReading(t *testing.T) {
 	}
 }
 
+func TestDeleteTemporaryFiles(t *testing.T) {
+	ctx := context.Background()
+
+	dir, store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	// a store that crashed leaves its temporary file behind
+	blobs, err := filestore.NewDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := blobs.CreateTemporaryFile(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteTemporaryFiles(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error got %v", err)
+	}
+}
+
+func TestVerifyQuarantine(t *testing.T) {
+	ctx := context.Background()
+
+	dir, store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	ref, err := store.Store(ctx, bytes.NewReader([]byte("butts")), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	throttle := sync2.NewThrottle()
+	if err := throttle.Produce(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Verify(ctx, ref, throttle); err != nil {
+		t.Fatal(err)
+	}
+
+	// flip a bit of the stored data
+	path := filepath.Join(dir, hex.EncodeToString(ref[:])[:2], hex.EncodeToString(ref[:])[2:])
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 1
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Verify(ctx, ref, nil); !filestore.ErrCorrupted.Has(err) {
+		t.Fatalf("expected corrupted error got %v", err)
+	}
+
+	if err := store.Quarantine(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "quarantine", hex.EncodeToString(ref[:]))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(ctx, ref); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error got %v", err)
+	}
+	if err := store.Verify(ctx, ref, nil); !filestore.ErrCorrupted.Has(err) {
+		t.Fatalf("expected corrupted error got %v", err)
+	}
+}
+
 type errorReader struct{}
 
 func (errorReader *errorReader) Read(data []byte) (n int, err error) {
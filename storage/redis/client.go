@@ -4,6 +4,7 @@
 package redis
 
 import (
+	"bytes"
 	"sort"
 	"strconv"
 	"time"
@@ -121,6 +122,40 @@ func (client *Client) Delete(key storage.Key) error {
 	return nil
 }
 
+// CompareAndSwap replaces the value of key with newValue if it is oldValue
+func (client *Client) CompareAndSwap(key storage.Key, oldValue, newValue storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey.New("")
+	}
+
+	err := client.db.Watch(func(tx *redis.Tx) error {
+		value, err := tx.Get(key.String()).Bytes()
+		if err == redis.Nil {
+			value, err = nil, nil
+		}
+		if err != nil {
+			return Error.New("get error: %v", err)
+		}
+		if oldValue == nil && value != nil || oldValue != nil && (value == nil || !bytes.Equal(value, oldValue)) {
+			return storage.ErrValueChanged.New("%s", key)
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			if newValue == nil {
+				pipe.Del(key.String())
+			} else {
+				pipe.Set(key.String(), []byte(newValue), client.TTL)
+			}
+			return nil
+		})
+		return err
+	}, key.String())
+	if err == redis.TxFailedErr {
+		return storage.ErrValueChanged.New("%s", key)
+	}
+	return err
+}
+
 // Close closes a redis client
 func (client *Client) Close() error {
 	return client.db.Close()
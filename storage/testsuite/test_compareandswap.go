@@ -0,0 +1,64 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"bytes"
+	"testing"
+
+	"storj.io/storj/storage"
+)
+
+func testCompareAndSwap(t *testing.T, store storage.KeyValueStore) {
+	key := storage.Key("compare-and-swap")
+	defer func() { _ = store.Delete(key) }()
+
+	check := func(expected storage.Value) {
+		t.Helper()
+		value, err := store.Get(key)
+		if expected == nil {
+			if !storage.ErrKeyNotFound.Has(err) {
+				t.Fatalf("expected %q to be missing: got %v, %v", key, value, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("failed to get %q: %v", key, err)
+		}
+		if !bytes.Equal([]byte(value), []byte(expected)) {
+			t.Fatalf("invalid value for %q = %v: got %v", key, expected, value)
+		}
+	}
+
+	if err := store.CompareAndSwap(key, nil, storage.Value("a")); err != nil {
+		t.Fatalf("failed to create %q: %v", key, err)
+	}
+	check(storage.Value("a"))
+
+	if err := store.CompareAndSwap(key, nil, storage.Value("b")); !storage.ErrValueChanged.Has(err) {
+		t.Fatalf("creating existing %q should fail: %v", key, err)
+	}
+	if err := store.CompareAndSwap(key, storage.Value("b"), storage.Value("c")); !storage.ErrValueChanged.Has(err) {
+		t.Fatalf("swapping changed %q should fail: %v", key, err)
+	}
+	check(storage.Value("a"))
+
+	if err := store.CompareAndSwap(key, storage.Value("a"), storage.Value("b")); err != nil {
+		t.Fatalf("failed to swap %q: %v", key, err)
+	}
+	check(storage.Value("b"))
+
+	if err := store.CompareAndSwap(key, storage.Value("a"), nil); !storage.ErrValueChanged.Has(err) {
+		t.Fatalf("deleting changed %q should fail: %v", key, err)
+	}
+	if err := store.CompareAndSwap(key, storage.Value("b"), nil); err != nil {
+		t.Fatalf("failed to delete %q: %v", key, err)
+	}
+	check(nil)
+
+	if err := store.CompareAndSwap(key, storage.Value("b"), storage.Value("c")); !storage.ErrValueChanged.Has(err) {
+		t.Fatalf("swapping missing %q should fail: %v", key, err)
+	}
+	check(nil)
+}